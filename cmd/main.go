@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Fusos embutidos: a imagem pode não ter o banco de fusos do sistema
 
 	"github.com/ericolvr/maintenance-v2/config"
+	"github.com/ericolvr/maintenance-v2/internal/carrier"
+	"github.com/ericolvr/maintenance-v2/internal/cnab"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/mailbox"
+	"github.com/ericolvr/maintenance-v2/internal/mailer"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/ericolvr/maintenance-v2/internal/nfse"
 	"github.com/ericolvr/maintenance-v2/internal/repository"
 	"github.com/ericolvr/maintenance-v2/internal/routes"
 	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+	"github.com/ericolvr/maintenance-v2/internal/worker"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -27,46 +42,250 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	problemRepo := repository.NewProblemRepository(db)
 	solutionRepo := repository.NewSolutionRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	checkinRepo := repository.NewCheckinRepository(db)
+	visitRepo := repository.NewVisitRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	purchaseRepo := repository.NewPurchaseRepository(db)
+	shipmentRepo := repository.NewShipmentRepository(db)
+	assetRepo := repository.NewAssetRepository(db)
+	warrantyRepo := repository.NewWarrantyRepository(db)
+	contractRepo := repository.NewContractRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
+	expenseRepo := repository.NewExpenseRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	dashboardRepo := repository.NewDashboardRepository(db)
+	scorecardRepo := repository.NewScorecardRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	scheduledReportRepo := repository.NewScheduledReportRepository(db)
+	reportDataRepo := repository.NewReportDataRepository(db)
+	ratingRepo := repository.NewRatingRepository(db)
+	quoteRepo := repository.NewQuoteRepository(db)
+	emailMessageRepo := repository.NewEmailMessageRepository(db)
+	txManager := repository.NewTxManager(db)
 
 	// Services
-	branchService := service.NewBranchService(branchRepo)
+	branchService := service.NewBranchService(branchRepo, clientRepo)
 	clientService := service.NewClientService(clientRepo)
 	costService := service.NewCostService(costRepo)
 	distanceService := service.NewDistanceService(distanceRepo)
 	providerService := service.NewProviderService(providerRepo)
-	ticketService := service.NewTicketService(ticketRepo, branchRepo, providerRepo, problemRepo, solutionRepo, distanceService)
-	userService := service.NewUserService(userRepo, []byte(cfg.JWTSecret))
+	contractService := service.NewContractService(contractRepo, clientRepo, solutionRepo, costRepo)
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Fatalf("Failed to configure timezone: %v", err)
+	}
+	visitService := service.NewVisitService(visitRepo, ticketRepo, branchRepo, providerRepo, contractService, location, []byte(cfg.JWTSecret), txManager)
+	warrantyService := service.NewWarrantyService(warrantyRepo, ticketRepo)
+	inventoryService := service.NewInventoryService(inventoryRepo, solutionRepo, providerRepo)
+	ticketService := service.NewTicketService(ticketRepo, branchRepo, providerRepo, problemRepo, solutionRepo, distanceService, checkinRepo, visitService, inventoryService, assetRepo, warrantyService, contractService, quoteRepo, txManager)
+	userService := service.NewUserService(userRepo, providerRepo, clientRepo, []byte(cfg.JWTSecret))
 	problemService := service.NewProblemService(problemRepo)
 	solutionService := service.NewSolutionService(solutionRepo, problemRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, ticketRepo, cfg.UploadDir)
+	commentService := service.NewCommentService(commentRepo, ticketRepo)
+	assetService := service.NewAssetService(assetRepo, branchRepo, ticketRepo, problemRepo)
+	purchaseService := service.NewPurchaseService(purchaseRepo, ticketRepo, inventoryRepo, txManager)
+	statementService := service.NewStatementService(statementRepo, providerRepo, payerAccount(cfg))
+	expenseService := service.NewExpenseService(expenseRepo, ticketRepo, attachmentRepo, txManager)
+	issuer, err := newIssuer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure nfs-e issuer: %v", err)
+	}
+	invoiceService := service.NewInvoiceService(invoiceRepo, clientRepo, inventoryService, issuer, issuerCompany(cfg), cfg.NfseServiceCode, txManager)
+	dashboardService := service.NewDashboardService(dashboardRepo, clientRepo)
+	sla, err := slaPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure sla: %v", err)
+	}
+	scorecardService := service.NewScorecardService(scorecardRepo, sla)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, clientRepo)
+	sender, err := newSender(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure mail sender: %v", err)
+	}
+	scheduledReportService := service.NewScheduledReportService(scheduledReportRepo, reportDataRepo, clientRepo, sender, cfg.UploadDir)
+	quoteService := service.NewQuoteService(quoteRepo, ticketRepo, ticketService, contractService, sender, []byte(cfg.JWTSecret), cfg.QuoteApprovalURL, cfg.QuoteLinkTTL)
+	tracker, err := newTracker(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure carrier: %v", err)
+	}
+	shipmentService := service.NewShipmentService(shipmentRepo, ticketRepo, inventoryRepo, tracker, txManager)
+	checkinService := service.NewCheckinService(checkinRepo, ticketRepo, branchRepo, userRepo, visitRepo, cfg.CheckinRadiusMeters, cfg.RouteTolerancePercent)
+	providerPortalService := service.NewProviderPortalService(userRepo, ticketRepo, timeEntryRepo, ticketService, attachmentService, commentService, expenseService, visitService, contractService, quoteRepo, txManager)
+	clientPortalService := service.NewClientPortalService(userRepo, ticketRepo, branchRepo, contractRepo, checkinRepo, ratingRepo, ticketService, commentService, attachmentService, quoteService)
+
+	// Workers: rodam para todos os clientes
+	ctx := tenant.System(context.Background())
+	if cfg.MailSource != "" {
+		source, err := newMailSource(cfg)
+		if err != nil {
+			log.Fatalf("Failed to configure mail source: %v", err)
+		}
+		defer source.Close()
+
+		emailIngestionService := service.NewEmailIngestionService(source, ticketService, commentService, attachmentService, ticketRepo, branchRepo, emailMessageRepo, txManager)
+		go worker.Every(ctx, "email-ingestion", cfg.MailPollInterval, emailIngestionService.Poll)
+	}
+	if tracker != nil {
+		go worker.Every(ctx, "shipment-tracking", cfg.CarrierPollInterval, shipmentService.SyncAll)
+	}
+	go worker.Every(ctx, "scheduled-reports", cfg.ReportPollInterval, scheduledReportService.RunDue)
 
 	router := gin.Default()
 
 	// CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 	}))
 
+	// Tenant middleware: exige o token nas rotas não públicas e restringe usuários de cliente ao próprio cliente
+	router.Use(middleware.Tenant([]byte(cfg.JWTSecret)))
+
 	// Routes
 	routes.BranchRoutes(router, handlers.NewBranchHandler(branchService))
 	routes.ClientRoutes(router, handlers.NewClientHandler(clientService))
 	routes.CostRoutes(router, handlers.NewCostHandler(costService))
 	routes.DistanceRoutes(router, handlers.NewDistanceHandler(distanceService))
 	routes.ProviderRoutes(router, handlers.NewProviderHandler(providerService))
-	routes.TicketRoutes(router, handlers.NewTicketHandler(ticketService), handlers.NewTicketProblemHandler(ticketService), handlers.NewTicketSolutionHandler(ticketService))
-	routes.UserRoutes(router, handlers.NewUserHandler(userService))
+	routes.TicketRoutes(router, handlers.NewTicketHandler(ticketService), handlers.NewTicketProblemHandler(ticketService), handlers.NewTicketSolutionHandler(ticketService), []byte(cfg.JWTSecret))
+	routes.UserRoutes(router, handlers.NewUserHandler(userService), []byte(cfg.JWTSecret))
 	routes.ProblemRoutes(router, handlers.NewProblemHandler(problemService))
 	routes.SolutionRoutes(router, handlers.NewSolutionHandler(solutionService))
+	routes.TicketAttachmentRoutes(router, handlers.NewTicketAttachmentHandler(attachmentService), handlers.NewTicketCommentHandler(commentService))
+	routes.TicketCheckinRoutes(router, handlers.NewTicketCheckinHandler(checkinService))
+	routes.VisitRoutes(router, handlers.NewVisitHandler(visitService))
+	routes.QuoteRoutes(router, handlers.NewQuoteHandler(quoteService))
+	routes.AssetRoutes(router, handlers.NewAssetHandler(assetService))
+	routes.ContractRoutes(router, handlers.NewContractHandler(contractService))
+	routes.WarrantyRoutes(router, handlers.NewWarrantyHandler(warrantyService))
+	routes.DashboardRoutes(router, handlers.NewDashboardHandler(dashboardService))
+	routes.ScorecardRoutes(router, handlers.NewScorecardHandler(scorecardService))
+	routes.AnalyticsRoutes(router, handlers.NewAnalyticsHandler(analyticsService))
+	routes.ScheduledReportRoutes(router, handlers.NewScheduledReportHandler(scheduledReportService), []byte(cfg.JWTSecret))
+	routes.InventoryRoutes(router, handlers.NewInventoryHandler(inventoryService))
+	routes.PurchaseRoutes(router, handlers.NewPurchaseHandler(purchaseService), []byte(cfg.JWTSecret))
+	routes.ShipmentRoutes(router, handlers.NewShipmentHandler(shipmentService))
+	routes.StatementRoutes(router, handlers.NewStatementHandler(statementService), []byte(cfg.JWTSecret))
+	routes.ExpenseRoutes(router, handlers.NewExpenseHandler(expenseService), []byte(cfg.JWTSecret))
+	routes.InvoiceRoutes(router, handlers.NewInvoiceHandler(invoiceService), []byte(cfg.JWTSecret))
+	routes.ProviderPortalRoutes(router, handlers.NewProviderPortalHandler(providerPortalService), []byte(cfg.JWTSecret))
+	routes.ClientPortalRoutes(router, handlers.NewClientPortalHandler(clientPortalService), []byte(cfg.JWTSecret))
 
 	log.Printf(
 		"Server is running on port %s", cfg.ServerPort,
 	)
 
-	err := router.Run(":" + cfg.ServerPort)
+	err = router.Run(":" + cfg.ServerPort)
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+func newMailSource(cfg *config.Config) (mailbox.Source, error) {
+	switch cfg.MailSource {
+	case "imap":
+		return mailbox.NewIMAP(cfg.IMAPAddr, cfg.IMAPUser, cfg.IMAPPassword, cfg.IMAPMailbox), nil
+	case "maildir":
+		return mailbox.NewMaildir(cfg.MailMaildir)
+	default:
+		return nil, fmt.Errorf("unknown MAIL_SOURCE %q", cfg.MailSource)
+	}
+}
+
+func newTracker(cfg *config.Config) (carrier.Tracker, error) {
+	switch cfg.CarrierProvider {
+	case "":
+		return nil, nil
+	case "correios":
+		return carrier.NewCorreios(cfg.CorreiosAPIURL, cfg.CorreiosAPIToken), nil
+	case "fake":
+		return carrier.NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown CARRIER_PROVIDER %q", cfg.CarrierProvider)
+	}
+}
+
+// payerAccount monta a conta da empresa usada nas remessas de pagamento
+func payerAccount(cfg *config.Config) cnab.Account {
+	return cnab.Account{
+		Name:        cfg.PayerName,
+		Document:    cfg.PayerDocument,
+		BankCode:    cfg.PayerBankCode,
+		Agency:      cfg.PayerAgency,
+		AgencyDigit: cfg.PayerAgencyDigit,
+		Number:      cfg.PayerAccount,
+		NumberDigit: cfg.PayerAccountDigit,
+		Agreement:   cfg.PayerAgreement,
+		Address:     cfg.PayerAddress,
+		City:        cfg.PayerCity,
+		Zipcode:     cfg.PayerZipcode,
+		State:       cfg.PayerState,
+	}
+}
+
+func newIssuer(cfg *config.Config) (nfse.Issuer, error) {
+	switch cfg.NfseProvider {
+	case "":
+		return nil, nil
+	case "fake":
+		return nfse.NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown NFSE_PROVIDER %q", cfg.NfseProvider)
+	}
+}
+
+// issuerCompany monta o prestador informado nas NFS-e a partir dos dados da empresa
+func issuerCompany(cfg *config.Config) nfse.Party {
+	return nfse.Party{
+		Name:                  cfg.PayerName,
+		Document:              cfg.PayerDocument,
+		MunicipalRegistration: cfg.NfseMunicipalRegistration,
+		City:                  cfg.PayerCity,
+		State:                 cfg.PayerState,
+	}
+}
+
+func newSender(cfg *config.Config) (mailer.Sender, error) {
+	switch cfg.MailSender {
+	case "":
+		return nil, nil
+	case "smtp":
+		return mailer.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+	case "fake":
+		return mailer.NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_SENDER %q", cfg.MailSender)
+	}
+}
+
+// slaPolicy lê os prazos por prioridade no formato "alta=24,média=48"
+func slaPolicy(cfg *config.Config) (domain.SLAPolicy, error) {
+	policy := domain.SLAPolicy{
+		Hours:        make(map[string]float64),
+		DefaultHours: cfg.SLADefaultHours,
+	}
+	if policy.DefaultHours <= 0 {
+		return policy, fmt.Errorf("invalid SLA_DEFAULT_HOURS %v", cfg.SLADefaultHours)
+	}
+
+	for _, entry := range strings.Split(cfg.SLAHours, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		priority, value, found := strings.Cut(entry, "=")
+		hours, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !found || strings.TrimSpace(priority) == "" || err != nil || hours <= 0 {
+			return policy, fmt.Errorf("invalid SLA_HOURS entry %q", entry)
+		}
+		policy.Hours[strings.ToLower(strings.TrimSpace(priority))] = hours
+	}
+
+	return policy, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/spf13/viper"
@@ -20,6 +21,65 @@ type Config struct {
 	PostgresHost string
 	PostgresPort string
 	JWTSecret    string
+	UploadDir    string
+
+	// Ingestão de emails
+	MailSource       string
+	MailMaildir      string
+	IMAPAddr         string
+	IMAPUser         string
+	IMAPPassword     string
+	IMAPMailbox      string
+	MailPollInterval time.Duration
+
+	// Fuso da empresa: horário de funcionamento das agências é comparado nele
+	Timezone string
+
+	// Check-in / check-out
+	CheckinRadiusMeters   float64
+	RouteTolerancePercent float64
+
+	// Rastreio de envios
+	CarrierProvider     string
+	CorreiosAPIURL      string
+	CorreiosAPIToken    string
+	CarrierPollInterval time.Duration
+
+	// Conta da empresa para a remessa de pagamentos (CNAB 240)
+	PayerName         string
+	PayerDocument     string
+	PayerBankCode     string
+	PayerAgency       string
+	PayerAgencyDigit  string
+	PayerAccount      string
+	PayerAccountDigit string
+	PayerAgreement    string
+	PayerAddress      string
+	PayerCity         string
+	PayerZipcode      string
+	PayerState        string
+
+	// Emissão de NFS-e (prestador = empresa dos PAYER_*)
+	NfseProvider              string
+	NfseServiceCode           string
+	NfseMunicipalRegistration string
+
+	// Prazos de atendimento (SLA) por prioridade, usados nos scorecards
+	SLAHours        string
+	SLADefaultHours float64
+
+	// Envio de emails e relatórios agendados
+	MailSender         string
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPFrom           string
+	ReportPollInterval time.Duration
+
+	// Link de aprovação de orçamentos enviado ao cliente
+	QuoteApprovalURL string
+	QuoteLinkTTL     time.Duration
 }
 
 var (
@@ -31,6 +91,23 @@ var (
 func LoadConfig() *Config {
 	once.Do(func() {
 		viper.SetConfigFile(".env")
+		viper.SetDefault("UPLOAD_DIR", "uploads")
+		viper.SetDefault("IMAP_MAILBOX", "INBOX")
+		viper.SetDefault("MAIL_POLL_INTERVAL", "1m")
+		viper.SetDefault("TIMEZONE", "America/Sao_Paulo")
+		viper.SetDefault("CHECKIN_RADIUS_METERS", 300)
+		viper.SetDefault("ROUTE_TOLERANCE_PERCENT", 20)
+		viper.SetDefault("CORREIOS_API_URL", "https://api.correios.com.br/srorastro/v1")
+		viper.SetDefault("CARRIER_POLL_INTERVAL", "30m")
+		viper.SetDefault("NFSE_SERVICE_CODE", "14.01")
+		viper.SetDefault("SLA_HOURS", "alta=24,média=48,baixa=72")
+		viper.SetDefault("SLA_DEFAULT_HOURS", 72)
+		viper.SetDefault("SMTP_HOST", "localhost")
+		viper.SetDefault("SMTP_PORT", "1025")
+		viper.SetDefault("SMTP_FROM", "relatorios@localhost")
+		viper.SetDefault("REPORT_POLL_INTERVAL", "5m")
+		viper.SetDefault("QUOTE_APPROVAL_URL", "http://localhost:5173/orcamentos")
+		viper.SetDefault("QUOTE_LINK_TTL", "168h")
 		if err := viper.ReadInConfig(); err != nil {
 			log.Fatalf("Error loading .env file: %v", err)
 		}
@@ -45,6 +122,56 @@ func LoadConfig() *Config {
 			PostgresHost: viper.GetString("POSTGRES_HOST"),
 			PostgresPort: viper.GetString("POSTGRES_PORT"),
 			JWTSecret:    viper.GetString("JWT_SECRET"),
+			UploadDir:    viper.GetString("UPLOAD_DIR"),
+
+			MailSource:       viper.GetString("MAIL_SOURCE"),
+			MailMaildir:      viper.GetString("MAIL_MAILDIR"),
+			IMAPAddr:         viper.GetString("IMAP_ADDR"),
+			IMAPUser:         viper.GetString("IMAP_USER"),
+			IMAPPassword:     viper.GetString("IMAP_PASSWORD"),
+			IMAPMailbox:      viper.GetString("IMAP_MAILBOX"),
+			MailPollInterval: viper.GetDuration("MAIL_POLL_INTERVAL"),
+
+			Timezone: viper.GetString("TIMEZONE"),
+
+			CheckinRadiusMeters:   viper.GetFloat64("CHECKIN_RADIUS_METERS"),
+			RouteTolerancePercent: viper.GetFloat64("ROUTE_TOLERANCE_PERCENT"),
+
+			CarrierProvider:     viper.GetString("CARRIER_PROVIDER"),
+			CorreiosAPIURL:      viper.GetString("CORREIOS_API_URL"),
+			CorreiosAPIToken:    viper.GetString("CORREIOS_API_TOKEN"),
+			CarrierPollInterval: viper.GetDuration("CARRIER_POLL_INTERVAL"),
+
+			PayerName:         viper.GetString("PAYER_NAME"),
+			PayerDocument:     viper.GetString("PAYER_DOCUMENT"),
+			PayerBankCode:     viper.GetString("PAYER_BANK_CODE"),
+			PayerAgency:       viper.GetString("PAYER_AGENCY"),
+			PayerAgencyDigit:  viper.GetString("PAYER_AGENCY_DIGIT"),
+			PayerAccount:      viper.GetString("PAYER_ACCOUNT"),
+			PayerAccountDigit: viper.GetString("PAYER_ACCOUNT_DIGIT"),
+			PayerAgreement:    viper.GetString("PAYER_AGREEMENT"),
+			PayerAddress:      viper.GetString("PAYER_ADDRESS"),
+			PayerCity:         viper.GetString("PAYER_CITY"),
+			PayerZipcode:      viper.GetString("PAYER_ZIPCODE"),
+			PayerState:        viper.GetString("PAYER_STATE"),
+
+			NfseProvider:              viper.GetString("NFSE_PROVIDER"),
+			NfseServiceCode:           viper.GetString("NFSE_SERVICE_CODE"),
+			NfseMunicipalRegistration: viper.GetString("NFSE_MUNICIPAL_REGISTRATION"),
+
+			SLAHours:        viper.GetString("SLA_HOURS"),
+			SLADefaultHours: viper.GetFloat64("SLA_DEFAULT_HOURS"),
+
+			MailSender:         viper.GetString("MAIL_SENDER"),
+			SMTPHost:           viper.GetString("SMTP_HOST"),
+			SMTPPort:           viper.GetString("SMTP_PORT"),
+			SMTPUsername:       viper.GetString("SMTP_USERNAME"),
+			SMTPPassword:       viper.GetString("SMTP_PASSWORD"),
+			SMTPFrom:           viper.GetString("SMTP_FROM"),
+			ReportPollInterval: viper.GetDuration("REPORT_POLL_INTERVAL"),
+
+			QuoteApprovalURL: viper.GetString("QUOTE_APPROVAL_URL"),
+			QuoteLinkTTL:     viper.GetDuration("QUOTE_LINK_TTL"),
 		}
 	})
 	return cfg
@@ -0,0 +1,35 @@
+package carrier
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTrackingNotFound indica que a transportadora não conhece o código de rastreio
+var ErrTrackingNotFound = errors.New("tracking number not found")
+
+// STATUS NORMALIZADOS DOS EVENTOS
+const (
+	StatusPosted         = "posted"
+	StatusInTransit      = "in_transit"
+	StatusOutForDelivery = "out_for_delivery"
+	StatusDelivered      = "delivered"
+	StatusFailed         = "failed"
+)
+
+// Tracker consulta o rastreio de um objeto na transportadora (Correios ou fake local)
+type Tracker interface {
+	// Name identifica a transportadora gravada no envio (ex: "correios")
+	Name() string
+	// Track retorna os eventos do objeto em ordem cronológica
+	Track(ctx context.Context, trackingNumber string) ([]Event, error)
+}
+
+// Event representa um evento de rastreio já normalizado
+type Event struct {
+	Status      string
+	Description string
+	Location    string
+	OccurredAt  time.Time
+}
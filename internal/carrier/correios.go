@@ -0,0 +1,135 @@
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formato de data da API de rastro dos Correios (horário de Brasília, sem fuso)
+const correiosTimeLayout = "2006-01-02T15:04:05"
+
+var brasilia = time.FixedZone("BRT", -3*60*60)
+
+// Correios consulta a API de rastro (SRO) dos Correios
+type Correios struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewCorreios(baseURL, token string) *Correios {
+	return &Correios{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *Correios) Name() string {
+	return "correios"
+}
+
+type correiosResponse struct {
+	Objetos []struct {
+		CodObjeto string `json:"codObjeto"`
+		Mensagem  string `json:"mensagem"`
+		Eventos   []struct {
+			Codigo     string `json:"codigo"`
+			Tipo       string `json:"tipo"`
+			DtHrCriado string `json:"dtHrCriado"`
+			Descricao  string `json:"descricao"`
+			Unidade    struct {
+				Endereco struct {
+					Cidade string `json:"cidade"`
+					UF     string `json:"uf"`
+				} `json:"endereco"`
+			} `json:"unidade"`
+		} `json:"eventos"`
+	} `json:"objetos"`
+}
+
+func (c *Correios) Track(ctx context.Context, trackingNumber string) ([]Event, error) {
+	endpoint := fmt.Sprintf("%s/objetos/%s?resultado=T", c.baseURL, url.PathEscape(strings.ToUpper(trackingNumber)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracking request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query correios: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrTrackingNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("correios returned status %d", resp.StatusCode)
+	}
+
+	var body correiosResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode correios response: %w", err)
+	}
+
+	if len(body.Objetos) == 0 || len(body.Objetos[0].Eventos) == 0 {
+		return nil, ErrTrackingNotFound
+	}
+
+	var events []Event
+	for _, evento := range body.Objetos[0].Eventos {
+		occurredAt, err := time.ParseInLocation(correiosTimeLayout, evento.DtHrCriado, brasilia)
+		if err != nil {
+			return nil, fmt.Errorf("invalid correios event date %q: %w", evento.DtHrCriado, err)
+		}
+
+		location := evento.Unidade.Endereco.Cidade
+		if evento.Unidade.Endereco.UF != "" {
+			location = strings.TrimSpace(location + " - " + evento.Unidade.Endereco.UF)
+		}
+
+		events = append(events, Event{
+			Status:      correiosStatus(evento.Codigo, evento.Tipo),
+			Description: evento.Descricao,
+			Location:    location,
+			OccurredAt:  occurredAt,
+		})
+	}
+
+	// A API devolve do mais recente para o mais antigo
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	return events, nil
+}
+
+// correiosStatus traduz o código SRO do evento para o status normalizado
+func correiosStatus(codigo, tipo string) string {
+	switch codigo {
+	case "PO", "PAR":
+		return StatusPosted
+	case "OEC":
+		return StatusOutForDelivery
+	case "BDE", "BDI", "BDR":
+		// Tipo 01 é a entrega ao destinatário; os demais são tentativas sem sucesso ou devoluções
+		if tipo == "01" {
+			return StatusDelivered
+		}
+		return StatusFailed
+	default:
+		return StatusInTransit
+	}
+}
@@ -0,0 +1,47 @@
+package carrier
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fake é uma transportadora em memória para desenvolvimento local e testes.
+// Os eventos são cadastrados com Add e devolvidos por Track.
+type Fake struct {
+	mu     sync.Mutex
+	events map[string][]Event
+}
+
+func NewFake() *Fake {
+	return &Fake{events: make(map[string][]Event)}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+// Add acrescenta eventos ao objeto informado
+func (f *Fake) Add(trackingNumber string, events ...Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.ToUpper(trackingNumber)
+	f.events[key] = append(f.events[key], events...)
+	sort.SliceStable(f.events[key], func(i, j int) bool {
+		return f.events[key][i].OccurredAt.Before(f.events[key][j].OccurredAt)
+	})
+}
+
+func (f *Fake) Track(ctx context.Context, trackingNumber string) ([]Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events, ok := f.events[strings.ToUpper(trackingNumber)]
+	if !ok {
+		return nil, ErrTrackingNotFound
+	}
+
+	return append([]Event(nil), events...), nil
+}
@@ -0,0 +1,364 @@
+// Package cnab gera arquivos de remessa de pagamento no layout CNAB 240 (FEBRABAN),
+// com um lote de crédito em conta (segmentos A e B por pagamento).
+package cnab
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Account representa uma conta bancária com o titular
+type Account struct {
+	Name        string
+	Document    string // CPF ou CNPJ, somente dígitos
+	BankCode    string
+	Agency      string
+	AgencyDigit string
+	Number      string
+	NumberDigit string
+	Agreement   string // Convênio da empresa com o banco (somente pagador)
+	Address     string
+	City        string
+	Zipcode     string
+	State       string
+}
+
+// Payment representa um crédito para um favorecido
+type Payment struct {
+	Reference string // "Seu número": identificação do pagamento na empresa
+	Payee     Account
+	Amount    int64 // Em centavos
+	Date      time.Time
+}
+
+// Códigos do lote de pagamento a fornecedores
+const (
+	serviceSupplierPayment = "20"
+	methodCurrentAccount   = "01" // Crédito em conta corrente (mesmo banco)
+	methodTED              = "41" // TED para outro banco
+	chamberTED             = "018"
+	chamberSameBank        = "000"
+	layoutFile             = "089"
+	layoutBatch            = "045"
+	recordLength           = 240
+)
+
+// Write escreve a remessa com um lote por forma de lançamento. sequence é o número sequencial do arquivo (NSA).
+func Write(w io.Writer, payer Account, payments []Payment, generatedAt time.Time, sequence int) error {
+	bw := bufio.NewWriter(w)
+	records := 0
+
+	emit := func(line string) error {
+		if len(line) != recordLength {
+			return fmt.Errorf("cnab: record %d has %d positions", records+1, len(line))
+		}
+		records++
+		_, err := bw.WriteString(line + "\r\n")
+		return err
+	}
+
+	if err := emit(fileHeader(payer, generatedAt, sequence)); err != nil {
+		return err
+	}
+
+	// Crédito em conta no mesmo banco e TED para os demais ficam em lotes separados
+	batches := map[string][]Payment{}
+	var methods []string
+	for _, payment := range payments {
+		method := methodTED
+		if payment.Payee.BankCode == payer.BankCode {
+			method = methodCurrentAccount
+		}
+		if _, ok := batches[method]; !ok {
+			methods = append(methods, method)
+		}
+		batches[method] = append(batches[method], payment)
+	}
+
+	for i, method := range methods {
+		batch := i + 1
+		if err := emit(batchHeader(payer, batch, method)); err != nil {
+			return err
+		}
+
+		var total int64
+		sequence := 0
+		for _, payment := range batches[method] {
+			amount := payment.Amount
+			total += amount
+
+			sequence++
+			if err := emit(segmentA(payer, batch, sequence, method, payment, amount)); err != nil {
+				return err
+			}
+			sequence++
+			if err := emit(segmentB(payer, batch, sequence, payment, amount)); err != nil {
+				return err
+			}
+		}
+
+		// Registros do lote: header, detalhes e trailer
+		if err := emit(batchTrailer(payer, batch, sequence+2, total)); err != nil {
+			return err
+		}
+	}
+
+	if err := emit(fileTrailer(payer, len(methods), records+1)); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+func fileHeader(payer Account, generatedAt time.Time, sequence int) string {
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num("0000", 4)
+	b.num("0", 1)
+	b.blank(9)
+	b.num(documentType(payer.Document), 1)
+	b.num(payer.Document, 14)
+	b.alpha(payer.Agreement, 20)
+	b.num(payer.Agency, 5)
+	b.alpha(payer.AgencyDigit, 1)
+	b.num(payer.Number, 12)
+	b.alpha(payer.NumberDigit, 1)
+	b.blank(1)
+	b.alpha(payer.Name, 30)
+	b.alpha("", 30) // Nome do banco
+	b.blank(10)
+	b.num("1", 1) // Remessa
+	b.num(generatedAt.Format("02012006"), 8)
+	b.num(generatedAt.Format("150405"), 6)
+	b.num(fmt.Sprint(sequence), 6)
+	b.num(layoutFile, 3)
+	b.num("01600", 5)
+	b.blank(20)
+	b.blank(20)
+	b.blank(29)
+	return b.String()
+}
+
+func batchHeader(payer Account, batch int, method string) string {
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num(fmt.Sprint(batch), 4)
+	b.num("1", 1)
+	b.alpha("C", 1)
+	b.num(serviceSupplierPayment, 2)
+	b.num(method, 2)
+	b.num(layoutBatch, 3)
+	b.blank(1)
+	b.num(documentType(payer.Document), 1)
+	b.num(payer.Document, 14)
+	b.alpha(payer.Agreement, 20)
+	b.num(payer.Agency, 5)
+	b.alpha(payer.AgencyDigit, 1)
+	b.num(payer.Number, 12)
+	b.alpha(payer.NumberDigit, 1)
+	b.blank(1)
+	b.alpha(payer.Name, 30)
+	b.blank(40) // Mensagem
+	b.alpha(payer.Address, 30)
+	b.num("", 5)
+	b.blank(15)
+	b.alpha(payer.City, 20)
+	zipcode := digits(payer.Zipcode)
+	b.num(prefix(zipcode, 5), 5)
+	b.num(suffix(zipcode, 3), 3)
+	b.alpha(payer.State, 2)
+	b.blank(2)
+	b.blank(6)
+	b.blank(10)
+	return b.String()
+}
+
+func segmentA(payer Account, batch, sequence int, method string, payment Payment, amount int64) string {
+	chamber := chamberTED
+	if method == methodCurrentAccount {
+		chamber = chamberSameBank
+	}
+
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num(fmt.Sprint(batch), 4)
+	b.num("3", 1)
+	b.num(fmt.Sprint(sequence), 5)
+	b.alpha("A", 1)
+	b.num("0", 1)  // Inclusão
+	b.num("00", 2) // Instrução
+	b.num(chamber, 3)
+	b.num(payment.Payee.BankCode, 3)
+	b.num(payment.Payee.Agency, 5)
+	b.alpha(payment.Payee.AgencyDigit, 1)
+	b.num(payment.Payee.Number, 12)
+	b.alpha(payment.Payee.NumberDigit, 1)
+	b.blank(1)
+	b.alpha(payment.Payee.Name, 30)
+	b.alpha(payment.Reference, 20)
+	b.num(payment.Date.Format("02012006"), 8)
+	b.alpha("BRL", 3)
+	b.num("", 15)
+	b.num(fmt.Sprint(amount), 15)
+	b.blank(20) // Nosso número
+	b.num("", 8)
+	b.num("", 15)
+	b.blank(40)
+	b.blank(2)
+	b.alpha("00010", 5) // Finalidade TED: crédito em conta
+	b.blank(2)
+	b.blank(3)
+	b.num("0", 1)
+	b.blank(10)
+	return b.String()
+}
+
+func segmentB(payer Account, batch, sequence int, payment Payment, amount int64) string {
+	zipcode := digits(payment.Payee.Zipcode)
+
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num(fmt.Sprint(batch), 4)
+	b.num("3", 1)
+	b.num(fmt.Sprint(sequence), 5)
+	b.alpha("B", 1)
+	b.blank(3)
+	b.num(documentType(payment.Payee.Document), 1)
+	b.num(payment.Payee.Document, 14)
+	b.alpha(payment.Payee.Address, 30)
+	b.num("", 5)
+	b.blank(15)
+	b.blank(15) // Bairro
+	b.alpha(payment.Payee.City, 20)
+	b.num(prefix(zipcode, 5), 5)
+	b.num(suffix(zipcode, 3), 3)
+	b.alpha(payment.Payee.State, 2)
+	b.num(payment.Date.Format("02012006"), 8)
+	b.num(fmt.Sprint(amount), 15)
+	b.num("", 15)
+	b.num("", 15)
+	b.num("", 15)
+	b.num("", 15)
+	b.blank(15)
+	b.num("0", 1)
+	b.blank(6)
+	b.blank(8)
+	return b.String()
+}
+
+func batchTrailer(payer Account, batch, records int, total int64) string {
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num(fmt.Sprint(batch), 4)
+	b.num("5", 1)
+	b.blank(9)
+	b.num(fmt.Sprint(records), 6)
+	b.num(fmt.Sprint(total), 18)
+	b.num("", 18)
+	b.num("", 6)
+	b.blank(165)
+	b.blank(10)
+	return b.String()
+}
+
+func fileTrailer(payer Account, batches, records int) string {
+	var b record
+	b.num(payer.BankCode, 3)
+	b.num("9999", 4)
+	b.num("9", 1)
+	b.blank(9)
+	b.num(fmt.Sprint(batches), 6)
+	b.num(fmt.Sprint(records), 6)
+	b.num("", 6)
+	b.blank(205)
+	return b.String()
+}
+
+// record monta um registro de posições fixas
+type record struct {
+	strings.Builder
+}
+
+// num escreve um campo numérico: somente dígitos, alinhado à direita com zeros
+func (r *record) num(value string, size int) {
+	value = digits(value)
+	if len(value) > size {
+		value = value[len(value)-size:]
+	}
+	r.WriteString(strings.Repeat("0", size-len(value)) + value)
+}
+
+// alpha escreve um campo alfanumérico: maiúsculo, sem acentos, alinhado à esquerda com brancos
+func (r *record) alpha(value string, size int) {
+	value = strings.ToUpper(ascii(value))
+	if len(value) > size {
+		value = value[:size]
+	}
+	r.WriteString(value + strings.Repeat(" ", size-len(value)))
+}
+
+func (r *record) blank(size int) {
+	r.WriteString(strings.Repeat(" ", size))
+}
+
+func documentType(document string) string {
+	if len(digits(document)) > 11 {
+		return "2" // CNPJ
+	}
+	return "1" // CPF
+}
+
+func digits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Letras acentuadas usadas em português e seus equivalentes sem acento
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// ascii remove acentos e troca por branco os caracteres fora do ASCII imprimível
+func ascii(value string) string {
+	var b strings.Builder
+	for _, r := range accents.Replace(value) {
+		if r < 32 || r > 126 {
+			r = ' '
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func prefix(value string, size int) string {
+	if len(value) > size {
+		return value[:size]
+	}
+	return value
+}
+
+func suffix(value string, size int) string {
+	if len(value) > size {
+		return value[len(value)-size:]
+	}
+	return ""
+}
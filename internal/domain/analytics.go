@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// ProblemAnalyticsFilter delimita os tickets analisados (abertos em [From, To))
+type ProblemAnalyticsFilter struct {
+	From         time.Time
+	To           time.Time
+	ClientID     *int
+	FollowUpDays int // Janela para considerar um novo ticket na mesma agência e problema como retorno
+}
+
+// ProblemAnalytics mostra a ocorrência de um problema do catálogo e como ele foi resolvido
+type ProblemAnalytics struct {
+	ProblemID     int                    `json:"problem_id"`
+	ProblemName   string                 `json:"problem_name"`
+	Occurrences   int                    `json:"occurrences"` // Tickets com o problema
+	ClosedTickets int                    `json:"closed_tickets"`
+	FollowUps     int                    `json:"follow_ups"`     // Fechados seguidos de outro ticket
+	FollowUpRate  *float64               `json:"follow_up_rate"` // % dos fechados
+	ByClient      []ProblemClientCount   `json:"by_client"`
+	ByRegion      []ProblemRegionCount   `json:"by_region"`
+	Solutions     []ProblemSolutionUsage `json:"solutions"`
+}
+
+// ProblemClientCount conta as ocorrências do problema nas agências de um cliente
+type ProblemClientCount struct {
+	ClientName string `json:"client_name"`
+	Tickets    int    `json:"tickets"`
+}
+
+// ProblemRegionCount conta as ocorrências do problema por estado/cidade da agência
+type ProblemRegionCount struct {
+	State   string `json:"state"`
+	City    string `json:"city"`
+	Tickets int    `json:"tickets"`
+}
+
+// ProblemSolutionUsage resume as aplicações de uma solução para o problema
+type ProblemSolutionUsage struct {
+	SolutionID   int      `json:"solution_id"`
+	SolutionName string   `json:"solution_name"`
+	Applications int      `json:"applications"` // Linhas de custo
+	Tickets      int      `json:"tickets"`
+	AvgCost      Money    `json:"avg_cost"` // Média dos subtotais por aplicação
+	FollowUps    int      `json:"follow_ups"`
+	FollowUpRate *float64 `json:"follow_up_rate"` // % dos tickets com a solução que voltaram
+}
+
+// UnusedSolution é uma solução do catálogo que nunca foi aplicada em um ticket
+type UnusedSolution struct {
+	SolutionID   int       `json:"solution_id"`
+	SolutionName string    `json:"solution_name"`
+	ProblemID    int       `json:"problem_id"`
+	ProblemName  string    `json:"problem_name"`
+	UnitPrice    Money     `json:"unit_price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Calculate preenche as taxas de retorno a partir das contagens
+func (p *ProblemAnalytics) Calculate() {
+	p.FollowUpRate = percentOf(p.FollowUps, p.ClosedTickets)
+	for i := range p.Solutions {
+		solution := &p.Solutions[i]
+		solution.FollowUpRate = percentOf(solution.FollowUps, solution.Tickets)
+	}
+}
+
+// percentOf retorna part/total em %, ou nil sem base de cálculo
+func percentOf(part, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	percent := float64(part) * 100 / float64(total)
+	return &percent
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Asset representa um equipamento instalado em uma agência (câmera, nobreak, gerador...)
+type Asset struct {
+	ID             int        `json:"id" db:"id"`
+	BranchID       int        `json:"branch_id" db:"branch_id"`
+	Type           string     `json:"type" db:"type"`
+	Model          string     `json:"model" db:"model"`
+	SerialNumber   string     `json:"serial_number" db:"serial_number"`
+	InstalledAt    *time.Time `json:"installed_at,omitempty" db:"installed_at"`
+	WarrantyEndsAt *time.Time `json:"warranty_ends_at,omitempty" db:"warranty_ends_at"`
+	Notes          string     `json:"notes" db:"notes"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	BranchName string `json:"branch_name" db:"branch_name"`
+}
+
+// UnderWarranty indica se a garantia do fabricante cobre a data informada
+func (a *Asset) UnderWarranty(at time.Time) bool {
+	return a.WarrantyEndsAt != nil && at.Before(a.WarrantyEndsAt.AddDate(0, 0, 1))
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssetUnderWarrantyIncludesLastDay(t *testing.T) {
+	endsAt := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		endsAt  *time.Time
+		at      time.Time
+		covered bool
+	}{
+		{"no warranty", nil, time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC), false},
+		{"before the end", &endsAt, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), true},
+		// A garantia vale até o fim do último dia
+		{"last day late", &endsAt, time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC), true},
+		{"day after", &endsAt, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := &Asset{WarrantyEndsAt: tt.endsAt}
+			if got := asset.UnderWarranty(tt.at); got != tt.covered {
+				t.Fatalf("UnderWarranty(%v) = %v, want %v", tt.at, got, tt.covered)
+			}
+		})
+	}
+}
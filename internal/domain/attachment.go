@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Attachment representa um arquivo anexado a um ticket
+type Attachment struct {
+	ID          int       `json:"id" db:"id"`
+	TicketID    int       `json:"ticket_id" db:"ticket_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	Path        string    `json:"-" db:"path"`
+	Public      bool      `json:"public" db:"public"` // Visível ao cliente no portal
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -1,14 +1,49 @@
 package domain
 
+import "time"
+
 type Branch struct {
-	ID           int    `json:"id"`
-	Client       string `json:"client"`
-	Name         string `json:"name"`
-	Uniorg       string `json:"uniorg"`
-	Zipcode      string `json:"zipcode"`
-	State        string `json:"state"`
-	City         string `json:"city"`
-	Neighborhood string `json:"neighborhood"`
-	Address      string `json:"address"`
-	Complement   string `json:"complement"`
+	ID           int      `json:"id"`
+	ClientID     int      `json:"client_id"`
+	Client       string   `json:"client"` // Nome do cliente, carregado pelo client_id
+	Name         string   `json:"name"`
+	Uniorg       string   `json:"uniorg"`
+	Zipcode      string   `json:"zipcode"`
+	State        string   `json:"state"`
+	City         string   `json:"city"`
+	Neighborhood string   `json:"neighborhood"`
+	Address      string   `json:"address"`
+	Complement   string   `json:"complement"`
+	EmailDomain  string   `json:"email_domain"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	OpensAt      string   `json:"opens_at"`
+	ClosesAt     string   `json:"closes_at"`
+	Version      int      `json:"version"` // Incrementada a cada alteração (ETag)
+}
+
+// WithinOpeningHours verifica se o intervalo cabe no horário de funcionamento da agência
+// (OpensAt/ClosesAt no formato "15:04", no fuso loc). Agências sem horário cadastrado aceitam qualquer intervalo.
+func (b *Branch) WithinOpeningHours(start, end time.Time, loc *time.Location) bool {
+	if b.OpensAt == "" || b.ClosesAt == "" {
+		return true
+	}
+
+	opens, err := time.Parse("15:04", b.OpensAt)
+	if err != nil {
+		return true
+	}
+	closes, err := time.Parse("15:04", b.ClosesAt)
+	if err != nil {
+		return true
+	}
+
+	// Datas chegam com o fuso de quem agendou: comparar no horário local da agência
+	start, end = start.In(loc), end.In(loc)
+	if start.Year() != end.Year() || start.YearDay() != end.YearDay() {
+		return false
+	}
+
+	minuteOfDay := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	return minuteOfDay(start) >= minuteOfDay(opens) && minuteOfDay(end) <= minuteOfDay(closes)
 }
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithinOpeningHoursComparesInCompanyTimezone(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch := &Branch{OpensAt: "09:00", ClosesAt: "17:00"}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		// 12:00-13:00 UTC = 09:00-10:00 em São Paulo
+		{"utc inside hours", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC), true},
+		// 09:00-10:00 UTC = 06:00-07:00 em São Paulo
+		{"utc before opening", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), false},
+		{"local inside hours", time.Date(2026, 10, 19, 16, 0, 0, 0, saoPaulo), time.Date(2026, 10, 19, 17, 0, 0, 0, saoPaulo), true},
+		// 22:00-23:00 UTC = 19:00-20:00 em São Paulo
+		{"utc after closing", time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC), false},
+		// 02:00-03:00 UTC do dia 20 = 23:00-00:00, atravessa o dia em São Paulo
+		{"crosses local midnight", time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC), time.Date(2026, 10, 20, 3, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		if got := branch.WithinOpeningHours(tt.start, tt.end, saoPaulo); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !(&Branch{}).WithinOpeningHours(tests[1].start, tests[1].end, saoPaulo) {
+		t.Errorf("branch without opening hours should accept any period")
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// TicketCheckin representa a presença do técnico na agência (check-in e check-out com GPS)
+type TicketCheckin struct {
+	ID                     int        `json:"id" db:"id"`
+	TicketID               int        `json:"ticket_id" db:"ticket_id"`
+	ProviderID             int        `json:"provider_id" db:"provider_id"`
+	CheckinAt              time.Time  `json:"checkin_at" db:"checkin_at"`
+	CheckinLatitude        float64    `json:"checkin_latitude" db:"checkin_latitude"`
+	CheckinLongitude       float64    `json:"checkin_longitude" db:"checkin_longitude"`
+	CheckinDistanceMeters  *float64   `json:"checkin_distance_meters,omitempty" db:"checkin_distance_meters"`
+	CheckoutAt             *time.Time `json:"checkout_at,omitempty" db:"checkout_at"`
+	CheckoutLatitude       *float64   `json:"checkout_latitude,omitempty" db:"checkout_latitude"`
+	CheckoutLongitude      *float64   `json:"checkout_longitude,omitempty" db:"checkout_longitude"`
+	CheckoutDistanceMeters *float64   `json:"checkout_distance_meters,omitempty" db:"checkout_distance_meters"`
+	ReportedKm             *float64   `json:"reported_km,omitempty" db:"reported_km"`
+	Anomalies              []string   `json:"anomalies" db:"anomalies"`
+	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OnSiteMinutes retorna o tempo em campo entre check-in e check-out (0 se ainda aberto)
+func (c *TicketCheckin) OnSiteMinutes() int {
+	if c.CheckoutAt == nil {
+		return 0
+	}
+	return int(c.CheckoutAt.Sub(c.CheckinAt).Minutes())
+}
+
+// ANOMALIES
+const (
+	AnomalyBranchNotGeocoded     = "branch_not_geocoded"
+	AnomalyCheckinOutOfRadius    = "checkin_out_of_radius"
+	AnomalyCheckoutOutOfRadius   = "checkout_out_of_radius"
+	AnomalyDistanceNotRegistered = "distance_not_registered"
+	AnomalyRouteMismatch         = "route_mismatch"
+	AnomalyRouteProviderMismatch = "route_provider_mismatch"
+)
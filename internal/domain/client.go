@@ -1,6 +1,7 @@
 package domain
 
 type Client struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Document string `json:"document"` // CNPJ do tomador na nota fiscal
 }
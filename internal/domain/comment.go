@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// TicketComment representa um comentário registrado em um ticket
+type TicketComment struct {
+	ID        int       `json:"id" db:"id"`
+	TicketID  int       `json:"ticket_id" db:"ticket_id"`
+	Author    string    `json:"author" db:"author"`
+	Body      string    `json:"body" db:"body"`
+	Source    string    `json:"source" db:"source"`
+	Public    bool      `json:"public" db:"public"` // Visível ao cliente no portal
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SOURCE
+// api   - registrado pela API
+// email - recebido por email (resposta a um ticket existente)
+const (
+	CommentSourceAPI   = "api"
+	CommentSourceEmail = "email"
+)
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Contract representa o contrato de um cliente com preços negociados
+type Contract struct {
+	ID           int        `json:"id" db:"id"`
+	ClientID     int        `json:"client_id" db:"client_id"`
+	Name         string     `json:"name" db:"name"`
+	StartsOn     time.Time  `json:"starts_on" db:"starts_on"`
+	EndsOn       *time.Time `json:"ends_on,omitempty" db:"ends_on"`             // NULL = sem data de término
+	ValuePerKm   *Money     `json:"value_per_km,omitempty" db:"value_per_km"`   // NULL = tabela de custos padrão
+	InitialValue *Money     `json:"initial_value,omitempty" db:"initial_value"` // NULL = tabela de custos padrão
+	ShowCosts    bool       `json:"show_costs" db:"show_costs"`                 // Exibe os custos no portal do cliente
+	RequireQuote bool       `json:"require_quote" db:"require_quote"`           // Exige orçamento aprovado antes do atendimento
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	ClientName string          `json:"client_name" db:"client_name"`
+	Prices     []ContractPrice `json:"prices"`
+}
+
+// ContractPrice representa o preço negociado de uma solution no contrato
+type ContractPrice struct {
+	ID           int    `json:"id" db:"id"`
+	ContractID   int    `json:"contract_id" db:"contract_id"`
+	SolutionID   int    `json:"solution_id" db:"solution_id"`
+	SolutionName string `json:"solution_name" db:"solution_name"`
+	UnitPrice    Money  `json:"unit_price" db:"unit_price"`
+}
+
+// Overlaps indica se as vigências dos dois contratos se sobrepõem
+func (c *Contract) Overlaps(other *Contract) bool {
+	if c.EndsOn != nil && c.EndsOn.Before(other.StartsOn) {
+		return false
+	}
+	if other.EndsOn != nil && other.EndsOn.Before(c.StartsOn) {
+		return false
+	}
+	return true
+}
+
+// SolutionPrice retorna o preço negociado da solution, se houver
+func (c *Contract) SolutionPrice(solutionID int) (Money, bool) {
+	for _, price := range c.Prices {
+		if price.SolutionID == solutionID {
+			return price.UnitPrice, true
+		}
+	}
+	return 0, false
+}
+
+// ApplyTo retorna a tabela de custos com os valores de deslocamento do contrato
+func (c *Contract) ApplyTo(cost *Cost) *Cost {
+	applied := *cost
+	if c.ValuePerKm != nil {
+		applied.ValuePerKm = *c.ValuePerKm
+	}
+	if c.InitialValue != nil {
+		applied.InitialValue = *c.InitialValue
+	}
+	return &applied
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContractOverlaps(t *testing.T) {
+	day := func(year, month, d int) *time.Time {
+		date := time.Date(year, time.Month(month), d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	current := &Contract{StartsOn: *day(2026, 1, 1), EndsOn: day(2026, 6, 30)}
+
+	tests := []struct {
+		name     string
+		startsOn *time.Time
+		endsOn   *time.Time
+		want     bool
+	}{
+		{"after the end", day(2026, 7, 1), nil, false},
+		{"before the start", day(2025, 1, 1), day(2025, 12, 31), false},
+		// As datas de início e término fazem parte da vigência
+		{"starts on the last day", day(2026, 6, 30), nil, true},
+		{"ends on the first day", day(2025, 1, 1), day(2026, 1, 1), true},
+		{"open ended before", day(2025, 1, 1), nil, true},
+		{"inside", day(2026, 3, 1), day(2026, 3, 31), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := &Contract{StartsOn: *tt.startsOn, EndsOn: tt.endsOn}
+			if got := current.Overlaps(other); got != tt.want {
+				t.Fatalf("Overlaps = %v, want %v", got, tt.want)
+			}
+			// A sobreposição é simétrica
+			if got := other.Overlaps(current); got != tt.want {
+				t.Fatalf("reverse Overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContractApplyToOverridesTravelValues(t *testing.T) {
+	money := func(v float64) *Money {
+		m := NewMoney(v)
+		return &m
+	}
+	table := &Cost{ValuePerKm: NewMoney(1.5), InitialValue: NewMoney(40)}
+
+	tests := []struct {
+		name         string
+		valuePerKm   *Money
+		initialValue *Money
+		want         Cost
+	}{
+		{"default table", nil, nil, Cost{ValuePerKm: NewMoney(1.5), InitialValue: NewMoney(40)}},
+		{"per km only", money(1.2), nil, Cost{ValuePerKm: NewMoney(1.2), InitialValue: NewMoney(40)}},
+		{"both values", money(1.2), money(0), Cost{ValuePerKm: NewMoney(1.2), InitialValue: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := &Contract{ValuePerKm: tt.valuePerKm, InitialValue: tt.initialValue}
+			got := contract.ApplyTo(table)
+			if *got != tt.want {
+				t.Fatalf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	// A tabela vigente não é alterada
+	if table.ValuePerKm != NewMoney(1.5) || table.InitialValue != NewMoney(40) {
+		t.Fatalf("cost table changed: %+v", *table)
+	}
+}
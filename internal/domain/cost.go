@@ -4,8 +4,14 @@ import "time"
 
 type Cost struct {
 	ID           int       `json:"id" db:"id"`
-	ValuePerKm   float64   `json:"value_per_km" db:"value_per_km"`
-	InitialValue float64   `json:"initial_value" db:"initial_value"`
+	ValuePerKm   Money     `json:"value_per_km" db:"value_per_km"`
+	InitialValue Money     `json:"initial_value" db:"initial_value"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// TravelCost calcula o custo de deslocamento para a distância informada
+// (o valor por km é arredondado para o centavo uma única vez)
+func (c *Cost) TravelCost(distanceKm float64) Money {
+	return c.InitialValue + c.ValuePerKm.Times(distanceKm)
+}
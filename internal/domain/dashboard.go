@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// TicketStatusLabels traz o nome exibido de cada status de ticket
+var TicketStatusLabels = map[int]string{
+	TicketStatusAgendado:            "Agendado",
+	TicketStatusAguardaAtendimento:  "Aguarda Atendimento",
+	TicketStatusAguardaFaturamento:  "Aguarda Faturamento",
+	TicketStatusAguardaFinalizacao:  "Aguarda Finalização",
+	TicketStatusCompras:             "Compras",
+	TicketStatusConcluido:           "Concluído",
+	TicketStatusEmAtendimento:       "Em Atendimento",
+	TicketStatusEnviado:             "Enviado",
+	TicketStatusEquipamentoEntregue: "Equipamento Entregue",
+	TicketStatusEstoque:             "Estoque",
+	TicketStatusLogisticaReversa:    "Logística Reversa",
+	TicketStatusNovo:                "Novo",
+	TicketStatusPrestacaoDeContas:   "Prestação de Contas",
+	TicketStatusContasNaoAprovadas:  "Contas Não Aprovadas",
+	TicketStatusEmitirNota:          "Emitir Nota",
+}
+
+// DashboardFilter delimita os tickets considerados no painel (abertos em [From, To))
+type DashboardFilter struct {
+	From     time.Time
+	To       time.Time
+	ClientID *int
+}
+
+// Dashboard reúne os indicadores operacionais do período
+type Dashboard struct {
+	From            time.Time               `json:"from"`
+	To              time.Time               `json:"to"`
+	ClientID        *int                    `json:"client_id,omitempty"`
+	TotalTickets    int                     `json:"total_tickets"`
+	OpenTickets     int                     `json:"open_tickets"` // Tickets ainda não concluídos
+	AvgHoursToClose *float64                `json:"avg_hours_to_close"`
+	ByStatus        []DashboardStatusCount  `json:"by_status"`
+	OpenByClient    []DashboardClientCount  `json:"open_by_client"`
+	MTTRByPriority  []DashboardPriorityMTTR `json:"mttr_by_priority"`
+	CostByBranch    []DashboardBranchCost   `json:"cost_by_branch"`
+	TopProblems     []DashboardProblemCount `json:"top_problems"`
+}
+
+// DashboardStatusCount conta os tickets em um status (todos os 15 aparecem, mesmo zerados)
+type DashboardStatusCount struct {
+	Status int    `json:"status"`
+	Label  string `json:"label"`
+	Count  int    `json:"count"`
+}
+
+// DashboardClientCount conta os tickets em aberto de um cliente
+type DashboardClientCount struct {
+	ClientID   *int   `json:"client_id"` // Nulo quando a agência aponta para um cliente não cadastrado
+	ClientName string `json:"client_name"`
+	Open       int    `json:"open"`
+}
+
+// DashboardPriorityMTTR é o tempo médio até o fechamento dos tickets de uma prioridade
+type DashboardPriorityMTTR struct {
+	Priority      string  `json:"priority"`
+	ClosedTickets int     `json:"closed_tickets"`
+	AvgHours      float64 `json:"avg_hours"`
+}
+
+// DashboardBranchCost soma os custos e o deslocamento dos tickets de uma agência
+type DashboardBranchCost struct {
+	BranchID   int     `json:"branch_id"`
+	BranchName string  `json:"branch_name"`
+	ClientName string  `json:"client_name"`
+	Tickets    int     `json:"tickets"`
+	Cost       Money   `json:"cost"`        // Soma dos subtotais em ticket_costs
+	DistanceKm float64 `json:"distance_km"` // Soma das distâncias registradas
+}
+
+// DashboardProblemCount conta os tickets em que um problema foi associado
+type DashboardProblemCount struct {
+	ProblemID   int    `json:"problem_id"`
+	ProblemName string `json:"problem_name"`
+	Tickets     int    `json:"tickets"`
+}
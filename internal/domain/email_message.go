@@ -0,0 +1,9 @@
+package domain
+
+// EmailMessage registra um email já ingerido pelo Message-ID e quantos dos seus anexos
+// (na ordem da mensagem) foram gravados no ticket
+type EmailMessage struct {
+	MessageID        string `json:"message_id" db:"message_id"`
+	TicketID         int    `json:"ticket_id" db:"ticket_id"`
+	AttachmentsSaved int    `json:"attachments_saved" db:"attachments_saved"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// Expense representa uma prestação de contas do técnico em um ticket
+type Expense struct {
+	ID           int           `json:"id" db:"id"`
+	TicketID     int           `json:"ticket_id" db:"ticket_id"`
+	ProviderID   int           `json:"provider_id" db:"provider_id"`
+	Status       string        `json:"status" db:"status"`
+	Notes        string        `json:"notes" db:"notes"`
+	DecidedBy    *int          `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt    *time.Time    `json:"decided_at,omitempty" db:"decided_at"`
+	DecisionNote string        `json:"decision_note" db:"decision_note"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+	Items        []ExpenseItem `json:"items"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	TicketNumber string `json:"ticket_number" db:"ticket_number"`
+	ProviderName string `json:"provider_name" db:"provider_name"`
+}
+
+// Total retorna o valor apresentado na prestação de contas
+func (e *Expense) Total() Money {
+	var total Money
+	for _, item := range e.Items {
+		total += item.Subtotal()
+	}
+	return total
+}
+
+// ApprovedTotal retorna o valor aprovado para reembolso
+func (e *Expense) ApprovedTotal() Money {
+	var total Money
+	for _, item := range e.Items {
+		if item.Status == ExpenseApproved {
+			total += item.Subtotal()
+		}
+	}
+	return total
+}
+
+// ExpenseItem representa uma despesa apresentada, com o comprovante anexado ao ticket
+type ExpenseItem struct {
+	ID              int    `json:"id" db:"id"`
+	ExpenseID       int    `json:"expense_id" db:"expense_id"`
+	Description     string `json:"description" db:"description"`
+	Quantity        int    `json:"quantity" db:"quantity"`
+	UnitPrice       Money  `json:"unit_price" db:"unit_price"`
+	AttachmentID    *int   `json:"attachment_id,omitempty" db:"attachment_id"`
+	Status          string `json:"status" db:"status"`
+	RejectionReason string `json:"rejection_reason" db:"rejection_reason"`
+}
+
+// Subtotal retorna quantidade vezes valor unitário
+func (i *ExpenseItem) Subtotal() Money {
+	return i.UnitPrice.Mul(i.Quantity)
+}
+
+// EXPENSE STATUS
+// Vale para a prestação de contas e para cada item. A prestação é rejeitada
+// quando ao menos um item é rejeitado; os itens aprovados continuam reembolsáveis.
+const (
+	ExpensePending  = "pending"
+	ExpenseApproved = "approved"
+	ExpenseRejected = "rejected"
+)
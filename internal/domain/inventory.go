@@ -0,0 +1,108 @@
+package domain
+
+import "time"
+
+// InventoryItem representa uma peça controlada pelo estoque
+type InventoryItem struct {
+	ID          int       `json:"id" db:"id"`
+	SKU         string    `json:"sku" db:"sku"`
+	Name        string    `json:"name" db:"name"`
+	Unit        string    `json:"unit" db:"unit"`
+	MinQuantity int       `json:"min_quantity" db:"min_quantity"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StockLocation representa um local de estoque (almoxarifado central ou carro do técnico)
+type StockLocation struct {
+	ID         int       `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	Kind       string    `json:"kind" db:"kind"`
+	ProviderID *int      `json:"provider_id,omitempty" db:"provider_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// LOCATION KINDS
+const (
+	StockLocationWarehouse = "warehouse"
+	StockLocationVan       = "van"
+)
+
+// StockLevel representa o saldo de um item em um local
+type StockLevel struct {
+	ItemID       int    `json:"item_id" db:"item_id"`
+	LocationID   int    `json:"location_id" db:"location_id"`
+	LocationName string `json:"location_name" db:"location_name"`
+	Quantity     int    `json:"quantity" db:"quantity"`
+	Reserved     int    `json:"reserved" db:"reserved"`
+}
+
+// Available retorna o saldo livre (em mãos menos reservado)
+func (l *StockLevel) Available() int {
+	return l.Quantity - l.Reserved
+}
+
+// StockMovement representa uma movimentação de estoque
+type StockMovement struct {
+	ID           int       `json:"id" db:"id"`
+	ItemID       int       `json:"item_id" db:"item_id"`
+	LocationID   int       `json:"location_id" db:"location_id"`
+	LocationName string    `json:"location_name" db:"location_name"`
+	Kind         string    `json:"kind" db:"kind"`
+	Quantity     int       `json:"quantity" db:"quantity"`
+	TicketID     *int      `json:"ticket_id,omitempty" db:"ticket_id"`
+	Note         string    `json:"note" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// MOVEMENT KINDS
+// in/out/adjust alteram o saldo em mãos; reserve/release alteram o reservado;
+// consume baixa o reservado do saldo; transfer_out/transfer_in movem entre locais
+const (
+	MovementIn          = "in"
+	MovementOut         = "out"
+	MovementAdjust      = "adjust"
+	MovementTransferOut = "transfer_out"
+	MovementTransferIn  = "transfer_in"
+	MovementReserve     = "reserve"
+	MovementRelease     = "release"
+	MovementConsume     = "consume"
+)
+
+// SolutionPart representa um item da lista de materiais (BOM) de uma solution
+type SolutionPart struct {
+	SolutionID int    `json:"solution_id" db:"solution_id"`
+	ItemID     int    `json:"item_id" db:"item_id"`
+	ItemSKU    string `json:"item_sku" db:"item_sku"`
+	ItemName   string `json:"item_name" db:"item_name"`
+	Quantity   int    `json:"quantity" db:"quantity"`
+}
+
+// StockReservation representa peças reservadas para um ticket
+type StockReservation struct {
+	ID           int       `json:"id" db:"id"`
+	TicketID     int       `json:"ticket_id" db:"ticket_id"`
+	SolutionID   int       `json:"solution_id" db:"solution_id"`
+	ItemID       int       `json:"item_id" db:"item_id"`
+	ItemName     string    `json:"item_name" db:"item_name"`
+	LocationID   int       `json:"location_id" db:"location_id"`
+	LocationName string    `json:"location_name" db:"location_name"`
+	Quantity     int       `json:"quantity" db:"quantity"`
+	Status       string    `json:"status" db:"status"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// RESERVATION STATUS
+const (
+	ReservationReserved = "reserved"
+	ReservationConsumed = "consumed"
+	ReservationReleased = "released"
+)
+
+// LowStockItem representa um item com saldo livre abaixo do mínimo
+type LowStockItem struct {
+	Item      InventoryItem `json:"item"`
+	Quantity  int           `json:"quantity"`
+	Reserved  int           `json:"reserved"`
+	Available int           `json:"available"`
+}
@@ -0,0 +1,91 @@
+package domain
+
+import "time"
+
+// Invoice representa a fatura de um cliente no ciclo de faturamento, emitida como NFS-e
+type Invoice struct {
+	ID               int             `json:"id" db:"id"`
+	ClientID         int             `json:"client_id" db:"client_id"`
+	PeriodStart      time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd        time.Time       `json:"period_end" db:"period_end"`
+	Status           string          `json:"status" db:"status"`
+	Notes            string          `json:"notes" db:"notes"`
+	Issuer           string          `json:"issuer" db:"issuer"`                       // Emissor da NFS-e (ex: "fake")
+	Number           string          `json:"number" db:"number"`                       // Número da NFS-e
+	VerificationCode string          `json:"verification_code" db:"verification_code"` // Código de verificação da NFS-e
+	XML              string          `json:"-" db:"xml"`                               // XML devolvido pela prefeitura
+	IssuedAt         *time.Time      `json:"issued_at,omitempty" db:"issued_at"`
+	CancelledAt      *time.Time      `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CancelReason     string          `json:"cancel_reason" db:"cancel_reason"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
+	Tickets          []InvoiceTicket `json:"tickets"`
+	Lines            []InvoiceLine   `json:"lines"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	ClientName     string `json:"client_name" db:"client_name"`
+	ClientDocument string `json:"client_document" db:"client_document"`
+}
+
+// InvoiceTicket representa um ticket faturado
+type InvoiceTicket struct {
+	InvoiceID  int        `json:"invoice_id" db:"invoice_id"`
+	TicketID   int        `json:"ticket_id" db:"ticket_id"`
+	Number     string     `json:"number" db:"number"`
+	BranchName string     `json:"branch_name" db:"branch_name"`
+	CloseDate  *time.Time `json:"close_date,omitempty" db:"close_date"`
+}
+
+// InvoiceLine representa um item da fatura (serviço ou deslocamento)
+type InvoiceLine struct {
+	ID          int       `json:"id" db:"id"`
+	InvoiceID   int       `json:"invoice_id" db:"invoice_id"`
+	Kind        string    `json:"kind" db:"kind"`
+	TicketID    int       `json:"ticket_id" db:"ticket_id"`
+	VisitID     *int      `json:"visit_id,omitempty" db:"visit_id"`
+	Description string    `json:"description" db:"description"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	UnitAmount  Money     `json:"unit_amount" db:"unit_amount"`
+	Amount      Money     `json:"amount" db:"amount"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ServicesTotal retorna o valor dos serviços da fatura
+func (i *Invoice) ServicesTotal() Money {
+	return i.sum(InvoiceLineService)
+}
+
+// TravelTotal retorna o valor de deslocamento da fatura
+func (i *Invoice) TravelTotal() Money {
+	return i.sum(InvoiceLineTravel)
+}
+
+// Total retorna o valor da nota: serviços + deslocamento
+func (i *Invoice) Total() Money {
+	return i.ServicesTotal() + i.TravelTotal()
+}
+
+func (i *Invoice) sum(kind string) Money {
+	var total Money
+	for _, line := range i.Lines {
+		if line.Kind == kind {
+			total += line.Amount
+		}
+	}
+	return total
+}
+
+// INVOICE STATUS
+// Rascunho -> Emitindo -> Emitida -> Cancelada
+const (
+	InvoiceDraft     = "draft"
+	InvoiceIssuing   = "issuing" // NFS-e em transmissão: bloqueia uma segunda emissão
+	InvoiceIssued    = "issued"
+	InvoiceCancelled = "cancelled"
+)
+
+// INVOICE LINE KIND
+const (
+	InvoiceLineService = "service"
+	InvoiceLineTravel  = "travel"
+)
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney indica um valor monetário mal formatado
+var ErrInvalidMoney = errors.New("invalid money value")
+
+// Money representa um valor em centavos de real. Somas e multiplicações por quantidade
+// são exatas; o arredondamento acontece apenas nestes pontos, sempre para o centavo
+// mais próximo e com empate (meio centavo) afastando do zero:
+//   - ParseMoney/JSON/banco com mais de duas casas decimais ("10.005" -> 10.01);
+//   - NewMoney, na conversão de float64;
+//   - Times, ao multiplicar por um fator fracionário (ex: km rodados), uma vez por valor.
+type Money int64
+
+// NewMoney converte um float64 em centavos, arredondando conforme as regras de Money
+func NewMoney(value float64) Money {
+	return Money(math.Round(value * 100))
+}
+
+// ParseMoney lê um decimal sem passar por float64. Aceita ponto ("1234.5", "-0.07") ou,
+// no formato brasileiro, vírgula como separador decimal ("1234,5", "1.234,56").
+func ParseMoney(value string) (Money, error) {
+	s := strings.TrimSpace(value)
+	if strings.ContainsAny(s, "eE") {
+		// Notação científica só aparece em JSON gerado por máquina; float64 é suficiente
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+		}
+		return NewMoney(f), nil
+	}
+
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	separator := "."
+	if strings.Contains(s, ",") {
+		// Formato brasileiro: pontos só como separador de milhar
+		var ok bool
+		if s, ok = stripThousands(s); !ok {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+		}
+		separator = ","
+	}
+
+	whole, fraction, _ := strings.Cut(s, separator)
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) || len(whole) > 15 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+
+	var cents int64
+	for _, r := range whole {
+		cents = cents*10 + int64(r-'0')
+	}
+	for i := 0; i < 2; i++ {
+		cents *= 10
+		if i < len(fraction) {
+			cents += int64(fraction[i] - '0')
+		}
+	}
+	if len(fraction) > 2 && fraction[2] >= '5' {
+		cents++
+	}
+
+	if negative {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+// stripThousands remove os pontos de milhar de "1.234.567,89", que precisam separar
+// grupos de três dígitos
+func stripThousands(s string) (string, bool) {
+	whole, fraction, _ := strings.Cut(s, ",")
+	groups := strings.Split(whole, ".")
+	for i, group := range groups {
+		if i > 0 && len(group) != 3 || i == 0 && len(groups) > 1 && (group == "" || len(group) > 3) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, "") + "," + fraction, true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Cents retorna o valor em centavos
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 retorna o valor em reais, para cálculos que não são monetários (ex: médias)
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Mul multiplica pelo número de unidades (exato)
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Times multiplica por um fator fracionário, arredondando o resultado para o centavo.
+// O fator é tratado como o decimal que ele representa (12.345 km, e não o binário mais
+// próximo): em float64 um empate de meio centavo pode cair logo abaixo e arredondar para baixo.
+func (m Money) Times(factor float64) Money {
+	exact, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'g', -1, 64))
+	if !ok {
+		// NaN e infinito não têm decimal
+		return Money(math.Round(float64(m) * factor))
+	}
+	exact.Mul(exact, new(big.Rat).SetInt64(int64(m)))
+
+	// Divisão inteira e empate afastando do zero
+	num := new(big.Int).Abs(exact.Num())
+	quo, rem := new(big.Int).QuoRem(num, exact.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(exact.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if exact.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return Money(quo.Int64())
+}
+
+// String formata o valor com duas casas decimais e ponto ("1234.56")
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON grava o valor como número JSON com duas casas decimais
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON aceita número ou string JSON ("12.34" ou 12.34)
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan lê colunas DECIMAL (entregues como texto pelo driver) sem passar por float64
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = NewMoney(v)
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidMoney, src)
+	}
+}
+
+// Value grava o valor como decimal em texto, convertido pelo banco para DECIMAL
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// quickConfig usa semente fixa: uma falha se repete ao rodar o teste de novo
+func quickConfig() *quick.Config {
+	return &quick.Config{MaxCount: 2000, Rand: rand.New(rand.NewSource(1))}
+}
+
+// halfUp divide arredondando o empate para longe do zero, em aritmética inteira
+func halfUp(num, den int64) int64 {
+	if num < 0 {
+		return -halfUp(-num, den)
+	}
+	return (num + den/2) / den
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{"1234.56", 123456},
+		{"1234,56", 123456},
+		{"1.234,56", 123456},
+		{"1.234.567,89", 123456789},
+		{"0,5", 50},
+		{"0.5", 50},
+		{",5", 50},
+		{"7", 700},
+		{"+3", 300},
+		{" 7.1 ", 710},
+		{"-0.07", -7},
+		{"-1.234,5", -123450},
+		// Terceira casa decimal arredonda para o centavo mais próximo, empate afastando do zero
+		{"10.005", 1001},
+		{"10,005", 1001},
+		{"10.004", 1000},
+		{"-10.005", -1001},
+		{"0.994", 99},
+		{"0.995", 100},
+		{"1e2", 10000},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input)
+		if err != nil {
+			t.Errorf("ParseMoney(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d cents, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	inputs := []string{"", " ", ".", ",", "abc", "R$ 10", "1.2.3", "12,3,4", "1.23,45", "12.3456,7", ".123,45",
+		"1,2.3", "--1", "-", "1 000", "1e", "9999999999999999"}
+
+	for _, input := range inputs {
+		if got, err := ParseMoney(input); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q) = %d, %v; want ErrInvalidMoney", input, got, err)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	if got := Money(1999).Mul(3); got != 5997 {
+		t.Errorf("19.99 x 3 = %s, want 59.97", got)
+	}
+	if got := Money(-250).Mul(4); got != -1000 {
+		t.Errorf("-2.50 x 4 = %s, want -10.00", got)
+	}
+}
+
+func TestMoneyTimesRoundsHalfUp(t *testing.T) {
+	tests := []struct {
+		value  Money
+		factor float64
+		want   Money
+	}{
+		{101, 0.5, 51},      // 0.505 -> 0.51
+		{333, 1.5, 500},     // 4.995 -> 5.00
+		{5, 0.5, 3},         // 0.025 -> 0.03
+		{-5, 0.5, -3},       // empate afasta do zero
+		{110, 12.345, 1358}, // 1.10/km x 12.345 km = 13.5795 -> 13.58
+		{110, 12.34, 1357},  // 13.574 -> 13.57
+		{1000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.Times(tt.factor); got != tt.want {
+			t.Errorf("%s x %v = %s, want %s", tt.value, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneySumIsExact(t *testing.T) {
+	// Em float64, somar 0.10 mil vezes não dá 100.00
+	var total Money
+	var floatTotal float64
+	for i := 0; i < 1000; i++ {
+		line, err := ParseMoney("0.10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += line
+		floatTotal += 0.10
+	}
+	if total != 10000 || total.String() != "100.00" {
+		t.Errorf("1000 x 0.10 = %s, want 100.00", total)
+	}
+	if floatTotal == 100 {
+		t.Fatalf("float64 sum was expected to drift")
+	}
+
+	// Linhas com quantidade: 3 x 33.33 + 7 x 0.07 + 1 x 0.01 = 100.49
+	lines := []struct {
+		price    string
+		quantity int
+	}{
+		{"33.33", 3},
+		{"0,07", 7},
+		{"0.01", 1},
+	}
+	total = 0
+	for _, line := range lines {
+		price, err := ParseMoney(line.price)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += price.Mul(line.quantity)
+	}
+	if total.Cents() != 10049 {
+		t.Errorf("sum of lines = %s, want 100.49", total)
+	}
+}
+
+func TestMoneyTimesMatchesExactDecimal(t *testing.T) {
+	// Propriedade: valor x fator com três casas (km rodados) é o produto exato arredondado,
+	// e a soma dos subtotais é a soma centavo a centavo desses arredondamentos
+	property := func(lines []struct {
+		Cents int32
+		Km    uint32
+	}) bool {
+		var total, want Money
+		for _, line := range lines {
+			cents := int64(line.Cents) % 10000000
+			km := int64(line.Km % 1000000)
+			subtotal := Money(cents).Times(float64(km) / 1000)
+			if subtotal.Cents() != halfUp(cents*km, 1000) {
+				t.Logf("%s x %d/1000 = %s, want %d cents", Money(cents), km, subtotal, halfUp(cents*km, 1000))
+				return false
+			}
+			total += subtotal
+			want += Money(halfUp(cents*km, 1000))
+		}
+		return total == want
+	}
+	if err := quick.Check(property, quickConfig()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyParseStringRoundTrip(t *testing.T) {
+	// Propriedade: ParseMoney(m.String()) devolve o mesmo valor, dentro dos 15 dígitos inteiros
+	property := func(cents int64) bool {
+		m := Money(cents % 100000000000000000)
+		parsed, err := ParseMoney(m.String())
+		return err == nil && parsed == m
+	}
+	if err := quick.Check(property, quickConfig()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyParseRoundsHalfUp(t *testing.T) {
+	// Propriedade: a terceira casa decimal arredonda para cima a partir de 5, afastando do zero
+	property := func(cents int64, digit uint8) bool {
+		m := Money(cents % 100000000000000000)
+		d := int64(digit % 10)
+		parsed, err := ParseMoney(fmt.Sprintf("%s%d", m, d))
+		if err != nil {
+			return false
+		}
+
+		want := m.Cents()
+		if d >= 5 {
+			if want < 0 {
+				want--
+			} else {
+				want++
+			}
+		}
+		return parsed.Cents() == want
+	}
+	if err := quick.Check(property, quickConfig()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var values struct {
+		Number Money `json:"number"`
+		Text   Money `json:"text"`
+		Comma  Money `json:"comma"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 12.34, "text": "0.1", "comma": "1.234,5"}`), &values); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values.Number != 1234 || values.Text != 10 || values.Comma != 123450 {
+		t.Fatalf("got %d, %d, %d cents", values.Number, values.Text, values.Comma)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"number":12.34,"text":0.10,"comma":1234.50}` {
+		t.Fatalf("got %s", data)
+	}
+}
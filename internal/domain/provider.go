@@ -10,4 +10,5 @@ type Provider struct {
 	Neighborhood string `json:"neighborhood"`
 	Address      string `json:"address"`
 	Complement   string `json:"complement"`
+	Version      int    `json:"version"` // Incrementada a cada alteração (ETag)
 }
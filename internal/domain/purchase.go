@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Supplier representa um fornecedor de peças
+type Supplier struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Document  string    `json:"document" db:"document"`
+	Email     string    `json:"email" db:"email"`
+	Phone     string    `json:"phone" db:"phone"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PurchaseRequest representa uma compra de peças vinculada a um ticket
+type PurchaseRequest struct {
+	ID           int                   `json:"id" db:"id"`
+	TicketID     int                   `json:"ticket_id" db:"ticket_id"`
+	SupplierID   int                   `json:"supplier_id" db:"supplier_id"`
+	Status       string                `json:"status" db:"status"`
+	Notes        string                `json:"notes" db:"notes"`
+	ApprovedBy   *int                  `json:"approved_by,omitempty" db:"approved_by"`
+	DecidedAt    *time.Time            `json:"decided_at,omitempty" db:"decided_at"`
+	DecisionNote string                `json:"decision_note" db:"decision_note"`
+	LocationID   *int                  `json:"location_id,omitempty" db:"location_id"`
+	ReceivedAt   *time.Time            `json:"received_at,omitempty" db:"received_at"`
+	CreatedAt    time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at" db:"updated_at"`
+	Items        []PurchaseRequestItem `json:"items"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	SupplierName string `json:"supplier_name" db:"supplier_name"`
+	TicketNumber string `json:"ticket_number" db:"ticket_number"`
+}
+
+// Total retorna o valor cotado da compra
+func (p *PurchaseRequest) Total() Money {
+	var total Money
+	for _, item := range p.Items {
+		total += item.Subtotal()
+	}
+	return total
+}
+
+// PurchaseRequestItem representa um item cotado na compra
+type PurchaseRequestItem struct {
+	ID                int    `json:"id" db:"id"`
+	PurchaseRequestID int    `json:"purchase_request_id" db:"purchase_request_id"`
+	ItemID            int    `json:"item_id" db:"item_id"`
+	ItemSKU           string `json:"item_sku" db:"item_sku"`
+	ItemName          string `json:"item_name" db:"item_name"`
+	Quantity          int    `json:"quantity" db:"quantity"`
+	UnitPrice         Money  `json:"unit_price" db:"unit_price"`
+}
+
+// Subtotal retorna quantidade vezes preço cotado
+func (i *PurchaseRequestItem) Subtotal() Money {
+	return i.UnitPrice.Mul(i.Quantity)
+}
+
+// PURCHASE STATUS
+const (
+	PurchasePending  = "pending"
+	PurchaseApproved = "approved"
+	PurchaseRejected = "rejected"
+	PurchaseReceived = "received"
+)
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Quote representa uma versão do orçamento do ticket enviada ao cliente para aprovação
+type Quote struct {
+	ID              int         `json:"id" db:"id"`
+	TicketID        int         `json:"ticket_id" db:"ticket_id"`
+	Version         int         `json:"version" db:"version"`
+	Status          string      `json:"status" db:"status"`
+	Notes           string      `json:"notes" db:"notes"`
+	ServicesTotal   Money       `json:"services_total" db:"services_total"`
+	TravelCost      Money       `json:"travel_cost" db:"travel_cost"`
+	Total           Money       `json:"total" db:"total"`
+	SentTo          []string    `json:"sent_to" db:"sent_to"` // Destinatários do link de aprovação
+	SentAt          *time.Time  `json:"sent_at,omitempty" db:"sent_at"`
+	DecidedAt       *time.Time  `json:"decided_at,omitempty" db:"decided_at"`
+	DecidedBy       string      `json:"decided_by" db:"decided_by"`                           // Nome de quem aprovou ou rejeitou
+	DecidedByUserID *int        `json:"decided_by_user_id,omitempty" db:"decided_by_user_id"` // NULL quando decidido pelo link
+	DecisionChannel string      `json:"decision_channel" db:"decision_channel"`
+	DecisionNote    string      `json:"decision_note" db:"decision_note"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	Items           []QuoteItem `json:"items"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	TicketNumber string `json:"ticket_number" db:"ticket_number"`
+}
+
+// QuoteItem representa uma solution planejada no orçamento
+type QuoteItem struct {
+	ID           int    `json:"id" db:"id"`
+	QuoteID      int    `json:"quote_id" db:"quote_id"`
+	SolutionID   *int   `json:"solution_id,omitempty" db:"solution_id"`
+	ProblemName  string `json:"problem_name" db:"problem_name"`
+	SolutionName string `json:"solution_name" db:"solution_name"`
+	Quantity     int    `json:"quantity" db:"quantity"`
+	UnitPrice    Money  `json:"unit_price" db:"unit_price"`
+	Subtotal     Money  `json:"subtotal" db:"subtotal"`
+	Warranty     bool   `json:"warranty" db:"warranty"` // Retrabalho em garantia (subtotal zerado)
+}
+
+// QuoteDecision representa a aprovação ou rejeição de um orçamento
+type QuoteDecision struct {
+	Status    string // QuoteApproved ou QuoteRejected
+	DecidedBy string
+	UserID    *int
+	Channel   string
+	Note      string
+}
+
+// QUOTE STATUS
+// Pendente -> Aprovado | Rejeitado; uma nova versão substitui a pendente
+const (
+	QuotePending    = "pending"
+	QuoteApproved   = "approved"
+	QuoteRejected   = "rejected"
+	QuoteSuperseded = "superseded"
+)
+
+// QUOTE DECISION CHANNEL
+const (
+	QuoteChannelPortal = "portal"
+	QuoteChannelLink   = "link"
+)
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// TicketRating representa a avaliação do atendimento feita pelo cliente no fechamento do ticket
+type TicketRating struct {
+	ID        int       `json:"id" db:"id"`
+	TicketID  int       `json:"ticket_id" db:"ticket_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Score     int       `json:"score" db:"score"` // 1 a 5
+	Comment   string    `json:"comment" db:"comment"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
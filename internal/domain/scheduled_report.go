@@ -0,0 +1,151 @@
+package domain
+
+import "time"
+
+// TIPOS DE RELATÓRIO AGENDADO
+const (
+	ReportTypeCosts         = "costs"          // Linhas de custo lançadas no período
+	ReportTypeClientTickets = "client_tickets" // Tickets das agências de um cliente abertos ou fechados no período
+)
+
+// FREQUÊNCIAS DO AGENDAMENTO
+const (
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+)
+
+// STATUS DO ARQUIVO GERADO
+const (
+	ReportFileSent      = "sent"      // Gerado e entregue por email
+	ReportFileGenerated = "generated" // Gerado e arquivado, sem envio configurado
+	ReportFileFailed    = "failed"    // Gerado e arquivado, mas o envio falhou
+)
+
+// ReportSchedule define quando o relatório é gerado (horário local do servidor).
+// Weekday (0 = domingo) vale para weekly e Day (1 a 28) para monthly.
+type ReportSchedule struct {
+	Frequency string `json:"frequency"`
+	Weekday   int    `json:"weekday"`
+	Day       int    `json:"day"`
+	Hour      int    `json:"hour"`
+}
+
+// Valid indica se o agendamento é consistente com a frequência
+func (s ReportSchedule) Valid() bool {
+	if s.Hour < 0 || s.Hour > 23 {
+		return false
+	}
+	switch s.Frequency {
+	case ScheduleDaily:
+		return true
+	case ScheduleWeekly:
+		return s.Weekday >= 0 && s.Weekday <= 6
+	case ScheduleMonthly:
+		return s.Day >= 1 && s.Day <= 28
+	default:
+		return false
+	}
+}
+
+// Next retorna a primeira execução estritamente depois de after
+func (s ReportSchedule) Next(after time.Time) time.Time {
+	year, month, day := after.Date()
+	switch s.Frequency {
+	case ScheduleMonthly:
+		next := time.Date(year, month, s.Day, s.Hour, 0, 0, 0, after.Location())
+		if !next.After(after) {
+			next = next.AddDate(0, 1, 0)
+		}
+		return next
+	case ScheduleWeekly:
+		next := time.Date(year, month, day, s.Hour, 0, 0, 0, after.Location())
+		for int(next.Weekday()) != s.Weekday || !next.After(after) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	default:
+		next := time.Date(year, month, day, s.Hour, 0, 0, 0, after.Location())
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+}
+
+// Period retorna o período completo anterior à execução em runAt, como [from, to):
+// o mês anterior (monthly), os 7 dias anteriores (weekly) ou o dia anterior (daily)
+func (s ReportSchedule) Period(runAt time.Time) (time.Time, time.Time) {
+	year, month, day := runAt.Date()
+	switch s.Frequency {
+	case ScheduleMonthly:
+		to := time.Date(year, month, 1, 0, 0, 0, 0, runAt.Location())
+		return to.AddDate(0, -1, 0), to
+	case ScheduleWeekly:
+		to := time.Date(year, month, day, 0, 0, 0, 0, runAt.Location())
+		return to.AddDate(0, 0, -7), to
+	default:
+		to := time.Date(year, month, day, 0, 0, 0, 0, runAt.Location())
+		return to.AddDate(0, 0, -1), to
+	}
+}
+
+// ReportDefinition é um relatório salvo, gerado e enviado conforme o agendamento
+type ReportDefinition struct {
+	ID         int            `json:"id" db:"id"`
+	Name       string         `json:"name" db:"name"`
+	Type       string         `json:"type" db:"type"`
+	ClientID   *int           `json:"client_id" db:"client_id"` // Filtro; obrigatório em client_tickets
+	Format     string         `json:"format" db:"format"`
+	Recipients []string       `json:"recipients" db:"recipients"`
+	Schedule   ReportSchedule `json:"schedule"`
+	Active     bool           `json:"active" db:"active"`
+	NextRunAt  time.Time      `json:"next_run_at" db:"next_run_at"`
+	LastRunAt  *time.Time     `json:"last_run_at" db:"last_run_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// ReportFile é um arquivo gerado, mantido no arquivo para download
+type ReportFile struct {
+	ID           int       `json:"id" db:"id"`
+	DefinitionID int       `json:"definition_id" db:"definition_id"`
+	PeriodStart  time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd    time.Time `json:"period_end" db:"period_end"` // Exclusivo
+	Format       string    `json:"format" db:"format"`
+	FileName     string    `json:"file_name" db:"file_name"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	Size         int64     `json:"size" db:"size"`
+	Path         string    `json:"-" db:"path"`
+	Recipients   []string  `json:"recipients" db:"recipients"`
+	Status       string    `json:"status" db:"status"`
+	Error        string    `json:"error" db:"error"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// CostReportLine é uma linha de custo no relatório de custos
+type CostReportLine struct {
+	TicketNumber string
+	ClientName   string
+	BranchName   string
+	CreatedAt    time.Time
+	ProblemName  string
+	SolutionName string
+	Quantity     int
+	UnitPrice    Money
+	Subtotal     Money
+	Warranty     bool
+}
+
+// TicketReportLine é um ticket no resumo de tickets do cliente
+type TicketReportLine struct {
+	TicketNumber string
+	BranchName   string
+	City         string
+	State        string
+	Status       int
+	Priority     string
+	OpenDate     time.Time
+	CloseDate    *time.Time
+	Cost         Money
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportScheduleValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule ReportSchedule
+		want     bool
+	}{
+		{"daily", ReportSchedule{Frequency: ScheduleDaily, Hour: 6}, true},
+		{"hour out of range", ReportSchedule{Frequency: ScheduleDaily, Hour: 24}, false},
+		{"weekly sunday", ReportSchedule{Frequency: ScheduleWeekly, Weekday: 0, Hour: 6}, true},
+		{"weekly invalid weekday", ReportSchedule{Frequency: ScheduleWeekly, Weekday: 7, Hour: 6}, false},
+		{"monthly day 28", ReportSchedule{Frequency: ScheduleMonthly, Day: 28, Hour: 6}, true},
+		// Dias 29 a 31 não existem em todos os meses
+		{"monthly day 29", ReportSchedule{Frequency: ScheduleMonthly, Day: 29, Hour: 6}, false},
+		{"monthly without day", ReportSchedule{Frequency: ScheduleMonthly, Hour: 6}, false},
+		{"unknown frequency", ReportSchedule{Frequency: "hourly"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Valid(); got != tt.want {
+				t.Fatalf("Valid = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportScheduleNext(t *testing.T) {
+	at := func(month, day, hour, minute int) time.Time {
+		return time.Date(2026, time.Month(month), day, hour, minute, 0, 0, time.UTC)
+	}
+	daily := ReportSchedule{Frequency: ScheduleDaily, Hour: 6}
+	monday := ReportSchedule{Frequency: ScheduleWeekly, Weekday: int(time.Monday), Hour: 6}
+	monthly := ReportSchedule{Frequency: ScheduleMonthly, Day: 5, Hour: 6}
+
+	tests := []struct {
+		name     string
+		schedule ReportSchedule
+		after    time.Time
+		want     time.Time
+	}{
+		{"daily before the hour", daily, at(10, 19, 5, 59), at(10, 19, 6, 0)},
+		// Estritamente depois: a execução no horário exato agenda a próxima
+		{"daily at the hour", daily, at(10, 19, 6, 0), at(10, 20, 6, 0)},
+		{"daily after the hour", daily, at(10, 19, 6, 1), at(10, 20, 6, 0)},
+		// 19/10/2026 é segunda-feira
+		{"weekly same day before", monday, at(10, 19, 5, 0), at(10, 19, 6, 0)},
+		{"weekly same day after", monday, at(10, 19, 7, 0), at(10, 26, 6, 0)},
+		{"weekly from wednesday", monday, at(10, 21, 12, 0), at(10, 26, 6, 0)},
+		{"monthly before the day", monthly, at(10, 4, 23, 0), at(10, 5, 6, 0)},
+		{"monthly after the day", monthly, at(10, 5, 6, 0), at(11, 5, 6, 0)},
+		{"monthly across the year", monthly, at(12, 20, 0, 0), time.Date(2027, 1, 5, 6, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Fatalf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportSchedulePeriod(t *testing.T) {
+	day := func(year, month, d int) time.Time {
+		return time.Date(year, time.Month(month), d, 0, 0, 0, 0, time.UTC)
+	}
+	runAt := time.Date(2026, 3, 5, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		frequency string
+		runAt     time.Time
+		from, to  time.Time
+	}{
+		{ScheduleDaily, runAt, day(2026, 3, 4), day(2026, 3, 5)},
+		{ScheduleWeekly, runAt, day(2026, 2, 26), day(2026, 3, 5)},
+		// O mês anterior inteiro, independente do dia da execução
+		{ScheduleMonthly, runAt, day(2026, 2, 1), day(2026, 3, 1)},
+		{ScheduleMonthly, time.Date(2026, 1, 5, 6, 0, 0, 0, time.UTC), day(2025, 12, 1), day(2026, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.frequency, func(t *testing.T) {
+			from, to := ReportSchedule{Frequency: tt.frequency}.Period(tt.runAt)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Fatalf("Period(%v) = [%v, %v), want [%v, %v)", tt.runAt, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Janela padrão (dias) para considerar um novo ticket na mesma agência e problema como retrabalho
+const DefaultReworkDays = 30
+
+// SLAPolicy define o prazo (em horas, da abertura ao fechamento) por prioridade do ticket
+type SLAPolicy struct {
+	Hours        map[string]float64 // Prioridade em minúsculas -> horas
+	DefaultHours float64            // Prazo das prioridades não configuradas
+}
+
+// ScorecardFilter delimita os tickets avaliados (abertos em [From, To))
+type ScorecardFilter struct {
+	From       time.Time
+	To         time.Time
+	ReworkDays int
+}
+
+// Scorecard resume o desempenho de um técnico no período
+type Scorecard struct {
+	ProviderID         int       `json:"provider_id"`
+	ProviderName       string    `json:"provider_name"`
+	From               time.Time `json:"from"`
+	To                 time.Time `json:"to"`
+	TicketsHandled     int       `json:"tickets_handled"`
+	TicketsClosed      int       `json:"tickets_closed"`
+	AvgHoursToComplete *float64  `json:"avg_hours_to_complete"` // Da atribuição (ou primeira visita) ao fechamento
+	WithinSLA          int       `json:"within_sla"`
+	SLACompliance      *float64  `json:"sla_compliance"` // % dos fechados dentro do prazo
+	ReworkTickets      int       `json:"rework_tickets"`
+	ReworkRate         *float64  `json:"rework_rate"` // % dos fechados que voltaram
+	TotalCost          Money     `json:"total_cost"`
+	AvgCostPerTicket   Money     `json:"avg_cost_per_ticket"`
+	AvgDistanceKm      *float64  `json:"avg_distance_km"`
+}
+
+// Calculate preenche os indicadores derivados das contagens
+func (s *Scorecard) Calculate() {
+	if s.TicketsHandled > 0 {
+		s.AvgCostPerTicket = s.TotalCost.Times(1 / float64(s.TicketsHandled))
+	}
+	s.SLACompliance = percentOf(s.WithinSLA, s.TicketsClosed)
+	s.ReworkRate = percentOf(s.ReworkTickets, s.TicketsClosed)
+}
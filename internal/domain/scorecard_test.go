@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestScorecardCalculate(t *testing.T) {
+	percent := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		scorecard Scorecard
+		avgCost   Money
+		sla       *float64
+		rework    *float64
+	}{
+		{"no tickets", Scorecard{}, 0, nil, nil},
+		// Sem tickets fechados não há SLA nem retrabalho, mas o custo médio existe
+		{"nothing closed", Scorecard{TicketsHandled: 2, TotalCost: NewMoney(300)}, NewMoney(150), nil, nil},
+		{"closed tickets", Scorecard{TicketsHandled: 4, TicketsClosed: 4, WithinSLA: 3, ReworkTickets: 1, TotalCost: NewMoney(1000)},
+			NewMoney(250), percent(75), percent(25)},
+		{"cost rounded to the cent", Scorecard{TicketsHandled: 3, TicketsClosed: 3, WithinSLA: 1, TotalCost: NewMoney(100)},
+			NewMoney(33.33), percent(100.0 / 3), percent(0)},
+	}
+
+	equal := func(a, b *float64) bool { return (a == nil) == (b == nil) && (a == nil || *a == *b) }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorecard := tt.scorecard
+			scorecard.Calculate()
+			if scorecard.AvgCostPerTicket != tt.avgCost {
+				t.Errorf("avg cost = %v, want %v", scorecard.AvgCostPerTicket, tt.avgCost)
+			}
+			if !equal(scorecard.SLACompliance, tt.sla) || !equal(scorecard.ReworkRate, tt.rework) {
+				t.Errorf("got sla %v and rework %v, want %v and %v", scorecard.SLACompliance, scorecard.ReworkRate, tt.sla, tt.rework)
+			}
+		})
+	}
+}
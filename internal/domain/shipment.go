@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Shipment representa um envio de equipamento vinculado a um ticket
+type Shipment struct {
+	ID             int             `json:"id" db:"id"`
+	TicketID       int             `json:"ticket_id" db:"ticket_id"`
+	Direction      string          `json:"direction" db:"direction"`
+	Carrier        string          `json:"carrier" db:"carrier"`
+	TrackingNumber string          `json:"tracking_number" db:"tracking_number"`
+	Status         string          `json:"status" db:"status"`
+	Notes          string          `json:"notes" db:"notes"`
+	ShippedAt      *time.Time      `json:"shipped_at,omitempty" db:"shipped_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	Items          []ShipmentItem  `json:"items"`
+	Events         []ShipmentEvent `json:"events"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	TicketNumber string `json:"ticket_number" db:"ticket_number"`
+}
+
+// ShipmentItem representa um equipamento ou peça do envio
+type ShipmentItem struct {
+	ID           int    `json:"id" db:"id"`
+	ShipmentID   int    `json:"shipment_id" db:"shipment_id"`
+	ItemID       *int   `json:"item_id,omitempty" db:"item_id"` // Item do estoque, se houver
+	Description  string `json:"description" db:"description"`
+	SerialNumber string `json:"serial_number" db:"serial_number"`
+	Quantity     int    `json:"quantity" db:"quantity"`
+}
+
+// ShipmentEvent representa um evento de rastreio do envio
+type ShipmentEvent struct {
+	ID          int       `json:"id" db:"id"`
+	ShipmentID  int       `json:"shipment_id" db:"shipment_id"`
+	Status      string    `json:"status" db:"status"`
+	Description string    `json:"description" db:"description"`
+	Location    string    `json:"location" db:"location"`
+	Source      string    `json:"source" db:"source"` // Transportadora ou "manual"
+	OccurredAt  time.Time `json:"occurred_at" db:"occurred_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// SHIPMENT DIRECTIONS
+const (
+	ShipmentOutbound = "outbound" // Envio do equipamento para a agência
+	ShipmentReturn   = "return"   // Retorno do equipamento (logística reversa)
+)
+
+// SHIPMENT STATUS
+const (
+	ShipmentCreated        = "created"
+	ShipmentPosted         = "posted"
+	ShipmentInTransit      = "in_transit"
+	ShipmentOutForDelivery = "out_for_delivery"
+	ShipmentDelivered      = "delivered"
+	ShipmentFailed         = "failed"
+)
+
+// Origem de eventos registrados pela API
+const ShipmentEventManual = "manual"
@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+// ProviderStatement representa o extrato de pagamento de um técnico em um período
+type ProviderStatement struct {
+	ID               int               `json:"id" db:"id"`
+	ProviderID       int               `json:"provider_id" db:"provider_id"`
+	PeriodStart      time.Time         `json:"period_start" db:"period_start"`
+	PeriodEnd        time.Time         `json:"period_end" db:"period_end"`
+	Status           string            `json:"status" db:"status"`
+	Notes            string            `json:"notes" db:"notes"`
+	ApprovedBy       *int              `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt       *time.Time        `json:"approved_at,omitempty" db:"approved_at"`
+	PaidAt           *time.Time        `json:"paid_at,omitempty" db:"paid_at"`
+	PaymentReference string            `json:"payment_reference" db:"payment_reference"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
+	Tickets          []StatementTicket `json:"tickets"`
+	Lines            []StatementLine   `json:"lines"`
+
+	// Campos preenchidos via JOIN (somente leitura)
+	ProviderName string `json:"provider_name" db:"provider_name"`
+}
+
+// StatementTicket representa um ticket concluído incluído no extrato
+type StatementTicket struct {
+	StatementID int        `json:"statement_id" db:"statement_id"`
+	TicketID    int        `json:"ticket_id" db:"ticket_id"`
+	Number      string     `json:"number" db:"number"`
+	BranchName  string     `json:"branch_name" db:"branch_name"`
+	CloseDate   *time.Time `json:"close_date,omitempty" db:"close_date"`
+}
+
+// StatementLine representa um lançamento do extrato (deslocamento, mão de obra, despesa ou desconto)
+type StatementLine struct {
+	ID          int       `json:"id" db:"id"`
+	StatementID int       `json:"statement_id" db:"statement_id"`
+	Kind        string    `json:"kind" db:"kind"`
+	TicketID    *int      `json:"ticket_id,omitempty" db:"ticket_id"`
+	VisitID     *int      `json:"visit_id,omitempty" db:"visit_id"`
+	Description string    `json:"description" db:"description"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	UnitAmount  Money     `json:"unit_amount" db:"unit_amount"`
+	Amount      Money     `json:"amount" db:"amount"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProviderBankAccount representa a conta bancária em que o técnico recebe
+type ProviderBankAccount struct {
+	ProviderID   int       `json:"provider_id" db:"provider_id"`
+	HolderName   string    `json:"holder_name" db:"holder_name"`
+	Document     string    `json:"document" db:"document"` // CPF ou CNPJ do titular
+	BankCode     string    `json:"bank_code" db:"bank_code"`
+	Agency       string    `json:"agency" db:"agency"`
+	AgencyDigit  string    `json:"agency_digit" db:"agency_digit"`
+	Account      string    `json:"account" db:"account"`
+	AccountDigit string    `json:"account_digit" db:"account_digit"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TravelTotal retorna o reembolso de deslocamento do extrato
+func (s *ProviderStatement) TravelTotal() Money {
+	return s.sum(StatementLineTravel)
+}
+
+// LaborTotal retorna o valor de mão de obra do extrato
+func (s *ProviderStatement) LaborTotal() Money {
+	return s.sum(StatementLineLabor)
+}
+
+// ExpensesTotal retorna o reembolso das despesas aprovadas na prestação de contas
+func (s *ProviderStatement) ExpensesTotal() Money {
+	return s.sum(StatementLineExpense)
+}
+
+// DeductionsTotal retorna os descontos do extrato
+func (s *ProviderStatement) DeductionsTotal() Money {
+	return s.sum(StatementLineDeduction)
+}
+
+// Total retorna o valor a pagar: deslocamento + mão de obra + despesas - descontos
+func (s *ProviderStatement) Total() Money {
+	return s.TravelTotal() + s.LaborTotal() + s.ExpensesTotal() - s.DeductionsTotal()
+}
+
+func (s *ProviderStatement) sum(kind string) Money {
+	var total Money
+	for _, line := range s.Lines {
+		if line.Kind == kind {
+			total += line.Amount
+		}
+	}
+	return total
+}
+
+// STATEMENT STATUS
+// Rascunho -> Aprovado -> Pago
+const (
+	StatementDraft    = "draft"
+	StatementApproved = "approved"
+	StatementPaid     = "paid"
+)
+
+// STATEMENT LINE KIND
+const (
+	StatementLineTravel    = "travel"
+	StatementLineLabor     = "labor"
+	StatementLineExpense   = "expense"
+	StatementLineDeduction = "deduction"
+)
@@ -4,31 +4,45 @@ import "time"
 
 // Ticket representa um ticket de manutenção
 type Ticket struct {
-	ID          int        `json:"id" db:"id"`
-	Number      string     `json:"number" db:"number"`
-	Status      int        `json:"status" db:"status"`
-	Priority    string     `json:"priority" db:"priority"`
-	Description string     `json:"description" db:"description"`
-	OpenDate    time.Time  `json:"open_date" db:"open_date"`
-	CloseDate   *time.Time `json:"close_date,omitempty" db:"close_date"`
-	BranchID    int        `json:"branch_id" db:"branch_id"`
-	ProviderID  *int       `json:"provider_id,omitempty" db:"provider_id"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID               int        `json:"id" db:"id"`
+	Number           string     `json:"number" db:"number"`
+	Status           int        `json:"status" db:"status"`
+	Priority         string     `json:"priority" db:"priority"`
+	Description      string     `json:"description" db:"description"`
+	OpenDate         time.Time  `json:"open_date" db:"open_date"`
+	CloseDate        *time.Time `json:"close_date,omitempty" db:"close_date"`
+	BranchID         int        `json:"branch_id" db:"branch_id"`
+	ProviderID       *int       `json:"provider_id,omitempty" db:"provider_id"`
+	AssetID          *int       `json:"asset_id,omitempty" db:"asset_id"`
+	AssignmentStatus string     `json:"assignment_status" db:"assignment_status"`
+	Version          int        `json:"version" db:"version"` // Incrementada a cada alteração (ETag)
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// ASSIGNMENT STATUS
+// Aceite da atribuição pelo técnico
+const (
+	AssignmentPending  = "pending"
+	AssignmentAccepted = "accepted"
+	AssignmentDeclined = "declined"
+)
+
 // TicketCost representa os custos aplicados a um ticket
 type TicketCost struct {
-	ID           int       `json:"id" db:"id"`
-	TicketID     int       `json:"ticket_id" db:"ticket_id"`
-	ProblemID    *int      `json:"problem_id,omitempty" db:"problem_id"`
-	ProblemName  string    `json:"problem_name" db:"problem_name"`
-	SolutionID   *int      `json:"solution_id,omitempty" db:"solution_id"`
-	SolutionName string    `json:"solution_name" db:"solution_name"`
-	Quantity     int       `json:"quantity" db:"quantity"`
-	UnitPrice    float64   `json:"unit_price" db:"unit_price"`
-	Subtotal     float64   `json:"subtotal" db:"subtotal"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ID               int       `json:"id" db:"id"`
+	TicketID         int       `json:"ticket_id" db:"ticket_id"`
+	VisitID          *int      `json:"visit_id,omitempty" db:"visit_id"`
+	ProblemID        *int      `json:"problem_id,omitempty" db:"problem_id"`
+	ProblemName      string    `json:"problem_name" db:"problem_name"`
+	SolutionID       *int      `json:"solution_id,omitempty" db:"solution_id"`
+	SolutionName     string    `json:"solution_name" db:"solution_name"`
+	Quantity         int       `json:"quantity" db:"quantity"`
+	UnitPrice        Money     `json:"unit_price" db:"unit_price"`
+	Subtotal         Money     `json:"subtotal" db:"subtotal"`
+	Warranty         bool      `json:"warranty" db:"warranty"`                               // Retrabalho em garantia (subtotal zerado)
+	WarrantyTicketID *int      `json:"warranty_ticket_id,omitempty" db:"warranty_ticket_id"` // Ticket original da garantia
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
 // TicketProblem representa a relação many-to-many entre Ticket e Problem
@@ -36,6 +50,7 @@ type TicketProblem struct {
 	ID        int       `json:"id" db:"id"`
 	TicketID  int       `json:"ticket_id" db:"ticket_id"`
 	ProblemID int       `json:"problem_id" db:"problem_id"`
+	AssetID   *int      `json:"asset_id,omitempty" db:"asset_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -55,3 +70,20 @@ type TicketProblem struct {
 // 13. Prestação de Contas
 // 14. Contas Não Aprovadas
 // 15. Emitir Nota
+const (
+	TicketStatusAgendado            = 1
+	TicketStatusAguardaAtendimento  = 2
+	TicketStatusAguardaFaturamento  = 3
+	TicketStatusAguardaFinalizacao  = 4
+	TicketStatusCompras             = 5
+	TicketStatusConcluido           = 6
+	TicketStatusEmAtendimento       = 7
+	TicketStatusEnviado             = 8
+	TicketStatusEquipamentoEntregue = 9
+	TicketStatusEstoque             = 10
+	TicketStatusLogisticaReversa    = 11
+	TicketStatusNovo                = 12
+	TicketStatusPrestacaoDeContas   = 13
+	TicketStatusContasNaoAprovadas  = 14
+	TicketStatusEmitirNota          = 15
+)
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// TicketTimeEntry representa o tempo em campo registrado pelo técnico em um ticket
+type TicketTimeEntry struct {
+	ID         int       `json:"id" db:"id"`
+	TicketID   int       `json:"ticket_id" db:"ticket_id"`
+	ProviderID int       `json:"provider_id" db:"provider_id"`
+	StartedAt  time.Time `json:"started_at" db:"started_at"`
+	EndedAt    time.Time `json:"ended_at" db:"ended_at"`
+	Minutes    int       `json:"minutes" db:"minutes"`
+	Note       string    `json:"note" db:"note"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
@@ -14,15 +14,34 @@ type User struct {
 	Password string `json:"password"`
 	// ROLE
 	// 1 Admin
-	// 3 Finaceiro
+	// 2 Financeiro
 	// 3 Suporte
 	// 4 Estoque
 	// 5 Técnicos
 	// 6 Pagamentos
+	// 7 Cliente
 	Role   int64 `json:"role"`
 	Status bool  `json:"status"`
+	// ProviderID vincula o usuário técnico (role 5) ao seu cadastro de prestador
+	ProviderID *int `json:"provider_id,omitempty"`
+	// ClientID vincula o usuário ao cliente (tenant) cujos dados ele pode ver.
+	// Obrigatório para o papel Cliente (7); nulo para usuários internos.
+	ClientID *int `json:"client_id,omitempty"`
 }
 
+const (
+	RoleAdmin      = 1
+	RoleFinanceiro = 2
+	RoleSuporte    = 3
+	RoleEstoque    = 4
+	RoleTecnico    = 5
+	RolePagamentos = 6
+	RoleCliente    = 7
+)
+
+// StaffRoles são os papéis da equipe interna: técnicos usam o portal (/me) e clientes o portal do cliente
+var StaffRoles = []int{RoleAdmin, RoleFinanceiro, RoleSuporte, RoleEstoque, RolePagamentos}
+
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
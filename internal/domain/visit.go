@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Visit representa uma ida do técnico à agência dentro de um ticket
+// (ex.: visita de diagnóstico e retorno com peças de Compras/Estoque)
+type Visit struct {
+	ID         int        `json:"id" db:"id"`
+	TicketID   int        `json:"ticket_id" db:"ticket_id"`
+	ProviderID int        `json:"provider_id" db:"provider_id"`
+	StartsAt   *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	DistanceKm *float64   `json:"distance_km,omitempty" db:"distance_km"`
+	TravelCost Money      `json:"travel_cost" db:"travel_cost"`
+	Notes      string     `json:"notes" db:"notes"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+
+	// Campos de leitura (join com providers, tickets, branchs e ticket_costs)
+	ProviderName   string `json:"provider_name,omitempty" db:"provider_name"`
+	TicketNumber   string `json:"ticket_number,omitempty" db:"ticket_number"`
+	BranchID       int    `json:"branch_id,omitempty" db:"branch_id"`
+	BranchName     string `json:"branch_name,omitempty" db:"branch_name"`
+	BranchAddress  string `json:"branch_address,omitempty" db:"branch_address"`
+	BranchCity     string `json:"branch_city,omitempty" db:"branch_city"`
+	SolutionsCount int    `json:"solutions_count" db:"solutions_count"`
+	SolutionsTotal Money  `json:"solutions_total" db:"solutions_total"`
+}
+
+// Scheduled indica se a visita já tem data marcada
+func (v *Visit) Scheduled() bool {
+	return v.StartsAt != nil && v.EndsAt != nil
+}
+
+// Total retorna o custo da visita (deslocamento + soluções aplicadas)
+func (v *Visit) Total() Money {
+	return v.TravelCost + v.SolutionsTotal
+}
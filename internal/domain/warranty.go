@@ -0,0 +1,11 @@
+package domain
+
+// WarrantyReturn resume os retrabalhos em garantia atribuídos a um técnico
+type WarrantyReturn struct {
+	ProviderID      int    `json:"provider_id" db:"provider_id"`
+	ProviderName    string `json:"provider_name" db:"provider_name"`
+	Returns         int    `json:"returns" db:"returns"`                   // Linhas de custo em garantia
+	OriginalTickets int    `json:"original_tickets" db:"original_tickets"` // Tickets do técnico que voltaram
+	ReworkTickets   int    `json:"rework_tickets" db:"rework_tickets"`     // Tickets abertos pelo retorno
+	WaivedAmount    Money  `json:"waived_amount" db:"waived_amount"`       // Valor que deixou de ser cobrado
+}
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// AssetRequest representa a requisição para cadastrar ou atualizar um equipamento
+type AssetRequest struct {
+	BranchID       int     `json:"branch_id" binding:"required"`
+	Type           string  `json:"type" binding:"required"`
+	Model          string  `json:"model"`
+	SerialNumber   string  `json:"serial_number"`
+	InstalledAt    *string `json:"installed_at" binding:"omitempty,datetime=2006-01-02"`     // Formato "2006-01-02"
+	WarrantyEndsAt *string `json:"warranty_ends_at" binding:"omitempty,datetime=2006-01-02"` // Formato "2006-01-02"
+	Notes          string  `json:"notes"`
+}
+
+// ToAssetDomain converte a requisição no domínio (datas já validadas pelo binding)
+func (r *AssetRequest) ToAssetDomain() *domain.Asset {
+	return &domain.Asset{
+		BranchID:       r.BranchID,
+		Type:           r.Type,
+		Model:          r.Model,
+		SerialNumber:   r.SerialNumber,
+		InstalledAt:    parseDate(r.InstalledAt),
+		WarrantyEndsAt: parseDate(r.WarrantyEndsAt),
+		Notes:          r.Notes,
+	}
+}
+
+func parseDate(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// AssetResponse representa um equipamento na resposta
+type AssetResponse struct {
+	ID             int        `json:"id"`
+	BranchID       int        `json:"branch_id"`
+	BranchName     string     `json:"branch_name"`
+	Type           string     `json:"type"`
+	Model          string     `json:"model"`
+	SerialNumber   string     `json:"serial_number"`
+	InstalledAt    *time.Time `json:"installed_at,omitempty"`
+	WarrantyEndsAt *time.Time `json:"warranty_ends_at,omitempty"`
+	UnderWarranty  bool       `json:"under_warranty"`
+	Notes          string     `json:"notes"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AssetTicketResponse representa um atendimento no histórico do equipamento
+type AssetTicketResponse struct {
+	TicketID    int                    `json:"ticket_id"`
+	Number      string                 `json:"number"`
+	Status      int                    `json:"status"`
+	Description string                 `json:"description"`
+	OpenDate    time.Time              `json:"open_date"`
+	CloseDate   *time.Time             `json:"close_date,omitempty"`
+	ProviderID  *int                   `json:"provider_id,omitempty"`
+	Problems    []string               `json:"problems"`
+	Costs       []SolutionItemResponse `json:"costs"`
+	TotalCost   domain.Money           `json:"total_cost"`
+}
+
+// AssetHistoryResponse representa o histórico completo de atendimento de um equipamento
+type AssetHistoryResponse struct {
+	Asset     *AssetResponse        `json:"asset"`
+	Tickets   []AssetTicketResponse `json:"tickets"`
+	TotalCost domain.Money          `json:"total_cost"`
+}
+
+func ToAssetResponse(asset *domain.Asset) *AssetResponse {
+	if asset == nil {
+		return nil
+	}
+
+	return &AssetResponse{
+		ID:             asset.ID,
+		BranchID:       asset.BranchID,
+		BranchName:     asset.BranchName,
+		Type:           asset.Type,
+		Model:          asset.Model,
+		SerialNumber:   asset.SerialNumber,
+		InstalledAt:    asset.InstalledAt,
+		WarrantyEndsAt: asset.WarrantyEndsAt,
+		UnderWarranty:  asset.UnderWarranty(time.Now()),
+		Notes:          asset.Notes,
+		CreatedAt:      asset.CreatedAt,
+		UpdatedAt:      asset.UpdatedAt,
+	}
+}
+
+func ToAssetResponseList(assets []domain.Asset) []AssetResponse {
+	responses := make([]AssetResponse, 0, len(assets))
+	for i := range assets {
+		responses = append(responses, *ToAssetResponse(&assets[i]))
+	}
+	return responses
+}
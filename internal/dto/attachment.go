@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// AttachmentResponse representa um anexo de ticket na resposta
+type AttachmentResponse struct {
+	ID          int       `json:"id"`
+	TicketID    int       `json:"ticket_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Public      bool      `json:"public"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ToAttachmentResponse converte domain para DTO
+func ToAttachmentResponse(attachment *domain.Attachment) *AttachmentResponse {
+	if attachment == nil {
+		return nil
+	}
+
+	return &AttachmentResponse{
+		ID:          attachment.ID,
+		TicketID:    attachment.TicketID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Public:      attachment.Public,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// ToAttachmentResponseList converte lista de domain para DTO
+func ToAttachmentResponseList(attachments []domain.Attachment) []AttachmentResponse {
+	responses := make([]AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = *ToAttachmentResponse(&attachment)
+	}
+	return responses
+}
@@ -1,28 +1,81 @@
 package dto
 
+import "github.com/ericolvr/maintenance-v2/internal/domain"
+
 type BranchRequest struct {
-	Name         string `json:"name" binding:"required"`
-	Client       string `json:"client" binding:"required"`
-	Uniorg       string `json:"uniorg" binding:"required"`
-	Zipcode      string `json:"zipcode" binding:"required"`
-	State        string `json:"state" binding:"required"`
-	City         string `json:"city" binding:"required"`
-	Neighborhood string `json:"neighborhood" binding:"required"`
-	Address      string `json:"address" binding:"required"`
-	Complement   string `json:"complement"`
+	Name         string   `json:"name" binding:"required"`
+	ClientID     int      `json:"client_id" binding:"required"`
+	Uniorg       string   `json:"uniorg" binding:"required"`
+	Zipcode      string   `json:"zipcode" binding:"required"`
+	State        string   `json:"state" binding:"required"`
+	City         string   `json:"city" binding:"required"`
+	Neighborhood string   `json:"neighborhood" binding:"required"`
+	Address      string   `json:"address" binding:"required"`
+	Complement   string   `json:"complement"`
+	EmailDomain  string   `json:"email_domain"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	OpensAt      string   `json:"opens_at" binding:"omitempty,datetime=15:04"`
+	ClosesAt     string   `json:"closes_at" binding:"omitempty,datetime=15:04"`
+}
+
+func (r *BranchRequest) ToBranchDomain() *domain.Branch {
+	return &domain.Branch{
+		ClientID:     r.ClientID,
+		Name:         r.Name,
+		Uniorg:       r.Uniorg,
+		Zipcode:      r.Zipcode,
+		State:        r.State,
+		City:         r.City,
+		Neighborhood: r.Neighborhood,
+		Address:      r.Address,
+		Complement:   r.Complement,
+		EmailDomain:  r.EmailDomain,
+		Latitude:     r.Latitude,
+		Longitude:    r.Longitude,
+		OpensAt:      r.OpensAt,
+		ClosesAt:     r.ClosesAt,
+	}
+}
+
+// ToBranchRequest monta o request com os dados atuais da agência, base do PATCH
+func ToBranchRequest(branch *domain.Branch) *BranchRequest {
+	return &BranchRequest{
+		Name:         branch.Name,
+		ClientID:     branch.ClientID,
+		Uniorg:       branch.Uniorg,
+		Zipcode:      branch.Zipcode,
+		State:        branch.State,
+		City:         branch.City,
+		Neighborhood: branch.Neighborhood,
+		Address:      branch.Address,
+		Complement:   branch.Complement,
+		EmailDomain:  branch.EmailDomain,
+		Latitude:     branch.Latitude,
+		Longitude:    branch.Longitude,
+		OpensAt:      branch.OpensAt,
+		ClosesAt:     branch.ClosesAt,
+	}
 }
 
 type BranchResponse struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Client       string `json:"client"`
-	Uniorg       string `json:"uniorg"`
-	Zipcode      string `json:"zipcode"`
-	State        string `json:"state"`
-	City         string `json:"city"`
-	Neighborhood string `json:"neighborhood"`
-	Address      string `json:"address"`
-	Complement   string `json:"complement"`
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	ClientID     int      `json:"client_id"`
+	Client       string   `json:"client"`
+	Uniorg       string   `json:"uniorg"`
+	Zipcode      string   `json:"zipcode"`
+	State        string   `json:"state"`
+	City         string   `json:"city"`
+	Neighborhood string   `json:"neighborhood"`
+	Address      string   `json:"address"`
+	Complement   string   `json:"complement"`
+	EmailDomain  string   `json:"email_domain"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	OpensAt      string   `json:"opens_at"`
+	ClosesAt     string   `json:"closes_at"`
+	Version      int      `json:"version"` // Também enviada no header ETag
 }
 
 type BranchSummaryResponse struct {
@@ -31,3 +84,33 @@ type BranchSummaryResponse struct {
 	Uniorg  string `json:"uniorg"`
 	Zipcode string `json:"zipcode"`
 }
+
+func ToBranchResponse(branch *domain.Branch) *BranchResponse {
+	return &BranchResponse{
+		ID:           branch.ID,
+		Name:         branch.Name,
+		ClientID:     branch.ClientID,
+		Client:       branch.Client,
+		Uniorg:       branch.Uniorg,
+		Zipcode:      branch.Zipcode,
+		State:        branch.State,
+		City:         branch.City,
+		Neighborhood: branch.Neighborhood,
+		Address:      branch.Address,
+		Complement:   branch.Complement,
+		EmailDomain:  branch.EmailDomain,
+		Latitude:     branch.Latitude,
+		Longitude:    branch.Longitude,
+		OpensAt:      branch.OpensAt,
+		ClosesAt:     branch.ClosesAt,
+		Version:      branch.Version,
+	}
+}
+
+func ToBranchResponseList(branchs []domain.Branch) []BranchResponse {
+	response := make([]BranchResponse, 0, len(branchs))
+	for i := range branchs {
+		response = append(response, *ToBranchResponse(&branchs[i]))
+	}
+	return response
+}
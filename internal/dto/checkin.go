@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// CheckinRequest representa a posição GPS capturada no check-in
+type CheckinRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+}
+
+// CheckoutRequest representa a posição GPS e o km percorrido informados no check-out
+type CheckoutRequest struct {
+	Latitude   *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude  *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	ReportedKm *float64 `json:"reported_km,omitempty" binding:"omitempty,min=0"`
+}
+
+// CheckinResponse representa um check-in do ticket na resposta
+type CheckinResponse struct {
+	ID                     int        `json:"id"`
+	TicketID               int        `json:"ticket_id"`
+	ProviderID             int        `json:"provider_id"`
+	CheckinAt              time.Time  `json:"checkin_at"`
+	CheckinLatitude        float64    `json:"checkin_latitude"`
+	CheckinLongitude       float64    `json:"checkin_longitude"`
+	CheckinDistanceMeters  *float64   `json:"checkin_distance_meters,omitempty"`
+	CheckoutAt             *time.Time `json:"checkout_at,omitempty"`
+	CheckoutLatitude       *float64   `json:"checkout_latitude,omitempty"`
+	CheckoutLongitude      *float64   `json:"checkout_longitude,omitempty"`
+	CheckoutDistanceMeters *float64   `json:"checkout_distance_meters,omitempty"`
+	ReportedKm             *float64   `json:"reported_km,omitempty"`
+	OnSiteMinutes          int        `json:"on_site_minutes"`
+	Anomalies              []string   `json:"anomalies"`
+}
+
+// ToCheckinResponse converte domain para DTO
+func ToCheckinResponse(checkin *domain.TicketCheckin) *CheckinResponse {
+	if checkin == nil {
+		return nil
+	}
+
+	return &CheckinResponse{
+		ID:                     checkin.ID,
+		TicketID:               checkin.TicketID,
+		ProviderID:             checkin.ProviderID,
+		CheckinAt:              checkin.CheckinAt,
+		CheckinLatitude:        checkin.CheckinLatitude,
+		CheckinLongitude:       checkin.CheckinLongitude,
+		CheckinDistanceMeters:  checkin.CheckinDistanceMeters,
+		CheckoutAt:             checkin.CheckoutAt,
+		CheckoutLatitude:       checkin.CheckoutLatitude,
+		CheckoutLongitude:      checkin.CheckoutLongitude,
+		CheckoutDistanceMeters: checkin.CheckoutDistanceMeters,
+		ReportedKm:             checkin.ReportedKm,
+		OnSiteMinutes:          checkin.OnSiteMinutes(),
+		Anomalies:              checkin.Anomalies,
+	}
+}
+
+// ToCheckinResponseList converte lista de domain para DTO
+func ToCheckinResponseList(checkins []domain.TicketCheckin) []CheckinResponse {
+	responses := make([]CheckinResponse, len(checkins))
+	for i, checkin := range checkins {
+		responses[i] = *ToCheckinResponse(&checkin)
+	}
+	return responses
+}
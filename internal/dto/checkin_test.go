@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestCheckinRequestValidatesCoordinates(t *testing.T) {
+	coordinate := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name      string
+		latitude  *float64
+		longitude *float64
+		valid     bool
+	}{
+		{"agency in São Paulo", coordinate(-23.55), coordinate(-46.63), true},
+		{"equator and greenwich", coordinate(0), coordinate(0), true},
+		{"limits", coordinate(-90), coordinate(180), true},
+		{"latitude above 90", coordinate(90.1), coordinate(-46.63), false},
+		{"latitude below -90", coordinate(-123.55), coordinate(-46.63), false},
+		{"longitude above 180", coordinate(-23.55), coordinate(180.5), false},
+		{"longitude below -180", coordinate(-23.55), coordinate(-246.63), false},
+		{"missing latitude", nil, coordinate(-46.63), false},
+	}
+
+	for _, tt := range tests {
+		checkin := CheckinRequest{Latitude: tt.latitude, Longitude: tt.longitude}
+		if err := binding.Validator.ValidateStruct(&checkin); (err == nil) != tt.valid {
+			t.Errorf("checkin %s: got %v, want valid=%v", tt.name, err, tt.valid)
+		}
+
+		checkout := CheckoutRequest{Latitude: tt.latitude, Longitude: tt.longitude}
+		if err := binding.Validator.ValidateStruct(&checkout); (err == nil) != tt.valid {
+			t.Errorf("checkout %s: got %v, want valid=%v", tt.name, err, tt.valid)
+		}
+	}
+}
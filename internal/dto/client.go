@@ -1,10 +1,12 @@
 package dto
 
 type ClientRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Document string `json:"document"`
 }
 
 type ClientResponse struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Document string `json:"document"`
 }
@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ClientTicketRequest contém a abertura de um ticket pelo cliente em uma de suas agências
+type ClientTicketRequest struct {
+	BranchID    int    `json:"branch_id" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	Priority    string `json:"priority"` // Opcional, padrão: medium
+	AssetID     *int   `json:"asset_id,omitempty"`
+}
+
+// ClientCommentRequest contém um comentário do cliente no ticket
+type ClientCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// TicketRatingRequest contém a avaliação do atendimento pelo cliente
+type TicketRatingRequest struct {
+	Score   int    `json:"score" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+// TicketRatingResponse representa a avaliação do atendimento na resposta
+type TicketRatingResponse struct {
+	Score     int       `json:"score"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToTicketRatingResponse converte domain para DTO
+func ToTicketRatingResponse(rating *domain.TicketRating) *TicketRatingResponse {
+	if rating == nil {
+		return nil
+	}
+
+	return &TicketRatingResponse{
+		Score:     rating.Score,
+		Comment:   rating.Comment,
+		CreatedAt: rating.CreatedAt,
+	}
+}
+
+// TIMELINE EVENTS
+const (
+	TimelineOpened     = "opened"
+	TimelineVisit      = "visit_scheduled"
+	TimelineArrived    = "technician_arrived"
+	TimelineLeft       = "technician_left"
+	TimelineQuote      = "quote"
+	TimelineComment    = "comment"
+	TimelineAttachment = "attachment"
+	TimelineClosed     = "closed"
+	TimelineRated      = "rated"
+)
+
+// TimelineEvent representa um acontecimento do ticket exibido ao cliente
+type TimelineEvent struct {
+	At          time.Time `json:"at"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+}
+
+// ClientVisitResponse representa uma visita agendada, sem custos de deslocamento
+type ClientVisitResponse struct {
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	ProviderName string     `json:"provider_name"`
+}
+
+// ClientTicketResponse representa o ticket como o cliente o vê no portal.
+// Custos só são preenchidos quando o contrato do cliente permite (show_costs).
+type ClientTicketResponse struct {
+	ID           int                    `json:"id"`
+	Number       string                 `json:"number"`
+	Status       int                    `json:"status"`
+	StatusLabel  string                 `json:"status_label"`
+	Priority     string                 `json:"priority"`
+	Description  string                 `json:"description"`
+	OpenDate     time.Time              `json:"open_date"`
+	CloseDate    *time.Time             `json:"close_date,omitempty"`
+	BranchID     int                    `json:"branch_id"`
+	BranchName   string                 `json:"branch_name"`
+	BranchUniorg string                 `json:"branch_uniorg"`
+	AssetID      *int                   `json:"asset_id,omitempty"`
+	ProviderName *string                `json:"provider_name,omitempty"`
+	Visits       []ClientVisitResponse  `json:"visits,omitempty"`
+	Costs        []SolutionItemResponse `json:"costs,omitempty"`
+	TravelCost   *domain.Money          `json:"travel_cost,omitempty"`
+	TotalCost    *domain.Money          `json:"total_cost,omitempty"`
+	Rating       *TicketRatingResponse  `json:"rating,omitempty"`
+	Timeline     []TimelineEvent        `json:"timeline,omitempty"`
+}
+
+// ToClientTicketResponse converte o ticket completo na visão do cliente
+func ToClientTicketResponse(ticket *TicketResponse, showCosts bool) *ClientTicketResponse {
+	if ticket == nil {
+		return nil
+	}
+
+	response := &ClientTicketResponse{
+		ID:           ticket.ID,
+		Number:       ticket.Number,
+		Status:       ticket.Status,
+		StatusLabel:  domain.TicketStatusLabels[ticket.Status],
+		Priority:     ticket.Priority,
+		Description:  ticket.Description,
+		OpenDate:     ticket.OpenDate,
+		CloseDate:    ticket.CloseDate,
+		BranchID:     ticket.BranchID,
+		BranchName:   ticket.BranchName,
+		BranchUniorg: ticket.BranchUniorg,
+		AssetID:      ticket.AssetID,
+		ProviderName: ticket.ProviderName,
+	}
+
+	for _, visit := range ticket.Visits {
+		response.Visits = append(response.Visits, ClientVisitResponse{
+			StartsAt:     visit.StartsAt,
+			EndsAt:       visit.EndsAt,
+			ProviderName: visit.ProviderName,
+		})
+	}
+
+	if showCosts {
+		travelCost := ticket.TravelCost
+		totalCost := ticket.TotalCost
+		response.Costs = ticket.Costs
+		response.TravelCost = &travelCost
+		response.TotalCost = &totalCost
+	}
+
+	return response
+}
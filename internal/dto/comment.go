@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// CommentRequest representa a requisição para registrar um comentário no ticket
+type CommentRequest struct {
+	Author string `json:"author" binding:"required"`
+	Body   string `json:"body" binding:"required"`
+	Public bool   `json:"public"` // Visível ao cliente no portal
+}
+
+// CommentResponse representa um comentário do ticket na resposta
+type CommentResponse struct {
+	ID        int       `json:"id"`
+	TicketID  int       `json:"ticket_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Source    string    `json:"source"`
+	Public    bool      `json:"public"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToCommentResponse converte domain para DTO
+func ToCommentResponse(comment *domain.TicketComment) *CommentResponse {
+	if comment == nil {
+		return nil
+	}
+
+	return &CommentResponse{
+		ID:        comment.ID,
+		TicketID:  comment.TicketID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		Source:    comment.Source,
+		Public:    comment.Public,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+// ToCommentResponseList converte lista de domain para DTO
+func ToCommentResponseList(comments []domain.TicketComment) []CommentResponse {
+	responses := make([]CommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = *ToCommentResponse(&comment)
+	}
+	return responses
+}
@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ContractPriceRequest representa o preço negociado de uma solution
+type ContractPriceRequest struct {
+	SolutionID int          `json:"solution_id" binding:"required"`
+	UnitPrice  domain.Money `json:"unit_price" binding:"min=0"`
+}
+
+// ContractRequest representa a requisição para cadastrar ou atualizar um contrato de cliente
+type ContractRequest struct {
+	ClientID     int                    `json:"client_id" binding:"required"`
+	Name         string                 `json:"name" binding:"required"`
+	StartsOn     string                 `json:"starts_on" binding:"required,datetime=2006-01-02"` // Formato "2006-01-02"
+	EndsOn       *string                `json:"ends_on" binding:"omitempty,datetime=2006-01-02"`  // Opcional, formato "2006-01-02"
+	ValuePerKm   *domain.Money          `json:"value_per_km" binding:"omitempty,min=0"`           // Opcional, padrão: tabela de custos
+	InitialValue *domain.Money          `json:"initial_value" binding:"omitempty,min=0"`          // Opcional, padrão: tabela de custos
+	ShowCosts    bool                   `json:"show_costs"`                                       // Exibe os custos no portal do cliente
+	RequireQuote bool                   `json:"require_quote"`                                    // Exige orçamento aprovado antes do atendimento
+	Prices       []ContractPriceRequest `json:"prices" binding:"dive"`
+}
+
+// ToContractDomain converte a requisição no domínio (datas já validadas pelo binding)
+func (r *ContractRequest) ToContractDomain() *domain.Contract {
+	contract := &domain.Contract{
+		ClientID:     r.ClientID,
+		Name:         r.Name,
+		EndsOn:       parseDate(r.EndsOn),
+		ValuePerKm:   r.ValuePerKm,
+		InitialValue: r.InitialValue,
+		ShowCosts:    r.ShowCosts,
+		RequireQuote: r.RequireQuote,
+	}
+	if startsOn := parseDate(&r.StartsOn); startsOn != nil {
+		contract.StartsOn = *startsOn
+	}
+
+	for _, price := range r.Prices {
+		contract.Prices = append(contract.Prices, domain.ContractPrice{
+			SolutionID: price.SolutionID,
+			UnitPrice:  price.UnitPrice,
+		})
+	}
+
+	return contract
+}
+
+// ContractPriceResponse representa um preço negociado na resposta
+type ContractPriceResponse struct {
+	SolutionID   int          `json:"solution_id"`
+	SolutionName string       `json:"solution_name"`
+	UnitPrice    domain.Money `json:"unit_price"`
+}
+
+// ContractResponse representa um contrato na resposta
+type ContractResponse struct {
+	ID           int                     `json:"id"`
+	ClientID     int                     `json:"client_id"`
+	ClientName   string                  `json:"client_name"`
+	Name         string                  `json:"name"`
+	StartsOn     time.Time               `json:"starts_on"`
+	EndsOn       *time.Time              `json:"ends_on,omitempty"`
+	ValuePerKm   *domain.Money           `json:"value_per_km,omitempty"`
+	InitialValue *domain.Money           `json:"initial_value,omitempty"`
+	ShowCosts    bool                    `json:"show_costs"`
+	RequireQuote bool                    `json:"require_quote"`
+	Prices       []ContractPriceResponse `json:"prices"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+func ToContractResponse(contract *domain.Contract) *ContractResponse {
+	if contract == nil {
+		return nil
+	}
+
+	prices := make([]ContractPriceResponse, 0, len(contract.Prices))
+	for _, price := range contract.Prices {
+		prices = append(prices, ContractPriceResponse{
+			SolutionID:   price.SolutionID,
+			SolutionName: price.SolutionName,
+			UnitPrice:    price.UnitPrice,
+		})
+	}
+
+	return &ContractResponse{
+		ID:           contract.ID,
+		ClientID:     contract.ClientID,
+		ClientName:   contract.ClientName,
+		Name:         contract.Name,
+		StartsOn:     contract.StartsOn,
+		EndsOn:       contract.EndsOn,
+		ValuePerKm:   contract.ValuePerKm,
+		InitialValue: contract.InitialValue,
+		ShowCosts:    contract.ShowCosts,
+		RequireQuote: contract.RequireQuote,
+		Prices:       prices,
+		CreatedAt:    contract.CreatedAt,
+		UpdatedAt:    contract.UpdatedAt,
+	}
+}
+
+func ToContractResponseList(contracts []domain.Contract) []ContractResponse {
+	responses := make([]ContractResponse, 0, len(contracts))
+	for i := range contracts {
+		responses = append(responses, *ToContractResponse(&contracts[i]))
+	}
+	return responses
+}
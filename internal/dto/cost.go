@@ -1,12 +1,14 @@
 package dto
 
+import "github.com/ericolvr/maintenance-v2/internal/domain"
+
 type CostRequest struct {
-	ValuePerKm   float64 `json:"value_per_km" binding:"required"`
-	InitialValue float64 `json:"initial_value" binding:"required"`
+	ValuePerKm   domain.Money `json:"value_per_km" binding:"required"`
+	InitialValue domain.Money `json:"initial_value" binding:"required"`
 }
 
 type CostResponse struct {
-	ID           int     `json:"id"`
-	ValuePerKm   float64 `json:"value_per_km"`
-	InitialValue float64 `json:"initial_value"`
+	ID           int          `json:"id"`
+	ValuePerKm   domain.Money `json:"value_per_km"`
+	InitialValue domain.Money `json:"initial_value"`
 }
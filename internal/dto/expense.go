@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ExpenseItemRequest representa uma despesa apresentada pelo técnico
+type ExpenseItemRequest struct {
+	Description  string       `json:"description" binding:"required"`
+	Quantity     int          `json:"quantity" binding:"required,min=1"`
+	UnitPrice    domain.Money `json:"unit_price" binding:"gt=0"`
+	AttachmentID *int         `json:"attachment_id"` // Comprovante já anexado ao ticket
+}
+
+// ExpenseRequest representa a prestação de contas de um ticket
+type ExpenseRequest struct {
+	Notes string               `json:"notes"`
+	Items []ExpenseItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// ExpenseItemDecision representa a decisão sobre um item da prestação
+type ExpenseItemDecision struct {
+	ItemID   int    `json:"item_id" binding:"required"`
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason"` // Obrigatório na rejeição
+}
+
+// ExpenseReviewRequest representa a revisão da prestação pelo Financeiro (todos os itens)
+type ExpenseReviewRequest struct {
+	Items []ExpenseItemDecision `json:"items" binding:"required,min=1,dive"`
+	Note  string                `json:"note"`
+}
+
+// ExpenseItemResponse representa uma despesa na resposta
+type ExpenseItemResponse struct {
+	ID              int          `json:"id"`
+	Description     string       `json:"description"`
+	Quantity        int          `json:"quantity"`
+	UnitPrice       domain.Money `json:"unit_price"`
+	Subtotal        domain.Money `json:"subtotal"`
+	AttachmentID    *int         `json:"attachment_id,omitempty"`
+	Status          string       `json:"status"`
+	RejectionReason string       `json:"rejection_reason,omitempty"`
+}
+
+// ExpenseResponse representa uma prestação de contas na resposta
+type ExpenseResponse struct {
+	ID            int                   `json:"id"`
+	TicketID      int                   `json:"ticket_id"`
+	TicketNumber  string                `json:"ticket_number"`
+	ProviderID    int                   `json:"provider_id"`
+	ProviderName  string                `json:"provider_name"`
+	Status        string                `json:"status"`
+	Notes         string                `json:"notes"`
+	Items         []ExpenseItemResponse `json:"items"`
+	Total         domain.Money          `json:"total"`
+	ApprovedTotal domain.Money          `json:"approved_total"`
+	DecidedBy     *int                  `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time            `json:"decided_at,omitempty"`
+	DecisionNote  string                `json:"decision_note"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+func ToExpenseResponse(expense *domain.Expense) *ExpenseResponse {
+	if expense == nil {
+		return nil
+	}
+
+	items := make([]ExpenseItemResponse, 0, len(expense.Items))
+	for i := range expense.Items {
+		item := &expense.Items[i]
+		items = append(items, ExpenseItemResponse{
+			ID:              item.ID,
+			Description:     item.Description,
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice,
+			Subtotal:        item.Subtotal(),
+			AttachmentID:    item.AttachmentID,
+			Status:          item.Status,
+			RejectionReason: item.RejectionReason,
+		})
+	}
+
+	return &ExpenseResponse{
+		ID:            expense.ID,
+		TicketID:      expense.TicketID,
+		TicketNumber:  expense.TicketNumber,
+		ProviderID:    expense.ProviderID,
+		ProviderName:  expense.ProviderName,
+		Status:        expense.Status,
+		Notes:         expense.Notes,
+		Items:         items,
+		Total:         expense.Total(),
+		ApprovedTotal: expense.ApprovedTotal(),
+		DecidedBy:     expense.DecidedBy,
+		DecidedAt:     expense.DecidedAt,
+		DecisionNote:  expense.DecisionNote,
+		CreatedAt:     expense.CreatedAt,
+		UpdatedAt:     expense.UpdatedAt,
+	}
+}
+
+func ToExpenseResponseList(expenses []domain.Expense) []ExpenseResponse {
+	responses := make([]ExpenseResponse, 0, len(expenses))
+	for i := range expenses {
+		responses = append(responses, *ToExpenseResponse(&expenses[i]))
+	}
+	return responses
+}
@@ -0,0 +1,263 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// InventoryItemRequest representa a requisição para cadastrar ou atualizar um item de estoque
+type InventoryItemRequest struct {
+	SKU         string `json:"sku" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Unit        string `json:"unit"` // Opcional, padrão: "un"
+	MinQuantity int    `json:"min_quantity" binding:"min=0"`
+}
+
+// InventoryItemResponse representa um item de estoque na resposta
+type InventoryItemResponse struct {
+	ID          int                  `json:"id"`
+	SKU         string               `json:"sku"`
+	Name        string               `json:"name"`
+	Unit        string               `json:"unit"`
+	MinQuantity int                  `json:"min_quantity"`
+	Levels      []StockLevelResponse `json:"levels,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// StockLevelResponse representa o saldo de um item em um local
+type StockLevelResponse struct {
+	LocationID   int    `json:"location_id"`
+	LocationName string `json:"location_name"`
+	Quantity     int    `json:"quantity"`
+	Reserved     int    `json:"reserved"`
+	Available    int    `json:"available"`
+}
+
+// StockLocationRequest representa a requisição para cadastrar um local de estoque
+type StockLocationRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Kind       string `json:"kind" binding:"required"` // "warehouse" ou "van"
+	ProviderID *int   `json:"provider_id"`             // Obrigatório para "van"
+}
+
+// StockLocationResponse representa um local de estoque na resposta
+type StockLocationResponse struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	ProviderID *int      `json:"provider_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StockMovementRequest representa uma entrada, saída ou ajuste manual de estoque
+type StockMovementRequest struct {
+	ItemID     int    `json:"item_id" binding:"required"`
+	LocationID int    `json:"location_id" binding:"required"`
+	Kind       string `json:"kind" binding:"required"` // "in", "out" ou "adjust"
+	Quantity   int    `json:"quantity"`                // Positiva para in/out; com sinal para adjust
+	Note       string `json:"note"`
+}
+
+// StockTransferRequest representa a transferência de um item entre locais
+type StockTransferRequest struct {
+	ItemID         int    `json:"item_id" binding:"required"`
+	FromLocationID int    `json:"from_location_id" binding:"required"`
+	ToLocationID   int    `json:"to_location_id" binding:"required"`
+	Quantity       int    `json:"quantity" binding:"required,min=1"`
+	Note           string `json:"note"`
+}
+
+// StockMovementResponse representa uma movimentação de estoque na resposta
+type StockMovementResponse struct {
+	ID           int       `json:"id"`
+	ItemID       int       `json:"item_id"`
+	LocationID   int       `json:"location_id"`
+	LocationName string    `json:"location_name"`
+	Kind         string    `json:"kind"`
+	Quantity     int       `json:"quantity"`
+	TicketID     *int      `json:"ticket_id,omitempty"`
+	Note         string    `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SolutionPartRequest representa um item da lista de materiais de uma solution
+type SolutionPartRequest struct {
+	ItemID   int `json:"item_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// SolutionPartsRequest substitui a lista de materiais de uma solution
+type SolutionPartsRequest struct {
+	Parts []SolutionPartRequest `json:"parts" binding:"dive"`
+}
+
+// SolutionPartResponse representa um item da lista de materiais na resposta
+type SolutionPartResponse struct {
+	ItemID   int    `json:"item_id"`
+	ItemSKU  string `json:"item_sku"`
+	ItemName string `json:"item_name"`
+	Quantity int    `json:"quantity"`
+}
+
+// StockReservationResponse representa uma reserva de peças de um ticket
+type StockReservationResponse struct {
+	ID           int       `json:"id"`
+	TicketID     int       `json:"ticket_id"`
+	SolutionID   int       `json:"solution_id"`
+	ItemID       int       `json:"item_id"`
+	ItemName     string    `json:"item_name"`
+	LocationID   int       `json:"location_id"`
+	LocationName string    `json:"location_name"`
+	Quantity     int       `json:"quantity"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LowStockResponse representa um item abaixo do estoque mínimo
+type LowStockResponse struct {
+	ItemID      int    `json:"item_id"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Unit        string `json:"unit"`
+	MinQuantity int    `json:"min_quantity"`
+	Quantity    int    `json:"quantity"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+	Shortage    int    `json:"shortage"`
+}
+
+// ToInventoryItemResponse converte domain para DTO
+func ToInventoryItemResponse(item *domain.InventoryItem, levels []domain.StockLevel) *InventoryItemResponse {
+	if item == nil {
+		return nil
+	}
+
+	response := &InventoryItemResponse{
+		ID:          item.ID,
+		SKU:         item.SKU,
+		Name:        item.Name,
+		Unit:        item.Unit,
+		MinQuantity: item.MinQuantity,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+
+	for _, level := range levels {
+		response.Levels = append(response.Levels, StockLevelResponse{
+			LocationID:   level.LocationID,
+			LocationName: level.LocationName,
+			Quantity:     level.Quantity,
+			Reserved:     level.Reserved,
+			Available:    level.Available(),
+		})
+	}
+
+	return response
+}
+
+// ToInventoryItemResponseList converte lista de domain para DTO
+func ToInventoryItemResponseList(items []domain.InventoryItem) []InventoryItemResponse {
+	responses := make([]InventoryItemResponse, 0, len(items))
+	for i := range items {
+		responses = append(responses, *ToInventoryItemResponse(&items[i], nil))
+	}
+	return responses
+}
+
+// ToStockLocationResponse converte domain para DTO
+func ToStockLocationResponse(location *domain.StockLocation) *StockLocationResponse {
+	if location == nil {
+		return nil
+	}
+
+	return &StockLocationResponse{
+		ID:         location.ID,
+		Name:       location.Name,
+		Kind:       location.Kind,
+		ProviderID: location.ProviderID,
+		CreatedAt:  location.CreatedAt,
+	}
+}
+
+// ToStockLocationResponseList converte lista de domain para DTO
+func ToStockLocationResponseList(locations []domain.StockLocation) []StockLocationResponse {
+	responses := make([]StockLocationResponse, 0, len(locations))
+	for i := range locations {
+		responses = append(responses, *ToStockLocationResponse(&locations[i]))
+	}
+	return responses
+}
+
+// ToStockMovementResponseList converte lista de domain para DTO
+func ToStockMovementResponseList(movements []domain.StockMovement) []StockMovementResponse {
+	responses := make([]StockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		responses = append(responses, StockMovementResponse{
+			ID:           movement.ID,
+			ItemID:       movement.ItemID,
+			LocationID:   movement.LocationID,
+			LocationName: movement.LocationName,
+			Kind:         movement.Kind,
+			Quantity:     movement.Quantity,
+			TicketID:     movement.TicketID,
+			Note:         movement.Note,
+			CreatedAt:    movement.CreatedAt,
+		})
+	}
+	return responses
+}
+
+// ToSolutionPartResponseList converte lista de domain para DTO
+func ToSolutionPartResponseList(parts []domain.SolutionPart) []SolutionPartResponse {
+	responses := make([]SolutionPartResponse, 0, len(parts))
+	for _, part := range parts {
+		responses = append(responses, SolutionPartResponse{
+			ItemID:   part.ItemID,
+			ItemSKU:  part.ItemSKU,
+			ItemName: part.ItemName,
+			Quantity: part.Quantity,
+		})
+	}
+	return responses
+}
+
+// ToStockReservationResponseList converte lista de domain para DTO
+func ToStockReservationResponseList(reservations []domain.StockReservation) []StockReservationResponse {
+	responses := make([]StockReservationResponse, 0, len(reservations))
+	for _, reservation := range reservations {
+		responses = append(responses, StockReservationResponse{
+			ID:           reservation.ID,
+			TicketID:     reservation.TicketID,
+			SolutionID:   reservation.SolutionID,
+			ItemID:       reservation.ItemID,
+			ItemName:     reservation.ItemName,
+			LocationID:   reservation.LocationID,
+			LocationName: reservation.LocationName,
+			Quantity:     reservation.Quantity,
+			Status:       reservation.Status,
+			CreatedAt:    reservation.CreatedAt,
+		})
+	}
+	return responses
+}
+
+// ToLowStockResponseList converte lista de domain para DTO
+func ToLowStockResponseList(items []domain.LowStockItem) []LowStockResponse {
+	responses := make([]LowStockResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, LowStockResponse{
+			ItemID:      item.Item.ID,
+			SKU:         item.Item.SKU,
+			Name:        item.Item.Name,
+			Unit:        item.Item.Unit,
+			MinQuantity: item.Item.MinQuantity,
+			Quantity:    item.Quantity,
+			Reserved:    item.Reserved,
+			Available:   item.Available,
+			Shortage:    item.Item.MinQuantity - item.Available,
+		})
+	}
+	return responses
+}
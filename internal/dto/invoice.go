@@ -0,0 +1,143 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// InvoiceRequest representa a requisição para gerar a fatura de um cliente no ciclo de faturamento
+type InvoiceRequest struct {
+	ClientID    int    `json:"client_id" binding:"required"`
+	PeriodStart string `json:"period_start" binding:"required,datetime=2006-01-02"` // Formato "2006-01-02"
+	PeriodEnd   string `json:"period_end" binding:"required,datetime=2006-01-02"`   // Formato "2006-01-02", inclusivo
+	Notes       string `json:"notes"`
+}
+
+// ToInvoiceDomain converte a requisição no domínio (datas já validadas pelo binding)
+func (r *InvoiceRequest) ToInvoiceDomain() *domain.Invoice {
+	invoice := &domain.Invoice{
+		ClientID: r.ClientID,
+		Notes:    r.Notes,
+	}
+	if start := parseDate(&r.PeriodStart); start != nil {
+		invoice.PeriodStart = *start
+	}
+	if end := parseDate(&r.PeriodEnd); end != nil {
+		invoice.PeriodEnd = *end
+	}
+
+	return invoice
+}
+
+// InvoiceCancelRequest representa o cancelamento da NFS-e
+type InvoiceCancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// InvoiceTicketResponse representa um ticket faturado na resposta
+type InvoiceTicketResponse struct {
+	TicketID   int        `json:"ticket_id"`
+	Number     string     `json:"number"`
+	BranchName string     `json:"branch_name"`
+	CloseDate  *time.Time `json:"close_date,omitempty"`
+}
+
+// InvoiceLineResponse representa um item da fatura na resposta
+type InvoiceLineResponse struct {
+	ID          int          `json:"id"`
+	Kind        string       `json:"kind"`
+	TicketID    int          `json:"ticket_id"`
+	VisitID     *int         `json:"visit_id,omitempty"`
+	Description string       `json:"description"`
+	Quantity    int          `json:"quantity"`
+	UnitAmount  domain.Money `json:"unit_amount"`
+	Amount      domain.Money `json:"amount"`
+}
+
+// InvoiceResponse representa uma fatura na resposta (o XML fica em /xml)
+type InvoiceResponse struct {
+	ID               int                     `json:"id"`
+	ClientID         int                     `json:"client_id"`
+	ClientName       string                  `json:"client_name"`
+	ClientDocument   string                  `json:"client_document"`
+	PeriodStart      time.Time               `json:"period_start"`
+	PeriodEnd        time.Time               `json:"period_end"`
+	Status           string                  `json:"status"`
+	Notes            string                  `json:"notes"`
+	Tickets          []InvoiceTicketResponse `json:"tickets"`
+	Lines            []InvoiceLineResponse   `json:"lines"`
+	ServicesTotal    domain.Money            `json:"services_total"`
+	TravelTotal      domain.Money            `json:"travel_total"`
+	Total            domain.Money            `json:"total"`
+	Issuer           string                  `json:"issuer,omitempty"`
+	Number           string                  `json:"number,omitempty"`
+	VerificationCode string                  `json:"verification_code,omitempty"`
+	IssuedAt         *time.Time              `json:"issued_at,omitempty"`
+	CancelledAt      *time.Time              `json:"cancelled_at,omitempty"`
+	CancelReason     string                  `json:"cancel_reason,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+}
+
+func ToInvoiceResponse(invoice *domain.Invoice) *InvoiceResponse {
+	if invoice == nil {
+		return nil
+	}
+
+	tickets := make([]InvoiceTicketResponse, 0, len(invoice.Tickets))
+	for _, ticket := range invoice.Tickets {
+		tickets = append(tickets, InvoiceTicketResponse{
+			TicketID:   ticket.TicketID,
+			Number:     ticket.Number,
+			BranchName: ticket.BranchName,
+			CloseDate:  ticket.CloseDate,
+		})
+	}
+
+	lines := make([]InvoiceLineResponse, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		lines = append(lines, InvoiceLineResponse{
+			ID:          line.ID,
+			Kind:        line.Kind,
+			TicketID:    line.TicketID,
+			VisitID:     line.VisitID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+		})
+	}
+
+	return &InvoiceResponse{
+		ID:               invoice.ID,
+		ClientID:         invoice.ClientID,
+		ClientName:       invoice.ClientName,
+		ClientDocument:   invoice.ClientDocument,
+		PeriodStart:      invoice.PeriodStart,
+		PeriodEnd:        invoice.PeriodEnd,
+		Status:           invoice.Status,
+		Notes:            invoice.Notes,
+		Tickets:          tickets,
+		Lines:            lines,
+		ServicesTotal:    invoice.ServicesTotal(),
+		TravelTotal:      invoice.TravelTotal(),
+		Total:            invoice.Total(),
+		Issuer:           invoice.Issuer,
+		Number:           invoice.Number,
+		VerificationCode: invoice.VerificationCode,
+		IssuedAt:         invoice.IssuedAt,
+		CancelledAt:      invoice.CancelledAt,
+		CancelReason:     invoice.CancelReason,
+		CreatedAt:        invoice.CreatedAt,
+		UpdatedAt:        invoice.UpdatedAt,
+	}
+}
+
+func ToInvoiceResponseList(invoices []domain.Invoice) []InvoiceResponse {
+	responses := make([]InvoiceResponse, 0, len(invoices))
+	for i := range invoices {
+		responses = append(responses, *ToInvoiceResponse(&invoices[i]))
+	}
+	return responses
+}
@@ -25,6 +25,14 @@ func (r *ProblemRequest) ToProblemDomain() *domain.Problem {
 	}
 }
 
+// ToProblemRequest monta o request com os dados atuais do problema, base do PATCH
+func ToProblemRequest(problem *domain.Problem) *ProblemRequest {
+	return &ProblemRequest{
+		Name:        problem.Name,
+		Description: problem.Description,
+	}
+}
+
 // ToProblemResponse converte domain para DTO
 func ToProblemResponse(problem *domain.Problem) *ProblemResponse {
 	if problem == nil {
@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
 type ProviderRequest struct {
 	Name         string `json:"name" binding:"required"`
 	Mobile       string `json:"mobile" binding:"required"`
@@ -11,6 +17,20 @@ type ProviderRequest struct {
 	Complement   string `json:"complement"`
 }
 
+// ToProviderRequest monta o request com os dados atuais do prestador, base do PATCH
+func ToProviderRequest(provider *domain.Provider) *ProviderRequest {
+	return &ProviderRequest{
+		Name:         provider.Name,
+		Mobile:       provider.Mobile,
+		Zipcode:      provider.Zipcode,
+		State:        provider.State,
+		City:         provider.City,
+		Neighborhood: provider.Neighborhood,
+		Address:      provider.Address,
+		Complement:   provider.Complement,
+	}
+}
+
 type ProviderResponse struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
@@ -21,6 +41,7 @@ type ProviderResponse struct {
 	Neighborhood string `json:"neighborhood"`
 	Address      string `json:"address"`
 	Complement   string `json:"complement"`
+	Version      int    `json:"version"` // Também enviada no header ETag
 }
 
 type ProviderSummaryResponse struct {
@@ -28,3 +49,18 @@ type ProviderSummaryResponse struct {
 	Name    string `json:"name"`
 	Zipcode string `json:"zipcode"`
 }
+
+func ToProviderResponse(provider *domain.Provider) ProviderResponse {
+	return ProviderResponse{
+		ID:           strconv.Itoa(provider.ID),
+		Name:         provider.Name,
+		Mobile:       provider.Mobile,
+		Zipcode:      provider.Zipcode,
+		State:        provider.State,
+		City:         provider.City,
+		Neighborhood: provider.Neighborhood,
+		Address:      provider.Address,
+		Complement:   provider.Complement,
+		Version:      provider.Version,
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// DeclineTicketRequest contém o motivo da recusa da atribuição pelo técnico
+type DeclineTicketRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// TicketStatusRequest contém o novo status do ticket
+type TicketStatusRequest struct {
+	Status int `json:"status" binding:"required"`
+}
+
+// TimeEntryRequest registra o tempo em campo do técnico
+type TimeEntryRequest struct {
+	StartedAt string `json:"started_at" binding:"required"` // Formato "2006-01-02T15:04:05Z"
+	EndedAt   string `json:"ended_at" binding:"required"`   // Formato "2006-01-02T15:04:05Z"
+	Note      string `json:"note"`
+}
+
+// TimeEntryResponse representa um registro de tempo em campo
+type TimeEntryResponse struct {
+	ID         int       `json:"id"`
+	TicketID   int       `json:"ticket_id"`
+	ProviderID int       `json:"provider_id"`
+	StartedAt  time.Time `json:"started_at"`
+	EndedAt    time.Time `json:"ended_at"`
+	Minutes    int       `json:"minutes"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ToTimeEntryResponse converte domain para DTO
+func ToTimeEntryResponse(entry *domain.TicketTimeEntry) *TimeEntryResponse {
+	if entry == nil {
+		return nil
+	}
+
+	return &TimeEntryResponse{
+		ID:         entry.ID,
+		TicketID:   entry.TicketID,
+		ProviderID: entry.ProviderID,
+		StartedAt:  entry.StartedAt,
+		EndedAt:    entry.EndedAt,
+		Minutes:    entry.Minutes,
+		Note:       entry.Note,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+// ToTimeEntryResponseList converte lista de domain para DTO
+func ToTimeEntryResponseList(entries []domain.TicketTimeEntry) []TimeEntryResponse {
+	responses := make([]TimeEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = *ToTimeEntryResponse(&entry)
+	}
+	return responses
+}
@@ -0,0 +1,162 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// SupplierRequest representa a requisição para cadastrar ou atualizar um fornecedor
+type SupplierRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Document string `json:"document"` // CNPJ
+	Email    string `json:"email" binding:"omitempty,email"`
+	Phone    string `json:"phone"`
+}
+
+// SupplierResponse representa um fornecedor na resposta
+type SupplierResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Document  string    `json:"document"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PurchaseItemRequest representa um item cotado
+type PurchaseItemRequest struct {
+	ItemID    int          `json:"item_id" binding:"required"`
+	Quantity  int          `json:"quantity" binding:"required,min=1"`
+	UnitPrice domain.Money `json:"unit_price" binding:"min=0"`
+}
+
+// PurchaseRequestRequest representa a requisição para abrir uma compra para um ticket
+type PurchaseRequestRequest struct {
+	TicketID   int                   `json:"ticket_id" binding:"required"`
+	SupplierID int                   `json:"supplier_id" binding:"required"`
+	Notes      string                `json:"notes"`
+	Items      []PurchaseItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdatePurchaseRequestRequest representa a requisição para revisar a cotação de uma compra pendente
+type UpdatePurchaseRequestRequest struct {
+	SupplierID int                   `json:"supplier_id" binding:"required"`
+	Notes      string                `json:"notes"`
+	Items      []PurchaseItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// PurchaseDecisionRequest representa a aprovação ou rejeição pelo Financeiro
+type PurchaseDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// PurchaseReceiveRequest representa o recebimento das peças compradas
+type PurchaseReceiveRequest struct {
+	LocationID *int `json:"location_id"` // Opcional, padrão: almoxarifado central
+}
+
+// PurchaseItemResponse representa um item cotado na resposta
+type PurchaseItemResponse struct {
+	ID        int          `json:"id"`
+	ItemID    int          `json:"item_id"`
+	ItemSKU   string       `json:"item_sku"`
+	ItemName  string       `json:"item_name"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice domain.Money `json:"unit_price"`
+	Subtotal  domain.Money `json:"subtotal"`
+}
+
+// PurchaseRequestResponse representa uma compra na resposta
+type PurchaseRequestResponse struct {
+	ID           int                    `json:"id"`
+	TicketID     int                    `json:"ticket_id"`
+	TicketNumber string                 `json:"ticket_number"`
+	SupplierID   int                    `json:"supplier_id"`
+	SupplierName string                 `json:"supplier_name"`
+	Status       string                 `json:"status"`
+	Notes        string                 `json:"notes"`
+	Items        []PurchaseItemResponse `json:"items"`
+	Total        domain.Money           `json:"total"`
+	ApprovedBy   *int                   `json:"approved_by,omitempty"`
+	DecidedAt    *time.Time             `json:"decided_at,omitempty"`
+	DecisionNote string                 `json:"decision_note"`
+	LocationID   *int                   `json:"location_id,omitempty"`
+	ReceivedAt   *time.Time             `json:"received_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// ToSupplierResponse converte domain para DTO
+func ToSupplierResponse(supplier *domain.Supplier) *SupplierResponse {
+	if supplier == nil {
+		return nil
+	}
+
+	return &SupplierResponse{
+		ID:        supplier.ID,
+		Name:      supplier.Name,
+		Document:  supplier.Document,
+		Email:     supplier.Email,
+		Phone:     supplier.Phone,
+		CreatedAt: supplier.CreatedAt,
+	}
+}
+
+// ToSupplierResponseList converte lista de domain para DTO
+func ToSupplierResponseList(suppliers []domain.Supplier) []SupplierResponse {
+	responses := make([]SupplierResponse, 0, len(suppliers))
+	for i := range suppliers {
+		responses = append(responses, *ToSupplierResponse(&suppliers[i]))
+	}
+	return responses
+}
+
+// ToPurchaseRequestResponse converte domain para DTO
+func ToPurchaseRequestResponse(purchase *domain.PurchaseRequest) *PurchaseRequestResponse {
+	if purchase == nil {
+		return nil
+	}
+
+	items := make([]PurchaseItemResponse, 0, len(purchase.Items))
+	for i := range purchase.Items {
+		item := &purchase.Items[i]
+		items = append(items, PurchaseItemResponse{
+			ID:        item.ID,
+			ItemID:    item.ItemID,
+			ItemSKU:   item.ItemSKU,
+			ItemName:  item.ItemName,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Subtotal:  item.Subtotal(),
+		})
+	}
+
+	return &PurchaseRequestResponse{
+		ID:           purchase.ID,
+		TicketID:     purchase.TicketID,
+		TicketNumber: purchase.TicketNumber,
+		SupplierID:   purchase.SupplierID,
+		SupplierName: purchase.SupplierName,
+		Status:       purchase.Status,
+		Notes:        purchase.Notes,
+		Items:        items,
+		Total:        purchase.Total(),
+		ApprovedBy:   purchase.ApprovedBy,
+		DecidedAt:    purchase.DecidedAt,
+		DecisionNote: purchase.DecisionNote,
+		LocationID:   purchase.LocationID,
+		ReceivedAt:   purchase.ReceivedAt,
+		CreatedAt:    purchase.CreatedAt,
+		UpdatedAt:    purchase.UpdatedAt,
+	}
+}
+
+// ToPurchaseRequestResponseList converte lista de domain para DTO
+func ToPurchaseRequestResponseList(purchases []domain.PurchaseRequest) []PurchaseRequestResponse {
+	responses := make([]PurchaseRequestResponse, 0, len(purchases))
+	for i := range purchases {
+		responses = append(responses, *ToPurchaseRequestResponse(&purchases[i]))
+	}
+	return responses
+}
@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// QuoteRequest representa a geração de uma nova versão do orçamento do ticket
+type QuoteRequest struct {
+	Notes string `json:"notes"`
+}
+
+// QuoteSendRequest representa o envio do link de aprovação ao cliente.
+// Sem destinatários, apenas gera o link para ser repassado pela equipe.
+type QuoteSendRequest struct {
+	Recipients []string `json:"recipients" binding:"omitempty,dive,email"`
+}
+
+// QuoteDecisionRequest representa a aprovação ou rejeição do orçamento no portal do cliente
+type QuoteDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// QuoteLinkDecisionRequest representa a aprovação ou rejeição pelo link assinado, sem login
+type QuoteLinkDecisionRequest struct {
+	Name string `json:"name" binding:"required"` // Nome de quem aprova ou rejeita
+	Note string `json:"note"`
+}
+
+// QuoteLinkResponse representa o link de aprovação gerado para o orçamento
+type QuoteLinkResponse struct {
+	QuoteID   int       `json:"quote_id"`
+	Version   int       `json:"version"`
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SentTo    []string  `json:"sent_to"`
+}
+
+// QuoteItemResponse representa uma solution planejada no orçamento
+type QuoteItemResponse struct {
+	SolutionID   *int         `json:"solution_id,omitempty"`
+	ProblemName  string       `json:"problem_name"`
+	SolutionName string       `json:"solution_name"`
+	Quantity     int          `json:"quantity"`
+	UnitPrice    domain.Money `json:"unit_price"`
+	Subtotal     domain.Money `json:"subtotal"`
+	Warranty     bool         `json:"warranty"`
+}
+
+// QuoteResponse representa uma versão do orçamento na resposta
+type QuoteResponse struct {
+	ID              int                 `json:"id"`
+	TicketID        int                 `json:"ticket_id"`
+	TicketNumber    string              `json:"ticket_number"`
+	Version         int                 `json:"version"`
+	Status          string              `json:"status"`
+	Notes           string              `json:"notes"`
+	Items           []QuoteItemResponse `json:"items"`
+	ServicesTotal   domain.Money        `json:"services_total"`
+	TravelCost      domain.Money        `json:"travel_cost"`
+	Total           domain.Money        `json:"total"`
+	SentTo          []string            `json:"sent_to"`
+	SentAt          *time.Time          `json:"sent_at,omitempty"`
+	DecidedAt       *time.Time          `json:"decided_at,omitempty"`
+	DecidedBy       string              `json:"decided_by,omitempty"`
+	DecidedByUserID *int                `json:"decided_by_user_id,omitempty"`
+	DecisionChannel string              `json:"decision_channel,omitempty"`
+	DecisionNote    string              `json:"decision_note,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+func ToQuoteResponse(quote *domain.Quote) *QuoteResponse {
+	if quote == nil {
+		return nil
+	}
+
+	items := make([]QuoteItemResponse, 0, len(quote.Items))
+	for _, item := range quote.Items {
+		items = append(items, QuoteItemResponse{
+			SolutionID:   item.SolutionID,
+			ProblemName:  item.ProblemName,
+			SolutionName: item.SolutionName,
+			Quantity:     item.Quantity,
+			UnitPrice:    item.UnitPrice,
+			Subtotal:     item.Subtotal,
+			Warranty:     item.Warranty,
+		})
+	}
+
+	sentTo := quote.SentTo
+	if sentTo == nil {
+		sentTo = []string{}
+	}
+
+	return &QuoteResponse{
+		ID:              quote.ID,
+		TicketID:        quote.TicketID,
+		TicketNumber:    quote.TicketNumber,
+		Version:         quote.Version,
+		Status:          quote.Status,
+		Notes:           quote.Notes,
+		Items:           items,
+		ServicesTotal:   quote.ServicesTotal,
+		TravelCost:      quote.TravelCost,
+		Total:           quote.Total,
+		SentTo:          sentTo,
+		SentAt:          quote.SentAt,
+		DecidedAt:       quote.DecidedAt,
+		DecidedBy:       quote.DecidedBy,
+		DecidedByUserID: quote.DecidedByUserID,
+		DecisionChannel: quote.DecisionChannel,
+		DecisionNote:    quote.DecisionNote,
+		CreatedAt:       quote.CreatedAt,
+	}
+}
+
+func ToQuoteResponseList(quotes []domain.Quote) []QuoteResponse {
+	responses := make([]QuoteResponse, 0, len(quotes))
+	for i := range quotes {
+		responses = append(responses, *ToQuoteResponse(&quotes[i]))
+	}
+	return responses
+}
@@ -0,0 +1,149 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ReportScheduleRequest representa o agendamento (weekday 0 = domingo para weekly, day 1 a 28 para monthly)
+type ReportScheduleRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	Weekday   int    `json:"weekday" binding:"min=0,max=6"`
+	Day       int    `json:"day" binding:"min=0,max=28"`
+	Hour      int    `json:"hour" binding:"min=0,max=23"`
+}
+
+// ReportDefinitionRequest representa a criação ou atualização de um relatório agendado
+type ReportDefinitionRequest struct {
+	Name       string                `json:"name" binding:"required"`
+	Type       string                `json:"type" binding:"required,oneof=costs client_tickets"`
+	ClientID   *int                  `json:"client_id"` // Obrigatório em client_tickets
+	Format     string                `json:"format" binding:"required,oneof=csv xlsx pdf"`
+	Recipients []string              `json:"recipients" binding:"required,min=1,dive,email"`
+	Schedule   ReportScheduleRequest `json:"schedule"`
+	Active     *bool                 `json:"active"` // Padrão true
+}
+
+func (r *ReportDefinitionRequest) ToReportDefinitionDomain() *domain.ReportDefinition {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+
+	return &domain.ReportDefinition{
+		Name:       r.Name,
+		Type:       r.Type,
+		ClientID:   r.ClientID,
+		Format:     r.Format,
+		Recipients: r.Recipients,
+		Schedule: domain.ReportSchedule{
+			Frequency: r.Schedule.Frequency,
+			Weekday:   r.Schedule.Weekday,
+			Day:       r.Schedule.Day,
+			Hour:      r.Schedule.Hour,
+		},
+		Active: active,
+	}
+}
+
+// ReportDefinitionResponse representa um relatório agendado na resposta
+type ReportDefinitionResponse struct {
+	ID         int                   `json:"id"`
+	Name       string                `json:"name"`
+	Type       string                `json:"type"`
+	ClientID   *int                  `json:"client_id"`
+	Format     string                `json:"format"`
+	Recipients []string              `json:"recipients"`
+	Schedule   domain.ReportSchedule `json:"schedule"`
+	Active     bool                  `json:"active"`
+	NextRunAt  time.Time             `json:"next_run_at"`
+	LastRunAt  *time.Time            `json:"last_run_at"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+func ToReportDefinitionResponse(definition *domain.ReportDefinition) *ReportDefinitionResponse {
+	if definition == nil {
+		return nil
+	}
+
+	recipients := definition.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+
+	return &ReportDefinitionResponse{
+		ID:         definition.ID,
+		Name:       definition.Name,
+		Type:       definition.Type,
+		ClientID:   definition.ClientID,
+		Format:     definition.Format,
+		Recipients: recipients,
+		Schedule:   definition.Schedule,
+		Active:     definition.Active,
+		NextRunAt:  definition.NextRunAt,
+		LastRunAt:  definition.LastRunAt,
+		CreatedAt:  definition.CreatedAt,
+		UpdatedAt:  definition.UpdatedAt,
+	}
+}
+
+func ToReportDefinitionResponseList(definitions []domain.ReportDefinition) []ReportDefinitionResponse {
+	responses := make([]ReportDefinitionResponse, 0, len(definitions))
+	for i := range definitions {
+		responses = append(responses, *ToReportDefinitionResponse(&definitions[i]))
+	}
+	return responses
+}
+
+// ReportFileResponse representa um arquivo gerado na resposta
+type ReportFileResponse struct {
+	ID           int       `json:"id"`
+	DefinitionID int       `json:"definition_id"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	Format       string    `json:"format"`
+	FileName     string    `json:"file_name"`
+	Size         int64     `json:"size"`
+	Recipients   []string  `json:"recipients"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	DownloadURL  string    `json:"download_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func ToReportFileResponse(file *domain.ReportFile) *ReportFileResponse {
+	if file == nil {
+		return nil
+	}
+
+	recipients := file.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+
+	return &ReportFileResponse{
+		ID:           file.ID,
+		DefinitionID: file.DefinitionID,
+		PeriodStart:  file.PeriodStart,
+		PeriodEnd:    file.PeriodEnd,
+		Format:       file.Format,
+		FileName:     file.FileName,
+		Size:         file.Size,
+		Recipients:   recipients,
+		Status:       file.Status,
+		Error:        file.Error,
+		DownloadURL:  fmt.Sprintf("/api/v1/scheduled-reports/%d/files/%d", file.DefinitionID, file.ID),
+		CreatedAt:    file.CreatedAt,
+	}
+}
+
+func ToReportFileResponseList(files []domain.ReportFile) []ReportFileResponse {
+	responses := make([]ReportFileResponse, 0, len(files))
+	for i := range files {
+		responses = append(responses, *ToReportFileResponse(&files[i]))
+	}
+	return responses
+}
@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ShipmentItemRequest representa um equipamento ou peça do envio
+type ShipmentItemRequest struct {
+	ItemID       *int   `json:"item_id"`     // Opcional, item do estoque
+	Description  string `json:"description"` // Obrigatório sem item_id
+	SerialNumber string `json:"serial_number"`
+	Quantity     int    `json:"quantity" binding:"min=0"` // Padrão: 1
+}
+
+// ShipmentRequest representa a requisição para registrar um envio do ticket
+type ShipmentRequest struct {
+	TicketID       int                   `json:"ticket_id" binding:"required"`
+	Direction      string                `json:"direction" binding:"required"` // "outbound" ou "return"
+	Carrier        string                `json:"carrier"`                      // Padrão: transportadora integrada
+	TrackingNumber string                `json:"tracking_number"`
+	Notes          string                `json:"notes"`
+	Items          []ShipmentItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdateShipmentRequest representa a requisição para atualizar transportadora e rastreio
+type UpdateShipmentRequest struct {
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+	Notes          string `json:"notes"`
+}
+
+// ShipmentEventRequest representa um evento de rastreio registrado manualmente
+type ShipmentEventRequest struct {
+	Status      string  `json:"status" binding:"required"`
+	Description string  `json:"description"`
+	Location    string  `json:"location"`
+	OccurredAt  *string `json:"occurred_at"` // Opcional, RFC3339. Padrão: agora
+}
+
+// ShipmentItemResponse representa um item do envio na resposta
+type ShipmentItemResponse struct {
+	ID           int    `json:"id"`
+	ItemID       *int   `json:"item_id,omitempty"`
+	Description  string `json:"description"`
+	SerialNumber string `json:"serial_number"`
+	Quantity     int    `json:"quantity"`
+}
+
+// ShipmentEventResponse representa um evento de rastreio na resposta
+type ShipmentEventResponse struct {
+	ID          int       `json:"id"`
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	Source      string    `json:"source"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// ShipmentResponse representa um envio na resposta
+type ShipmentResponse struct {
+	ID             int                     `json:"id"`
+	TicketID       int                     `json:"ticket_id"`
+	TicketNumber   string                  `json:"ticket_number"`
+	Direction      string                  `json:"direction"`
+	Carrier        string                  `json:"carrier"`
+	TrackingNumber string                  `json:"tracking_number"`
+	Status         string                  `json:"status"`
+	Notes          string                  `json:"notes"`
+	ShippedAt      *time.Time              `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time              `json:"delivered_at,omitempty"`
+	Items          []ShipmentItemResponse  `json:"items"`
+	Events         []ShipmentEventResponse `json:"events"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// ToShipmentResponse converte domain para DTO
+func ToShipmentResponse(shipment *domain.Shipment) *ShipmentResponse {
+	if shipment == nil {
+		return nil
+	}
+
+	items := make([]ShipmentItemResponse, 0, len(shipment.Items))
+	for _, item := range shipment.Items {
+		items = append(items, ShipmentItemResponse{
+			ID:           item.ID,
+			ItemID:       item.ItemID,
+			Description:  item.Description,
+			SerialNumber: item.SerialNumber,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	events := make([]ShipmentEventResponse, 0, len(shipment.Events))
+	for _, event := range shipment.Events {
+		events = append(events, ShipmentEventResponse{
+			ID:          event.ID,
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    event.Location,
+			Source:      event.Source,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+
+	return &ShipmentResponse{
+		ID:             shipment.ID,
+		TicketID:       shipment.TicketID,
+		TicketNumber:   shipment.TicketNumber,
+		Direction:      shipment.Direction,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		Notes:          shipment.Notes,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		Items:          items,
+		Events:         events,
+		CreatedAt:      shipment.CreatedAt,
+		UpdatedAt:      shipment.UpdatedAt,
+	}
+}
+
+// ToShipmentResponseList converte lista de domain para DTO
+func ToShipmentResponseList(shipments []domain.Shipment) []ShipmentResponse {
+	responses := make([]ShipmentResponse, 0, len(shipments))
+	for i := range shipments {
+		responses = append(responses, *ToShipmentResponse(&shipments[i]))
+	}
+	return responses
+}
@@ -4,28 +4,42 @@ import "github.com/ericolvr/maintenance-v2/internal/domain"
 
 // SolutionRequest representa uma requisição para criar/atualizar solução
 type SolutionRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	UnitPrice   float64 `json:"unit_price" binding:"required"`
-	ProblemID   int     `json:"problem_id" binding:"required"`
+	Name         string       `json:"name" binding:"required"`
+	Description  string       `json:"description"`
+	UnitPrice    domain.Money `json:"unit_price" binding:"required"`
+	ProblemID    int          `json:"problem_id" binding:"required"`
+	WarrantyDays int          `json:"warranty_days" binding:"min=0"`
 }
 
 // SolutionResponse representa uma solução na resposta
 type SolutionResponse struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	UnitPrice   float64 `json:"unit_price"`
-	ProblemID   int     `json:"problem_id"`
+	ID           int          `json:"id"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	UnitPrice    domain.Money `json:"unit_price"`
+	ProblemID    int          `json:"problem_id"`
+	WarrantyDays int          `json:"warranty_days"`
 }
 
 // ToSolutionDomain converte DTO para domain
 func (r *SolutionRequest) ToSolutionDomain() *domain.Solution {
 	return &domain.Solution{
-		Name:        r.Name,
-		Description: r.Description,
-		UnitPrice:   r.UnitPrice,
-		ProblemID:   r.ProblemID,
+		Name:         r.Name,
+		Description:  r.Description,
+		UnitPrice:    r.UnitPrice,
+		ProblemID:    r.ProblemID,
+		WarrantyDays: r.WarrantyDays,
+	}
+}
+
+// ToSolutionRequest monta o request com os dados atuais da solução, base do PATCH
+func ToSolutionRequest(solution *domain.Solution) *SolutionRequest {
+	return &SolutionRequest{
+		Name:         solution.Name,
+		Description:  solution.Description,
+		UnitPrice:    solution.UnitPrice,
+		ProblemID:    solution.ProblemID,
+		WarrantyDays: solution.WarrantyDays,
 	}
 }
 
@@ -36,11 +50,12 @@ func ToSolutionResponse(solution *domain.Solution) *SolutionResponse {
 	}
 	
 	return &SolutionResponse{
-		ID:          solution.ID,
-		Name:        solution.Name,
-		Description: solution.Description,
-		UnitPrice:   solution.UnitPrice,
-		ProblemID:   solution.ProblemID,
+		ID:           solution.ID,
+		Name:         solution.Name,
+		Description:  solution.Description,
+		UnitPrice:    solution.UnitPrice,
+		ProblemID:    solution.ProblemID,
+		WarrantyDays: solution.WarrantyDays,
 	}
 }
 
@@ -0,0 +1,190 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// StatementRequest representa a requisição para gerar o extrato de um técnico no período
+type StatementRequest struct {
+	ProviderID  int    `json:"provider_id" binding:"required"`
+	PeriodStart string `json:"period_start" binding:"required,datetime=2006-01-02"` // Formato "2006-01-02"
+	PeriodEnd   string `json:"period_end" binding:"required,datetime=2006-01-02"`   // Formato "2006-01-02", inclusivo
+	Notes       string `json:"notes"`
+}
+
+// ToStatementDomain converte a requisição no domínio (datas já validadas pelo binding)
+func (r *StatementRequest) ToStatementDomain() *domain.ProviderStatement {
+	statement := &domain.ProviderStatement{
+		ProviderID: r.ProviderID,
+		Notes:      r.Notes,
+	}
+	if start := parseDate(&r.PeriodStart); start != nil {
+		statement.PeriodStart = *start
+	}
+	if end := parseDate(&r.PeriodEnd); end != nil {
+		statement.PeriodEnd = *end
+	}
+
+	return statement
+}
+
+// StatementDeductionRequest representa um desconto lançado no extrato
+type StatementDeductionRequest struct {
+	Description string       `json:"description" binding:"required"`
+	Amount      domain.Money `json:"amount" binding:"required,gt=0"`
+	TicketID    *int         `json:"ticket_id"` // Opcional, ticket a que o desconto se refere
+}
+
+// StatementPaymentRequest representa a baixa do pagamento do extrato
+type StatementPaymentRequest struct {
+	Reference string `json:"reference"` // Comprovante ou identificação do pagamento no banco
+}
+
+// BankAccountRequest representa a conta bancária do técnico
+type BankAccountRequest struct {
+	HolderName   string `json:"holder_name" binding:"required"`
+	Document     string `json:"document" binding:"required"` // CPF ou CNPJ
+	BankCode     string `json:"bank_code" binding:"required,len=3,numeric"`
+	Agency       string `json:"agency" binding:"required,max=5,numeric"`
+	AgencyDigit  string `json:"agency_digit" binding:"max=1"`
+	Account      string `json:"account" binding:"required,max=12,numeric"`
+	AccountDigit string `json:"account_digit" binding:"max=1"`
+}
+
+// BankAccountResponse representa a conta bancária do técnico na resposta
+type BankAccountResponse struct {
+	ProviderID   int       `json:"provider_id"`
+	HolderName   string    `json:"holder_name"`
+	Document     string    `json:"document"`
+	BankCode     string    `json:"bank_code"`
+	Agency       string    `json:"agency"`
+	AgencyDigit  string    `json:"agency_digit"`
+	Account      string    `json:"account"`
+	AccountDigit string    `json:"account_digit"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StatementTicketResponse representa um ticket incluído no extrato
+type StatementTicketResponse struct {
+	TicketID   int        `json:"ticket_id"`
+	Number     string     `json:"number"`
+	BranchName string     `json:"branch_name"`
+	CloseDate  *time.Time `json:"close_date,omitempty"`
+}
+
+// StatementLineResponse representa um lançamento do extrato
+type StatementLineResponse struct {
+	ID          int          `json:"id"`
+	Kind        string       `json:"kind"`
+	TicketID    *int         `json:"ticket_id,omitempty"`
+	VisitID     *int         `json:"visit_id,omitempty"`
+	Description string       `json:"description"`
+	Quantity    int          `json:"quantity"`
+	UnitAmount  domain.Money `json:"unit_amount"`
+	Amount      domain.Money `json:"amount"`
+}
+
+// StatementResponse representa um extrato de pagamento na resposta
+type StatementResponse struct {
+	ID               int                       `json:"id"`
+	ProviderID       int                       `json:"provider_id"`
+	ProviderName     string                    `json:"provider_name"`
+	PeriodStart      time.Time                 `json:"period_start"`
+	PeriodEnd        time.Time                 `json:"period_end"`
+	Status           string                    `json:"status"`
+	Notes            string                    `json:"notes"`
+	Tickets          []StatementTicketResponse `json:"tickets"`
+	Lines            []StatementLineResponse   `json:"lines"`
+	TravelTotal      domain.Money              `json:"travel_total"`
+	LaborTotal       domain.Money              `json:"labor_total"`
+	ExpensesTotal    domain.Money              `json:"expenses_total"`
+	DeductionsTotal  domain.Money              `json:"deductions_total"`
+	Total            domain.Money              `json:"total"`
+	ApprovedBy       *int                      `json:"approved_by,omitempty"`
+	ApprovedAt       *time.Time                `json:"approved_at,omitempty"`
+	PaidAt           *time.Time                `json:"paid_at,omitempty"`
+	PaymentReference string                    `json:"payment_reference"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+}
+
+func ToStatementResponse(statement *domain.ProviderStatement) *StatementResponse {
+	if statement == nil {
+		return nil
+	}
+
+	tickets := make([]StatementTicketResponse, 0, len(statement.Tickets))
+	for _, ticket := range statement.Tickets {
+		tickets = append(tickets, StatementTicketResponse{
+			TicketID:   ticket.TicketID,
+			Number:     ticket.Number,
+			BranchName: ticket.BranchName,
+			CloseDate:  ticket.CloseDate,
+		})
+	}
+
+	lines := make([]StatementLineResponse, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		lines = append(lines, StatementLineResponse{
+			ID:          line.ID,
+			Kind:        line.Kind,
+			TicketID:    line.TicketID,
+			VisitID:     line.VisitID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+		})
+	}
+
+	return &StatementResponse{
+		ID:               statement.ID,
+		ProviderID:       statement.ProviderID,
+		ProviderName:     statement.ProviderName,
+		PeriodStart:      statement.PeriodStart,
+		PeriodEnd:        statement.PeriodEnd,
+		Status:           statement.Status,
+		Notes:            statement.Notes,
+		Tickets:          tickets,
+		Lines:            lines,
+		TravelTotal:      statement.TravelTotal(),
+		LaborTotal:       statement.LaborTotal(),
+		ExpensesTotal:    statement.ExpensesTotal(),
+		DeductionsTotal:  statement.DeductionsTotal(),
+		Total:            statement.Total(),
+		ApprovedBy:       statement.ApprovedBy,
+		ApprovedAt:       statement.ApprovedAt,
+		PaidAt:           statement.PaidAt,
+		PaymentReference: statement.PaymentReference,
+		CreatedAt:        statement.CreatedAt,
+		UpdatedAt:        statement.UpdatedAt,
+	}
+}
+
+func ToStatementResponseList(statements []domain.ProviderStatement) []StatementResponse {
+	responses := make([]StatementResponse, 0, len(statements))
+	for i := range statements {
+		responses = append(responses, *ToStatementResponse(&statements[i]))
+	}
+	return responses
+}
+
+func ToBankAccountResponse(account *domain.ProviderBankAccount) *BankAccountResponse {
+	if account == nil {
+		return nil
+	}
+
+	return &BankAccountResponse{
+		ProviderID:   account.ProviderID,
+		HolderName:   account.HolderName,
+		Document:     account.Document,
+		BankCode:     account.BankCode,
+		Agency:       account.Agency,
+		AgencyDigit:  account.AgencyDigit,
+		Account:      account.Account,
+		AccountDigit: account.AccountDigit,
+		UpdatedAt:    account.UpdatedAt,
+	}
+}
@@ -13,6 +13,7 @@ type TicketRequest struct {
 	Description string `json:"description" binding:"required"`
 	OpenDate    string `json:"open_date" binding:"required"` // Formato "2006-01-02T15:04:05Z"
 	BranchID    int    `json:"branch_id" binding:"required"`
+	AssetID     *int   `json:"asset_id,omitempty"`
 }
 
 type UpdateTicketRequest struct {
@@ -24,40 +25,52 @@ type UpdateTicketRequest struct {
 	CloseDate     *string               `json:"close_date,omitempty"`         // Opcional, formato "2006-01-02T15:04:05Z"
 	BranchID      int                   `json:"branch_id" binding:"required"`
 	ProviderID    int                   `json:"provider_id"`
-	SolutionItems []SolutionItemRequest `json:"solution_items,omitempty"`
+	AssetID       *int                  `json:"asset_id,omitempty"`
+	SolutionItems []SolutionItemRequest `json:"solution_items,omitempty"` // Ausente mantém os custos; [] remove todos
 }
 
 type TicketResponse struct {
-	ID           int                    `json:"id"`
-	Number       string                 `json:"number"`
-	Status       int                    `json:"status"`
-	Priority     string                 `json:"priority"`
-	Description  string                 `json:"description"`
-	OpenDate     time.Time              `json:"open_date"`
-	CloseDate    *time.Time             `json:"close_date,omitempty"`
-	BranchID     int                    `json:"branch_id"`
-	BranchName   string                 `json:"branch_name"`
-	BranchUniorg string                 `json:"branch_uniorg"`
-	ProviderID   *int                   `json:"provider_id,omitempty"`
-	ProviderName *string                `json:"provider_name,omitempty"`
-	Distance     *float64               `json:"distance,omitempty"`
-	Costs        []SolutionItemResponse `json:"costs,omitempty"`
-	TotalCost    float64                `json:"total_cost"`
+	ID               int                    `json:"id"`
+	Number           string                 `json:"number"`
+	Status           int                    `json:"status"`
+	Priority         string                 `json:"priority"`
+	Description      string                 `json:"description"`
+	OpenDate         time.Time              `json:"open_date"`
+	CloseDate        *time.Time             `json:"close_date,omitempty"`
+	BranchID         int                    `json:"branch_id"`
+	BranchName       string                 `json:"branch_name"`
+	BranchUniorg     string                 `json:"branch_uniorg"`
+	ProviderID       *int                   `json:"provider_id,omitempty"`
+	ProviderName     *string                `json:"provider_name,omitempty"`
+	AssetID          *int                   `json:"asset_id,omitempty"`
+	AssignmentStatus string                 `json:"assignment_status,omitempty"`
+	Distance         *float64               `json:"distance,omitempty"`
+	Visits           []VisitResponse        `json:"visits,omitempty"`
+	TravelCost       domain.Money           `json:"travel_cost"`
+	OnSiteMinutes    int                    `json:"on_site_minutes"`
+	CheckinAnomalies []string               `json:"checkin_anomalies,omitempty"`
+	Costs            []SolutionItemResponse `json:"costs,omitempty"`
+	TotalCost        domain.Money           `json:"total_cost"`
+	Version          int                    `json:"version"` // Também enviada no header ETag
 }
 
 // SolutionItemRequest representa um item de solução na requisição
 type SolutionItemRequest struct {
-	Description string  `json:"description" binding:"required"`
-	UnitPrice   float64 `json:"unit_price" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required"`
+	Description string       `json:"description" binding:"required"`
+	UnitPrice   domain.Money `json:"unit_price" binding:"required"`
+	Quantity    int          `json:"quantity" binding:"required"`
+	VisitID     *int         `json:"visit_id,omitempty"`
 }
 
 // SolutionItemResponse representa um item de solução na resposta
 type SolutionItemResponse struct {
-	ProblemName  string  `json:"problem_name"`
-	SolutionName string  `json:"solution_name"`
-	UnitPrice    float64 `json:"unit_price"`
-	Subtotal     float64 `json:"subtotal"`
+	VisitID          *int         `json:"visit_id,omitempty"`
+	ProblemName      string       `json:"problem_name"`
+	SolutionName     string       `json:"solution_name"`
+	UnitPrice        domain.Money `json:"unit_price"`
+	Subtotal         domain.Money `json:"subtotal"`
+	Warranty         bool         `json:"warranty"`
+	WarrantyTicketID *int         `json:"warranty_ticket_id,omitempty"`
 }
 
 // MapToTicketResponse mapeia um domínio Ticket para sua representação DTO TicketResponse
@@ -67,21 +80,46 @@ func ToTicketResponse(ticket *domain.Ticket) *TicketResponse {
 	}
 
 	return &TicketResponse{
-		ID:          ticket.ID,
-		Number:      ticket.Number,
-		Status:      ticket.Status,
-		Priority:    ticket.Priority,
-		Description: ticket.Description,
-		OpenDate:    ticket.OpenDate,
-		CloseDate:   ticket.CloseDate,
-		BranchID:    ticket.BranchID,
-		ProviderID:  ticket.ProviderID,
-		Distance:    nil,                      // Será preenchido pelo service
-		Costs:       []SolutionItemResponse{}, // Será preenchido pelo service
-		TotalCost:   0.0,                      // Será calculado pelo service
+		ID:               ticket.ID,
+		Number:           ticket.Number,
+		Status:           ticket.Status,
+		Priority:         ticket.Priority,
+		Description:      ticket.Description,
+		OpenDate:         ticket.OpenDate,
+		CloseDate:        ticket.CloseDate,
+		BranchID:         ticket.BranchID,
+		ProviderID:       ticket.ProviderID,
+		AssetID:          ticket.AssetID,
+		AssignmentStatus: ticket.AssignmentStatus,
+		Version:          ticket.Version,
+		Distance:         nil,                      // Será preenchido pelo service
+		Costs:            []SolutionItemResponse{}, // Será preenchido pelo service
+		TotalCost:        0.0,                      // Será calculado pelo service
 	}
 }
 
+// ToUpdateTicketRequest monta o request de atualização com os dados atuais do ticket, base do PATCH.
+// Os custos ficam de fora: são alterados pelos sub-recursos do ticket.
+func (t *TicketResponse) ToUpdateTicketRequest() *UpdateTicketRequest {
+	req := &UpdateTicketRequest{
+		Number:      t.Number,
+		Status:      int64(t.Status),
+		Priority:    t.Priority,
+		Description: t.Description,
+		OpenDate:    t.OpenDate.Format(time.RFC3339Nano),
+		BranchID:    t.BranchID,
+		AssetID:     t.AssetID,
+	}
+	if t.CloseDate != nil {
+		closeDate := t.CloseDate.Format(time.RFC3339Nano)
+		req.CloseDate = &closeDate
+	}
+	if t.ProviderID != nil {
+		req.ProviderID = *t.ProviderID
+	}
+	return req
+}
+
 // ToTicketResponseWithCosts mapeia um domínio Ticket com seus custos para TicketResponse
 func ToTicketResponseWithCosts(ticket *domain.Ticket, costs []domain.TicketCost) *TicketResponse {
 	if ticket == nil {
@@ -89,32 +127,38 @@ func ToTicketResponseWithCosts(ticket *domain.Ticket, costs []domain.TicketCost)
 	}
 
 	var costItems []SolutionItemResponse
-	var totalCost float64
+	var totalCost domain.Money
 
 	for _, cost := range costs {
 		costItem := SolutionItemResponse{
-			ProblemName:  cost.ProblemName,
-			SolutionName: cost.SolutionName,
-			UnitPrice:    cost.UnitPrice,
-			Subtotal:     cost.Subtotal,
+			VisitID:          cost.VisitID,
+			ProblemName:      cost.ProblemName,
+			SolutionName:     cost.SolutionName,
+			UnitPrice:        cost.UnitPrice,
+			Subtotal:         cost.Subtotal,
+			Warranty:         cost.Warranty,
+			WarrantyTicketID: cost.WarrantyTicketID,
 		}
 		costItems = append(costItems, costItem)
 		totalCost += cost.Subtotal
 	}
 
 	return &TicketResponse{
-		ID:          ticket.ID,
-		Number:      ticket.Number,
-		Status:      ticket.Status,
-		Priority:    ticket.Priority,
-		Description: ticket.Description,
-		OpenDate:    ticket.OpenDate,
-		CloseDate:   ticket.CloseDate,
-		BranchID:    ticket.BranchID,
-		ProviderID:  ticket.ProviderID,
-		Distance:    nil, // Será preenchido pelo service
-		Costs:       costItems,
-		TotalCost:   totalCost,
+		ID:               ticket.ID,
+		Number:           ticket.Number,
+		Status:           ticket.Status,
+		Priority:         ticket.Priority,
+		Description:      ticket.Description,
+		OpenDate:         ticket.OpenDate,
+		CloseDate:        ticket.CloseDate,
+		BranchID:         ticket.BranchID,
+		ProviderID:       ticket.ProviderID,
+		AssetID:          ticket.AssetID,
+		AssignmentStatus: ticket.AssignmentStatus,
+		Version:          ticket.Version,
+		Distance:         nil, // Será preenchido pelo service
+		Costs:            costItems,
+		TotalCost:        totalCost,
 	}
 }
 
@@ -125,55 +169,64 @@ func ToTicketResponseWithDistanceAndCosts(ticket *domain.Ticket, distance *float
 	}
 
 	var costItems []SolutionItemResponse
-	var totalCost float64
+	var totalCost domain.Money
 
 	for _, cost := range costs {
 		costItem := SolutionItemResponse{
-			ProblemName:  cost.ProblemName,
-			SolutionName: cost.SolutionName,
-			UnitPrice:    cost.UnitPrice,
-			Subtotal:     cost.Subtotal,
+			VisitID:          cost.VisitID,
+			ProblemName:      cost.ProblemName,
+			SolutionName:     cost.SolutionName,
+			UnitPrice:        cost.UnitPrice,
+			Subtotal:         cost.Subtotal,
+			Warranty:         cost.Warranty,
+			WarrantyTicketID: cost.WarrantyTicketID,
 		}
 		costItems = append(costItems, costItem)
 		totalCost += cost.Subtotal
 	}
 
 	return &TicketResponse{
-		ID:           ticket.ID,
-		Number:       ticket.Number,
-		Status:       ticket.Status,
-		Priority:     ticket.Priority,
-		Description:  ticket.Description,
-		OpenDate:     ticket.OpenDate,
-		CloseDate:    ticket.CloseDate,
-		BranchID:     ticket.BranchID,
-		BranchName:   "", // Será preenchido pela nova função
-		BranchUniorg: "", // Será preenchido pela nova função
-		ProviderID:   ticket.ProviderID,
-		ProviderName: nil, // Será preenchido pela nova função
-		Distance:     distance,
-		Costs:        costItems,
-		TotalCost:    totalCost,
+		ID:               ticket.ID,
+		Number:           ticket.Number,
+		Status:           ticket.Status,
+		Priority:         ticket.Priority,
+		Description:      ticket.Description,
+		OpenDate:         ticket.OpenDate,
+		CloseDate:        ticket.CloseDate,
+		BranchID:         ticket.BranchID,
+		BranchName:       "", // Será preenchido pela nova função
+		BranchUniorg:     "", // Será preenchido pela nova função
+		ProviderID:       ticket.ProviderID,
+		AssetID:          ticket.AssetID,
+		AssignmentStatus: ticket.AssignmentStatus,
+		Version:          ticket.Version,
+		ProviderName:     nil, // Será preenchido pela nova função
+		Distance:         distance,
+		Costs:            costItems,
+		TotalCost:        totalCost,
 	}
 }
 
-// AddProviderRequest contém o ID do provider a ser adicionado ao ticket
+// AddProviderRequest agenda uma visita do provider no ticket (datas opcionais)
 type AddProviderRequest struct {
-	ProviderID int `json:"provider_id" binding:"required"`
+	ProviderID int     `json:"provider_id" binding:"required"`
+	StartsAt   *string `json:"starts_at,omitempty"` // Formato "2006-01-02T15:04:05-03:00"
+	EndsAt     *string `json:"ends_at,omitempty"`   // Formato "2006-01-02T15:04:05-03:00"
+	Notes      string  `json:"notes"`
 }
 
 // AddSolutionItemRequest contém dados para adicionar item de solução ao ticket
 type AddSolutionItemRequest struct {
-	Description string  `json:"description" binding:"required"`
-	UnitPrice   float64 `json:"unit_price" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required"`
+	Description string       `json:"description" binding:"required"`
+	UnitPrice   domain.Money `json:"unit_price" binding:"required"`
+	Quantity    int          `json:"quantity" binding:"required"`
 }
 
 // UpdateSolutionItemRequest contém dados para atualizar item de solução
 type UpdateSolutionItemRequest struct {
-	Description string  `json:"description" binding:"required"`
-	UnitPrice   float64 `json:"unit_price" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required"`
+	Description string       `json:"description" binding:"required"`
+	UnitPrice   domain.Money `json:"unit_price" binding:"required"`
+	Quantity    int          `json:"quantity" binding:"required"`
 }
 
 // ToTicketResponseWithBranchProviderDistanceAndCosts mapeia um domínio Ticket com branch, provider, distance e custos para TicketResponse
@@ -183,14 +236,17 @@ func ToTicketResponseWithBranchProviderDistanceAndCosts(ticket *domain.Ticket, b
 	}
 
 	var costItems []SolutionItemResponse
-	var totalCost float64
+	var totalCost domain.Money
 
 	for _, cost := range costs {
 		costItem := SolutionItemResponse{
-			ProblemName:  cost.ProblemName,
-			SolutionName: cost.SolutionName,
-			UnitPrice:    cost.UnitPrice,
-			Subtotal:     cost.Subtotal,
+			VisitID:          cost.VisitID,
+			ProblemName:      cost.ProblemName,
+			SolutionName:     cost.SolutionName,
+			UnitPrice:        cost.UnitPrice,
+			Subtotal:         cost.Subtotal,
+			Warranty:         cost.Warranty,
+			WarrantyTicketID: cost.WarrantyTicketID,
 		}
 		costItems = append(costItems, costItem)
 		totalCost += cost.Subtotal
@@ -211,20 +267,23 @@ func ToTicketResponseWithBranchProviderDistanceAndCosts(ticket *domain.Ticket, b
 	}
 
 	return &TicketResponse{
-		ID:           ticket.ID,
-		Number:       ticket.Number,
-		Status:       ticket.Status,
-		Priority:     ticket.Priority,
-		Description:  ticket.Description,
-		OpenDate:     ticket.OpenDate,
-		CloseDate:    ticket.CloseDate,
-		BranchID:     ticket.BranchID,
-		BranchName:   branchName,
-		BranchUniorg: branchUniorg,
-		ProviderID:   ticket.ProviderID,
-		ProviderName: providerName,
-		Distance:     distance,
-		Costs:        costItems,
-		TotalCost:    totalCost,
+		ID:               ticket.ID,
+		Number:           ticket.Number,
+		Status:           ticket.Status,
+		Priority:         ticket.Priority,
+		Description:      ticket.Description,
+		OpenDate:         ticket.OpenDate,
+		CloseDate:        ticket.CloseDate,
+		BranchID:         ticket.BranchID,
+		BranchName:       branchName,
+		BranchUniorg:     branchUniorg,
+		ProviderID:       ticket.ProviderID,
+		AssetID:          ticket.AssetID,
+		AssignmentStatus: ticket.AssignmentStatus,
+		Version:          ticket.Version,
+		ProviderName:     providerName,
+		Distance:         distance,
+		Costs:            costItems,
+		TotalCost:        totalCost,
 	}
 }
@@ -4,7 +4,8 @@ import "time"
 
 // TicketProblemRequest representa a requisição para associar um problema a um ticket
 type TicketProblemRequest struct {
-	ProblemID int `json:"problem_id" binding:"required"`
+	ProblemID int  `json:"problem_id" binding:"required"`
+	AssetID   *int `json:"asset_id,omitempty"`
 }
 
 // TicketProblemResponse representa a resposta de um problema associado a um ticket
@@ -12,6 +13,7 @@ type TicketProblemResponse struct {
 	ID        int       `json:"id"`
 	TicketID  int       `json:"ticket_id"`
 	ProblemID int       `json:"problem_id"`
+	AssetID   *int      `json:"asset_id,omitempty"`
 	Problem   *ProblemResponse `json:"problem,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -1,22 +1,30 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
 
 // TicketSolutionRequest representa a requisição para associar uma solution a um ticket
 type TicketSolutionRequest struct {
-	SolutionID int `json:"solution_id" binding:"required"`
-	Quantity   int `json:"quantity" binding:"required"`
+	SolutionID int  `json:"solution_id" binding:"required"`
+	Quantity   int  `json:"quantity" binding:"required"`
+	VisitID    *int `json:"visit_id,omitempty"`
 }
 
 // TicketSolutionResponse representa a resposta de uma solution associada a um ticket
 type TicketSolutionResponse struct {
 	ID         int              `json:"id"`
 	TicketID   int              `json:"ticket_id"`
+	VisitID    *int             `json:"visit_id,omitempty"`
 	SolutionID int              `json:"solution_id"`
 	Solution   *SolutionResponse `json:"solution,omitempty"`
 	Quantity   int              `json:"quantity"`
-	UnitPrice  float64          `json:"unit_price"`
-	Subtotal   float64          `json:"subtotal"`
+	UnitPrice  domain.Money     `json:"unit_price"`
+	Subtotal   domain.Money     `json:"subtotal"`
+	Warranty   bool             `json:"warranty"`
+	WarrantyTicketID *int       `json:"warranty_ticket_id,omitempty"`
 	CreatedAt  time.Time        `json:"created_at"`
 }
 
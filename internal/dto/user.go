@@ -1,19 +1,38 @@
 package dto
 
+import "github.com/ericolvr/maintenance-v2/internal/domain"
+
 type UserRequest struct {
-	Name     string `json:"name" validate:"required,min=5"`
-	Mobile   string `json:"mobile" validate:"required,min=11,max=11"`
-	Password string `json:"password" validate:"required,min=6"`
-	Role     int64  `json:"role" validate:"required"`
-	Status   bool   `json:"status"`
+	Name       string `json:"name" validate:"required,min=5"`
+	Mobile     string `json:"mobile" validate:"required,min=11,max=11"`
+	Password   string `json:"password" validate:"required,min=6"`
+	Role       int64  `json:"role" validate:"required"`
+	Status     bool   `json:"status"`
+	ProviderID *int   `json:"provider_id,omitempty"`
+	ClientID   *int   `json:"client_id,omitempty"`
+}
+
+// ToUserRequest monta o request com os dados atuais do usuário, base do PATCH. A senha fica
+// vazia, o que mantém a atual.
+func ToUserRequest(user *domain.User) *UserRequest {
+	return &UserRequest{
+		Name:       user.Name,
+		Mobile:     user.Mobile,
+		Role:       user.Role,
+		Status:     user.Status,
+		ProviderID: user.ProviderID,
+		ClientID:   user.ClientID,
+	}
 }
 
 type UserResponse struct {
-	ID     int    `json:"id"`
-	Name   string `json:"name"`
-	Mobile string `json:"mobile"`
-	Role   int64  `json:"role"`
-	Status bool   `json:"status"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Mobile     string `json:"mobile"`
+	Role       int64  `json:"role"`
+	Status     bool   `json:"status"`
+	ProviderID *int   `json:"provider_id,omitempty"`
+	ClientID   *int   `json:"client_id,omitempty"`
 }
 
 type UserLogin struct {
@@ -22,7 +41,8 @@ type UserLogin struct {
 }
 
 type AuthResponse struct {
-	Name  string `json:"name"`
-	Token string `json:"token"`
-	Role  int64  `json:"role"`
+	Name     string `json:"name"`
+	Token    string `json:"token"`
+	Role     int64  `json:"role"`
+	ClientID *int   `json:"client_id,omitempty"`
 }
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// VisitRequest representa a requisição para agendar ou atualizar uma visita
+type VisitRequest struct {
+	ProviderID int      `json:"provider_id"` // Opcional no agendamento, padrão: técnico do ticket
+	StartsAt   *string  `json:"starts_at"`   // Opcional, formato "2006-01-02T15:04:05-03:00"
+	EndsAt     *string  `json:"ends_at"`     // Opcional, formato "2006-01-02T15:04:05-03:00"
+	DistanceKm *float64 `json:"distance_km"` // Opcional, km percorridos até a agência
+	Notes      string   `json:"notes"`
+}
+
+// VisitResponse representa uma visita na resposta
+type VisitResponse struct {
+	ID             int          `json:"id"`
+	TicketID       int          `json:"ticket_id"`
+	TicketNumber   string       `json:"ticket_number"`
+	ProviderID     int          `json:"provider_id"`
+	ProviderName   string       `json:"provider_name"`
+	BranchID       int          `json:"branch_id"`
+	BranchName     string       `json:"branch_name"`
+	BranchAddress  string       `json:"branch_address"`
+	BranchCity     string       `json:"branch_city"`
+	StartsAt       *time.Time   `json:"starts_at,omitempty"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty"`
+	DistanceKm     *float64     `json:"distance_km,omitempty"`
+	TravelCost     domain.Money `json:"travel_cost"`
+	SolutionsTotal domain.Money `json:"solutions_total"`
+	Total          domain.Money `json:"total"`
+	Notes          string       `json:"notes"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// ToVisitResponse converte domain para DTO
+func ToVisitResponse(visit *domain.Visit) *VisitResponse {
+	if visit == nil {
+		return nil
+	}
+
+	return &VisitResponse{
+		ID:             visit.ID,
+		TicketID:       visit.TicketID,
+		TicketNumber:   visit.TicketNumber,
+		ProviderID:     visit.ProviderID,
+		ProviderName:   visit.ProviderName,
+		BranchID:       visit.BranchID,
+		BranchName:     visit.BranchName,
+		BranchAddress:  visit.BranchAddress,
+		BranchCity:     visit.BranchCity,
+		StartsAt:       visit.StartsAt,
+		EndsAt:         visit.EndsAt,
+		DistanceKm:     visit.DistanceKm,
+		TravelCost:     visit.TravelCost,
+		SolutionsTotal: visit.SolutionsTotal,
+		Total:          visit.Total(),
+		Notes:          visit.Notes,
+		CreatedAt:      visit.CreatedAt,
+	}
+}
+
+// ToVisitResponseList converte lista de domain para DTO
+func ToVisitResponseList(visits []domain.Visit) []VisitResponse {
+	responses := make([]VisitResponse, len(visits))
+	for i, visit := range visits {
+		responses[i] = *ToVisitResponse(&visit)
+	}
+	return responses
+}
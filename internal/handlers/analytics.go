@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Período padrão da análise do catálogo quando from/to não são informados
+const defaultAnalyticsDays = 180
+
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetProblemAnalytics analisa a ocorrência e as soluções de cada problema (query from/to, client_id e follow_up_days)
+func (h *AnalyticsHandler) GetProblemAnalytics(c *gin.Context) {
+	from, to, ok := reportPeriod(c, defaultAnalyticsDays)
+	if !ok {
+		return
+	}
+
+	filter := domain.ProblemAnalyticsFilter{From: from, To: to, FollowUpDays: domain.DefaultReworkDays}
+	if value := c.Query("client_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+			return
+		}
+		filter.ClientID = &id
+	}
+	if value := c.Query("follow_up_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid follow_up_days"})
+			return
+		}
+		filter.FollowUpDays = days
+	}
+
+	problems, err := h.analyticsService.Problems(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if problems == nil {
+		problems = []domain.ProblemAnalytics{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":     from,
+		"to":       to,
+		"problems": problems,
+	})
+}
+
+// GetUnusedSolutions lista as soluções do catálogo que nunca foram aplicadas
+func (h *AnalyticsHandler) GetUnusedSolutions(c *gin.Context) {
+	solutions, err := h.analyticsService.UnusedSolutions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if solutions == nil {
+		solutions = []domain.UnusedSolution{}
+	}
+
+	c.JSON(http.StatusOK, solutions)
+}
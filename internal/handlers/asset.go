@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AssetHandler struct {
+	assetService service.AssetService
+}
+
+func NewAssetHandler(assetService service.AssetService) *AssetHandler {
+	return &AssetHandler{
+		assetService: assetService,
+	}
+}
+
+func (h *AssetHandler) CreateAsset(c *gin.Context) {
+	var req dto.AssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	asset, err := h.assetService.Create(c.Request.Context(), req.ToAssetDomain())
+	if err != nil {
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAssetResponse(asset))
+}
+
+// ListAssets lista os equipamentos (query branch_id opcional)
+func (h *AssetHandler) ListAssets(c *gin.Context) {
+	var branchID *int
+	if value := c.Query("branch_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+			return
+		}
+		branchID = &id
+	}
+
+	assets, err := h.assetService.List(c.Request.Context(), branchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAssetResponseList(assets))
+}
+
+func (h *AssetHandler) GetAsset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return
+	}
+
+	asset, err := h.assetService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAssetResponse(asset))
+}
+
+func (h *AssetHandler) UpdateAsset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return
+	}
+
+	var req dto.AssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	asset := req.ToAssetDomain()
+	asset.ID = id
+
+	updated, err := h.assetService.Update(c.Request.Context(), asset)
+	if err != nil {
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAssetResponse(updated))
+}
+
+func (h *AssetHandler) DeleteAsset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return
+	}
+
+	if err := h.assetService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAssetHistory retorna todos os tickets, soluções e custos do equipamento
+func (h *AssetHandler) GetAssetHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return
+	}
+
+	history, err := h.assetService.History(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *AssetHandler) GetBranchAssets(c *gin.Context) {
+	branchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+		return
+	}
+
+	assets, err := h.assetService.List(c.Request.Context(), &branchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAssetResponseList(assets))
+}
+
+func assetErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrAssetBranchMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrAssetNotFound),
+		errors.Is(err, repository.ErrBranchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -28,62 +28,27 @@ func (h *BranchHandler) Create(c *gin.Context) {
 		return
 	}
 
-	branch := domain.Branch{
-		Name:         req.Name,
-		Client:       req.Client,
-		Uniorg:       req.Uniorg,
-		Zipcode:      req.Zipcode,
-		State:        req.State,
-		City:         req.City,
-		Neighborhood: req.Neighborhood,
-		Address:      req.Address,
-		Complement:   req.Complement,
-	}
-
-	id, err := h.service.Create(context.Background(), &branch)
+	branch, err := h.service.Create(c.Request.Context(), req.ToBranchDomain())
 	if err != nil {
+		if status := branchErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create branch"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.BranchResponse{
-		ID:           id,
-		Name:         req.Name,
-		Client:       req.Client,
-		Uniorg:       req.Uniorg,
-		Zipcode:      req.Zipcode,
-		State:        req.State,
-		City:         req.City,
-		Neighborhood: req.Neighborhood,
-		Address:      req.Address,
-		Complement:   req.Complement,
-	})
+	c.JSON(http.StatusCreated, dto.ToBranchResponse(branch))
 }
 
 func (h *BranchHandler) List(c *gin.Context) {
-	branchs, err := h.service.List(context.Background())
+	branchs, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list branchs"})
 		return
 	}
 
-	response := make([]dto.BranchResponse, 0, len(branchs))
-	for _, branch := range branchs {
-		response = append(response, dto.BranchResponse{
-			ID:           branch.ID,
-			Name:         branch.Name,
-			Client:       branch.Client,
-			Uniorg:       branch.Uniorg,
-			Zipcode:      branch.Zipcode,
-			State:        branch.State,
-			City:         branch.City,
-			Neighborhood: branch.Neighborhood,
-			Address:      branch.Address,
-			Complement:   branch.Complement,
-		})
-	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.ToBranchResponseList(branchs))
 }
 
 func (h *BranchHandler) FindByID(c *gin.Context) {
@@ -94,9 +59,9 @@ func (h *BranchHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	branch, err := h.service.FindByID(context.Background(), id)
+	branch, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
-		if err == repository.ErrNotFound {
+		if branchErrorStatus(err) == http.StatusNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
 			return
 		}
@@ -104,26 +69,16 @@ func (h *BranchHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.BranchResponse{
-		ID:           branch.ID,
-		Name:         branch.Name,
-		Client:       branch.Client,
-		Uniorg:       branch.Uniorg,
-		Zipcode:      branch.Zipcode,
-		State:        branch.State,
-		City:         branch.City,
-		Neighborhood: branch.Neighborhood,
-		Address:      branch.Address,
-		Complement:   branch.Complement,
-	})
+	setETag(c, branch.Version)
+	c.JSON(http.StatusOK, dto.ToBranchResponse(branch))
 }
 
 func (h *BranchHandler) FindByUniorg(c *gin.Context) {
 	uniorg := c.Param("uniorg")
 
-	branch, err := h.service.FindByUniorg(context.Background(), uniorg)
+	branch, err := h.service.FindByUniorg(c.Request.Context(), uniorg)
 	if err != nil {
-		if err == repository.ErrNotFound {
+		if branchErrorStatus(err) == http.StatusNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
 			return
 		}
@@ -131,46 +86,23 @@ func (h *BranchHandler) FindByUniorg(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.BranchResponse{
-		ID:           branch.ID,
-		Name:         branch.Name,
-		Client:       branch.Client,
-		Uniorg:       branch.Uniorg,
-		Zipcode:      branch.Zipcode,
-		State:        branch.State,
-		City:         branch.City,
-		Neighborhood: branch.Neighborhood,
-		Address:      branch.Address,
-		Complement:   branch.Complement,
-	})
+	c.JSON(http.StatusOK, dto.ToBranchResponse(branch))
 }
 
 func (h *BranchHandler) GetByClient(c *gin.Context) {
-	client := c.Param("client")
-
-	branchs, err := h.service.GetByClient(context.Background(), client)
+	clientID, err := strconv.Atoi(c.Param("client"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get branches by client"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
 		return
 	}
 
-	response := make([]dto.BranchResponse, 0, len(branchs))
-	for _, branch := range branchs {
-		response = append(response, dto.BranchResponse{
-			ID:           branch.ID,
-			Name:         branch.Name,
-			Client:       branch.Client,
-			Uniorg:       branch.Uniorg,
-			Zipcode:      branch.Zipcode,
-			State:        branch.State,
-			City:         branch.City,
-			Neighborhood: branch.Neighborhood,
-			Address:      branch.Address,
-			Complement:   branch.Complement,
-		})
+	branchs, err := h.service.GetByClient(c.Request.Context(), clientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get branches by client"})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.ToBranchResponseList(branchs))
 }
 
 func (h *BranchHandler) Update(c *gin.Context) {
@@ -181,47 +113,84 @@ func (h *BranchHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req dto.BranchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	branch := domain.Branch{
-		ID:           id,
-		Name:         req.Name,
-		Client:       req.Client,
-		Uniorg:       req.Uniorg,
-		Zipcode:      req.Zipcode,
-		State:        req.State,
-		City:         req.City,
-		Neighborhood: req.Neighborhood,
-		Address:      req.Address,
-		Complement:   req.Complement,
+	branch := req.ToBranchDomain()
+	branch.ID = id
+	branch.Version = version
+
+	h.update(c, branch)
+}
+
+// Patch altera só os campos enviados (JSON Merge Patch)
+func (h *BranchHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
 	}
 
-	err = h.service.Update(context.Background(), &branch)
+	current, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
-		if err == repository.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
+		c.JSON(branchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if version > 0 && version != current.Version {
+		respondVersionConflict(c, current.Version, dto.ToBranchResponse(current))
+		return
+	}
+
+	var req dto.BranchRequest
+	if !bindMergePatch(c, dto.ToBranchRequest(current), &req) {
+		return
+	}
+
+	// O patch foi aplicado sobre a versão lida; se a agência mudou nesse meio tempo, responde 412
+	branch := req.ToBranchDomain()
+	branch.ID = id
+	branch.Version = current.Version
+
+	h.update(c, branch)
+}
+
+// update grava a agência e responde com a nova versão, ou 412 com a atual em caso de conflito
+func (h *BranchHandler) update(c *gin.Context, branch *domain.Branch) {
+	updated, err := h.service.Update(c.Request.Context(), branch)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.service.FindByID(c.Request.Context(), branch.ID)
+		if err != nil {
+			c.JSON(branchErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		respondVersionConflict(c, current.Version, dto.ToBranchResponse(current))
+		return
+	}
+	if err != nil {
+		if status := branchErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update branch"})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.BranchResponse{
-		ID:           id,
-		Name:         branch.Name,
-		Client:       branch.Client,
-		Uniorg:       branch.Uniorg,
-		Zipcode:      branch.Zipcode,
-		State:        branch.State,
-		City:         branch.City,
-		Neighborhood: branch.Neighborhood,
-		Address:      branch.Address,
-		Complement:   branch.Complement,
-	})
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, dto.ToBranchResponse(updated))
 }
 
 func (h *BranchHandler) Delete(c *gin.Context) {
@@ -232,9 +201,9 @@ func (h *BranchHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
-		if err == repository.ErrNotFound {
+		if branchErrorStatus(err) == http.StatusNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Branch not found"})
 			return
 		}
@@ -244,3 +213,16 @@ func (h *BranchHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Branch deleted successfully"})
 }
+
+// branchErrorStatus mapeia os erros de agência para o status HTTP
+func branchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrBranchNotFound),
+		errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -29,23 +29,29 @@ func (h *ClientHandler) Create(c *gin.Context) {
 	}
 
 	client := domain.Client{
-		Name: req.Name,
+		Name:     req.Name,
+		Document: req.Document,
 	}
 
-	id, err := h.service.Create(context.Background(), &client)
+	id, err := h.service.Create(c.Request.Context(), &client)
 	if err != nil {
+		if errors.Is(err, repository.ErrCrossTenant) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create client"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, dto.ClientResponse{
-		ID:   id,
-		Name: client.Name,
+		ID:       id,
+		Name:     client.Name,
+		Document: client.Document,
 	})
 }
 
 func (h *ClientHandler) List(c *gin.Context) {
-	clients, err := h.service.List(context.Background())
+	clients, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list clients"})
 		return
@@ -54,8 +60,9 @@ func (h *ClientHandler) List(c *gin.Context) {
 	response := make([]dto.ClientResponse, 0, len(clients))
 	for _, client := range clients {
 		response = append(response, dto.ClientResponse{
-			ID:   client.ID,
-			Name: client.Name,
+			ID:       client.ID,
+			Name:     client.Name,
+			Document: client.Document,
 		})
 	}
 
@@ -70,7 +77,7 @@ func (h *ClientHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	client, err := h.service.FindByID(context.Background(), id)
+	client, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
@@ -81,8 +88,9 @@ func (h *ClientHandler) FindByID(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.ClientResponse{
-		ID:   client.ID,
-		Name: client.Name,
+		ID:       client.ID,
+		Name:     client.Name,
+		Document: client.Document,
 	})
 }
 
@@ -101,11 +109,12 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	}
 
 	client := domain.Client{
-		ID:   id,
-		Name: req.Name,
+		ID:       id,
+		Name:     req.Name,
+		Document: req.Document,
 	}
 
-	err = h.service.Update(context.Background(), &client)
+	err = h.service.Update(c.Request.Context(), &client)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
@@ -116,8 +125,9 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.ClientResponse{
-		ID:   id,
-		Name: client.Name,
+		ID:       id,
+		Name:     client.Name,
+		Document: client.Document,
 	})
 }
 
@@ -129,7 +139,7 @@ func (h *ClientHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ClientPortalHandler struct {
+	portalService service.ClientPortalService
+}
+
+func NewClientPortalHandler(portalService service.ClientPortalService) *ClientPortalHandler {
+	return &ClientPortalHandler{
+		portalService: portalService,
+	}
+}
+
+func (h *ClientPortalHandler) ListBranches(c *gin.Context) {
+	branchs, err := h.portalService.ListBranches(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBranchResponseList(branchs))
+}
+
+func (h *ClientPortalHandler) ListTickets(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	tickets, total, err := h.portalService.ListTickets(c.Request.Context(), c.GetInt("user_id"), limit, offset)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   tickets,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *ClientPortalHandler) OpenTicket(c *gin.Context) {
+	var req dto.ClientTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticket, err := h.portalService.OpenTicket(c.Request.Context(), c.GetInt("user_id"), &req)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+func (h *ClientPortalHandler) GetTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	ticket, err := h.portalService.GetTicket(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+func (h *ClientPortalHandler) ListComments(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	comments, err := h.portalService.ListComments(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentResponseList(comments))
+}
+
+func (h *ClientPortalHandler) AddComment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.ClientCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.portalService.AddComment(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCommentResponse(comment))
+}
+
+func (h *ClientPortalHandler) ListAttachments(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	attachments, err := h.portalService.ListAttachments(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAttachmentResponseList(attachments))
+}
+
+func (h *ClientPortalHandler) UploadAttachment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.portalService.UploadAttachment(c.Request.Context(), c.GetInt("user_id"), ticketID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), file)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(attachment))
+}
+
+func (h *ClientPortalHandler) DownloadAttachment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	attachment, err := h.portalService.FindAttachment(c.Request.Context(), c.GetInt("user_id"), ticketID, attachmentID)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.FileAttachment(attachment.Path, attachment.FileName)
+}
+
+func (h *ClientPortalHandler) RateTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.TicketRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating, err := h.portalService.RateTicket(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rating)
+}
+
+func (h *ClientPortalHandler) ListQuotes(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	quotes, err := h.portalService.ListQuotes(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponseList(quotes))
+}
+
+func (h *ClientPortalHandler) ApproveQuote(c *gin.Context) {
+	h.decideQuote(c, domain.QuoteApproved)
+}
+
+func (h *ClientPortalHandler) RejectQuote(c *gin.Context) {
+	h.decideQuote(c, domain.QuoteRejected)
+}
+
+func (h *ClientPortalHandler) decideQuote(c *gin.Context, status string) {
+	ticketID, quoteID, ok := quoteParams(c)
+	if !ok {
+		return
+	}
+
+	// Corpo opcional
+	var req dto.QuoteDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	quote, err := h.portalService.DecideQuote(c.Request.Context(), c.GetInt("user_id"), ticketID, quoteID, status, &req)
+	if err != nil {
+		c.JSON(clientPortalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponse(quote))
+}
+
+func clientPortalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotClientUser):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrClientTicketAccess),
+		errors.Is(err, repository.ErrAttachmentNotFound),
+		errors.Is(err, repository.ErrQuoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTicketNotClosed),
+		errors.Is(err, repository.ErrTicketRated),
+		errors.Is(err, repository.ErrQuoteStatus):
+		return http.StatusConflict
+	default:
+		return assetErrorStatus(err)
+	}
+}
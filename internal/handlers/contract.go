@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ContractHandler struct {
+	contractService service.ContractService
+}
+
+func NewContractHandler(contractService service.ContractService) *ContractHandler {
+	return &ContractHandler{
+		contractService: contractService,
+	}
+}
+
+func (h *ContractHandler) CreateContract(c *gin.Context) {
+	var req dto.ContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contract, err := h.contractService.Create(c.Request.Context(), req.ToContractDomain())
+	if err != nil {
+		c.JSON(contractErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToContractResponse(contract))
+}
+
+// ListContracts lista os contratos (query client_id opcional)
+func (h *ContractHandler) ListContracts(c *gin.Context) {
+	var clientID *int
+	if value := c.Query("client_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+			return
+		}
+		clientID = &id
+	}
+
+	contracts, err := h.contractService.List(c.Request.Context(), clientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToContractResponseList(contracts))
+}
+
+func (h *ContractHandler) GetContract(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	contract, err := h.contractService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(contractErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToContractResponse(contract))
+}
+
+func (h *ContractHandler) UpdateContract(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	var req dto.ContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contract := req.ToContractDomain()
+	contract.ID = id
+
+	updated, err := h.contractService.Update(c.Request.Context(), contract)
+	if err != nil {
+		c.JSON(contractErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToContractResponse(updated))
+}
+
+func (h *ContractHandler) DeleteContract(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return
+	}
+
+	if err := h.contractService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(contractErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ContractHandler) GetClientContracts(c *gin.Context) {
+	clientID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	contracts, err := h.contractService.List(c.Request.Context(), &clientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToContractResponseList(contracts))
+}
+
+// GetTicketContract retorna o contrato vigente na abertura do ticket
+func (h *ContractHandler) GetTicketContract(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	contract, err := h.contractService.ForTicket(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if contract == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no contract in force for this ticket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToContractResponse(contract))
+}
+
+func contractErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidContractPeriod),
+		errors.Is(err, service.ErrDuplicateContractPrice):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrContractOverlap):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrContractNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
 
@@ -21,7 +20,7 @@ func NewCostHandler(service service.CostService) *CostHandler {
 }
 
 func (h *CostHandler) List(c *gin.Context) {
-	costs, err := h.service.List(context.Background())
+	costs, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list costs"})
 		return
@@ -47,7 +46,7 @@ func (h *CostHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	cost, err := h.service.FindByID(context.Background(), id)
+	cost, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cost not found"})
@@ -84,7 +83,7 @@ func (h *CostHandler) Update(c *gin.Context) {
 		InitialValue: req.InitialValue,
 	}
 
-	err = h.service.Update(context.Background(), &cost)
+	err = h.service.Update(c.Request.Context(), &cost)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cost not found"})
@@ -109,7 +108,7 @@ func (h *CostHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cost not found"})
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Período padrão do painel quando from/to não são informados
+const defaultDashboardDays = 30
+
+type DashboardHandler struct {
+	dashboardService service.DashboardService
+}
+
+func NewDashboardHandler(dashboardService service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+	}
+}
+
+// GetDashboard retorna os indicadores operacionais dos tickets abertos no período (query from/to e client_id)
+func (h *DashboardHandler) GetDashboard(c *gin.Context) {
+	from, to, ok := reportPeriod(c, defaultDashboardDays)
+	if !ok {
+		return
+	}
+
+	filter := domain.DashboardFilter{From: from, To: to}
+	if value := c.Query("client_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+			return
+		}
+		filter.ClientID = &id
+	}
+
+	dashboard, err := h.dashboardService.Dashboard(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
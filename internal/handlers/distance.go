@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
 
@@ -35,7 +34,7 @@ func (h *DistanceHandler) Create(c *gin.Context) {
 		ProviderName: req.ProviderName,
 	}
 
-	id, err := h.service.Create(context.Background(), &distance)
+	id, err := h.service.Create(c.Request.Context(), &distance)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create distance"})
 		return
@@ -51,7 +50,7 @@ func (h *DistanceHandler) Create(c *gin.Context) {
 }
 
 func (h *DistanceHandler) List(c *gin.Context) {
-	distances, err := h.service.List(context.Background())
+	distances, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list distances"})
 		return
@@ -79,7 +78,7 @@ func (h *DistanceHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	distance, err := h.service.FindByID(context.Background(), id)
+	distance, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrDistanceNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Distance not found"})
@@ -101,7 +100,7 @@ func (h *DistanceHandler) FindByID(c *gin.Context) {
 func (h *DistanceHandler) FindByNumber(c *gin.Context) {
 	number := c.Param("number")
 
-	distance, err := h.service.FindByNumber(context.Background(), number)
+	distance, err := h.service.FindByNumber(c.Request.Context(), number)
 	if err != nil {
 		if err == repository.ErrDistanceNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Distance not found"})
@@ -142,7 +141,7 @@ func (h *DistanceHandler) Update(c *gin.Context) {
 		ProviderName: req.ProviderName,
 	}
 
-	err = h.service.Update(context.Background(), &distance)
+	err = h.service.Update(c.Request.Context(), &distance)
 	if err != nil {
 		if err == repository.ErrDistanceNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Distance not found"})
@@ -169,7 +168,7 @@ func (h *DistanceHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrDistanceNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Distance not found"})
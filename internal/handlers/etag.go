@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// setETag envia a versão do recurso no header ETag
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion lê a versão esperada do header If-Match ("3" ou W/"3"); responde 400 quando inválida.
+// Sem o header (ou com "*") retorna 0 e a atualização não confere a versão.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+		return 0, false
+	}
+
+	return version, true
+}
+
+// respondVersionConflict responde 412 com a representação atual do recurso e a sua versão no ETag
+func respondVersionConflict(c *gin.Context, version int, current interface{}) {
+	setETag(c, version)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   repository.ErrVersionConflict.Error(),
+		"current": current,
+	})
+}
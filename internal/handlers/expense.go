@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ExpenseHandler struct {
+	expenseService service.ExpenseService
+}
+
+func NewExpenseHandler(expenseService service.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+func (h *ExpenseHandler) ListExpenses(c *gin.Context) {
+	var ticketID *int
+	if value := c.Query("ticket_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+			return
+		}
+		ticketID = &id
+	}
+
+	expenses, err := h.expenseService.List(c.Request.Context(), ticketID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToExpenseResponseList(expenses))
+}
+
+func (h *ExpenseHandler) ListTicketExpenses(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	expenses, err := h.expenseService.List(c.Request.Context(), &ticketID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToExpenseResponseList(expenses))
+}
+
+func (h *ExpenseHandler) GetExpense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	expense, err := h.expenseService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(expenseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToExpenseResponse(expense))
+}
+
+func (h *ExpenseHandler) ReviewExpense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	var req dto.ExpenseReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.expenseService.Review(c.Request.Context(), id, c.GetInt("user_id"), &req)
+	if err != nil {
+		c.JSON(expenseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToExpenseResponse(expense))
+}
+
+func expenseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReceiptNotFound),
+		errors.Is(err, service.ErrInvalidExpenseReview),
+		errors.Is(err, service.ErrRejectionReasonRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrExpenseStatus),
+		errors.Is(err, repository.ErrExpensePending),
+		errors.Is(err, service.ErrTicketConcluded),
+		errors.Is(err, service.ErrTicketCostsLocked):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrExpenseNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
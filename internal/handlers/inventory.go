@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+}
+
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+func (h *InventoryHandler) CreateItem(c *gin.Context) {
+	var req dto.InventoryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.inventoryService.CreateItem(c.Request.Context(), &domain.InventoryItem{
+		SKU:         req.SKU,
+		Name:        req.Name,
+		Unit:        req.Unit,
+		MinQuantity: req.MinQuantity,
+	})
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToInventoryItemResponse(item, nil))
+}
+
+func (h *InventoryHandler) ListItems(c *gin.Context) {
+	items, err := h.inventoryService.ListItems(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInventoryItemResponseList(items))
+}
+
+func (h *InventoryHandler) GetItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	item, levels, err := h.inventoryService.FindItemByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInventoryItemResponse(item, levels))
+}
+
+func (h *InventoryHandler) UpdateItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req dto.InventoryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.inventoryService.UpdateItem(c.Request.Context(), &domain.InventoryItem{
+		ID:          id,
+		SKU:         req.SKU,
+		Name:        req.Name,
+		Unit:        req.Unit,
+		MinQuantity: req.MinQuantity,
+	})
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInventoryItemResponse(item, nil))
+}
+
+func (h *InventoryHandler) GetItemMovements(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	movements, total, err := h.inventoryService.ListMovements(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   dto.ToStockMovementResponseList(movements),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *InventoryHandler) CreateLocation(c *gin.Context) {
+	var req dto.StockLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	location, err := h.inventoryService.CreateLocation(c.Request.Context(), &domain.StockLocation{
+		Name:       req.Name,
+		Kind:       req.Kind,
+		ProviderID: req.ProviderID,
+	})
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToStockLocationResponse(location))
+}
+
+func (h *InventoryHandler) ListLocations(c *gin.Context) {
+	locations, err := h.inventoryService.ListLocations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStockLocationResponseList(locations))
+}
+
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	var req dto.StockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.inventoryService.RecordMovement(c.Request.Context(), &domain.StockMovement{
+		ItemID:     req.ItemID,
+		LocationID: req.LocationID,
+		Kind:       req.Kind,
+		Quantity:   req.Quantity,
+		Note:       req.Note,
+	})
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Stock movement recorded successfully"})
+}
+
+func (h *InventoryHandler) Transfer(c *gin.Context) {
+	var req dto.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.inventoryService.Transfer(c.Request.Context(), req.ItemID, req.FromLocationID, req.ToLocationID, req.Quantity, req.Note)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Stock transferred successfully"})
+}
+
+func (h *InventoryHandler) GetLowStock(c *gin.Context) {
+	items, err := h.inventoryService.LowStock(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToLowStockResponseList(items))
+}
+
+func (h *InventoryHandler) GetSolutionParts(c *gin.Context) {
+	solutionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid solution ID"})
+		return
+	}
+
+	parts, err := h.inventoryService.GetSolutionParts(c.Request.Context(), solutionID)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSolutionPartResponseList(parts))
+}
+
+func (h *InventoryHandler) SetSolutionParts(c *gin.Context) {
+	solutionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid solution ID"})
+		return
+	}
+
+	var req dto.SolutionPartsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parts := make([]domain.SolutionPart, 0, len(req.Parts))
+	for _, part := range req.Parts {
+		parts = append(parts, domain.SolutionPart{
+			SolutionID: solutionID,
+			ItemID:     part.ItemID,
+			Quantity:   part.Quantity,
+		})
+	}
+
+	saved, err := h.inventoryService.SetSolutionParts(c.Request.Context(), solutionID, parts)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSolutionPartResponseList(saved))
+}
+
+func (h *InventoryHandler) GetTicketReservations(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	reservations, err := h.inventoryService.ListTicketReservations(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStockReservationResponseList(reservations))
+}
+
+func inventoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidMovement),
+		errors.Is(err, service.ErrInvalidLocationKind),
+		errors.Is(err, service.ErrInvalidSolutionParts):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrInsufficientStock),
+		errors.Is(err, repository.ErrDuplicateSKU):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrProviderNotFound),
+		errors.Is(err, repository.ErrInventoryItemNotFound),
+		errors.Is(err, repository.ErrStockLocationNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/nfse"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	invoiceService service.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
+	var req dto.InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.invoiceService.Create(c.Request.Context(), req.ToInvoiceDomain())
+	if err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToInvoiceResponse(invoice))
+}
+
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	var clientID *int
+	if value := c.Query("client_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+			return
+		}
+		clientID = &id
+	}
+
+	invoices, err := h.invoiceService.List(c.Request.Context(), clientID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInvoiceResponseList(invoices))
+}
+
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	invoice, err := h.invoiceService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInvoiceResponse(invoice))
+}
+
+func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	if err := h.invoiceService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	invoice, err := h.invoiceService.Issue(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInvoiceResponse(invoice))
+}
+
+func (h *InvoiceHandler) CancelInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	var req dto.InvoiceCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.invoiceService.Cancel(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInvoiceResponse(invoice))
+}
+
+// GetInvoiceXML retorna o XML da NFS-e emitida
+func (h *InvoiceHandler) GetInvoiceXML(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	content, err := h.invoiceService.XML(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(invoiceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"nfse-%d.xml\"", id))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(content))
+}
+
+func invoiceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidInvoicePeriod):
+		return http.StatusBadRequest
+	case errors.Is(err, nfse.ErrRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrInvoiceStatus),
+		errors.Is(err, repository.ErrInvoiceNoTickets),
+		errors.Is(err, service.ErrNothingToInvoice),
+		errors.Is(err, service.ErrIssuerNotConfigured),
+		errors.Is(err, service.ErrInvoiceNotIssued):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrInvoiceNotFound),
+		errors.Is(err, nfse.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -91,6 +91,34 @@ func (h *ProblemHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Patch altera só os campos enviados (JSON Merge Patch)
+func (h *ProblemHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid problem ID"})
+		return
+	}
+
+	current, err := h.problemService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Problem not found"})
+		return
+	}
+
+	var req dto.ProblemRequest
+	if !bindMergePatch(c, dto.ToProblemRequest(current), &req) {
+		return
+	}
+
+	updatedProblem, err := h.problemService.Update(c.Request.Context(), id, req.ToProblemDomain())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProblemResponse(updatedProblem))
+}
+
 func (h *ProblemHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -39,7 +39,7 @@ func (h *ProviderHandler) Create(c *gin.Context) {
 		Complement:   req.Complement,
 	}
 
-	id, err := h.service.Create(context.Background(), &provider)
+	id, err := h.service.Create(c.Request.Context(), &provider)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create provider"})
 		return
@@ -59,7 +59,7 @@ func (h *ProviderHandler) Create(c *gin.Context) {
 }
 
 func (h *ProviderHandler) List(c *gin.Context) {
-	providers, err := h.service.List(context.Background())
+	providers, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list providers"})
 		return
@@ -67,17 +67,7 @@ func (h *ProviderHandler) List(c *gin.Context) {
 
 	response := make([]dto.ProviderResponse, 0, len(providers))
 	for _, provider := range providers {
-		response = append(response, dto.ProviderResponse{
-			ID:           strconv.Itoa(provider.ID),
-			Name:         provider.Name,
-			Mobile:       provider.Mobile,
-			Zipcode:      provider.Zipcode,
-			State:        provider.State,
-			City:         provider.City,
-			Neighborhood: provider.Neighborhood,
-			Address:      provider.Address,
-			Complement:   provider.Complement,
-		})
+		response = append(response, dto.ToProviderResponse(&provider))
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -91,9 +81,9 @@ func (h *ProviderHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	provider, err := h.service.FindByID(context.Background(), id)
+	provider, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
-		if err == repository.ErrNotFound {
+		if providerErrorStatus(err) == http.StatusNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
 			return
 		}
@@ -101,23 +91,14 @@ func (h *ProviderHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ProviderResponse{
-		ID:           strconv.Itoa(provider.ID),
-		Name:         provider.Name,
-		Mobile:       provider.Mobile,
-		Zipcode:      provider.Zipcode,
-		State:        provider.State,
-		City:         provider.City,
-		Neighborhood: provider.Neighborhood,
-		Address:      provider.Address,
-		Complement:   provider.Complement,
-	})
+	setETag(c, provider.Version)
+	c.JSON(http.StatusOK, dto.ToProviderResponse(provider))
 }
 
 func (h *ProviderHandler) FindByName(c *gin.Context) {
 	name := c.Param("name")
 
-	provider, err := h.service.FindByName(context.Background(), name)
+	provider, err := h.service.FindByName(c.Request.Context(), name)
 	if err != nil {
 		if err == repository.ErrProviderNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
@@ -127,17 +108,7 @@ func (h *ProviderHandler) FindByName(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ProviderResponse{
-		ID:           strconv.Itoa(provider.ID),
-		Name:         provider.Name,
-		Mobile:       provider.Mobile,
-		Zipcode:      provider.Zipcode,
-		State:        provider.State,
-		City:         provider.City,
-		Neighborhood: provider.Neighborhood,
-		Address:      provider.Address,
-		Complement:   provider.Complement,
-	})
+	c.JSON(http.StatusOK, dto.ToProviderResponse(provider))
 }
 
 func (h *ProviderHandler) Update(c *gin.Context) {
@@ -148,6 +119,11 @@ func (h *ProviderHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req dto.ProviderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
@@ -164,11 +140,70 @@ func (h *ProviderHandler) Update(c *gin.Context) {
 		Neighborhood: req.Neighborhood,
 		Address:      req.Address,
 		Complement:   req.Complement,
+		Version:      version,
 	}
 
-	err = h.service.Update(context.Background(), &provider)
+	h.update(c, &provider)
+}
+
+// Patch altera só os campos enviados (JSON Merge Patch)
+func (h *ProviderHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		if err == repository.ErrNotFound {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	current, err := h.service.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(providerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if version > 0 && version != current.Version {
+		respondVersionConflict(c, current.Version, dto.ToProviderResponse(current))
+		return
+	}
+
+	var req dto.ProviderRequest
+	if !bindMergePatch(c, dto.ToProviderRequest(current), &req) {
+		return
+	}
+
+	// O patch foi aplicado sobre a versão lida; se o prestador mudou nesse meio tempo, responde 412
+	h.update(c, &domain.Provider{
+		ID:           id,
+		Name:         req.Name,
+		Mobile:       req.Mobile,
+		Zipcode:      req.Zipcode,
+		State:        req.State,
+		City:         req.City,
+		Neighborhood: req.Neighborhood,
+		Address:      req.Address,
+		Complement:   req.Complement,
+		Version:      current.Version,
+	})
+}
+
+// update grava o prestador e responde com a nova versão, ou 412 com a atual em caso de conflito
+func (h *ProviderHandler) update(c *gin.Context, provider *domain.Provider) {
+	err := h.service.Update(c.Request.Context(), provider)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.service.FindByID(c.Request.Context(), provider.ID)
+		if err != nil {
+			c.JSON(providerErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		respondVersionConflict(c, current.Version, dto.ToProviderResponse(current))
+		return
+	}
+	if err != nil {
+		if providerErrorStatus(err) == http.StatusNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
 			return
 		}
@@ -176,17 +211,8 @@ func (h *ProviderHandler) Update(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ProviderResponse{
-		ID:           strconv.Itoa(id),
-		Name:         provider.Name,
-		Mobile:       provider.Mobile,
-		Zipcode:      provider.Zipcode,
-		State:        provider.State,
-		City:         provider.City,
-		Neighborhood: provider.Neighborhood,
-		Address:      provider.Address,
-		Complement:   provider.Complement,
-	})
+	setETag(c, provider.Version)
+	c.JSON(http.StatusOK, dto.ToProviderResponse(provider))
 }
 
 func (h *ProviderHandler) Delete(c *gin.Context) {
@@ -197,7 +223,7 @@ func (h *ProviderHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
@@ -209,3 +235,14 @@ func (h *ProviderHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Provider deleted successfully"})
 }
+
+// providerErrorStatus mapeia os erros de prestador para o status HTTP
+func providerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrProviderNotFound),
+		errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
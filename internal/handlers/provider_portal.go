@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ProviderPortalHandler struct {
+	portalService service.ProviderPortalService
+}
+
+func NewProviderPortalHandler(portalService service.ProviderPortalService) *ProviderPortalHandler {
+	return &ProviderPortalHandler{
+		portalService: portalService,
+	}
+}
+
+func (h *ProviderPortalHandler) ListMyTickets(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	tickets, total, err := h.portalService.ListTickets(c.Request.Context(), c.GetInt("user_id"), limit, offset)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   tickets,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *ProviderPortalHandler) GetMyTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	ticket, err := h.portalService.GetTicket(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+func (h *ProviderPortalHandler) AcceptTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	err = h.portalService.AcceptTicket(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket accepted successfully"})
+}
+
+func (h *ProviderPortalHandler) DeclineTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.DeclineTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.portalService.DeclineTicket(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket declined successfully"})
+}
+
+func (h *ProviderPortalHandler) UpdateTicketStatus(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.TicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.portalService.UpdateStatus(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket status updated successfully"})
+}
+
+func (h *ProviderPortalHandler) UploadAttachment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.portalService.UploadAttachment(c.Request.Context(), c.GetInt("user_id"), ticketID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), file)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(attachment))
+}
+
+func (h *ProviderPortalHandler) RecordTime(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.TimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.portalService.RecordTime(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *ProviderPortalHandler) ListTimeEntries(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	entries, err := h.portalService.ListTimeEntries(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *ProviderPortalHandler) SubmitExpense(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.ExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense, err := h.portalService.SubmitExpense(c.Request.Context(), c.GetInt("user_id"), ticketID, &req)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToExpenseResponse(expense))
+}
+
+func (h *ProviderPortalHandler) ListExpenses(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	expenses, err := h.portalService.ListExpenses(c.Request.Context(), c.GetInt("user_id"), ticketID)
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToExpenseResponseList(expenses))
+}
+
+// GetCalendarFeedURL retorna a URL assinada do feed .ics da agenda do técnico logado
+func (h *ProviderPortalHandler) GetCalendarFeedURL(c *gin.Context) {
+	providerID, token, err := h.portalService.CalendarFeedToken(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(portalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provider_id": providerID,
+		"url":         calendarFeedURL(c, providerID, token),
+	})
+}
+
+func portalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotProviderUser):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTicketNotAssigned):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAssignmentNotAccepted), errors.Is(err, service.ErrInvalidStatusTransition),
+		errors.Is(err, service.ErrQuoteApprovalRequired):
+		return http.StatusConflict
+	default:
+		return expenseErrorStatus(err)
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseHandler struct {
+	purchaseService service.PurchaseService
+}
+
+func NewPurchaseHandler(purchaseService service.PurchaseService) *PurchaseHandler {
+	return &PurchaseHandler{
+		purchaseService: purchaseService,
+	}
+}
+
+func (h *PurchaseHandler) CreateSupplier(c *gin.Context) {
+	var req dto.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := h.purchaseService.CreateSupplier(c.Request.Context(), &domain.Supplier{
+		Name:     req.Name,
+		Document: req.Document,
+		Email:    req.Email,
+		Phone:    req.Phone,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToSupplierResponse(supplier))
+}
+
+func (h *PurchaseHandler) ListSuppliers(c *gin.Context) {
+	suppliers, err := h.purchaseService.ListSuppliers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSupplierResponseList(suppliers))
+}
+
+func (h *PurchaseHandler) GetSupplier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid supplier ID"})
+		return
+	}
+
+	supplier, err := h.purchaseService.FindSupplierByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSupplierResponse(supplier))
+}
+
+func (h *PurchaseHandler) UpdateSupplier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid supplier ID"})
+		return
+	}
+
+	var req dto.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := h.purchaseService.UpdateSupplier(c.Request.Context(), &domain.Supplier{
+		ID:       id,
+		Name:     req.Name,
+		Document: req.Document,
+		Email:    req.Email,
+		Phone:    req.Phone,
+	})
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSupplierResponse(supplier))
+}
+
+func (h *PurchaseHandler) CreatePurchaseRequest(c *gin.Context) {
+	var req dto.PurchaseRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	purchase, err := h.purchaseService.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToPurchaseRequestResponse(purchase))
+}
+
+func (h *PurchaseHandler) ListPurchaseRequests(c *gin.Context) {
+	var ticketID *int
+	if value := c.Query("ticket_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+			return
+		}
+		ticketID = &id
+	}
+
+	purchases, err := h.purchaseService.List(c.Request.Context(), ticketID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponseList(purchases))
+}
+
+func (h *PurchaseHandler) GetTicketPurchaseRequests(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	purchases, err := h.purchaseService.List(c.Request.Context(), &ticketID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponseList(purchases))
+}
+
+func (h *PurchaseHandler) GetPurchaseRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid purchase request ID"})
+		return
+	}
+
+	purchase, err := h.purchaseService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponse(purchase))
+}
+
+func (h *PurchaseHandler) UpdatePurchaseRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid purchase request ID"})
+		return
+	}
+
+	var req dto.UpdatePurchaseRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	purchase, err := h.purchaseService.UpdateQuote(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponse(purchase))
+}
+
+func (h *PurchaseHandler) ApprovePurchaseRequest(c *gin.Context) {
+	h.decide(c, h.purchaseService.Approve)
+}
+
+func (h *PurchaseHandler) RejectPurchaseRequest(c *gin.Context) {
+	h.decide(c, h.purchaseService.Reject)
+}
+
+func (h *PurchaseHandler) ReceivePurchaseRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid purchase request ID"})
+		return
+	}
+
+	// Corpo opcional
+	var req dto.PurchaseReceiveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	purchase, err := h.purchaseService.Receive(c.Request.Context(), id, req.LocationID)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponse(purchase))
+}
+
+// decide registra a decisão do usuário autenticado (Financeiro)
+func (h *PurchaseHandler) decide(c *gin.Context, decision func(ctx context.Context, id, userID int, note string) (*domain.PurchaseRequest, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid purchase request ID"})
+		return
+	}
+
+	var req dto.PurchaseDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	purchase, err := decision(c.Request.Context(), id, c.GetInt("user_id"), req.Note)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseRequestResponse(purchase))
+}
+
+func purchaseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPurchaseItems):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrPurchaseRequestStatus),
+		errors.Is(err, service.ErrTicketConcluded),
+		errors.Is(err, service.ErrNoWarehouse):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrSupplierNotFound),
+		errors.Is(err, repository.ErrPurchaseRequestNotFound),
+		errors.Is(err, repository.ErrInventoryItemNotFound),
+		errors.Is(err, repository.ErrStockLocationNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
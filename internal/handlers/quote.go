@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type QuoteHandler struct {
+	quoteService service.QuoteService
+}
+
+func NewQuoteHandler(quoteService service.QuoteService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService: quoteService,
+	}
+}
+
+func (h *QuoteHandler) GenerateQuote(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	// Corpo opcional
+	var req dto.QuoteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	quote, err := h.quoteService.Generate(c.Request.Context(), ticketID, &req)
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToQuoteResponse(quote))
+}
+
+func (h *QuoteHandler) ListQuotes(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	quotes, err := h.quoteService.List(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponseList(quotes))
+}
+
+func (h *QuoteHandler) GetQuote(c *gin.Context) {
+	ticketID, quoteID, ok := quoteParams(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoteService.FindByID(c.Request.Context(), ticketID, quoteID)
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponse(quote))
+}
+
+func (h *QuoteHandler) SendQuote(c *gin.Context) {
+	ticketID, quoteID, ok := quoteParams(c)
+	if !ok {
+		return
+	}
+
+	// Corpo opcional: sem destinatários, apenas gera o link
+	var req dto.QuoteSendRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	link, err := h.quoteService.Send(c.Request.Context(), ticketID, quoteID, &req)
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+func (h *QuoteHandler) GetQuoteByToken(c *gin.Context) {
+	quote, err := h.quoteService.FindByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponse(quote))
+}
+
+func (h *QuoteHandler) ApproveQuoteByToken(c *gin.Context) {
+	h.decideByToken(c, domain.QuoteApproved)
+}
+
+func (h *QuoteHandler) RejectQuoteByToken(c *gin.Context) {
+	h.decideByToken(c, domain.QuoteRejected)
+}
+
+func (h *QuoteHandler) decideByToken(c *gin.Context, status string) {
+	var req dto.QuoteLinkDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.quoteService.DecideByToken(c.Request.Context(), c.Param("token"), &domain.QuoteDecision{
+		Status:    status,
+		DecidedBy: req.Name,
+		Note:      req.Note,
+	})
+	if err != nil {
+		c.JSON(quoteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToQuoteResponse(quote))
+}
+
+// quoteParams lê os ids do ticket e do orçamento da rota; responde 400 quando inválidos
+func quoteParams(c *gin.Context) (int, int, bool) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return 0, 0, false
+	}
+
+	quoteID, err := strconv.Atoi(c.Param("quote_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quote ID"})
+		return 0, 0, false
+	}
+
+	return ticketID, quoteID, true
+}
+
+func quoteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrQuoteStatus),
+		errors.Is(err, service.ErrQuoteWithoutSolutions),
+		errors.Is(err, service.ErrSenderNotConfigured):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrQuoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidQuoteLink),
+		errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
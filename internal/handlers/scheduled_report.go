@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/report"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledReportHandler struct {
+	reportService service.ScheduledReportService
+}
+
+func NewScheduledReportHandler(reportService service.ScheduledReportService) *ScheduledReportHandler {
+	return &ScheduledReportHandler{
+		reportService: reportService,
+	}
+}
+
+func (h *ScheduledReportHandler) CreateReport(c *gin.Context) {
+	var req dto.ReportDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	definition, err := h.reportService.Create(c.Request.Context(), req.ToReportDefinitionDomain())
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToReportDefinitionResponse(definition))
+}
+
+func (h *ScheduledReportHandler) ListReports(c *gin.Context) {
+	definitions, err := h.reportService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReportDefinitionResponseList(definitions))
+}
+
+func (h *ScheduledReportHandler) GetReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	definition, err := h.reportService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReportDefinitionResponse(definition))
+}
+
+func (h *ScheduledReportHandler) UpdateReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var req dto.ReportDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	definition := req.ToReportDefinitionDomain()
+	definition.ID = id
+
+	updated, err := h.reportService.Update(c.Request.Context(), definition)
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReportDefinitionResponse(updated))
+}
+
+func (h *ScheduledReportHandler) DeleteReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	if err := h.reportService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RunReport gera e envia o relatório agora, com o último período completo
+func (h *ScheduledReportHandler) RunReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	file, err := h.reportService.Run(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToReportFileResponse(file))
+}
+
+// ListReportFiles lista os arquivos gerados, do mais recente ao mais antigo
+func (h *ScheduledReportHandler) ListReportFiles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	files, err := h.reportService.ListFiles(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReportFileResponseList(files))
+}
+
+// DownloadReportFile baixa um arquivo gerado
+func (h *ScheduledReportHandler) DownloadReportFile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	fileID, err := strconv.Atoi(c.Param("file_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+		return
+	}
+
+	file, err := h.reportService.FindFile(c.Request.Context(), id, fileID)
+	if err != nil {
+		c.JSON(scheduledReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", file.ContentType)
+	c.FileAttachment(file.Path, file.FileName)
+}
+
+func scheduledReportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidReportType),
+		errors.Is(err, service.ErrInvalidReportSchedule),
+		errors.Is(err, service.ErrReportClientRequired),
+		errors.Is(err, report.ErrUnknownFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrReportDefinitionNotFound),
+		errors.Is(err, repository.ErrReportFileNotFound),
+		errors.Is(err, service.ErrReportFileMissing):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
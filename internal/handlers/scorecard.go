@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Período padrão dos scorecards quando from/to não são informados
+const defaultScorecardDays = 90
+
+type ScorecardHandler struct {
+	scorecardService service.ScorecardService
+}
+
+func NewScorecardHandler(scorecardService service.ScorecardService) *ScorecardHandler {
+	return &ScorecardHandler{
+		scorecardService: scorecardService,
+	}
+}
+
+// GetProviderScorecard retorna os indicadores do técnico (query from/to e rework_days)
+func (h *ScorecardHandler) GetProviderScorecard(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	filter, ok := scorecardFilter(c)
+	if !ok {
+		return
+	}
+
+	scorecard, err := h.scorecardService.FindByProvider(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(scorecardErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scorecard)
+}
+
+// GetProviderRanking ordena os técnicos com tickets no período (query sort, from/to e rework_days)
+func (h *ScorecardHandler) GetProviderRanking(c *gin.Context) {
+	filter, ok := scorecardFilter(c)
+	if !ok {
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort", service.DefaultRankingSort)
+	scorecards, err := h.scorecardService.Ranking(c.Request.Context(), filter, sortBy)
+	if err != nil {
+		c.JSON(scorecardErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if scorecards == nil {
+		scorecards = []domain.Scorecard{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      filter.From,
+		"to":        filter.To,
+		"sort":      sortBy,
+		"providers": scorecards,
+	})
+}
+
+// scorecardFilter lê o período e a janela de retrabalho. Responde 400 e retorna ok=false quando inválidos.
+func scorecardFilter(c *gin.Context) (domain.ScorecardFilter, bool) {
+	from, to, ok := reportPeriod(c, defaultScorecardDays)
+	if !ok {
+		return domain.ScorecardFilter{}, false
+	}
+
+	filter := domain.ScorecardFilter{From: from, To: to, ReworkDays: domain.DefaultReworkDays}
+	if value := c.Query("rework_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rework_days"})
+			return domain.ScorecardFilter{}, false
+		}
+		filter.ReworkDays = days
+	}
+
+	return filter, true
+}
+
+func scorecardErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidRankingSort):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrProviderNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
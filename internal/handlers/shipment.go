@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/carrier"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ShipmentHandler struct {
+	shipmentService service.ShipmentService
+}
+
+func NewShipmentHandler(shipmentService service.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{
+		shipmentService: shipmentService,
+	}
+}
+
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	var req dto.ShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToShipmentResponse(shipment))
+}
+
+func (h *ShipmentHandler) ListShipments(c *gin.Context) {
+	var ticketID *int
+	if value := c.Query("ticket_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+			return
+		}
+		ticketID = &id
+	}
+
+	shipments, err := h.shipmentService.List(c.Request.Context(), ticketID, c.Query("serial_number"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShipmentResponseList(shipments))
+}
+
+func (h *ShipmentHandler) GetTicketShipments(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	shipments, err := h.shipmentService.List(c.Request.Context(), &ticketID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShipmentResponseList(shipments))
+}
+
+func (h *ShipmentHandler) GetShipment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	shipment, err := h.shipmentService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShipmentResponse(shipment))
+}
+
+func (h *ShipmentHandler) UpdateShipment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	var req dto.UpdateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShipmentResponse(shipment))
+}
+
+func (h *ShipmentHandler) AddShipmentEvent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	var req dto.ShipmentEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.AddEvent(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToShipmentResponse(shipment))
+}
+
+func (h *ShipmentHandler) TrackShipment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shipment ID"})
+		return
+	}
+
+	shipment, err := h.shipmentService.Track(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShipmentResponse(shipment))
+}
+
+func shipmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidShipmentDirection),
+		errors.Is(err, service.ErrInvalidShipmentStatus),
+		errors.Is(err, service.ErrInvalidShipmentItems):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTrackingUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrShipmentNotFound),
+		errors.Is(err, repository.ErrInventoryItemNotFound),
+		errors.Is(err, carrier.ErrTrackingNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
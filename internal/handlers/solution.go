@@ -111,6 +111,34 @@ func (h *SolutionHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Patch altera só os campos enviados (JSON Merge Patch)
+func (h *SolutionHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid solution ID"})
+		return
+	}
+
+	current, err := h.solutionService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Solution not found"})
+		return
+	}
+
+	var req dto.SolutionRequest
+	if !bindMergePatch(c, dto.ToSolutionRequest(current), &req) {
+		return
+	}
+
+	updatedSolution, err := h.solutionService.Update(c.Request.Context(), id, req.ToSolutionDomain())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSolutionResponse(updatedSolution))
+}
+
 func (h *SolutionHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type StatementHandler struct {
+	statementService service.StatementService
+}
+
+func NewStatementHandler(statementService service.StatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+func (h *StatementHandler) CreateStatement(c *gin.Context) {
+	var req dto.StatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.statementService.Create(c.Request.Context(), req.ToStatementDomain())
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToStatementResponse(statement))
+}
+
+func (h *StatementHandler) ListStatements(c *gin.Context) {
+	var providerID *int
+	if value := c.Query("provider_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+			return
+		}
+		providerID = &id
+	}
+
+	statements, err := h.statementService.List(c.Request.Context(), providerID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatementResponseList(statements))
+}
+
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	statement, err := h.statementService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatementResponse(statement))
+}
+
+func (h *StatementHandler) DeleteStatement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	if err := h.statementService.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *StatementHandler) AddDeduction(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	var req dto.StatementDeductionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.statementService.AddDeduction(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToStatementResponse(statement))
+}
+
+func (h *StatementHandler) RemoveDeduction(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	lineID, err := strconv.Atoi(c.Param("line_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deduction ID"})
+		return
+	}
+
+	statement, err := h.statementService.RemoveDeduction(c.Request.Context(), id, lineID)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatementResponse(statement))
+}
+
+// ApproveStatement registra a aprovação pelo usuário autenticado (Pagamentos)
+func (h *StatementHandler) ApproveStatement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	statement, err := h.statementService.Approve(c.Request.Context(), id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatementResponse(statement))
+}
+
+func (h *StatementHandler) PayStatement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	// Corpo opcional
+	var req dto.StatementPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	statement, err := h.statementService.Pay(c.Request.Context(), id, req.Reference)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatementResponse(statement))
+}
+
+// GetStatementPDF retorna o extrato em PDF
+func (h *StatementHandler) GetStatementPDF(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	document, err := h.statementService.PDF(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"extrato-%d.pdf\"", id))
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// GetStatementPaymentFile retorna a remessa CNAB 240 do extrato aprovado
+func (h *StatementHandler) GetStatementPaymentFile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	file, err := h.statementService.PaymentFile(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"remessa-%d.rem\"", id))
+	c.Data(http.StatusOK, "text/plain; charset=us-ascii", file)
+}
+
+func (h *StatementHandler) GetBankAccount(c *gin.Context) {
+	providerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	account, err := h.statementService.FindBankAccount(c.Request.Context(), providerID)
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBankAccountResponse(account))
+}
+
+func (h *StatementHandler) SaveBankAccount(c *gin.Context) {
+	providerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	var req dto.BankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.statementService.SaveBankAccount(c.Request.Context(), &domain.ProviderBankAccount{
+		ProviderID:   providerID,
+		HolderName:   req.HolderName,
+		Document:     req.Document,
+		BankCode:     req.BankCode,
+		Agency:       req.Agency,
+		AgencyDigit:  req.AgencyDigit,
+		Account:      req.Account,
+		AccountDigit: req.AccountDigit,
+	})
+	if err != nil {
+		c.JSON(statementErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBankAccountResponse(account))
+}
+
+func statementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatementPeriod),
+		errors.Is(err, service.ErrDeductionTicket):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrStatementStatus),
+		errors.Is(err, repository.ErrStatementNoTickets),
+		errors.Is(err, service.ErrStatementNotApproved),
+		errors.Is(err, service.ErrNothingToPay),
+		errors.Is(err, service.ErrPayerNotConfigured):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrProviderNotFound),
+		errors.Is(err, repository.ErrStatementNotFound),
+		errors.Is(err, repository.ErrStatementLineNotFound),
+		errors.Is(err, repository.ErrBankAccountNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
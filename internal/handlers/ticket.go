@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
 	"github.com/ericolvr/maintenance-v2/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -28,7 +30,7 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 
 	ticket, err := h.ticketService.Create(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -66,6 +68,7 @@ func (h *TicketHandler) FindTicketByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, ticket.Version)
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -76,21 +79,92 @@ func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ticket, err := h.ticketService.Update(c.Request.Context(), id, &req)
+	ticket, err := h.ticketService.Update(c.Request.Context(), id, version, &req)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondTicketConflict(c, id)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(c, ticket.Version)
+	c.JSON(http.StatusOK, ticket)
+}
+
+// PatchTicket altera só os campos enviados (JSON Merge Patch). Os custos não entram:
+// são alterados por /tickets/:id/solutions.
+func (h *TicketHandler) PatchTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	current, err := h.ticketService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if version > 0 && version != current.Version {
+		respondVersionConflict(c, current.Version, current)
+		return
+	}
+
+	var req dto.UpdateTicketRequest
+	if !bindMergePatch(c, current.ToUpdateTicketRequest(), &req) {
+		return
+	}
+
+	if req.SolutionItems != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "solution_items cannot be patched; use /tickets/:id/solutions"})
+		return
+	}
+
+	// O patch foi aplicado sobre a versão lida; se o ticket mudou nesse meio tempo, responde 412
+	ticket, err := h.ticketService.Update(c.Request.Context(), id, current.Version, &req)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		h.respondTicketConflict(c, id)
+		return
+	}
+	if err != nil {
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	setETag(c, ticket.Version)
 	c.JSON(http.StatusOK, ticket)
 }
 
+// respondTicketConflict responde 412 com o ticket como está agora
+func (h *TicketHandler) respondTicketConflict(c *gin.Context, id int) {
+	current, err := h.ticketService.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	respondVersionConflict(c, current.Version, current)
+}
+
 func (h *TicketHandler) DeleteTicket(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -100,7 +174,7 @@ func (h *TicketHandler) DeleteTicket(c *gin.Context) {
 
 	err = h.ticketService.Delete(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(ticketErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -132,7 +206,7 @@ func (h *TicketHandler) AddProviderToTicket(c *gin.Context) {
 
 	err = h.ticketService.AddProvider(c.Request.Context(), ticketID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondVisitError(c, err)
 		return
 	}
 
@@ -170,3 +244,12 @@ func (h *TicketHandler) GetProviderOnTicket(c *gin.Context) {
 
 	c.JSON(http.StatusOK, provider)
 }
+
+// ticketErrorStatus trata os custos travados pelo extrato de pagamento e o orçamento pendente
+// de aprovação; demais erros seguem assetErrorStatus
+func ticketErrorStatus(err error) int {
+	if errors.Is(err, service.ErrTicketCostsLocked) || errors.Is(err, service.ErrQuoteApprovalRequired) {
+		return http.StatusConflict
+	}
+	return assetErrorStatus(err)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TicketAttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+func NewTicketAttachmentHandler(attachmentService service.AttachmentService) *TicketAttachmentHandler {
+	return &TicketAttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+func (h *TicketAttachmentHandler) UploadAttachment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	// Campo "public" do formulário: anexo visível ao cliente no portal
+	public, _ := strconv.ParseBool(c.PostForm("public"))
+
+	attachment, err := h.attachmentService.Save(c.Request.Context(), ticketID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), public, file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(attachment))
+}
+
+func (h *TicketAttachmentHandler) GetTicketAttachments(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	attachments, err := h.attachmentService.ListByTicket(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAttachmentResponseList(attachments))
+}
+
+func (h *TicketAttachmentHandler) DownloadAttachment(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	attachment, err := h.attachmentService.FindByID(c.Request.Context(), ticketID, attachmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.FileAttachment(attachment.Path, attachment.FileName)
+}
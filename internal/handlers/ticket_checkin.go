@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TicketCheckinHandler struct {
+	checkinService service.CheckinService
+}
+
+func NewTicketCheckinHandler(checkinService service.CheckinService) *TicketCheckinHandler {
+	return &TicketCheckinHandler{
+		checkinService: checkinService,
+	}
+}
+
+func (h *TicketCheckinHandler) Checkin(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	checkin, err := h.checkinService.Checkin(c.Request.Context(), c.GetInt("user_id"), ticketID, *req.Latitude, *req.Longitude)
+	if err != nil {
+		c.JSON(checkinErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCheckinResponse(checkin))
+}
+
+func (h *TicketCheckinHandler) Checkout(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	checkin, err := h.checkinService.Checkout(c.Request.Context(), c.GetInt("user_id"), ticketID, *req.Latitude, *req.Longitude, req.ReportedKm)
+	if err != nil {
+		c.JSON(checkinErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCheckinResponse(checkin))
+}
+
+func (h *TicketCheckinHandler) GetTicketCheckins(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	checkins, err := h.checkinService.ListByTicket(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(checkinErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCheckinResponseList(checkins))
+}
+
+func checkinErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCheckinAlreadyOpen),
+		errors.Is(err, service.ErrNoOpenCheckin),
+		errors.Is(err, service.ErrTicketWithoutProvider):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant),
+		errors.Is(err, service.ErrCheckinNotAssigned):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TicketCommentHandler struct {
+	commentService service.CommentService
+}
+
+func NewTicketCommentHandler(commentService service.CommentService) *TicketCommentHandler {
+	return &TicketCommentHandler{
+		commentService: commentService,
+	}
+}
+
+func (h *TicketCommentHandler) AddCommentToTicket(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.Create(c.Request.Context(), &domain.TicketComment{
+		TicketID: ticketID,
+		Author:   req.Author,
+		Body:     req.Body,
+		Source:   domain.CommentSourceAPI,
+		Public:   req.Public,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCommentResponse(comment))
+}
+
+func (h *TicketCommentHandler) GetTicketComments(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	comments, err := h.commentService.ListByTicket(c.Request.Context(), ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentResponseList(comments))
+}
@@ -35,7 +35,7 @@ func (h *TicketProblemHandler) AddProblemToTicket(c *gin.Context) {
 
 	err = h.ticketService.AddProblemToTicket(c.Request.Context(), ticketID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(assetErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
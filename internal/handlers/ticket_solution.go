@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
 	"github.com/ericolvr/maintenance-v2/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -35,6 +37,10 @@ func (h *TicketSolutionHandler) AddSolutionToTicket(c *gin.Context) {
 
 	err = h.ticketService.AddSolutionToTicket(c.Request.Context(), ticketID, &req)
 	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) || errors.Is(err, service.ErrTicketCostsLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -76,6 +82,10 @@ func (h *TicketSolutionHandler) RemoveSolutionFromTicket(c *gin.Context) {
 
 	err = h.ticketService.RemoveSolutionFromTicket(c.Request.Context(), ticketID, solutionID)
 	if err != nil {
+		if errors.Is(err, service.ErrTicketCostsLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
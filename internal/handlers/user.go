@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -29,30 +29,38 @@ func (h *UserHandler) Create(c *gin.Context) {
 	}
 
 	user := domain.User{
-		Name:     req.Name,
-		Mobile:   req.Mobile,
-		Password: req.Password,
-		Role:     req.Role,
-		Status:   req.Status,
+		Name:       req.Name,
+		Mobile:     req.Mobile,
+		Password:   req.Password,
+		Role:       req.Role,
+		Status:     req.Status,
+		ProviderID: req.ProviderID,
+		ClientID:   req.ClientID,
 	}
 
-	id, err := h.service.Create(context.Background(), &user)
+	id, err := h.service.Create(c.Request.Context(), &user)
 	if err != nil {
+		if status := userErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, dto.UserResponse{
-		ID:     id,
-		Name:   req.Name,
-		Mobile: req.Mobile,
-		Role:   req.Role,
-		Status: req.Status,
+		ID:         id,
+		Name:       req.Name,
+		Mobile:     req.Mobile,
+		Role:       req.Role,
+		Status:     req.Status,
+		ProviderID: req.ProviderID,
+		ClientID:   req.ClientID,
 	})
 }
 
 func (h *UserHandler) List(c *gin.Context) {
-	users, err := h.service.List(context.Background())
+	users, err := h.service.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list users"})
 		return
@@ -61,11 +69,13 @@ func (h *UserHandler) List(c *gin.Context) {
 	response := make([]dto.UserResponse, 0, len(users))
 	for _, user := range users {
 		response = append(response, dto.UserResponse{
-			ID:     user.ID,
-			Name:   user.Name,
-			Mobile: user.Mobile,
-			Role:   user.Role,
-			Status: user.Status,
+			ID:         user.ID,
+			Name:       user.Name,
+			Mobile:     user.Mobile,
+			Role:       user.Role,
+			Status:     user.Status,
+			ProviderID: user.ProviderID,
+			ClientID:   user.ClientID,
 		})
 	}
 
@@ -80,7 +90,7 @@ func (h *UserHandler) FindByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.FindByID(context.Background(), id)
+	user, err := h.service.FindByID(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -91,18 +101,20 @@ func (h *UserHandler) FindByID(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.UserResponse{
-		ID:     user.ID,
-		Name:   user.Name,
-		Mobile: user.Mobile,
-		Role:   user.Role,
-		Status: user.Status,
+		ID:         user.ID,
+		Name:       user.Name,
+		Mobile:     user.Mobile,
+		Role:       user.Role,
+		Status:     user.Status,
+		ProviderID: user.ProviderID,
+		ClientID:   user.ClientID,
 	})
 }
 
 func (h *UserHandler) FindByname(c *gin.Context) {
 	name := c.Param("name")
 
-	users, err := h.service.FindByName(context.Background(), name)
+	users, err := h.service.FindByName(c.Request.Context(), name)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -119,18 +131,20 @@ func (h *UserHandler) FindByname(c *gin.Context) {
 
 	user := users[0]
 	c.JSON(http.StatusOK, dto.UserResponse{
-		ID:     user.ID,
-		Name:   user.Name,
-		Mobile: user.Mobile,
-		Role:   user.Role,
-		Status: user.Status,
+		ID:         user.ID,
+		Name:       user.Name,
+		Mobile:     user.Mobile,
+		Role:       user.Role,
+		Status:     user.Status,
+		ProviderID: user.ProviderID,
+		ClientID:   user.ClientID,
 	})
 }
 
 func (h *UserHandler) FindByMobile(c *gin.Context) {
 	mobile := c.Param("mobile")
 
-	users, err := h.service.FindByMobile(context.Background(), mobile)
+	users, err := h.service.FindByMobile(c.Request.Context(), mobile)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User or mobile not found"})
@@ -147,11 +161,13 @@ func (h *UserHandler) FindByMobile(c *gin.Context) {
 
 	user := users[0]
 	c.JSON(http.StatusOK, dto.UserResponse{
-		ID:     user.ID,
-		Name:   user.Name,
-		Mobile: user.Mobile,
-		Role:   user.Role,
-		Status: user.Status,
+		ID:         user.ID,
+		Name:       user.Name,
+		Mobile:     user.Mobile,
+		Role:       user.Role,
+		Status:     user.Status,
+		ProviderID: user.ProviderID,
+		ClientID:   user.ClientID,
 	})
 }
 
@@ -170,30 +186,77 @@ func (h *UserHandler) Update(c *gin.Context) {
 	}
 
 	user := domain.User{
-		ID:       id,
-		Name:     req.Name,
-		Mobile:   req.Mobile,
-		Password: req.Password, // Adicionando o campo Password
-		Role:     req.Role,
-		Status:   req.Status,
+		ID:         id,
+		Name:       req.Name,
+		Mobile:     req.Mobile,
+		Password:   req.Password, // Adicionando o campo Password
+		Role:       req.Role,
+		Status:     req.Status,
+		ProviderID: req.ProviderID,
+		ClientID:   req.ClientID,
 	}
 
-	err = h.service.Update(context.Background(), &user)
+	h.update(c, &user)
+}
+
+// Patch altera só os campos enviados (JSON Merge Patch); sem password, a senha atual é mantida
+func (h *UserHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	current, err := h.service.FindByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	var req dto.UserRequest
+	if !bindMergePatch(c, dto.ToUserRequest(current), &req) {
+		return
+	}
+
+	h.update(c, &domain.User{
+		ID:         id,
+		Name:       req.Name,
+		Mobile:     req.Mobile,
+		Password:   req.Password,
+		Role:       req.Role,
+		Status:     req.Status,
+		ProviderID: req.ProviderID,
+		ClientID:   req.ClientID,
+	})
+}
+
+func (h *UserHandler) update(c *gin.Context, user *domain.User) {
+	err := h.service.Update(c.Request.Context(), user)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 			return
 		}
+		if status := userErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.UserResponse{
-		ID:     id,
-		Name:   user.Name,
-		Mobile: user.Mobile,
-		Role:   user.Role,
-		Status: user.Status,
+		ID:         user.ID,
+		Name:       user.Name,
+		Mobile:     user.Mobile,
+		Role:       user.Role,
+		Status:     user.Status,
+		ProviderID: user.ProviderID,
+		ClientID:   user.ClientID,
 	})
 }
 
@@ -205,7 +268,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(context.Background(), id)
+	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -226,7 +289,7 @@ func (h *UserHandler) Authenticate(c *gin.Context) {
 		return
 	}
 
-	authResponse, err := h.service.Authenticate(context.Background(), loginData.Mobile, loginData.Password)
+	authResponse, err := h.service.Authenticate(c.Request.Context(), loginData.Mobile, loginData.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
@@ -234,3 +297,19 @@ func (h *UserHandler) Authenticate(c *gin.Context) {
 
 	c.JSON(http.StatusOK, authResponse)
 }
+
+// userErrorStatus mapeia os erros de vínculo do usuário para o status HTTP
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserClientRequired),
+		errors.Is(err, repository.ErrNotFound):
+		// ErrNotFound aqui vem do cliente vinculado, não do usuário
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Período padrão do calendário quando from/to não são informados
+const defaultCalendarDays = 30
+
+type VisitHandler struct {
+	visitService service.VisitService
+}
+
+func NewVisitHandler(visitService service.VisitService) *VisitHandler {
+	return &VisitHandler{
+		visitService: visitService,
+	}
+}
+
+func (h *VisitHandler) ScheduleVisit(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req dto.VisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	visit, err := h.visitService.Schedule(c.Request.Context(), ticketID, &req)
+	if err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToVisitResponse(visit))
+}
+
+func (h *VisitHandler) UpdateVisit(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	visitID, err := strconv.Atoi(c.Param("visit_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
+		return
+	}
+
+	var req dto.VisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	visit, err := h.visitService.Update(c.Request.Context(), ticketID, visitID, &req)
+	if err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToVisitResponse(visit))
+}
+
+func (h *VisitHandler) GetTicketVisits(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	visits, err := h.visitService.ListByTicket(c.Request.Context(), ticketID)
+	if err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToVisitResponseList(visits))
+}
+
+func (h *VisitHandler) CancelVisit(c *gin.Context) {
+	ticketID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	visitID, err := strconv.Atoi(c.Param("visit_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
+		return
+	}
+
+	if err := h.visitService.Cancel(c.Request.Context(), ticketID, visitID); err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Visit cancelled successfully"})
+}
+
+// GetProviderCalendar lista as visitas do técnico (query from/to no formato 2006-01-02)
+func (h *VisitHandler) GetProviderCalendar(c *gin.Context) {
+	providerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if value := c.Query("from"); value != "" {
+		from, err = time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if value := c.Query("to"); value != "" {
+		to, err = time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		// Incluir o dia final inteiro
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return
+	}
+
+	visits, err := h.visitService.ProviderCalendar(c.Request.Context(), providerID, from, to)
+	if err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provider_id": providerID,
+		"from":        from,
+		"to":          to,
+		"visits":      dto.ToVisitResponseList(visits),
+	})
+}
+
+// GetProviderCalendarFeedURL retorna a URL assinada do feed .ics do técnico, para assinar no celular
+func (h *VisitHandler) GetProviderCalendarFeedURL(c *gin.Context) {
+	providerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provider_id": providerID,
+		"url":         calendarFeedURL(c, providerID, h.visitService.FeedToken(providerID)),
+	})
+}
+
+// GetProviderCalendarFeed retorna o feed iCalendar (.ics) do técnico. A rota é pública:
+// apps de agenda não enviam o header Authorization, e o acesso vem do token da URL
+func (h *VisitHandler) GetProviderCalendarFeed(c *gin.Context) {
+	providerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+
+	feed, err := h.visitService.ProviderFeed(c.Request.Context(), providerID, c.Query("token"))
+	if err != nil {
+		respondVisitError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "inline; filename=\"provider-"+strconv.Itoa(providerID)+".ics\"")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// calendarFeedURL monta a URL absoluta do feed .ics com o token do técnico
+func calendarFeedURL(c *gin.Context, providerID int, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s/api/v1/providers/%d/calendar.ics?token=%s", scheme, c.Request.Host, providerID, token)
+}
+
+// respondVisitError responde erros de agenda; conflitos retornam as visitas conflitantes
+func respondVisitError(c *gin.Context, err error) {
+	var conflictErr *service.ScheduleConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     err.Error(),
+			"conflicts": dto.ToVisitResponseList(conflictErr.Conflicts),
+		})
+		return
+	}
+
+	c.JSON(visitErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+func visitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidVisitDate),
+		errors.Is(err, service.ErrInvalidVisitPeriod),
+		errors.Is(err, service.ErrOutsideOpeningHours):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTicketWithoutProvider),
+		errors.Is(err, service.ErrVisitHasSolutions),
+		errors.Is(err, service.ErrTicketCostsLocked):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrProviderNotFound),
+		errors.Is(err, repository.ErrVisitNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCrossTenant),
+		errors.Is(err, service.ErrInvalidFeedToken):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Período padrão do relatório de garantia quando from/to não são informados
+const defaultWarrantyReportDays = 30
+
+type WarrantyHandler struct {
+	warrantyService service.WarrantyService
+}
+
+func NewWarrantyHandler(warrantyService service.WarrantyService) *WarrantyHandler {
+	return &WarrantyHandler{
+		warrantyService: warrantyService,
+	}
+}
+
+// GetWarrantyReturns lista os retornos em garantia por técnico (query from/to no formato 2006-01-02)
+func (h *WarrantyHandler) GetWarrantyReturns(c *gin.Context) {
+	from, to, ok := reportPeriod(c, defaultWarrantyReportDays)
+	if !ok {
+		return
+	}
+
+	returns, err := h.warrantyService.ReturnsByProvider(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if returns == nil {
+		returns = []domain.WarrantyReturn{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      from,
+		"to":        to,
+		"providers": returns,
+	})
+}
+
+// reportPeriod lê o período dos relatórios (query from/to no formato 2006-01-02, to inclusivo).
+// Sem datas, cobre os últimos defaultDays dias até hoje. Responde 400 e retorna ok=false quando inválido.
+func reportPeriod(c *gin.Context, defaultDays int) (from, to time.Time, ok bool) {
+	now := time.Now()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return from, to, false
+		}
+		// Incluir o dia final inteiro
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	from = to.AddDate(0, 0, -defaultDays)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return from, to, false
+		}
+		from = parsed
+	}
+
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return from, to, false
+	}
+
+	return from, to, true
+}
@@ -0,0 +1,95 @@
+// Package ical gera feeds iCalendar (RFC 5545) para assinatura em apps de agenda.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event representa um VEVENT do feed
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Updated     time.Time
+}
+
+const timeLayout = "20060102T150405Z"
+
+// Write escreve um VCALENDAR com os eventos informados
+func Write(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(timeLayout)
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//maintenance-v2//calendar//PT")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "METHOD:PUBLISH")
+	writeLine(bw, "X-WR-CALNAME:"+escape(name))
+
+	for _, event := range events {
+		updated := stamp
+		if !event.Updated.IsZero() {
+			updated = event.Updated.UTC().Format(timeLayout)
+		}
+
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escape(event.UID))
+		writeLine(bw, "DTSTAMP:"+updated)
+		writeLine(bw, "DTSTART:"+event.Start.UTC().Format(timeLayout))
+		writeLine(bw, "DTEND:"+event.End.UTC().Format(timeLayout))
+		writeLine(bw, "SUMMARY:"+escape(event.Summary))
+		if event.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escape(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(bw, "LOCATION:"+escape(event.Location))
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing calendar: %w", err)
+	}
+	return nil
+}
+
+// writeLine escreve a linha com CRLF, quebrando em 75 octetos conforme a RFC 5545
+func writeLine(w *bufio.Writer, line string) {
+	limit := 75
+
+	for len(line) > limit {
+		cut := limit
+		// Não quebrar no meio de um caractere UTF-8
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Linhas de continuação começam com espaço, que conta no limite
+		limit = 74
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func escape(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
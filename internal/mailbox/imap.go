@@ -0,0 +1,232 @@
+package mailbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IMAP é um cliente IMAP4rev1 mínimo (TLS) com apenas os comandos usados na
+// ingestão: LOGIN, SELECT, UID SEARCH, UID FETCH e UID STORE.
+type IMAP struct {
+	addr     string
+	username string
+	password string
+	mailbox  string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+func NewIMAP(addr, username, password, mailbox string) *IMAP {
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	return &IMAP{
+		addr:     addr,
+		username: username,
+		password: password,
+		mailbox:  mailbox,
+	}
+}
+
+func (c *IMAP) Fetch(ctx context.Context) ([]Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	lines, _, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		c.reset()
+		return nil, err
+	}
+
+	var uids []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "* SEARCH") {
+			uids = append(uids, strings.Fields(strings.TrimPrefix(line, "* SEARCH"))...)
+		}
+	}
+
+	var messages []Message
+	for _, uid := range uids {
+		if err := ctx.Err(); err != nil {
+			return messages, err
+		}
+
+		_, literals, err := c.command("UID FETCH %s BODY.PEEK[]", uid)
+		if err != nil {
+			c.reset()
+			return messages, err
+		}
+		if len(literals) == 0 {
+			continue
+		}
+
+		msg, err := Parse(uid, bytes.NewReader(literals[0]))
+		if err != nil {
+			// Mensagem ilegível sai das próximas buscas (UNSEEN) marcada para tratamento manual
+			log.Printf("Email %s not readable, flagged for review: %v", uid, err)
+			if _, _, err := c.command(`UID STORE %s +FLAGS.SILENT (\Seen \Flagged)`, uid); err != nil {
+				c.reset()
+				return messages, fmt.Errorf("failed to flag message %s: %w", uid, err)
+			}
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+
+	return messages, nil
+}
+
+func (c *IMAP) MarkProcessed(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	if _, _, err := c.command(`UID STORE %s +FLAGS.SILENT (\Seen)`, id); err != nil {
+		c.reset()
+		return fmt.Errorf("failed to mark message %s as seen: %w", id, err)
+	}
+	return nil
+}
+
+func (c *IMAP) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	c.command("LOGOUT")
+	c.reset()
+	return nil
+}
+
+func (c *IMAP) connect(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(c.addr)
+	if err != nil {
+		return fmt.Errorf("invalid imap address: %w", err)
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 30 * time.Second},
+		Config:    &tls.Config{ServerName: host},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to imap server: %w", err)
+	}
+
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	// Saudação do servidor
+	if _, err := c.reader.ReadString('\n'); err != nil {
+		c.reset()
+		return fmt.Errorf("failed to read imap greeting: %w", err)
+	}
+
+	if _, _, err := c.command("LOGIN %s %s", quote(c.username), quote(c.password)); err != nil {
+		c.reset()
+		return fmt.Errorf("imap login failed: %w", err)
+	}
+
+	if _, _, err := c.command("SELECT %s", quote(c.mailbox)); err != nil {
+		c.reset()
+		return fmt.Errorf("failed to select mailbox %s: %w", c.mailbox, err)
+	}
+
+	return nil
+}
+
+func (c *IMAP) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
+// command envia um comando e lê a resposta até a linha com a tag correspondente.
+// Retorna as linhas não marcadas (untagged) e os literais {n} recebidos.
+func (c *IMAP) command(format string, args ...interface{}) ([]string, [][]byte, error) {
+	c.tag++
+	tag := "A" + strconv.Itoa(c.tag)
+
+	c.conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, nil, fmt.Errorf("failed to send imap command: %w", err)
+	}
+
+	var lines []string
+	var literals [][]byte
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read imap response: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// Literal: "... {123}" seguido de 123 bytes e do restante da linha
+		for strings.HasSuffix(line, "}") {
+			open := strings.LastIndex(line, "{")
+			if open < 0 {
+				break
+			}
+			size, err := strconv.Atoi(line[open+1 : len(line)-1])
+			if err != nil {
+				break
+			}
+
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(c.reader, literal); err != nil {
+				return nil, nil, fmt.Errorf("failed to read imap literal: %w", err)
+			}
+			literals = append(literals, literal)
+
+			rest, err := c.reader.ReadString('\n')
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read imap response: %w", err)
+			}
+			line = line[:open] + strings.TrimRight(rest, "\r\n")
+		}
+
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return lines, literals, fmt.Errorf("imap command failed: %s", status)
+			}
+			return lines, literals, nil
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
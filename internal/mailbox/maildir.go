@@ -0,0 +1,96 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Maildir lê mensagens de um diretório no formato maildir (new/, cur/, tmp/).
+// Mensagens em new/ são consideradas não processadas e, ao serem marcadas,
+// são movidas para cur/ com a flag "S" (seen), como faria um cliente de email.
+// Mensagens ilegíveis vão para cur/ com a flag "F" (flagged), para tratamento manual.
+type Maildir struct {
+	path string
+}
+
+func NewMaildir(path string) (*Maildir, error) {
+	for _, dir := range []string{"new", "cur", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(path, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to prepare maildir: %w", err)
+		}
+	}
+	return &Maildir{path: path}, nil
+}
+
+func (m *Maildir) Fetch(ctx context.Context) ([]Message, error) {
+	entries, err := os.ReadDir(filepath.Join(m.path, "new"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maildir: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var messages []Message
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return messages, err
+		}
+
+		msg, err := m.read(name)
+		if err != nil {
+			log.Printf("Email %s not readable, moved to quarantine: %v", name, err)
+			if err := m.quarantine(name); err != nil {
+				log.Printf("Email %s not quarantined: %v", name, err)
+			}
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+
+	return messages, nil
+}
+
+func (m *Maildir) read(name string) (*Message, error) {
+	file, err := os.Open(filepath.Join(m.path, "new", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open message %s: %w", name, err)
+	}
+	defer file.Close()
+
+	msg, err := Parse(name, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message %s: %w", name, err)
+	}
+	return msg, nil
+}
+
+// quarantine tira de new/ a mensagem ilegível, marcando-a para tratamento manual
+func (m *Maildir) quarantine(name string) error {
+	source := filepath.Join(m.path, "new", name)
+	target := filepath.Join(m.path, "cur", name+":2,F")
+	return os.Rename(source, target)
+}
+
+func (m *Maildir) MarkProcessed(ctx context.Context, id string) error {
+	source := filepath.Join(m.path, "new", filepath.Base(id))
+	target := filepath.Join(m.path, "cur", filepath.Base(id)+":2,S")
+	if err := os.Rename(source, target); err != nil {
+		return fmt.Errorf("failed to mark message %s as processed: %w", id, err)
+	}
+	return nil
+}
+
+func (m *Maildir) Close() error {
+	return nil
+}
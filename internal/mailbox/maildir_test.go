@@ -0,0 +1,77 @@
+package mailbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validMessage = "From: Agência Centro <centro@banco.com.br>\r\n" +
+	"Subject: Chamado 001-0001 ar condicionado\r\n" +
+	"Message-ID: <abc@banco.com.br>\r\n" +
+	"Date: Mon, 19 Oct 2026 09:00:00 -0300\r\n" +
+	"\r\n" +
+	"Ar condicionado parou.\r\n"
+
+func writeMessage(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "new", name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaildirFetchQuarantinesUnreadableMessages(t *testing.T) {
+	dir := t.TempDir()
+	maildir, err := NewMaildir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeMessage(t, dir, "1-ok", validMessage)
+	writeMessage(t, dir, "2-bad", "not a header line\r\n\r\nbody")
+	writeMessage(t, dir, "3-ok", strings.Replace(validMessage, "<abc@", "<def@", 1))
+
+	messages, err := maildir.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != "1-ok" || messages[1].ID != "3-ok" {
+		t.Fatalf("got %+v, want the two readable messages", messages)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "cur", "2-bad:2,F")); err != nil {
+		t.Fatalf("unreadable message not quarantined: %v", err)
+	}
+
+	// A mensagem em quarentena não volta nas próximas leituras
+	messages, err = maildir.Fetch(context.Background())
+	if err != nil || len(messages) != 2 {
+		t.Fatalf("got %d messages, err %v; want 2", len(messages), err)
+	}
+}
+
+func TestParseMessageID(t *testing.T) {
+	msg, err := Parse("1", strings.NewReader(validMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageID != "<abc@banco.com.br>" {
+		t.Fatalf("got Message-ID %q", msg.MessageID)
+	}
+
+	// Sem o header, o conteúdo identifica a mensagem de forma estável
+	withoutID := strings.Replace(validMessage, "Message-ID: <abc@banco.com.br>\r\n", "", 1)
+	first, err := Parse("1", strings.NewReader(withoutID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Parse("2", strings.NewReader(withoutID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first.MessageID, "sha256:") || first.MessageID != second.MessageID {
+		t.Fatalf("got %q and %q, want the same content hash", first.MessageID, second.MessageID)
+	}
+}
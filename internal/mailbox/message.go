@@ -0,0 +1,185 @@
+package mailbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Source é a origem das mensagens lidas pelo worker de ingestão (IMAP ou maildir)
+type Source interface {
+	// Fetch retorna as mensagens ainda não processadas. Mensagens que não podem ser lidas
+	// ficam em quarentena (fora das próximas leituras) e não interrompem as demais.
+	Fetch(ctx context.Context) ([]Message, error)
+	// MarkProcessed marca a mensagem como processada para não ser lida novamente
+	MarkProcessed(ctx context.Context, id string) error
+	Close() error
+}
+
+// Message representa um email já decodificado
+type Message struct {
+	ID          string
+	MessageID   string // Header Message-ID ou, na falta dele, hash do conteúdo
+	From        string
+	FromName    string
+	Subject     string
+	Body        string
+	Date        time.Time
+	Attachments []Attachment
+}
+
+// Attachment representa um arquivo anexado ao email
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// SenderDomain retorna o domínio do remetente (ex: "basa.com.br")
+func (m *Message) SenderDomain() string {
+	at := strings.LastIndex(m.From, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(m.From[at+1:])
+}
+
+var wordDecoder = &mime.WordDecoder{}
+
+// Parse decodifica uma mensagem RFC 5322, incluindo corpo e anexos MIME
+func Parse(id string, r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	msg := &Message{ID: id}
+
+	if from, err := mail.ParseAddress(raw.Header.Get("From")); err == nil {
+		msg.From = strings.ToLower(from.Address)
+		msg.FromName = from.Name
+	} else {
+		msg.From = strings.ToLower(strings.TrimSpace(raw.Header.Get("From")))
+	}
+
+	subject := raw.Header.Get("Subject")
+	if decoded, err := wordDecoder.DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+	msg.Subject = strings.TrimSpace(subject)
+
+	if date, err := raw.Header.Date(); err == nil {
+		msg.Date = date
+	} else {
+		msg.Date = time.Now()
+	}
+
+	if err := parsePart(msg, raw.Header.Get("Content-Type"), raw.Header.Get("Content-Transfer-Encoding"), "", raw.Body); err != nil {
+		return nil, err
+	}
+
+	msg.Body = strings.TrimSpace(msg.Body)
+
+	msg.MessageID = strings.TrimSpace(raw.Header.Get("Message-Id"))
+	if msg.MessageID == "" {
+		// Sem Message-ID, o conteúdo identifica a mensagem em uma nova leitura
+		sum := sha256.Sum256([]byte(strings.Join([]string{msg.From, raw.Header.Get("Date"), msg.Subject, msg.Body}, "\n")))
+		msg.MessageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return msg, nil
+}
+
+func parsePart(msg *Message, contentType, encoding, disposition string, body io.Reader) error {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read multipart section: %w", err)
+			}
+
+			err = parsePart(msg,
+				part.Header.Get("Content-Type"),
+				part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"),
+				part)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(encoding, body))
+	if err != nil {
+		return fmt.Errorf("failed to decode message part: %w", err)
+	}
+
+	fileName := attachmentName(disposition, params)
+	if fileName == "" && strings.HasPrefix(mediaType, "text/") {
+		// Corpo em texto puro tem prioridade sobre a versão HTML
+		if mediaType == "text/plain" || msg.Body == "" {
+			msg.Body = string(data)
+		}
+		return nil
+	}
+
+	if fileName == "" {
+		fileName = "anexo"
+	}
+
+	msg.Attachments = append(msg.Attachments, Attachment{
+		FileName:    fileName,
+		ContentType: mediaType,
+		Data:        data,
+	})
+	return nil
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func attachmentName(disposition string, contentTypeParams map[string]string) string {
+	if disposition != "" {
+		if _, params, err := mime.ParseMediaType(disposition); err == nil && params["filename"] != "" {
+			return decodeWord(params["filename"])
+		}
+	}
+	return decodeWord(contentTypeParams["name"])
+}
+
+func decodeWord(value string) string {
+	if decoded, err := wordDecoder.DecodeHeader(value); err == nil {
+		return decoded
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Fake guarda as mensagens em memória para desenvolvimento local e testes
+type Fake struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, msg)
+	log.Printf("Fake mailer: %q to %v (%d attachments)", msg.Subject, msg.To, len(msg.Attachments))
+	return nil
+}
+
+// Sent retorna as mensagens enviadas, da mais antiga para a mais recente
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.sent...)
+}
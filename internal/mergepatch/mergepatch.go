@@ -0,0 +1,52 @@
+// Package mergepatch aplica documentos JSON Merge Patch (RFC 7396).
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPatch indica que o patch não é um objeto JSON
+var ErrInvalidPatch = errors.New("merge patch must be a JSON object")
+
+// Apply aplica o patch sobre o documento: campos com null são removidos, objetos são
+// mesclados recursivamente e qualquer outro valor substitui o atual.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	if _, ok := patchValue.(map[string]interface{}); !ok {
+		return nil, ErrInvalidPatch
+	}
+
+	var docValue interface{}
+	if err := json.Unmarshal(doc, &docValue); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	return json.Marshal(merge(docValue, patchValue))
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+
+	return targetObject
+}
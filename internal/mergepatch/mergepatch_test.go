@@ -0,0 +1,78 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Casos baseados no apêndice A da RFC 7396
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"replaces a field", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"adds a field", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null removes a field", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"null on a missing field", `{"a":"b"}`, `{"c":null}`, `{"a":"b"}`},
+		{"empty patch keeps the document", `{"a":"b","n":1}`, `{}`, `{"a":"b","n":1}`},
+		{"nested objects are merged", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"x","d":null}}`, `{"a":{"b":"x"}}`},
+		// Listas não são mescladas: o valor do patch substitui a lista inteira
+		{"arrays are replaced", `{"a":[{"b":"c"}],"n":[1,2]}`, `{"a":[1],"n":[]}`, `{"a":[1],"n":[]}`},
+		{"object replaces a scalar", `{"a":"b"}`, `{"a":{"c":"d","e":null}}`, `{"a":{"c":"d"}}`},
+		{"scalar replaces an object", `{"a":{"b":"c"}}`, `{"a":0}`, `{"a":0}`},
+		{"zero values are kept", `{"a":"b","n":3,"ok":true}`, `{"a":"","n":0,"ok":false}`, `{"a":"","n":0,"ok":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotValue, wantValue interface{}
+			if err := json.Unmarshal(got, &gotValue); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		invalid bool // ErrInvalidPatch: o patch é JSON válido, mas não é um objeto
+	}{
+		// O PATCH altera campos: patch que substitui o documento inteiro é recusado
+		{"array patch", `{"a":"b"}`, `["c"]`, true},
+		{"null patch", `{"a":"b"}`, `null`, true},
+		{"scalar patch", `{"a":"b"}`, `"c"`, true},
+		{"malformed patch", `{"a":"b"}`, `{"a":`, false},
+		{"malformed document", `{"a":`, `{"a":"c"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if errors.Is(err, ErrInvalidPatch) != tt.invalid {
+				t.Fatalf("got %v, want ErrInvalidPatch=%v", err, tt.invalid)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,50 +20,146 @@ func AuthMiddleware(jwtSecret []byte) gin.HandlerFunc {
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be 'Bearer <token>'"})
-			c.Abort()
+		if !authenticate(c, authHeader, jwtSecret) {
 			return
 		}
 
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return jwtSecret, nil
-		})
+		c.Next()
+	}
+}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+// publicRoutes são as rotas acessadas sem token: o login, os links de aprovação de
+// orçamento enviados ao cliente e o feed .ics assinado no celular, que carregam o próprio
+// token na URL e são validados no handler
+var publicRoutes = map[string]bool{
+	"/api/v1/users/auth":                 true,
+	"/api/v1/quotes/:token":              true,
+	"/api/v1/quotes/:token/approve":      true,
+	"/api/v1/quotes/:token/reject":       true,
+	"/api/v1/providers/:id/calendar.ics": true,
+}
+
+// clientPortalPrefix é a área da API liberada para usuários de cliente
+const clientPortalPrefix = "/api/v1/client/"
+
+// Tenant exige o token em todas as rotas, exceto as públicas, e carrega o cliente do token
+// para que os repositórios restrinjam os dados. Usuários de cliente só acessam o portal do
+// cliente; nas rotas internas recebem 403.
+func Tenant(jwtSecret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		// Rota inexistente segue para o 404
+		if path == "" || publicRoutes[path] {
+			c.Next()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Failed to extract claims"})
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			c.Abort()
 			return
 		}
 
-		_, mobilOK := claims["mobile"].(string)
-		if mobilOK {
-			c.Set("user_id", 16)
+		if !authenticate(c, authHeader, jwtSecret) {
+			return
 		}
 
-		name, nameOK := claims["name"].(string)
-		if nameOK {
-			c.Set("user_name", name)
+		if role, _ := c.Get("role"); role == domain.RoleCliente && !strings.HasPrefix(path, clientPortalPrefix) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied for this role"})
+			c.Abort()
+			return
 		}
 
-		role, roleOK := claims["role"].(float64)
-		if roleOK {
-			c.Set("role", int(role))
-			c.Set("is_admin", role == 0)
+		c.Next()
+	}
+}
+
+// authenticate valida o token e carrega as claims no gin.Context e o escopo (cliente ou
+// acesso interno) no context da requisição. Em caso de erro responde 401 e retorna false.
+func authenticate(c *gin.Context, authHeader string, jwtSecret []byte) bool {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be 'Bearer <token>'"})
+		c.Abort()
+		return false
+	}
+
+	tokenString := parts[1]
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return jwtSecret, nil
+	})
 
-		c.Next()
+	if err != nil || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Failed to extract claims"})
+		c.Abort()
+		return false
+	}
+
+	userID, idOK := claims["id"].(float64)
+	if idOK {
+		c.Set("user_id", int(userID))
+	}
+
+	mobile, mobileOK := claims["mobile"].(string)
+	if mobileOK {
+		c.Set("user_mobile", mobile)
+	}
+
+	name, nameOK := claims["name"].(string)
+	if nameOK {
+		c.Set("user_name", name)
+	}
+
+	role, roleOK := claims["role"].(float64)
+	if roleOK {
+		c.Set("role", int(role))
+		c.Set("is_admin", role == 0)
+	}
+
+	// Usuário de cliente fica restrito ao próprio cliente; os demais são internos e acessam
+	// todos os clientes. Token de cliente sem client_id não tem escopo e é recusado.
+	clientID, clientOK := claims["client_id"].(float64)
+	switch {
+	case clientOK:
+		c.Set("client_id", int(clientID))
+		c.Request = c.Request.WithContext(tenant.WithClient(c.Request.Context(), int(clientID)))
+	case roleOK && int(role) == domain.RoleCliente:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
+	default:
+		c.Request = c.Request.WithContext(tenant.System(c.Request.Context()))
+	}
+
+	return true
+}
+
+// RequireRole bloqueia o acesso de usuários cujo papel não está entre os permitidos.
+// Deve ser usado depois de AuthMiddleware.
+func RequireRole(roles ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := c.Get("role")
+		if ok {
+			for _, allowed := range roles {
+				if role.(int) == allowed {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied for this role"})
+		c.Abort()
 	}
 }
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+var testSecret = []byte("test-secret")
+
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tenant(testSecret))
+
+	ok := func(c *gin.Context) {
+		clientID, _ := tenant.ClientID(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"client_id": clientID, "system": tenant.IsSystem(c.Request.Context())})
+	}
+	router.POST("/api/v1/users/auth", ok)
+	router.GET("/api/v1/quotes/:token", ok)
+	router.GET("/api/v1/providers/:id/calendar.ics", ok)
+	router.GET("/api/v1/providers/:id/calendar", ok)
+	router.GET("/api/v1/tickets/:id", ok)
+	router.GET("/api/v1/shipments", ok)
+	router.GET("/api/v1/client/tickets/:id", ok)
+	return router
+}
+
+func testToken(t *testing.T, role, clientID int) string {
+	t.Helper()
+	claims := jwt.MapClaims{"id": 1, "name": "Teste", "role": role}
+	if clientID > 0 {
+		claims["client_id"] = clientID
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestTenant(t *testing.T) {
+	router := testRouter()
+	clientToken := testToken(t, domain.RoleCliente, 2)
+	internalToken := testToken(t, domain.RoleSuporte, 0)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		want   int
+	}{
+		{"login is public", http.MethodPost, "/api/v1/users/auth", "", http.StatusOK},
+		{"quote link is public", http.MethodGet, "/api/v1/quotes/abc", "", http.StatusOK},
+		{"calendar feed is public", http.MethodGet, "/api/v1/providers/3/calendar.ics?token=abc", "", http.StatusOK},
+		{"calendar without token", http.MethodGet, "/api/v1/providers/3/calendar", "", http.StatusUnauthorized},
+		{"ticket without token", http.MethodGet, "/api/v1/tickets/1", "", http.StatusUnauthorized},
+		{"shipments without token", http.MethodGet, "/api/v1/shipments", "", http.StatusUnauthorized},
+		{"client portal without token", http.MethodGet, "/api/v1/client/tickets/1", "", http.StatusUnauthorized},
+		{"invalid token", http.MethodGet, "/api/v1/tickets/1", "Bearer invalid", http.StatusUnauthorized},
+		{"client user on internal ticket route", http.MethodGet, "/api/v1/tickets/1", clientToken, http.StatusForbidden},
+		{"client user on internal shipment route", http.MethodGet, "/api/v1/shipments", clientToken, http.StatusForbidden},
+		{"client user on client portal", http.MethodGet, "/api/v1/client/tickets/1", clientToken, http.StatusOK},
+		{"client token without client", http.MethodGet, "/api/v1/client/tickets/1", testToken(t, domain.RoleCliente, 0), http.StatusUnauthorized},
+		{"internal user on ticket route", http.MethodGet, "/api/v1/tickets/1", internalToken, http.StatusOK},
+		{"unknown route", http.MethodGet, "/api/v1/unknown", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("%s %s: got status %d, want %d (%s)", tt.method, tt.path, w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenantLoadsScopeIntoContext(t *testing.T) {
+	router := testRouter()
+
+	tests := []struct {
+		name string
+		path string
+		auth string
+		want string
+	}{
+		{"client user is restricted to the client", "/api/v1/client/tickets/1", testToken(t, domain.RoleCliente, 2), `{"client_id":2,"system":false}`},
+		{"internal user has internal access", "/api/v1/tickets/1", testToken(t, domain.RoleSuporte, 0), `{"client_id":0,"system":true}`},
+		{"technician has internal access", "/api/v1/tickets/1", testToken(t, domain.RoleTecnico, 0), `{"client_id":0,"system":true}`},
+		// Rotas públicas não recebem escopo: o serviço libera o acesso depois de validar o token da URL
+		{"public route has no scope", "/api/v1/quotes/abc", "", `{"client_id":0,"system":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK || w.Body.String() != tt.want {
+				t.Fatalf("got status %d body %s, want %s", w.Code, w.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package nfse
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fake é um emissor em memória para desenvolvimento local e testes.
+// Numera as notas em sequência e devolve um XML no leiaute ABRASF simplificado.
+// Como a prefeitura, não emite duas notas para o mesmo RPS: reenviar uma Reference
+// devolve a nota já autorizada, salvo se ela foi cancelada.
+type Fake struct {
+	mu          sync.Mutex
+	next        int
+	issued      map[string]Request
+	cancelled   map[string]bool
+	byReference map[string]*Result
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		next:        1,
+		issued:      make(map[string]Request),
+		cancelled:   make(map[string]bool),
+		byReference: make(map[string]*Result),
+	}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+type fakeParty struct {
+	Name                  string `xml:"RazaoSocial"`
+	Document              string `xml:"Cnpj"`
+	MunicipalRegistration string `xml:"InscricaoMunicipal,omitempty"`
+	City                  string `xml:"Municipio,omitempty"`
+	State                 string `xml:"Uf,omitempty"`
+}
+
+type fakeNfse struct {
+	XMLName          xml.Name  `xml:"CompNfse"`
+	Number           string    `xml:"Nfse>InfNfse>Numero"`
+	VerificationCode string    `xml:"Nfse>InfNfse>CodigoVerificacao"`
+	IssuedAt         string    `xml:"Nfse>InfNfse>DataEmissao"`
+	Reference        string    `xml:"Nfse>InfNfse>IdentificacaoRps>Numero"`
+	Amount           string    `xml:"Nfse>InfNfse>Servico>Valores>ValorServicos"`
+	ServiceCode      string    `xml:"Nfse>InfNfse>Servico>ItemListaServico"`
+	Description      string    `xml:"Nfse>InfNfse>Servico>Discriminacao"`
+	Provider         fakeParty `xml:"Nfse>InfNfse>PrestadorServico"`
+	Taker            fakeParty `xml:"Nfse>InfNfse>TomadorServico"`
+}
+
+func (f *Fake) Issue(ctx context.Context, req Request) (*Result, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrRejected)
+	}
+	if strings.TrimSpace(req.Taker.Document) == "" {
+		return nil, fmt.Errorf("%w: taker document is required", ErrRejected)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if previous, ok := f.byReference[req.Reference]; ok && !f.cancelled[previous.Number] {
+		result := *previous
+		return &result, nil
+	}
+
+	number := fmt.Sprintf("%d%08d", req.IssuedAt.Year(), f.next)
+	f.next++
+
+	sum := sha1.Sum([]byte(number + req.Reference + req.Taker.Document))
+	code := strings.ToUpper(hex.EncodeToString(sum[:])[:8])
+
+	content, err := xml.MarshalIndent(fakeNfse{
+		Number:           number,
+		VerificationCode: code,
+		IssuedAt:         req.IssuedAt.Format(time.RFC3339),
+		Reference:        req.Reference,
+		Amount:           fmt.Sprintf("%d.%02d", req.Amount/100, req.Amount%100),
+		ServiceCode:      req.ServiceCode,
+		Description:      req.Description,
+		Provider:         fakeParty(req.Provider),
+		Taker:            fakeParty(req.Taker),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build nfs-e xml: %w", err)
+	}
+
+	result := &Result{
+		Number:           number,
+		VerificationCode: code,
+		XML:              xml.Header + string(content),
+		IssuedAt:         req.IssuedAt,
+	}
+	f.issued[number] = req
+	f.byReference[req.Reference] = result
+
+	copied := *result
+	return &copied, nil
+}
+
+func (f *Fake) Cancel(ctx context.Context, number, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.issued[number]; !ok {
+		return ErrNotFound
+	}
+	if f.cancelled[number] {
+		return fmt.Errorf("%w: nfs-e already cancelled", ErrRejected)
+	}
+
+	f.cancelled[number] = true
+	return nil
+}
@@ -0,0 +1,87 @@
+package nfse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fakeRequest(reference string) Request {
+	return Request{
+		Reference: reference,
+		IssuedAt:  time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		Taker:     Party{Name: "Banco", Document: "00000000000191"},
+		Amount:    12345,
+	}
+}
+
+func TestFakeIssueIsIdempotentByReference(t *testing.T) {
+	fake := NewFake()
+	ctx := context.Background()
+
+	first, err := fake.Issue(ctx, fakeRequest("10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := fake.Issue(ctx, fakeRequest("10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Number != first.Number {
+		t.Fatalf("got nfs-e %s on retry, want the same %s", again.Number, first.Number)
+	}
+
+	other, err := fake.Issue(ctx, fakeRequest("11"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Number == first.Number {
+		t.Fatalf("another reference must get its own nfs-e")
+	}
+
+	// Depois do cancelamento, o mesmo RPS gera uma nova nota
+	if err := fake.Cancel(ctx, first.Number, "teste"); err != nil {
+		t.Fatal(err)
+	}
+	reissued, err := fake.Issue(ctx, fakeRequest("10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reissued.Number == first.Number {
+		t.Fatalf("got cancelled nfs-e %s again", first.Number)
+	}
+}
+
+func TestFakeIssueAndCancelErrors(t *testing.T) {
+	fake := NewFake()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  Request
+	}{
+		{"zero amount", Request{Reference: "1", Taker: Party{Document: "1"}}},
+		{"missing taker document", Request{Reference: "2", Amount: 100}},
+	}
+	for _, tt := range tests {
+		if _, err := fake.Issue(ctx, tt.req); !errors.Is(err, ErrRejected) {
+			t.Errorf("%s: got %v, want ErrRejected", tt.name, err)
+		}
+	}
+
+	if err := fake.Cancel(ctx, "unknown", "teste"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+
+	result, err := fake.Issue(ctx, fakeRequest("3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.Cancel(ctx, result.Number, "teste"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.Cancel(ctx, result.Number, "teste"); !errors.Is(err, ErrRejected) {
+		t.Errorf("second cancel: got %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,52 @@
+package nfse
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRejected indica que a prefeitura recusou a emissão ou o cancelamento
+	ErrRejected = errors.New("nfs-e rejected by issuer")
+	// ErrNotFound indica que a prefeitura não conhece o número da nota
+	ErrNotFound = errors.New("nfs-e not found")
+)
+
+// Issuer emite e cancela notas fiscais de serviço na prefeitura (integração real ou fake local)
+type Issuer interface {
+	// Name identifica o emissor gravado na fatura (ex: "fake")
+	Name() string
+	// Issue transmite o RPS e retorna a nota autorizada
+	Issue(ctx context.Context, req Request) (*Result, error)
+	// Cancel cancela uma nota autorizada
+	Cancel(ctx context.Context, number, reason string) error
+}
+
+// Party identifica o prestador (empresa) ou o tomador (cliente) da nota
+type Party struct {
+	Name                  string
+	Document              string // CNPJ ou CPF
+	MunicipalRegistration string // Inscrição municipal (somente prestador)
+	City                  string
+	State                 string
+}
+
+// Request representa o RPS enviado para emissão
+type Request struct {
+	Reference   string // Número do RPS (id da fatura)
+	IssuedAt    time.Time
+	Provider    Party
+	Taker       Party
+	ServiceCode string // Item da lista de serviços (LC 116)
+	Description string
+	Amount      int64 // Em centavos
+}
+
+// Result representa a nota autorizada pela prefeitura
+type Result struct {
+	Number           string
+	VerificationCode string
+	XML              string
+	IssuedAt         time.Time
+}
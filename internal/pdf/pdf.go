@@ -0,0 +1,156 @@
+// Package pdf gera documentos PDF simples de texto (relatórios e extratos),
+// em páginas A4 com as fontes padrão Courier e Courier-Bold.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dimensões da página A4 em pontos
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 40.0
+)
+
+// Largura de um caractere da Courier, em milésimos do tamanho da fonte
+const charWidth = 600.0 / 1000.0
+
+// Cell é um texto posicionado em uma linha de tabela.
+// X é a coluna em pontos a partir da margem; com Right, o texto termina em X.
+type Cell struct {
+	Text  string
+	X     float64
+	Right bool
+}
+
+// Document acumula as páginas do PDF
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// New cria um documento com a primeira página em branco
+func New() *Document {
+	d := &Document{}
+	d.addPage()
+	return d
+}
+
+// Title escreve um título em negrito
+func (d *Document) Title(text string) {
+	d.Row(16, true, Cell{Text: text})
+	d.Space(6)
+}
+
+// Text escreve uma linha de texto corrido
+func (d *Document) Text(text string) {
+	d.Row(10, false, Cell{Text: text})
+}
+
+// Row escreve uma linha com as células informadas, quebrando a página quando necessário
+func (d *Document) Row(size float64, bold bool, cells ...Cell) {
+	leading := size * 1.4
+	if d.y-leading < margin {
+		d.addPage()
+	}
+	d.y -= leading
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	page := d.pages[len(d.pages)-1]
+	for _, cell := range cells {
+		x := margin + cell.X
+		if cell.Right {
+			x -= float64(len([]rune(cell.Text))) * size * charWidth
+		}
+		fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.y, escape(cell.Text))
+	}
+}
+
+// Rule desenha uma linha horizontal na largura útil da página
+func (d *Document) Rule() {
+	d.Space(4)
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y, pageWidth-margin, d.y)
+	d.Space(4)
+}
+
+// Space avança verticalmente a posição de escrita
+func (d *Document) Space(height float64) {
+	d.y -= height
+	if d.y < margin {
+		d.addPage()
+	}
+}
+
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// Write escreve o PDF: catálogo, árvore de páginas, fontes e uma página + conteúdo por página
+func (d *Document) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var offsets []int
+	written := 0
+
+	object := func(body string) {
+		offsets = append(offsets, written)
+		n, _ := fmt.Fprintf(bw, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+		written += n
+	}
+
+	n, _ := bw.WriteString("%PDF-1.4\n")
+	written += n
+
+	// Objetos 1 a 4; cada página ocupa dois objetos a partir do 5
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := written
+	fmt.Fprintf(bw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(bw, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(bw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return bw.Flush()
+}
+
+// escape converte o texto para Latin-1 (WinAnsi) e escapa os delimitadores de string do PDF
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// writeCSV grava o CSV separado por ponto e vírgula, com BOM UTF-8 para o Excel reconhecer a codificação
+func writeCSV(w io.Writer, table *Table) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	writer.UseCRLF = true
+
+	header := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	if err := writer.WriteAll(table.Rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"io"
+
+	"github.com/ericolvr/maintenance-v2/internal/pdf"
+)
+
+// Tamanho da fonte das linhas da tabela e largura de um caractere da Courier nesse tamanho
+const (
+	pdfFontSize  = 8.0
+	pdfCharWidth = pdfFontSize * 0.6
+)
+
+// writePDF grava a tabela em colunas de largura fixa (Width caracteres + 1 de espaçamento).
+// Textos maiores que a coluna são truncados.
+func writePDF(w io.Writer, table *Table) error {
+	doc := pdf.New()
+	doc.Title(table.Title)
+	if table.Subtitle != "" {
+		doc.Text(table.Subtitle)
+		doc.Space(6)
+	}
+
+	header := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		header = append(header, column.Name)
+	}
+	doc.Row(pdfFontSize, true, pdfCells(table.Columns, header)...)
+	doc.Rule()
+
+	for _, row := range table.Rows {
+		doc.Row(pdfFontSize, false, pdfCells(table.Columns, row)...)
+	}
+
+	return doc.Write(w)
+}
+
+func pdfCells(columns []Column, values []string) []pdf.Cell {
+	cells := make([]pdf.Cell, 0, len(values))
+	start := 0
+	for i, column := range columns {
+		if i >= len(values) {
+			break
+		}
+
+		text := fit(values[i], column.Width)
+		if column.Numeric {
+			cells = append(cells, pdf.Cell{Text: text, X: float64(start+column.Width) * pdfCharWidth, Right: true})
+		} else {
+			cells = append(cells, pdf.Cell{Text: text, X: float64(start) * pdfCharWidth})
+		}
+		start += column.Width + 1
+	}
+	return cells
+}
+
+// fit trunca o texto na largura da coluna
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
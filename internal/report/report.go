@@ -0,0 +1,72 @@
+// Package report exporta tabelas de relatório em CSV, XLSX ou PDF.
+package report
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrUnknownFormat indica um formato de exportação não suportado
+var ErrUnknownFormat = errors.New("unknown report format")
+
+// FORMATOS DE EXPORTAÇÃO
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Column descreve uma coluna da tabela.
+// Width é a largura no PDF, em caracteres; colunas Numeric são números no XLSX e alinhadas à direita no PDF.
+type Column struct {
+	Name    string
+	Width   int
+	Numeric bool
+}
+
+// Table é o conteúdo de um relatório: título, subtítulo (ex: período), colunas e linhas já formatadas.
+// Valores numéricos usam ponto decimal ("1234.56").
+type Table struct {
+	Title    string
+	Subtitle string
+	Columns  []Column
+	Rows     [][]string
+}
+
+// Write exporta a tabela no formato informado
+func Write(w io.Writer, format string, table *Table) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, table)
+	case FormatXLSX:
+		return writeXLSX(w, table)
+	case FormatPDF:
+		return writePDF(w, table)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// ValidFormat indica se o formato é suportado
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatPDF:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentType retorna o tipo MIME do formato
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
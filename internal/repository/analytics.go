@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+type AnalyticsRepository interface {
+	Problems(ctx context.Context, filter domain.ProblemAnalyticsFilter) ([]domain.ProblemAnalytics, error)
+	UnusedSolutions(ctx context.Context) ([]domain.UnusedSolution, error)
+}
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// problemScope monta a CTE "scoped" com uma linha por ticket e problema no período, já com a
+// região da agência e se o ticket fechado foi seguido por outro na mesma agência e problema
+func problemScope(ctx context.Context, filter domain.ProblemAnalyticsFilter) (string, []interface{}) {
+	scope, args := dashboardScope(ctx, `ticket_problems tp
+		 JOIN tickets t ON t.id = tp.ticket_id`,
+		domain.DashboardFilter{From: filter.From, To: filter.To, ClientID: filter.ClientID})
+	args = append(args, filter.FollowUpDays)
+
+	return `WITH scoped AS (
+		SELECT tp.problem_id, t.id AS ticket_id, c.name AS client, b.state, b.city,
+			t.close_date IS NOT NULL AS closed,
+			t.close_date IS NOT NULL AND EXISTS (
+				SELECT 1
+				FROM tickets r
+				JOIN ticket_problems rp ON rp.ticket_id = r.id
+				WHERE rp.problem_id = tp.problem_id
+					AND r.id <> t.id
+					AND r.branch_id = t.branch_id
+					AND r.open_date > t.close_date
+					AND r.open_date <= t.close_date + ` + fmt.Sprintf("$%d", len(args)) + ` * INTERVAL '1 day'
+			) AS followed
+		 ` + scope + `
+	)`, args
+}
+
+// Problems retorna todos os problemas do catálogo, dos mais frequentes no período aos sem ocorrência
+func (r *analyticsRepository) Problems(ctx context.Context, filter domain.ProblemAnalyticsFilter) ([]domain.ProblemAnalytics, error) {
+	scope, args := problemScope(ctx, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		scope+`
+		SELECT p.id, p.name,
+			COUNT(s.ticket_id),
+			COUNT(*) FILTER (WHERE s.closed),
+			COUNT(*) FILTER (WHERE s.followed)
+		FROM problems p
+		LEFT JOIN scoped s ON s.problem_id = p.id
+		GROUP BY p.id, p.name
+		ORDER BY COUNT(s.ticket_id) DESC, p.name ASC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing problem analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []domain.ProblemAnalytics
+	for rows.Next() {
+		problem := domain.ProblemAnalytics{
+			ByClient:  []domain.ProblemClientCount{},
+			ByRegion:  []domain.ProblemRegionCount{},
+			Solutions: []domain.ProblemSolutionUsage{},
+		}
+		if err := rows.Scan(
+			&problem.ProblemID,
+			&problem.ProblemName,
+			&problem.Occurrences,
+			&problem.ClosedTickets,
+			&problem.FollowUps,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning problem analytics: %w", err)
+		}
+		problems = append(problems, problem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating problem analytics: %w", err)
+	}
+
+	index := make(map[int]*domain.ProblemAnalytics, len(problems))
+	for i := range problems {
+		index[problems[i].ProblemID] = &problems[i]
+	}
+
+	if err := r.loadByClient(ctx, filter, index); err != nil {
+		return nil, err
+	}
+	if err := r.loadByRegion(ctx, filter, index); err != nil {
+		return nil, err
+	}
+	if err := r.loadSolutions(ctx, filter, index); err != nil {
+		return nil, err
+	}
+
+	for i := range problems {
+		problems[i].Calculate()
+	}
+
+	return problems, nil
+}
+
+func (r *analyticsRepository) loadByClient(ctx context.Context, filter domain.ProblemAnalyticsFilter, index map[int]*domain.ProblemAnalytics) error {
+	scope, args := problemScope(ctx, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		scope+`
+		SELECT problem_id, COALESCE(client, ''), COUNT(*)
+		FROM scoped
+		GROUP BY problem_id, client
+		ORDER BY COUNT(*) DESC, client ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error counting problems by client: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var problemID int
+		var item domain.ProblemClientCount
+		if err := rows.Scan(&problemID, &item.ClientName, &item.Tickets); err != nil {
+			return fmt.Errorf("error scanning problem client count: %w", err)
+		}
+		if problem, ok := index[problemID]; ok {
+			problem.ByClient = append(problem.ByClient, item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating problem client counts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *analyticsRepository) loadByRegion(ctx context.Context, filter domain.ProblemAnalyticsFilter, index map[int]*domain.ProblemAnalytics) error {
+	scope, args := problemScope(ctx, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		scope+`
+		SELECT problem_id, COALESCE(state, ''), COALESCE(city, ''), COUNT(*)
+		FROM scoped
+		GROUP BY problem_id, state, city
+		ORDER BY COUNT(*) DESC, state ASC, city ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error counting problems by region: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var problemID int
+		var item domain.ProblemRegionCount
+		if err := rows.Scan(&problemID, &item.State, &item.City, &item.Tickets); err != nil {
+			return fmt.Errorf("error scanning problem region count: %w", err)
+		}
+		if problem, ok := index[problemID]; ok {
+			problem.ByRegion = append(problem.ByRegion, item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating problem region counts: %w", err)
+	}
+
+	return nil
+}
+
+// loadSolutions agrupa as linhas de custo lançadas para o problema por solução aplicada
+func (r *analyticsRepository) loadSolutions(ctx context.Context, filter domain.ProblemAnalyticsFilter, index map[int]*domain.ProblemAnalytics) error {
+	scope, args := problemScope(ctx, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		scope+`
+		SELECT s.problem_id, tc.solution_id, COALESCE(sol.name, MIN(tc.solution_name)),
+			COUNT(*),
+			COUNT(DISTINCT s.ticket_id),
+			ROUND(AVG(tc.subtotal), 2),
+			COUNT(DISTINCT s.ticket_id) FILTER (WHERE s.followed)
+		FROM scoped s
+		JOIN ticket_costs tc ON tc.ticket_id = s.ticket_id AND tc.problem_id = s.problem_id
+		LEFT JOIN solutions sol ON sol.id = tc.solution_id
+		GROUP BY s.problem_id, tc.solution_id, sol.name
+		ORDER BY COUNT(*) DESC, tc.solution_id ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error listing applied solutions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var problemID int
+		var item domain.ProblemSolutionUsage
+		if err := rows.Scan(
+			&problemID,
+			&item.SolutionID,
+			&item.SolutionName,
+			&item.Applications,
+			&item.Tickets,
+			&item.AvgCost,
+			&item.FollowUps,
+		); err != nil {
+			return fmt.Errorf("error scanning applied solution: %w", err)
+		}
+		if problem, ok := index[problemID]; ok {
+			problem.Solutions = append(problem.Solutions, item)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating applied solutions: %w", err)
+	}
+
+	return nil
+}
+
+// UnusedSolutions lista as soluções do catálogo sem nenhuma linha de custo, em qualquer período.
+// Para o usuário de um cliente, contam só as linhas de custo dos tickets do próprio cliente.
+func (r *analyticsRepository) UnusedSolutions(ctx context.Context) ([]domain.UnusedSolution, error) {
+	filter, args := tenantTicket(ctx, "tc.ticket_id", nil)
+	if filter == tenantDenied {
+		return nil, ErrNoTenant
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT sol.id, sol.name, COALESCE(sol.problem_id, 0), COALESCE(p.name, ''), sol.unit_price, sol.created_at
+		 FROM solutions sol
+		 LEFT JOIN problems p ON p.id = sol.problem_id
+		 WHERE NOT EXISTS (SELECT 1 FROM ticket_costs tc WHERE tc.solution_id = sol.id`+filter+`)
+		 ORDER BY p.name ASC, sol.name ASC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing unused solutions: %w", err)
+	}
+	defer rows.Close()
+
+	var solutions []domain.UnusedSolution
+	for rows.Next() {
+		var item domain.UnusedSolution
+		if err := rows.Scan(
+			&item.SolutionID,
+			&item.SolutionName,
+			&item.ProblemID,
+			&item.ProblemName,
+			&item.UnitPrice,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning unused solution: %w", err)
+		}
+		solutions = append(solutions, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unused solutions: %w", err)
+	}
+
+	return solutions, nil
+}
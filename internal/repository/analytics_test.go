@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// Consultas feitas pelos indicadores de problemas, uma por bloco
+var analyticsQueries = []string{"FROM problems p", "GROUP BY problem_id, client", "GROUP BY problem_id, state, city", "JOIN ticket_costs tc"}
+
+func TestAnalyticsProblemsPlaceholders(t *testing.T) {
+	clientID := ownerClient
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		clientID *int
+	}{
+		{"system", tenant.System(context.Background()), nil},
+		{"system with client", tenant.System(context.Background()), &clientID},
+		{"tenant with client", tenant.WithClient(context.Background(), ownerClient), &clientID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			// Um problema no catálogo para que os blocos de detalhe sejam consultados
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				if strings.Contains(query, "FROM problems p") {
+					return []string{"id", "name", "occurrences", "closed", "follow_ups"},
+						[][]driver.Value{{int64(1), "Sem rede", int64(0), int64(0), int64(0)}}
+				}
+				return nil, nil
+			}
+
+			filter := domain.ProblemAnalyticsFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now(), ClientID: tt.clientID, FollowUpDays: 15}
+			if _, err := NewAnalyticsRepository(db).Problems(tt.ctx, filter); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, fragment := range analyticsQueries {
+				calls := fake.calls(fragment)
+				if len(calls) != 1 {
+					t.Fatalf("got %d queries with %q, want 1", len(calls), fragment)
+				}
+				assertPlaceholders(t, calls)
+
+				// A janela de retorno é o último parâmetro, depois dos filtros de cliente
+				args := calls[0].args
+				if args[len(args)-1] != 15 {
+					t.Errorf("%q: got follow-up days %v, want 15", fragment, args[len(args)-1])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyticsProblemsAssemblesDetails(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "FROM problems p"):
+			return []string{"id", "name", "occurrences", "closed", "follow_ups"}, [][]driver.Value{
+				{int64(1), "Sem rede", int64(5), int64(4), int64(1)},
+				{int64(2), "Sem energia", int64(0), int64(0), int64(0)},
+			}
+		case strings.Contains(query, "GROUP BY problem_id, client"):
+			return []string{"problem_id", "client", "count"}, [][]driver.Value{
+				{int64(1), "Banco", int64(5)},
+				// Problema fora do catálogo listado é ignorado
+				{int64(99), "Outro", int64(1)},
+			}
+		case strings.Contains(query, "GROUP BY problem_id, state, city"):
+			return []string{"problem_id", "state", "city", "count"}, [][]driver.Value{
+				{int64(1), "SP", "Campinas", int64(3)},
+				{int64(1), "SP", "São Paulo", int64(2)},
+			}
+		case strings.Contains(query, "JOIN ticket_costs tc"):
+			return []string{"problem_id", "solution_id", "name", "applications", "tickets", "avg", "follow_ups"}, [][]driver.Value{
+				{int64(1), int64(7), "Troca de switch", int64(3), int64(2), "120.50", int64(1)},
+			}
+		}
+		return nil, nil
+	}
+
+	filter := domain.ProblemAnalyticsFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now(), FollowUpDays: 30}
+	problems, err := NewAnalyticsRepository(db).Problems(tenant.System(context.Background()), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("got %d problems, want 2", len(problems))
+	}
+
+	network := problems[0]
+	if len(network.ByClient) != 1 || len(network.ByRegion) != 2 || len(network.Solutions) != 1 {
+		t.Fatalf("got %d clients, %d regions and %d solutions", len(network.ByClient), len(network.ByRegion), len(network.Solutions))
+	}
+	if network.FollowUpRate == nil || *network.FollowUpRate != 25 {
+		t.Fatalf("got follow-up rate %v, want 25", network.FollowUpRate)
+	}
+	solution := network.Solutions[0]
+	if solution.AvgCost != domain.NewMoney(120.5) || solution.FollowUpRate == nil || *solution.FollowUpRate != 50 {
+		t.Fatalf("got solution %+v", solution)
+	}
+
+	// Problema sem ocorrência: listas vazias e sem taxa de retorno
+	energy := problems[1]
+	if energy.ByClient == nil || energy.ByRegion == nil || energy.Solutions == nil || energy.FollowUpRate != nil {
+		t.Fatalf("got %+v", energy)
+	}
+}
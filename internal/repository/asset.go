@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+var ErrAssetNotFound = errors.New("asset not found")
+
+type AssetRepository interface {
+	Create(ctx context.Context, asset *domain.Asset) (int, error)
+	List(ctx context.Context, branchID *int) ([]domain.Asset, error)
+	FindByID(ctx context.Context, id int) (*domain.Asset, error)
+	Update(ctx context.Context, asset *domain.Asset) error
+	Delete(ctx context.Context, id int) error
+	ListTickets(ctx context.Context, assetID int) ([]domain.Ticket, error)
+}
+
+type assetRepository struct {
+	db *sql.DB
+}
+
+func NewAssetRepository(db *sql.DB) AssetRepository {
+	return &assetRepository{db: db}
+}
+
+func (r *assetRepository) Create(ctx context.Context, asset *domain.Asset) (int, error) {
+	if err := checkTenantBranch(ctx, r.db, asset.BranchID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO assets (branch_id, type, model, serial_number, installed_at, warranty_ends_at, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		asset.BranchID, asset.Type, asset.Model, asset.SerialNumber, asset.InstalledAt, asset.WarrantyEndsAt, asset.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating asset: %w", err)
+	}
+
+	return id, nil
+}
+
+const assetSelect = `SELECT a.id, a.branch_id, b.name, a.type, a.model, a.serial_number, a.installed_at,
+			a.warranty_ends_at, a.notes, a.created_at, a.updated_at
+			FROM assets a
+			JOIN branchs b ON b.id = a.branch_id`
+
+func scanAsset(row rowScanner) (*domain.Asset, error) {
+	var asset domain.Asset
+	err := row.Scan(
+		&asset.ID,
+		&asset.BranchID,
+		&asset.BranchName,
+		&asset.Type,
+		&asset.Model,
+		&asset.SerialNumber,
+		&asset.InstalledAt,
+		&asset.WarrantyEndsAt,
+		&asset.Notes,
+		&asset.CreatedAt,
+		&asset.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asset, nil
+}
+
+// List retorna os equipamentos, opcionalmente filtrados pela agência
+func (r *assetRepository) List(ctx context.Context, branchID *int) ([]domain.Asset, error) {
+	query := assetSelect + " WHERE TRUE"
+	var args []interface{}
+	if branchID != nil {
+		args = append(args, *branchID)
+		query += " AND a.branch_id = $1"
+	}
+	tenant, args := tenantClient(ctx, "b.client_id", args)
+	query += tenant
+	query += " ORDER BY b.name ASC, a.type ASC, a.id ASC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning asset: %w", err)
+		}
+		assets = append(assets, *asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+func (r *assetRepository) FindByID(ctx context.Context, id int) (*domain.Asset, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{id})
+	asset, err := scanAsset(conn(ctx, r.db).QueryRowContext(ctx, assetSelect+` WHERE a.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("error finding asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *assetRepository) Update(ctx context.Context, asset *domain.Asset) error {
+	if err := checkTenantBranch(ctx, r.db, asset.BranchID); err != nil {
+		return err
+	}
+
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{
+		asset.BranchID, asset.Type, asset.Model, asset.SerialNumber, asset.InstalledAt, asset.WarrantyEndsAt, asset.Notes, asset.ID,
+	})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE assets SET branch_id = $1, type = $2, model = $3, serial_number = $4, installed_at = $5,
+			warranty_ends_at = $6, notes = $7, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $8`+filter,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error updating asset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrAssetNotFound
+	}
+
+	return nil
+}
+
+// Delete remove o equipamento e desvincula os tickets e problemas que o referenciam
+func (r *assetRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE tickets SET asset_id = NULL, version = version + 1 WHERE asset_id = $1`, id); err != nil {
+		return fmt.Errorf("error unlinking asset tickets: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE ticket_problems SET asset_id = NULL WHERE asset_id = $1`, id); err != nil {
+		return fmt.Errorf("error unlinking asset problems: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM assets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting asset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrAssetNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListTickets retorna os tickets que referenciam o equipamento, no ticket ou em um de seus problemas
+func (r *assetRepository) ListTickets(ctx context.Context, assetID int) ([]domain.Ticket, error) {
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{assetID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.id, t.number, t.status, t.priority, t.description, t.open_date, t.close_date,
+			t.branch_id, t.provider_id, t.asset_id, t.assignment_status
+		 FROM tickets t
+		 WHERE (t.asset_id = $1
+			OR EXISTS (SELECT 1 FROM ticket_problems tp WHERE tp.ticket_id = t.id AND tp.asset_id = $1))`+filter+`
+		 ORDER BY t.open_date DESC, t.id DESC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing asset tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []domain.Ticket
+	for rows.Next() {
+		var ticket domain.Ticket
+		if err := rows.Scan(
+			&ticket.ID,
+			&ticket.Number,
+			&ticket.Status,
+			&ticket.Priority,
+			&ticket.Description,
+			&ticket.OpenDate,
+			&ticket.CloseDate,
+			&ticket.BranchID,
+			&ticket.ProviderID,
+			&ticket.AssetID,
+			&ticket.AssignmentStatus,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning asset ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating asset tickets: %w", err)
+	}
+
+	return tickets, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) (int, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.Attachment, error)
+	FindByID(ctx context.Context, id int) (*domain.Attachment, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, attachment.TicketID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO ticket_attachments (ticket_id, file_name, content_type, size, path, public)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		attachment.TicketID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Path,
+		attachment.Public).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating attachment: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *attachmentRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.Attachment, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	query := `SELECT id, ticket_id, file_name, content_type, size, path, public, created_at
+			FROM ticket_attachments WHERE ticket_id = $1` + filter + ` ORDER BY created_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		var attachment domain.Attachment
+		if err := rows.Scan(
+			&attachment.ID,
+			&attachment.TicketID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.Path,
+			&attachment.Public,
+			&attachment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id int) (*domain.Attachment, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{id})
+	query := `SELECT id, ticket_id, file_name, content_type, size, path, public, created_at
+			FROM ticket_attachments WHERE id = $1` + filter
+
+	var attachment domain.Attachment
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&attachment.ID,
+		&attachment.TicketID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Path,
+		&attachment.Public,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("error finding attachment by id: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id int) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{id})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM ticket_attachments WHERE id = $1`+filter, args...)
+	if err != nil {
+		return fmt.Errorf("error deleting attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
@@ -17,7 +17,8 @@ type BranchRepository interface {
 	List(ctx context.Context) ([]domain.Branch, error)
 	FindByID(ctx context.Context, id int) (*domain.Branch, error)
 	FindByUniorg(ctx context.Context, uniorg string) (*domain.Branch, error)
-	GetByClient(ctx context.Context, client string) ([]domain.Branch, error)
+	GetByClient(ctx context.Context, clientID int) ([]domain.Branch, error)
+	FindByEmailDomain(ctx context.Context, emailDomain string) ([]domain.Branch, error)
 	Update(ctx context.Context, branch *domain.Branch) error
 	Delete(ctx context.Context, id int) error
 }
@@ -32,13 +33,21 @@ func NewBranchRepository(db *sql.DB) BranchRepository {
 	}
 }
 
+const branchSelect = `SELECT b.id, b.client_id, c.name, b.name, b.uniorg, b.zipcode, b.state, b.city, b.neighborhood, b.address, b.complement, b.email_domain, b.latitude, b.longitude, b.opens_at, b.closes_at, b.version
+	FROM branchs b
+	JOIN clients c ON c.id = b.client_id`
+
 func (r *branchRepository) Create(ctx context.Context, branch *domain.Branch) (int, error) {
-	query := `INSERT INTO branchs (client, name, uniorg, zipcode, state, city, neighborhood, address, complement) 
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	if err := checkTenant(ctx, branch.ClientID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO branchs (client_id, name, uniorg, zipcode, state, city, neighborhood, address, complement, email_domain, latitude, longitude, opens_at, closes_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
 
 	var id int
-	err := r.db.QueryRowContext(ctx, query,
-		branch.Client,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		branch.ClientID,
 		branch.Name,
 		branch.Uniorg,
 		branch.Zipcode,
@@ -46,7 +55,12 @@ func (r *branchRepository) Create(ctx context.Context, branch *domain.Branch) (i
 		branch.City,
 		branch.Neighborhood,
 		branch.Address,
-		branch.Complement).Scan(&id)
+		branch.Complement,
+		branch.EmailDomain,
+		branch.Latitude,
+		branch.Longitude,
+		branch.OpensAt,
+		branch.ClosesAt).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("error creating branch: %w", err)
 	}
@@ -55,56 +69,13 @@ func (r *branchRepository) Create(ctx context.Context, branch *domain.Branch) (i
 }
 
 func (r *branchRepository) List(ctx context.Context) ([]domain.Branch, error) {
-	query := `SELECT id, client, name, uniorg, zipcode, state, city, neighborhood, address, complement FROM branchs ORDER BY name ASC`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("error listing branchs: %w", err)
-	}
-	defer rows.Close()
-
-	var branchs []domain.Branch
-	for rows.Next() {
-		var branch domain.Branch
-		if err := rows.Scan(
-			&branch.ID,
-			&branch.Client,
-			&branch.Name,
-			&branch.Uniorg,
-			&branch.Zipcode,
-			&branch.State,
-			&branch.City,
-			&branch.Neighborhood,
-			&branch.Address,
-			&branch.Complement); err != nil {
-			return nil, fmt.Errorf("error scanning branch: %w", err)
-		}
-		branchs = append(branchs, branch)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating branchs: %w", err)
-	}
-
-	return branchs, nil
+	filter, args := tenantClient(ctx, "b.client_id", nil)
+	return r.list(ctx, branchSelect+` WHERE TRUE`+filter+` ORDER BY b.name ASC`, args...)
 }
 
 func (r *branchRepository) FindByID(ctx context.Context, id int) (*domain.Branch, error) {
-	query := `SELECT id, name, client, uniorg, zipcode, state, city, neighborhood, address, complement FROM branchs WHERE id = $1`
-	var branch domain.Branch
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&branch.ID,
-		&branch.Name,    // Corrigido: name vem antes de client na query
-		&branch.Client,  // Corrigido: client vem depois de name na query
-		&branch.Uniorg,
-		&branch.Zipcode,
-		&branch.State,
-		&branch.City,
-		&branch.Neighborhood,
-		&branch.Address,
-		&branch.Complement,
-	)
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{id})
+	branch, err := scanBranch(conn(ctx, r.db).QueryRowContext(ctx, branchSelect+` WHERE b.id = $1`+filter, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrBranchNotFound
@@ -112,25 +83,12 @@ func (r *branchRepository) FindByID(ctx context.Context, id int) (*domain.Branch
 		return nil, fmt.Errorf("error finding branch by id: %w", err)
 	}
 
-	return &branch, nil
+	return branch, nil
 }
 
 func (r *branchRepository) FindByUniorg(ctx context.Context, uniorg string) (*domain.Branch, error) {
-	query := `SELECT * FROM branchs WHERE uniorg = $1 LIMIT 1`
-
-	var branch domain.Branch
-	err := r.db.QueryRowContext(ctx, query, uniorg).Scan(
-		&branch.ID,
-		&branch.Client,
-		&branch.Name,
-		&branch.Uniorg,
-		&branch.Zipcode,
-		&branch.State,
-		&branch.City,
-		&branch.Neighborhood,
-		&branch.Address,
-		&branch.Complement)
-
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{uniorg})
+	branch, err := scanBranch(conn(ctx, r.db).QueryRowContext(ctx, branchSelect+` WHERE b.uniorg = $1`+filter+` LIMIT 1`, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -138,58 +96,51 @@ func (r *branchRepository) FindByUniorg(ctx context.Context, uniorg string) (*do
 		return nil, fmt.Errorf("error finding branch by uniorg: %w", err)
 	}
 
-	return &branch, nil
+	return branch, nil
 }
 
-func (r *branchRepository) GetByClient(ctx context.Context, client string) ([]domain.Branch, error) {
-	query := `SELECT id, name, uniorg, zipcode, state, city, neighborhood, address FROM branchs WHERE client = $1 ORDER BY name ASC`
+func (r *branchRepository) GetByClient(ctx context.Context, clientID int) ([]domain.Branch, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{clientID})
+	return r.list(ctx, branchSelect+` WHERE b.client_id = $1`+filter+` ORDER BY b.name ASC`, args...)
+}
+
+// FindByEmailDomain retorna as agências cadastradas com o domínio de email informado
+func (r *branchRepository) FindByEmailDomain(ctx context.Context, emailDomain string) ([]domain.Branch, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{emailDomain})
+	return r.list(ctx, branchSelect+` WHERE LOWER(b.email_domain) = LOWER($1)`+filter+` ORDER BY b.name ASC`, args...)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, client)
+func (r *branchRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Branch, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error getting branches by client: %w", err)
+		return nil, fmt.Errorf("error listing branchs: %w", err)
 	}
 	defer rows.Close()
 
-	var branches []domain.Branch
+	var branchs []domain.Branch
 	for rows.Next() {
-		var branch domain.Branch
-		if err := rows.Scan(
-			&branch.ID,
-			&branch.Name,
-			&branch.Uniorg,
-			&branch.Zipcode,
-			&branch.State,
-			&branch.City,
-			&branch.Neighborhood,
-			&branch.Address); err != nil {
+		branch, err := scanBranch(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning branch: %w", err)
 		}
-		branches = append(branches, branch)
+		branchs = append(branchs, *branch)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating branches: %w", err)
+		return nil, fmt.Errorf("error iterating branchs: %w", err)
 	}
 
-	return branches, nil
+	return branchs, nil
 }
 
 func (r *branchRepository) Update(ctx context.Context, branch *domain.Branch) error {
-	query := `UPDATE branchs SET 
-			name = $1, 
-			client = $2, 
-			uniorg = $3, 
-			zipcode = $4, 
-			state = $5, 
-			city = $6, 
-			neighborhood = $7, 
-			address = $8, 
-			complement = $9
-			WHERE id = $10`
-
-	result, err := r.db.ExecContext(ctx, query,
+	if err := checkTenant(ctx, branch.ClientID); err != nil {
+		return err
+	}
+
+	filter, args := tenantClient(ctx, "client_id", []interface{}{
 		branch.Name,
-		branch.Client,
+		branch.ClientID,
 		branch.Uniorg,
 		branch.Zipcode,
 		branch.State,
@@ -197,28 +148,54 @@ func (r *branchRepository) Update(ctx context.Context, branch *domain.Branch) er
 		branch.Neighborhood,
 		branch.Address,
 		branch.Complement,
-
-		branch.ID)
-	if err != nil {
-		return fmt.Errorf("error updating branch: %w", err)
+		branch.EmailDomain,
+		branch.Latitude,
+		branch.Longitude,
+		branch.OpensAt,
+		branch.ClosesAt,
+		branch.ID,
+		branch.Version,
+	})
+	query := `UPDATE branchs SET
+			name = $1,
+			client_id = $2,
+			uniorg = $3,
+			zipcode = $4,
+			state = $5,
+			city = $6,
+			neighborhood = $7,
+			address = $8,
+			complement = $9,
+			email_domain = $10,
+			latitude = $11,
+			longitude = $12,
+			opens_at = $13,
+			closes_at = $14,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+			WHERE id = $15 AND ($16::INTEGER = 0 OR version = $16)` + filter + `
+			RETURNING version`
+
+	// Com versão informada, só atualiza se a agência não mudou desde a leitura
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&branch.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		if branch.Version > 0 {
+			return ErrVersionConflict
+		}
+		return ErrBranchNotFound
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error checking rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return ErrBranchNotFound
+		return fmt.Errorf("error updating branch: %w", err)
 	}
 
 	return nil
 }
 
 func (r *branchRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM branchs WHERE id = $1`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
+	query := `DELETE FROM branchs WHERE id = $1` + filter
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error deleting branch: %w", err)
 	}
@@ -234,3 +211,30 @@ func (r *branchRepository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func scanBranch(row rowScanner) (*domain.Branch, error) {
+	var branch domain.Branch
+	if err := row.Scan(
+		&branch.ID,
+		&branch.ClientID,
+		&branch.Client,
+		&branch.Name,
+		&branch.Uniorg,
+		&branch.Zipcode,
+		&branch.State,
+		&branch.City,
+		&branch.Neighborhood,
+		&branch.Address,
+		&branch.Complement,
+		&branch.EmailDomain,
+		&branch.Latitude,
+		&branch.Longitude,
+		&branch.OpensAt,
+		&branch.ClosesAt,
+		&branch.Version,
+	); err != nil {
+		return nil, err
+	}
+
+	return &branch, nil
+}
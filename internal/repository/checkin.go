@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+var ErrCheckinNotFound = errors.New("checkin not found")
+
+type CheckinRepository interface {
+	Create(ctx context.Context, checkin *domain.TicketCheckin) (int, error)
+	FindOpenByTicket(ctx context.Context, ticketID int) (*domain.TicketCheckin, error)
+	Checkout(ctx context.Context, checkin *domain.TicketCheckin) error
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketCheckin, error)
+}
+
+type checkinRepository struct {
+	db *sql.DB
+}
+
+func NewCheckinRepository(db *sql.DB) CheckinRepository {
+	return &checkinRepository{db: db}
+}
+
+const checkinColumns = `id, ticket_id, provider_id, checkin_at, checkin_latitude, checkin_longitude, checkin_distance_meters,
+	checkout_at, checkout_latitude, checkout_longitude, checkout_distance_meters, reported_km, anomalies, created_at`
+
+func (r *checkinRepository) Create(ctx context.Context, checkin *domain.TicketCheckin) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, checkin.TicketID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO ticket_checkins (ticket_id, provider_id, checkin_at, checkin_latitude, checkin_longitude, checkin_distance_meters, anomalies)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		checkin.TicketID,
+		checkin.ProviderID,
+		checkin.CheckinAt,
+		checkin.CheckinLatitude,
+		checkin.CheckinLongitude,
+		checkin.CheckinDistanceMeters,
+		strings.Join(checkin.Anomalies, ",")).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating checkin: %w", err)
+	}
+
+	return id, nil
+}
+
+// FindOpenByTicket retorna o check-in do ticket que ainda não teve check-out
+func (r *checkinRepository) FindOpenByTicket(ctx context.Context, ticketID int) (*domain.TicketCheckin, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	query := `SELECT ` + checkinColumns + ` FROM ticket_checkins
+			WHERE ticket_id = $1 AND checkout_at IS NULL` + filter + `
+			ORDER BY checkin_at DESC LIMIT 1`
+
+	checkin, err := scanCheckin(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCheckinNotFound
+		}
+		return nil, fmt.Errorf("error finding open checkin: %w", err)
+	}
+
+	return checkin, nil
+}
+
+func (r *checkinRepository) Checkout(ctx context.Context, checkin *domain.TicketCheckin) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		checkin.CheckoutAt,
+		checkin.CheckoutLatitude,
+		checkin.CheckoutLongitude,
+		checkin.CheckoutDistanceMeters,
+		checkin.ReportedKm,
+		strings.Join(checkin.Anomalies, ","),
+		checkin.ID,
+	})
+	query := `UPDATE ticket_checkins SET
+			checkout_at = $1,
+			checkout_latitude = $2,
+			checkout_longitude = $3,
+			checkout_distance_meters = $4,
+			reported_km = $5,
+			anomalies = $6
+			WHERE id = $7` + filter
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating checkin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCheckinNotFound
+	}
+
+	return nil
+}
+
+func (r *checkinRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketCheckin, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	query := `SELECT ` + checkinColumns + ` FROM ticket_checkins WHERE ticket_id = $1` + filter + ` ORDER BY checkin_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing checkins: %w", err)
+	}
+	defer rows.Close()
+
+	var checkins []domain.TicketCheckin
+	for rows.Next() {
+		checkin, err := scanCheckin(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning checkin: %w", err)
+		}
+		checkins = append(checkins, *checkin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating checkins: %w", err)
+	}
+
+	return checkins, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCheckin(row rowScanner) (*domain.TicketCheckin, error) {
+	var checkin domain.TicketCheckin
+	var anomalies string
+
+	err := row.Scan(
+		&checkin.ID,
+		&checkin.TicketID,
+		&checkin.ProviderID,
+		&checkin.CheckinAt,
+		&checkin.CheckinLatitude,
+		&checkin.CheckinLongitude,
+		&checkin.CheckinDistanceMeters,
+		&checkin.CheckoutAt,
+		&checkin.CheckoutLatitude,
+		&checkin.CheckoutLongitude,
+		&checkin.CheckoutDistanceMeters,
+		&checkin.ReportedKm,
+		&anomalies,
+		&checkin.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	checkin.Anomalies = []string{}
+	if anomalies != "" {
+		checkin.Anomalies = strings.Split(anomalies, ",")
+	}
+
+	return &checkin, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
 	_ "github.com/lib/pq"
 )
 
@@ -29,10 +30,18 @@ func NewClientRepository(db *sql.DB) ClientRepository {
 }
 
 func (r *clientRepository) Create(ctx context.Context, client *domain.Client) (int, error) {
-	query := `INSERT INTO clients (name) VALUES ($1) RETURNING id`
+	// Usuários de clientes não cadastram outros clientes
+	if _, ok := tenant.ClientID(ctx); ok {
+		return 0, ErrCrossTenant
+	}
+	if err := checkSystem(ctx); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO clients (name, document) VALUES ($1, $2) RETURNING id`
 
 	var id int
-	err := r.db.QueryRowContext(ctx, query, client.Name).Scan(&id)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, client.Name, client.Document).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("error creating client: %w", err)
 	}
@@ -41,9 +50,10 @@ func (r *clientRepository) Create(ctx context.Context, client *domain.Client) (i
 }
 
 func (r *clientRepository) List(ctx context.Context) ([]domain.Client, error) {
-	query := `SELECT id, name FROM clients ORDER BY name ASC`
+	filter, args := tenantClient(ctx, "id", nil)
+	query := `SELECT id, name, document FROM clients WHERE TRUE` + filter + ` ORDER BY name ASC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error listing clients: %w", err)
 	}
@@ -52,7 +62,7 @@ func (r *clientRepository) List(ctx context.Context) ([]domain.Client, error) {
 	var clients []domain.Client
 	for rows.Next() {
 		var client domain.Client
-		if err := rows.Scan(&client.ID, &client.Name); err != nil {
+		if err := rows.Scan(&client.ID, &client.Name, &client.Document); err != nil {
 			return nil, fmt.Errorf("error scanning client: %w", err)
 		}
 		clients = append(clients, client)
@@ -66,10 +76,11 @@ func (r *clientRepository) List(ctx context.Context) ([]domain.Client, error) {
 }
 
 func (r *clientRepository) FindByID(ctx context.Context, id int) (*domain.Client, error) {
-	query := `SELECT id, name FROM clients WHERE id = $1`
+	filter, args := tenantClient(ctx, "id", []interface{}{id})
+	query := `SELECT id, name, document FROM clients WHERE id = $1` + filter
 	var client domain.Client
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&client.ID, &client.Name)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&client.ID, &client.Name, &client.Document)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -81,9 +92,10 @@ func (r *clientRepository) FindByID(ctx context.Context, id int) (*domain.Client
 }
 
 func (r *clientRepository) Update(ctx context.Context, client *domain.Client) error {
-	query := `UPDATE clients SET name = $1 WHERE id = $2`
+	filter, args := tenantClient(ctx, "id", []interface{}{client.Name, client.Document, client.ID})
+	query := `UPDATE clients SET name = $1, document = $2 WHERE id = $3` + filter
 
-	result, err := r.db.ExecContext(ctx, query, client.Name, client.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error updating client: %w", err)
 	}
@@ -101,9 +113,10 @@ func (r *clientRepository) Update(ctx context.Context, client *domain.Client) er
 }
 
 func (r *clientRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM clients WHERE id = $1`
+	filter, args := tenantClient(ctx, "id", []interface{}{id})
+	query := `DELETE FROM clients WHERE id = $1` + filter
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error deleting client: %w", err)
 	}
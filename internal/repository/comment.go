@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.TicketComment) (int, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketComment, error)
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.TicketComment) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, comment.TicketID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO ticket_comments (ticket_id, author, body, source, public)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		comment.TicketID,
+		comment.Author,
+		comment.Body,
+		comment.Source,
+		comment.Public).Scan(&id, &comment.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("error creating comment: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *commentRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketComment, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	query := `SELECT id, ticket_id, author, body, source, public, created_at
+			FROM ticket_comments WHERE ticket_id = $1` + filter + ` ORDER BY created_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []domain.TicketComment
+	for rows.Next() {
+		var comment domain.TicketComment
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.TicketID,
+			&comment.Author,
+			&comment.Body,
+			&comment.Source,
+			&comment.Public,
+			&comment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return comments, nil
+}
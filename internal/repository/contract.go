@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var ErrContractNotFound = errors.New("contract not found")
+
+type ContractRepository interface {
+	Create(ctx context.Context, contract *domain.Contract) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.Contract, error)
+	List(ctx context.Context, clientID *int) ([]domain.Contract, error)
+	Update(ctx context.Context, contract *domain.Contract) error
+	Delete(ctx context.Context, id int) error
+	FindForTicket(ctx context.Context, ticketID int) (*domain.Contract, error)
+}
+
+type contractRepository struct {
+	db *sql.DB
+}
+
+func NewContractRepository(db *sql.DB) ContractRepository {
+	return &contractRepository{db: db}
+}
+
+// Create grava o contrato e seus preços na mesma transação
+func (r *contractRepository) Create(ctx context.Context, contract *domain.Contract) (int, error) {
+	if err := checkTenant(ctx, contract.ClientID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO contracts (client_id, name, starts_on, ends_on, value_per_km, initial_value, show_costs, require_quote)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		contract.ClientID, contract.Name, contract.StartsOn, contract.EndsOn, contract.ValuePerKm, contract.InitialValue, contract.ShowCosts,
+		contract.RequireQuote).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating contract: %w", err)
+	}
+
+	if err := insertContractPrices(ctx, tx, id, contract.Prices); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const contractSelect = `SELECT ct.id, ct.client_id, c.name, ct.name, ct.starts_on, ct.ends_on, ct.value_per_km,
+			ct.initial_value, ct.show_costs, ct.require_quote, ct.created_at, ct.updated_at
+			FROM contracts ct
+			JOIN clients c ON c.id = ct.client_id`
+
+func (r *contractRepository) FindByID(ctx context.Context, id int) (*domain.Contract, error) {
+	filter, args := tenantClient(ctx, "ct.client_id", []interface{}{id})
+	contract, err := scanContract(conn(ctx, r.db).QueryRowContext(ctx, contractSelect+` WHERE ct.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrContractNotFound
+		}
+		return nil, fmt.Errorf("error finding contract: %w", err)
+	}
+
+	contracts := []domain.Contract{*contract}
+	if err := r.loadPrices(ctx, contracts); err != nil {
+		return nil, err
+	}
+
+	return &contracts[0], nil
+}
+
+// List retorna os contratos, opcionalmente filtrados pelo cliente
+func (r *contractRepository) List(ctx context.Context, clientID *int) ([]domain.Contract, error) {
+	query := contractSelect + " WHERE TRUE"
+	var args []interface{}
+	if clientID != nil {
+		args = append(args, *clientID)
+		query += " AND ct.client_id = $1"
+	}
+	tenant, args := tenantClient(ctx, "ct.client_id", args)
+	query += tenant
+	query += " ORDER BY c.name ASC, ct.starts_on DESC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing contracts: %w", err)
+	}
+	defer rows.Close()
+
+	var contracts []domain.Contract
+	for rows.Next() {
+		contract, err := scanContract(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning contract: %w", err)
+		}
+		contracts = append(contracts, *contract)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating contracts: %w", err)
+	}
+
+	if err := r.loadPrices(ctx, contracts); err != nil {
+		return nil, err
+	}
+
+	return contracts, nil
+}
+
+// Update substitui os dados e a tabela de preços do contrato
+func (r *contractRepository) Update(ctx context.Context, contract *domain.Contract) error {
+	if err := checkTenant(ctx, contract.ClientID); err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	filter, args := tenantClient(ctx, "client_id", []interface{}{
+		contract.ClientID, contract.Name, contract.StartsOn, contract.EndsOn, contract.ValuePerKm, contract.InitialValue, contract.ShowCosts,
+		contract.RequireQuote, contract.ID,
+	})
+	result, err := tx.ExecContext(ctx,
+		`UPDATE contracts SET client_id = $1, name = $2, starts_on = $3, ends_on = $4, value_per_km = $5,
+			initial_value = $6, show_costs = $7, require_quote = $8, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $9`+filter,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error updating contract: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrContractNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM contract_prices WHERE contract_id = $1`, contract.ID); err != nil {
+		return fmt.Errorf("error deleting contract prices: %w", err)
+	}
+
+	if err := insertContractPrices(ctx, tx, contract.ID, contract.Prices); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *contractRepository) Delete(ctx context.Context, id int) error {
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM contracts WHERE id = $1`+filter, args...)
+	if err != nil {
+		return fmt.Errorf("error deleting contract: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrContractNotFound
+	}
+
+	return nil
+}
+
+// FindForTicket retorna o contrato do cliente da agência vigente na data de abertura do ticket.
+// Retorna nil quando o cliente não tem contrato vigente.
+func (r *contractRepository) FindForTicket(ctx context.Context, ticketID int) (*domain.Contract, error) {
+	filter, args := tenantClient(ctx, "ct.client_id", []interface{}{ticketID})
+	contract, err := scanContract(conn(ctx, r.db).QueryRowContext(ctx, contractSelect+`
+			JOIN branchs b ON b.client_id = c.id
+			JOIN tickets t ON t.branch_id = b.id
+			WHERE t.id = $1
+				AND ct.starts_on <= t.open_date::date
+				AND (ct.ends_on IS NULL OR ct.ends_on >= t.open_date::date)`+filter+`
+			ORDER BY ct.starts_on DESC
+			LIMIT 1`, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding ticket contract: %w", err)
+	}
+
+	contracts := []domain.Contract{*contract}
+	if err := r.loadPrices(ctx, contracts); err != nil {
+		return nil, err
+	}
+
+	return &contracts[0], nil
+}
+
+// loadPrices carrega os preços negociados dos contratos informados
+func (r *contractRepository) loadPrices(ctx context.Context, contracts []domain.Contract) error {
+	if len(contracts) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(contracts))
+	index := make(map[int]int, len(contracts))
+	for i := range contracts {
+		ids = append(ids, int64(contracts[i].ID))
+		index[contracts[i].ID] = i
+		contracts[i].Prices = []domain.ContractPrice{}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT cp.id, cp.contract_id, cp.solution_id, s.name, cp.unit_price
+		 FROM contract_prices cp
+		 JOIN solutions s ON s.id = cp.solution_id
+		 WHERE cp.contract_id = ANY($1)
+		 ORDER BY s.name`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing contract prices: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var price domain.ContractPrice
+		if err := rows.Scan(&price.ID, &price.ContractID, &price.SolutionID, &price.SolutionName, &price.UnitPrice); err != nil {
+			return fmt.Errorf("error scanning contract price: %w", err)
+		}
+		contract := &contracts[index[price.ContractID]]
+		contract.Prices = append(contract.Prices, price)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating contract prices: %w", err)
+	}
+
+	return nil
+}
+
+func insertContractPrices(ctx context.Context, tx dbConn, contractID int, prices []domain.ContractPrice) error {
+	for _, price := range prices {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO contract_prices (contract_id, solution_id, unit_price) VALUES ($1, $2, $3)`,
+			contractID, price.SolutionID, price.UnitPrice)
+		if err != nil {
+			return fmt.Errorf("error creating contract price: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func scanContract(row rowScanner) (*domain.Contract, error) {
+	var contract domain.Contract
+	err := row.Scan(
+		&contract.ID,
+		&contract.ClientID,
+		&contract.ClientName,
+		&contract.Name,
+		&contract.StartsOn,
+		&contract.EndsOn,
+		&contract.ValuePerKm,
+		&contract.InitialValue,
+		&contract.ShowCosts,
+		&contract.RequireQuote,
+		&contract.CreatedAt,
+		&contract.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contract, nil
+}
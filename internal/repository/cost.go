@@ -15,6 +15,7 @@ var ErrCosthNotFound = errors.New("cost not found")
 type CostRepository interface {
 	List(ctx context.Context) ([]domain.Cost, error)
 	FindByID(ctx context.Context, id int) (*domain.Cost, error)
+	FindCurrent(ctx context.Context) (*domain.Cost, error)
 	Update(ctx context.Context, cost *domain.Cost) error
 	Delete(ctx context.Context, id int) error
 }
@@ -32,7 +33,7 @@ func NewCostRepository(db *sql.DB) CostRepository {
 func (r *costRepository) List(ctx context.Context) ([]domain.Cost, error) {
 	query := `SELECT id, value_per_km, initial_value FROM costs`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error listing branchs: %w", err)
 	}
@@ -61,7 +62,7 @@ func (r *costRepository) FindByID(ctx context.Context, id int) (*domain.Cost, er
 	query := `SELECT id, value_per_km, initial_value FROM costs WHERE id = $1`
 	var cost domain.Cost
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&cost.ID,
 		&cost.ValuePerKm,
 		&cost.InitialValue,
@@ -76,13 +77,33 @@ func (r *costRepository) FindByID(ctx context.Context, id int) (*domain.Cost, er
 	return &cost, nil
 }
 
+// FindCurrent retorna a tabela de custos vigente (último registro cadastrado)
+func (r *costRepository) FindCurrent(ctx context.Context) (*domain.Cost, error) {
+	query := `SELECT id, value_per_km, initial_value FROM costs ORDER BY id DESC LIMIT 1`
+	var cost domain.Cost
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query).Scan(
+		&cost.ID,
+		&cost.ValuePerKm,
+		&cost.InitialValue,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCosthNotFound
+		}
+		return nil, fmt.Errorf("error finding current cost: %w", err)
+	}
+
+	return &cost, nil
+}
+
 func (r *costRepository) Update(ctx context.Context, cost *domain.Cost) error {
 	query := `UPDATE costs SET 
 			value_per_km = $1, 
 			initial_value = $2, 
 			WHERE id = $3`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		cost.ValuePerKm,
 		cost.InitialValue,
 		cost.ID)
@@ -105,7 +126,7 @@ func (r *costRepository) Update(ctx context.Context, cost *domain.Cost) error {
 func (r *costRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM costs WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting branch: %w", err)
 	}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// Quantidade de problemas listados no ranking do painel
+const dashboardTopProblems = 10
+
+type DashboardRepository interface {
+	Dashboard(ctx context.Context, filter domain.DashboardFilter) (*domain.Dashboard, error)
+}
+
+type dashboardRepository struct {
+	db *sql.DB
+}
+
+func NewDashboardRepository(db *sql.DB) DashboardRepository {
+	return &dashboardRepository{db: db}
+}
+
+// Origem padrão dos indicadores; as consultas podem juntar outras tabelas desde que exponham "t"
+const dashboardTickets = "tickets t"
+
+// dashboardScope monta o FROM/WHERE comum a todos os indicadores: tickets abertos no período,
+// opcionalmente restritos às agências de um cliente, e sempre ao cliente (tenant) do context
+func dashboardScope(ctx context.Context, source string, filter domain.DashboardFilter) (string, []interface{}) {
+	scope := `FROM ` + source + `
+		 JOIN branchs b ON b.id = t.branch_id
+		 JOIN clients c ON c.id = b.client_id
+		 WHERE t.open_date >= $1 AND t.open_date < $2`
+	args := []interface{}{filter.From, filter.To}
+
+	if filter.ClientID != nil {
+		args = append(args, *filter.ClientID)
+		scope += fmt.Sprintf(" AND b.client_id = $%d", len(args))
+	}
+
+	tenant, args := tenantClient(ctx, "b.client_id", args)
+	return scope + tenant, args
+}
+
+// Dashboard calcula os indicadores do período direto no banco
+func (r *dashboardRepository) Dashboard(ctx context.Context, filter domain.DashboardFilter) (*domain.Dashboard, error) {
+	dashboard := &domain.Dashboard{
+		From:     filter.From,
+		To:       filter.To,
+		ClientID: filter.ClientID,
+	}
+
+	if err := r.loadStatusCounts(ctx, filter, dashboard); err != nil {
+		return nil, err
+	}
+	if err := r.loadOpenByClient(ctx, filter, dashboard); err != nil {
+		return nil, err
+	}
+	if err := r.loadMTTR(ctx, filter, dashboard); err != nil {
+		return nil, err
+	}
+	if err := r.loadCostByBranch(ctx, filter, dashboard); err != nil {
+		return nil, err
+	}
+	if err := r.loadTopProblems(ctx, filter, dashboard); err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+func (r *dashboardRepository) loadStatusCounts(ctx context.Context, filter domain.DashboardFilter, dashboard *domain.Dashboard) error {
+	scope, args := dashboardScope(ctx, dashboardTickets, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.status, COUNT(*) `+scope+` GROUP BY t.status`, args...)
+	if err != nil {
+		return fmt.Errorf("error counting tickets by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var status, count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return fmt.Errorf("error scanning status count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating status counts: %w", err)
+	}
+
+	// Todos os status aparecem, na ordem da numeração, mesmo sem tickets
+	dashboard.ByStatus = make([]domain.DashboardStatusCount, 0, len(domain.TicketStatusLabels))
+	for status := domain.TicketStatusAgendado; status <= domain.TicketStatusEmitirNota; status++ {
+		count := counts[status]
+		dashboard.ByStatus = append(dashboard.ByStatus, domain.DashboardStatusCount{
+			Status: status,
+			Label:  domain.TicketStatusLabels[status],
+			Count:  count,
+		})
+		dashboard.TotalTickets += count
+		if status != domain.TicketStatusConcluido {
+			dashboard.OpenTickets += count
+		}
+	}
+
+	return nil
+}
+
+func (r *dashboardRepository) loadOpenByClient(ctx context.Context, filter domain.DashboardFilter, dashboard *domain.Dashboard) error {
+	scope, args := dashboardScope(ctx, dashboardTickets, filter)
+	args = append(args, domain.TicketStatusConcluido)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT c.id, c.name, COUNT(*) `+scope+
+			fmt.Sprintf(` AND t.status <> $%d`, len(args))+
+			` GROUP BY c.id, c.name
+		 ORDER BY COUNT(*) DESC, c.name ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error counting open tickets by client: %w", err)
+	}
+	defer rows.Close()
+
+	dashboard.OpenByClient = []domain.DashboardClientCount{}
+	for rows.Next() {
+		var item domain.DashboardClientCount
+		var clientID sql.NullInt64
+		if err := rows.Scan(&clientID, &item.ClientName, &item.Open); err != nil {
+			return fmt.Errorf("error scanning client count: %w", err)
+		}
+		if clientID.Valid {
+			id := int(clientID.Int64)
+			item.ClientID = &id
+		}
+		dashboard.OpenByClient = append(dashboard.OpenByClient, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating client counts: %w", err)
+	}
+
+	return nil
+}
+
+// loadMTTR calcula o tempo médio de fechamento (em horas) geral e por prioridade
+func (r *dashboardRepository) loadMTTR(ctx context.Context, filter domain.DashboardFilter, dashboard *domain.Dashboard) error {
+	scope, args := dashboardScope(ctx, dashboardTickets, filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.priority, COUNT(*), AVG(EXTRACT(EPOCH FROM (t.close_date - t.open_date)) / 3600) `+scope+
+			` AND t.close_date IS NOT NULL
+		 GROUP BY t.priority
+		 ORDER BY t.priority ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error calculating mttr: %w", err)
+	}
+	defer rows.Close()
+
+	var closed int
+	var totalHours float64
+	dashboard.MTTRByPriority = []domain.DashboardPriorityMTTR{}
+	for rows.Next() {
+		var item domain.DashboardPriorityMTTR
+		if err := rows.Scan(&item.Priority, &item.ClosedTickets, &item.AvgHours); err != nil {
+			return fmt.Errorf("error scanning mttr: %w", err)
+		}
+		dashboard.MTTRByPriority = append(dashboard.MTTRByPriority, item)
+		closed += item.ClosedTickets
+		totalHours += item.AvgHours * float64(item.ClosedTickets)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating mttr: %w", err)
+	}
+
+	// Média geral ponderada pela quantidade de tickets fechados de cada prioridade
+	if closed > 0 {
+		avg := totalHours / float64(closed)
+		dashboard.AvgHoursToClose = &avg
+	}
+
+	return nil
+}
+
+// loadCostByBranch soma custos e distâncias por agência. Os totais por ticket são agregados
+// antes do JOIN para que várias linhas de custo não multipliquem as distâncias (e vice-versa).
+func (r *dashboardRepository) loadCostByBranch(ctx context.Context, filter domain.DashboardFilter, dashboard *domain.Dashboard) error {
+	scope, args := dashboardScope(ctx, `tickets t
+		 LEFT JOIN (SELECT ticket_id, SUM(subtotal) AS cost FROM ticket_costs GROUP BY ticket_id) tc ON tc.ticket_id = t.id
+		 LEFT JOIN (SELECT ticket_number, SUM(distance) AS km FROM distances GROUP BY ticket_number) d ON d.ticket_number = t.number`,
+		filter)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT b.id, b.name, c.name, COUNT(*),
+			COALESCE(SUM(tc.cost), 0), COALESCE(SUM(d.km), 0) `+scope+
+			` GROUP BY b.id, b.name, c.name
+		 ORDER BY COALESCE(SUM(tc.cost), 0) DESC, b.name ASC`,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error calculating cost by branch: %w", err)
+	}
+	defer rows.Close()
+
+	dashboard.CostByBranch = []domain.DashboardBranchCost{}
+	for rows.Next() {
+		var item domain.DashboardBranchCost
+		if err := rows.Scan(
+			&item.BranchID,
+			&item.BranchName,
+			&item.ClientName,
+			&item.Tickets,
+			&item.Cost,
+			&item.DistanceKm,
+		); err != nil {
+			return fmt.Errorf("error scanning branch cost: %w", err)
+		}
+		dashboard.CostByBranch = append(dashboard.CostByBranch, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating branch costs: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dashboardRepository) loadTopProblems(ctx context.Context, filter domain.DashboardFilter, dashboard *domain.Dashboard) error {
+	scope, args := dashboardScope(ctx, `ticket_problems tp
+		 JOIN problems p ON p.id = tp.problem_id
+		 JOIN tickets t ON t.id = tp.ticket_id`,
+		filter)
+	args = append(args, dashboardTopProblems)
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT p.id, p.name, COUNT(DISTINCT tp.ticket_id) `+scope+
+			` GROUP BY p.id, p.name
+		 ORDER BY COUNT(DISTINCT tp.ticket_id) DESC, p.name ASC`+
+			fmt.Sprintf(` LIMIT $%d`, len(args)),
+		args...)
+	if err != nil {
+		return fmt.Errorf("error listing top problems: %w", err)
+	}
+	defer rows.Close()
+
+	dashboard.TopProblems = []domain.DashboardProblemCount{}
+	for rows.Next() {
+		var item domain.DashboardProblemCount
+		if err := rows.Scan(&item.ProblemID, &item.ProblemName, &item.Tickets); err != nil {
+			return fmt.Errorf("error scanning top problem: %w", err)
+		}
+		dashboard.TopProblems = append(dashboard.TopProblems, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating top problems: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// Consultas feitas pelo painel, uma por indicador
+var dashboardQueries = []string{"GROUP BY t.status", "t.status <> $", "EXTRACT(EPOCH", "FROM ticket_costs", "FROM ticket_problems tp"}
+
+func TestDashboardScope(t *testing.T) {
+	clientID := ownerClient
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		clientID *int
+		filters  int // filtros por cliente em cada consulta
+		denied   bool
+	}{
+		{"system without client", tenant.System(context.Background()), nil, 0, false},
+		{"system with client", tenant.System(context.Background()), &clientID, 1, false},
+		// O tenant restringe mesmo quando o filtro aponta para outro cliente
+		{"tenant", tenant.WithClient(context.Background(), otherClient), nil, 1, false},
+		{"tenant with client", tenant.WithClient(context.Background(), otherClient), &clientID, 2, false},
+		{"no scope", context.Background(), nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			filter := domain.DashboardFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now(), ClientID: tt.clientID}
+			if _, err := NewDashboardRepository(db).Dashboard(tt.ctx, filter); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, fragment := range dashboardQueries {
+				calls := fake.calls(fragment)
+				if len(calls) != 1 {
+					t.Fatalf("got %d queries with %q, want 1", len(calls), fragment)
+				}
+				assertPlaceholders(t, calls)
+
+				query := calls[0].query
+				if got := strings.Count(query, "b.client_id = $"); got != tt.filters {
+					t.Errorf("%q: got %d client filters, want %d", fragment, got, tt.filters)
+				}
+				if strings.Contains(query, tenantDenied) != tt.denied {
+					t.Errorf("%q: denied = %v, want %v", fragment, !tt.denied, tt.denied)
+				}
+			}
+		})
+	}
+}
+
+func TestDashboardAggregates(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "GROUP BY t.status"):
+			return []string{"status", "count"}, [][]driver.Value{
+				{int64(domain.TicketStatusAgendado), int64(2)},
+				{int64(domain.TicketStatusConcluido), int64(4)},
+				{int64(domain.TicketStatusEmAtendimento), int64(1)},
+			}
+		case strings.Contains(query, "EXTRACT(EPOCH"):
+			return []string{"priority", "count", "avg"}, [][]driver.Value{
+				{"alta", int64(1), 10.0},
+				{"baixa", int64(3), 30.0},
+			}
+		}
+		return nil, nil
+	}
+
+	filter := domain.DashboardFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now()}
+	dashboard, err := NewDashboardRepository(db).Dashboard(tenant.System(context.Background()), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Todos os status aparecem, mesmo sem tickets; concluídos não contam como abertos
+	if len(dashboard.ByStatus) != len(domain.TicketStatusLabels) {
+		t.Fatalf("got %d statuses, want %d", len(dashboard.ByStatus), len(domain.TicketStatusLabels))
+	}
+	for i, item := range dashboard.ByStatus {
+		if item.Status != i+1 {
+			t.Fatalf("status %d at position %d", item.Status, i)
+		}
+	}
+	if dashboard.TotalTickets != 7 || dashboard.OpenTickets != 3 {
+		t.Fatalf("got total %d and open %d, want 7 and 3", dashboard.TotalTickets, dashboard.OpenTickets)
+	}
+
+	// Média geral ponderada: (1×10 + 3×30) / 4
+	if dashboard.AvgHoursToClose == nil || *dashboard.AvgHoursToClose != 25 {
+		t.Fatalf("got avg hours %v, want 25", dashboard.AvgHoursToClose)
+	}
+
+	// Listas vazias são devolvidas como [] e não null
+	if dashboard.OpenByClient == nil || dashboard.CostByBranch == nil || dashboard.TopProblems == nil {
+		t.Fatalf("expected empty lists, got %+v", dashboard)
+	}
+}
+
+func TestDashboardCostAggregatesPerTicketBeforeJoin(t *testing.T) {
+	fake, db := newFakeDB(t)
+	filter := domain.DashboardFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now()}
+	if _, err := NewDashboardRepository(db).Dashboard(tenant.System(context.Background()), filter); err != nil {
+		t.Fatal(err)
+	}
+
+	// Custos e distâncias somados por ticket em subconsultas: várias linhas de custo não
+	// multiplicam as distâncias
+	query := fake.calls("FROM ticket_costs")[0].query
+	for _, fragment := range []string{
+		"SELECT ticket_id, SUM(subtotal) AS cost FROM ticket_costs GROUP BY ticket_id",
+		"SELECT ticket_number, SUM(distance) AS km FROM distances GROUP BY ticket_number",
+	} {
+		if !strings.Contains(query, fragment) {
+			t.Errorf("query is missing %q: %s", fragment, query)
+		}
+	}
+}
@@ -32,11 +32,15 @@ func NewDistanceRepository(db *sql.DB) DistanceRepository {
 }
 
 func (r *distanceRepository) Create(ctx context.Context, distance *domain.Distance) (int, error) {
+	if err := checkTenantTicketNumber(ctx, r.db, distance.TicketNumber); err != nil {
+		return 0, err
+	}
+
 	query := `INSERT INTO distances (distance, ticket_number, provider_id, provider_name) 
 			VALUES ($1, $2, $3, $4) RETURNING id`
 
 	var id int
-	err := r.db.QueryRowContext(ctx, query,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		distance.Distance,
 		distance.TicketNumber,
 		distance.ProviderId,
@@ -49,9 +53,10 @@ func (r *distanceRepository) Create(ctx context.Context, distance *domain.Distan
 }
 
 func (r *distanceRepository) List(ctx context.Context) ([]domain.Distance, error) {
-	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances`
+	filter, args := tenantTicketNumber(ctx, "ticket_number", nil)
+	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances WHERE TRUE` + filter
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error listing distances: %w", err)
 	}
@@ -79,10 +84,11 @@ func (r *distanceRepository) List(ctx context.Context) ([]domain.Distance, error
 }
 
 func (r *distanceRepository) FindByID(ctx context.Context, id int) (*domain.Distance, error) {
-	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances WHERE id = $1`
+	filter, args := tenantTicketNumber(ctx, "ticket_number", []interface{}{id})
+	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances WHERE id = $1` + filter
 	var distance domain.Distance
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
 		&distance.ID,
 		&distance.Distance,
 		&distance.TicketNumber,
@@ -100,10 +106,11 @@ func (r *distanceRepository) FindByID(ctx context.Context, id int) (*domain.Dist
 }
 
 func (r *distanceRepository) FindByNumber(ctx context.Context, number string) (*domain.Distance, error) {
-	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances WHERE ticket_number = $1`
+	filter, args := tenantTicketNumber(ctx, "ticket_number", []interface{}{number})
+	query := `SELECT id, distance, ticket_number, provider_id, provider_name FROM distances WHERE ticket_number = $1` + filter
 	var distance domain.Distance
 
-	err := r.db.QueryRowContext(ctx, query, number).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
 		&distance.ID,
 		&distance.Distance,
 		&distance.TicketNumber,
@@ -121,19 +128,25 @@ func (r *distanceRepository) FindByNumber(ctx context.Context, number string) (*
 }
 
 func (r *distanceRepository) Update(ctx context.Context, distance *domain.Distance) error {
+	if err := checkTenantTicketNumber(ctx, r.db, distance.TicketNumber); err != nil {
+		return err
+	}
+
+	filter, args := tenantTicketNumber(ctx, "ticket_number", []interface{}{
+		distance.Distance,
+		distance.TicketNumber,
+		distance.ProviderId,
+		distance.ProviderName,
+		distance.ID,
+	})
 	query := `UPDATE distances SET 
 			distance = $1, 
 			ticket_number = $2, 
 			provider_id = $3, 
 			provider_name = $4 
-			WHERE id = $5`
+			WHERE id = $5` + filter
 
-	result, err := r.db.ExecContext(ctx, query,
-		distance.Distance,
-		distance.TicketNumber,
-		distance.ProviderId,
-		distance.ProviderName,
-		distance.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error updating distance: %w", err)
 	}
@@ -151,9 +164,10 @@ func (r *distanceRepository) Update(ctx context.Context, distance *domain.Distan
 }
 
 func (r *distanceRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM distances WHERE id = $1`
+	filter, args := tenantTicketNumber(ctx, "ticket_number", []interface{}{id})
+	query := `DELETE FROM distances WHERE id = $1` + filter
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error deleting distance: %w", err)
 	}
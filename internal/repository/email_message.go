@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// EmailMessageRepository registra os emails já ingeridos pelo Message-ID, para que uma
+// mensagem lida de novo (ex: falha ao marcá-la na caixa) não gere outro ticket ou comentário
+type EmailMessageRepository interface {
+	FindByMessageID(ctx context.Context, messageID string) (*domain.EmailMessage, error)
+	Create(ctx context.Context, messageID string, ticketID int) error
+	// UpdateAttachmentsSaved grava quantos anexos da mensagem já estão no ticket
+	UpdateAttachmentsSaved(ctx context.Context, messageID string, saved int) error
+}
+
+type emailMessageRepository struct {
+	db *sql.DB
+}
+
+func NewEmailMessageRepository(db *sql.DB) EmailMessageRepository {
+	return &emailMessageRepository{db: db}
+}
+
+func (r *emailMessageRepository) FindByMessageID(ctx context.Context, messageID string) (*domain.EmailMessage, error) {
+	var message domain.EmailMessage
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT message_id, ticket_id, attachments_saved FROM email_messages WHERE message_id = $1`, messageID,
+	).Scan(&message.MessageID, &message.TicketID, &message.AttachmentsSaved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding email message: %w", err)
+	}
+
+	return &message, nil
+}
+
+func (r *emailMessageRepository) Create(ctx context.Context, messageID string, ticketID int) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO email_messages (message_id, ticket_id) VALUES ($1, $2)`, messageID, ticketID)
+	if err != nil {
+		return fmt.Errorf("error recording email message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *emailMessageRepository) UpdateAttachmentsSaved(ctx context.Context, messageID string, saved int) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE email_messages SET attachments_saved = $2 WHERE message_id = $1`, messageID, saved)
+	if err != nil {
+		return fmt.Errorf("error updating email message attachments: %w", err)
+	}
+
+	return nil
+}
@@ -5,4 +5,7 @@ import "errors"
 // Common repository errors
 var (
 	ErrNotFound = errors.New("record not found")
+
+	// ErrVersionConflict indica que o registro foi alterado depois da versão informada
+	ErrVersionConflict = errors.New("record was modified by another request")
 )
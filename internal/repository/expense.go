@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrExpenseNotFound = errors.New("expense not found")
+	ErrExpenseStatus   = errors.New("expense status does not allow this operation")
+	ErrExpensePending  = errors.New("ticket already has an expense awaiting review")
+)
+
+type ExpenseRepository interface {
+	Create(ctx context.Context, expense *domain.Expense) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.Expense, error)
+	List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error)
+	Review(ctx context.Context, expense *domain.Expense, userID int) error
+}
+
+type expenseRepository struct {
+	db *sql.DB
+}
+
+func NewExpenseRepository(db *sql.DB) ExpenseRepository {
+	return &expenseRepository{db: db}
+}
+
+// Create grava a prestação de contas e seus itens pendentes de revisão
+func (r *expenseRepository) Create(ctx context.Context, expense *domain.Expense) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, expense.TicketID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O índice único parcial garante uma única prestação pendente por ticket
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO ticket_expenses (ticket_id, provider_id, status, notes) VALUES ($1, $2, $3, $4) RETURNING id`,
+		expense.TicketID, expense.ProviderID, domain.ExpensePending, expense.Notes).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ErrExpensePending
+		}
+		return 0, fmt.Errorf("error creating expense: %w", err)
+	}
+
+	for _, item := range expense.Items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO ticket_expense_items (expense_id, description, quantity, unit_price, attachment_id, status)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, item.Description, item.Quantity, item.UnitPrice, item.AttachmentID, domain.ExpensePending)
+		if err != nil {
+			return 0, fmt.Errorf("error creating expense item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const expenseSelect = `SELECT e.id, e.ticket_id, t.number, e.provider_id, p.name, e.status, e.notes,
+			e.decided_by, e.decided_at, e.decision_note, e.created_at, e.updated_at
+			FROM ticket_expenses e
+			JOIN tickets t ON t.id = e.ticket_id
+			JOIN providers p ON p.id = e.provider_id`
+
+func (r *expenseRepository) FindByID(ctx context.Context, id int) (*domain.Expense, error) {
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{id})
+	expense, err := scanExpense(conn(ctx, r.db).QueryRowContext(ctx, expenseSelect+` WHERE e.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, fmt.Errorf("error finding expense: %w", err)
+	}
+
+	expenses := []domain.Expense{*expense}
+	if err := r.loadItems(ctx, expenses); err != nil {
+		return nil, err
+	}
+
+	return &expenses[0], nil
+}
+
+// List retorna as prestações de contas, opcionalmente filtradas por ticket e status
+func (r *expenseRepository) List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error) {
+	query := expenseSelect + " WHERE 1=1"
+	var args []interface{}
+	if ticketID != nil {
+		args = append(args, *ticketID)
+		query += fmt.Sprintf(" AND e.ticket_id = $%d", len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND e.status = $%d", len(args))
+	}
+	tenant, args := tenantBranch(ctx, "t.branch_id", args)
+	query += tenant
+	query += " ORDER BY e.created_at DESC, e.id DESC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var expenses []domain.Expense
+	for rows.Next() {
+		expense, err := scanExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning expense: %w", err)
+		}
+		expenses = append(expenses, *expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expenses: %w", err)
+	}
+
+	if err := r.loadItems(ctx, expenses); err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+// Review grava a decisão de cada item e o resultado da prestação pendente
+func (r *expenseRepository) Review(ctx context.Context, expense *domain.Expense, userID int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O lock no ticket serializa as revisões: a última a concluir enxerga as demais ao
+	// conferir se ainda há despesas pendentes
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM tickets WHERE id = $1 FOR UPDATE`, expense.TicketID); err != nil {
+		return fmt.Errorf("error locking ticket: %w", err)
+	}
+
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		expense.Status, expense.DecisionNote, userID, expense.ID, domain.ExpensePending,
+	})
+	result, err := tx.ExecContext(ctx,
+		`UPDATE ticket_expenses
+		 SET status = $1, decision_note = $2, decided_by = $3, decided_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $4 AND status = $5`+filter,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error reviewing expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrExpenseStatus
+	}
+
+	for _, item := range expense.Items {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE ticket_expense_items SET status = $1, rejection_reason = $2 WHERE id = $3 AND expense_id = $4`,
+			item.Status, item.RejectionReason, item.ID, expense.ID)
+		if err != nil {
+			return fmt.Errorf("error reviewing expense item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// loadItems carrega os itens das prestações informadas
+func (r *expenseRepository) loadItems(ctx context.Context, expenses []domain.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(expenses))
+	index := make(map[int]int, len(expenses))
+	for i := range expenses {
+		ids = append(ids, int64(expenses[i].ID))
+		index[expenses[i].ID] = i
+		expenses[i].Items = []domain.ExpenseItem{}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, expense_id, description, quantity, unit_price, attachment_id, status, rejection_reason
+		 FROM ticket_expense_items
+		 WHERE expense_id = ANY($1)
+		 ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing expense items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.ExpenseItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.ExpenseID,
+			&item.Description,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.AttachmentID,
+			&item.Status,
+			&item.RejectionReason,
+		); err != nil {
+			return fmt.Errorf("error scanning expense item: %w", err)
+		}
+		expense := &expenses[index[item.ExpenseID]]
+		expense.Items = append(expense.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating expense items: %w", err)
+	}
+
+	return nil
+}
+
+func scanExpense(row rowScanner) (*domain.Expense, error) {
+	var expense domain.Expense
+	err := row.Scan(
+		&expense.ID,
+		&expense.TicketID,
+		&expense.TicketNumber,
+		&expense.ProviderID,
+		&expense.ProviderName,
+		&expense.Status,
+		&expense.Notes,
+		&expense.DecidedBy,
+		&expense.DecidedAt,
+		&expense.DecisionNote,
+		&expense.CreatedAt,
+		&expense.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+// Auxiliares do banco falso para os testes externos, que exercitam os serviços sobre os
+// repositórios reais
+
+type FakeDB = fakeDB
+
+var ErrInjected = errInjected
+
+func NewFakeDB(t *testing.T) (*FakeDB, *sql.DB) { return newFakeDB(t) }
+
+// FailOn faz falhar o primeiro comando que contém o trecho
+func (f *fakeDB) FailOn(fragment string) { f.failOn = fragment }
+
+// Respond define as linhas devolvidas pelas consultas
+func (f *fakeDB) Respond(respond func(query string, args []driver.Value) ([]string, [][]driver.Value)) {
+	f.respond = respond
+}
+
+// Calls conta os comandos que contêm o trecho
+func (f *fakeDB) Calls(fragment string) int { return len(f.calls(fragment)) }
+
+func AssertRolledBack(t *testing.T, fake *FakeDB) {
+	t.Helper()
+	assertRolledBack(t, fake)
+}
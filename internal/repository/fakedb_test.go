@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB é um banco em memória para testes dos repositórios: registra os comandos
+// executados, responde as consultas com respond e falha no comando que contém failOn.
+type fakeDB struct {
+	mu        sync.Mutex
+	execs     []fakeCall
+	begins    int
+	commits   int
+	rollbacks int
+
+	// failOn faz falhar o primeiro comando que contém o trecho
+	failOn string
+	// respond devolve as colunas e linhas da consulta; nil responde sem linhas
+	respond func(query string, args []driver.Value) ([]string, [][]driver.Value)
+	// affected devolve as linhas afetadas pelo comando; nil afeta uma linha
+	affected func(query string, args []driver.Value) int64
+}
+
+type fakeCall struct {
+	query string
+	args  []driver.Value
+	inTx  bool
+}
+
+var errInjected = errors.New("injected failure")
+
+func newFakeDB(t *testing.T) (*fakeDB, *sql.DB) {
+	t.Helper()
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	// Uma conexão só: a transação e os comandos fora dela usam o mesmo estado
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return fake, db
+}
+
+// calls retorna os comandos que contêm o trecho
+func (f *fakeDB) calls(fragment string) []fakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []fakeCall
+	for _, call := range f.execs {
+		if strings.Contains(call.query, fragment) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// assertPlaceholders confere que cada comando usa exatamente os argumentos enviados ($1..$N)
+func assertPlaceholders(t *testing.T, calls []fakeCall) {
+	t.Helper()
+	for _, call := range calls {
+		highest := 0
+		for _, match := range placeholderPattern.FindAllStringSubmatch(call.query, -1) {
+			if n, _ := strconv.Atoi(match[1]); n > highest {
+				highest = n
+			}
+		}
+		if highest != len(call.args) {
+			t.Errorf("query uses $%d but got %d args: %s", highest, len(call.args), call.query)
+		}
+	}
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return fakeDriver{f} }
+
+type fakeDriver struct{ db *fakeDB }
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{db: d.db}, nil }
+
+type fakeConn struct {
+	db   *fakeDB
+	inTx bool
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.begins++
+	c.inTx = true
+	return &fakeTx{conn: c}, nil
+}
+
+// CheckNamedValue aceita qualquer argumento, para que os testes vejam os valores originais
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) record(query string, args []driver.NamedValue) ([]driver.Value, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.db.execs = append(c.db.execs, fakeCall{query: query, args: values, inTx: c.inTx})
+
+	if c.db.failOn != "" && strings.Contains(query, c.db.failOn) {
+		c.db.failOn = ""
+		return nil, errInjected
+	}
+	return values, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values, err := c.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.db.affected != nil {
+		return driver.RowsAffected(c.db.affected(query, values)), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := c.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &fakeRows{}
+	if c.db.respond != nil {
+		rows.columns, rows.values = c.db.respond(query, values)
+	}
+	return rows, nil
+}
+
+type fakeTx struct{ conn *fakeConn }
+
+func (t *fakeTx) Commit() error {
+	t.conn.db.mu.Lock()
+	defer t.conn.db.mu.Unlock()
+	t.conn.db.commits++
+	t.conn.inTx = false
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.conn.db.mu.Lock()
+	defer t.conn.db.mu.Unlock()
+	t.conn.db.rollbacks++
+	t.conn.inTx = false
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	if r.columns == nil {
+		return []string{"?"}
+	}
+	return r.columns
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,675 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInventoryItemNotFound = errors.New("inventory item not found")
+	ErrStockLocationNotFound = errors.New("stock location not found")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrDuplicateSKU          = errors.New("sku already registered")
+)
+
+// Código do PostgreSQL para violação de UNIQUE
+const uniqueViolation = "23505"
+
+type InventoryRepository interface {
+	// Itens
+	CreateItem(ctx context.Context, item *domain.InventoryItem) (int, error)
+	ListItems(ctx context.Context) ([]domain.InventoryItem, error)
+	FindItemByID(ctx context.Context, id int) (*domain.InventoryItem, error)
+	UpdateItem(ctx context.Context, item *domain.InventoryItem) error
+
+	// Locais
+	CreateLocation(ctx context.Context, location *domain.StockLocation) (int, error)
+	ListLocations(ctx context.Context) ([]domain.StockLocation, error)
+	FindLocationByID(ctx context.Context, id int) (*domain.StockLocation, error)
+
+	// Saldos e movimentações
+	ListLevelsByItem(ctx context.Context, itemID int) ([]domain.StockLevel, error)
+	RecordMovement(ctx context.Context, movement *domain.StockMovement) error
+	Transfer(ctx context.Context, itemID, fromLocationID, toLocationID, quantity int, note string) error
+	ListMovementsByItem(ctx context.Context, itemID, limit, offset int) ([]domain.StockMovement, int, error)
+	LowStock(ctx context.Context) ([]domain.LowStockItem, error)
+
+	// Lista de materiais (BOM)
+	ListSolutionParts(ctx context.Context, solutionID int) ([]domain.SolutionPart, error)
+	ReplaceSolutionParts(ctx context.Context, solutionID int, parts []domain.SolutionPart) error
+
+	// Reservas por ticket
+	Reserve(ctx context.Context, ticketID, solutionID int, parts []domain.SolutionPart, multiplier int, locationIDs []int) ([]int, error)
+	ReleaseReservations(ctx context.Context, ticketID int, solutionID *int) error
+	ConsumeReservations(ctx context.Context, ticketID int) error
+	ListReservationsByTicket(ctx context.Context, ticketID int) ([]domain.StockReservation, error)
+}
+
+type inventoryRepository struct {
+	db *sql.DB
+}
+
+func NewInventoryRepository(db *sql.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) CreateItem(ctx context.Context, item *domain.InventoryItem) (int, error) {
+	query := `INSERT INTO inventory_items (sku, name, unit, min_quantity) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, item.SKU, item.Name, item.Unit, item.MinQuantity).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ErrDuplicateSKU
+		}
+		return 0, fmt.Errorf("error creating inventory item: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *inventoryRepository) ListItems(ctx context.Context) ([]domain.InventoryItem, error) {
+	query := `SELECT id, sku, name, unit, min_quantity, created_at, updated_at FROM inventory_items ORDER BY name ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing inventory items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.InventoryItem
+	for rows.Next() {
+		var item domain.InventoryItem
+		if err := rows.Scan(&item.ID, &item.SKU, &item.Name, &item.Unit, &item.MinQuantity, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning inventory item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *inventoryRepository) FindItemByID(ctx context.Context, id int) (*domain.InventoryItem, error) {
+	query := `SELECT id, sku, name, unit, min_quantity, created_at, updated_at FROM inventory_items WHERE id = $1`
+
+	var item domain.InventoryItem
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&item.ID, &item.SKU, &item.Name, &item.Unit, &item.MinQuantity, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInventoryItemNotFound
+		}
+		return nil, fmt.Errorf("error finding inventory item: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *inventoryRepository) UpdateItem(ctx context.Context, item *domain.InventoryItem) error {
+	query := `UPDATE inventory_items SET sku = $1, name = $2, unit = $3, min_quantity = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, item.SKU, item.Name, item.Unit, item.MinQuantity, item.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicateSKU
+		}
+		return fmt.Errorf("error updating inventory item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrInventoryItemNotFound
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) CreateLocation(ctx context.Context, location *domain.StockLocation) (int, error) {
+	query := `INSERT INTO stock_locations (name, kind, provider_id) VALUES ($1, $2, $3) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, location.Name, location.Kind, location.ProviderID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating stock location: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *inventoryRepository) ListLocations(ctx context.Context) ([]domain.StockLocation, error) {
+	query := `SELECT id, name, kind, provider_id, created_at FROM stock_locations ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stock locations: %w", err)
+	}
+	defer rows.Close()
+
+	var locations []domain.StockLocation
+	for rows.Next() {
+		var location domain.StockLocation
+		if err := rows.Scan(&location.ID, &location.Name, &location.Kind, &location.ProviderID, &location.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning stock location: %w", err)
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+func (r *inventoryRepository) FindLocationByID(ctx context.Context, id int) (*domain.StockLocation, error) {
+	query := `SELECT id, name, kind, provider_id, created_at FROM stock_locations WHERE id = $1`
+
+	var location domain.StockLocation
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&location.ID, &location.Name, &location.Kind, &location.ProviderID, &location.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStockLocationNotFound
+		}
+		return nil, fmt.Errorf("error finding stock location: %w", err)
+	}
+
+	return &location, nil
+}
+
+func (r *inventoryRepository) ListLevelsByItem(ctx context.Context, itemID int) ([]domain.StockLevel, error) {
+	query := `SELECT sl.item_id, sl.location_id, l.name, sl.quantity, sl.reserved
+			FROM stock_levels sl
+			JOIN stock_locations l ON l.id = sl.location_id
+			WHERE sl.item_id = $1
+			ORDER BY l.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stock levels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []domain.StockLevel
+	for rows.Next() {
+		var level domain.StockLevel
+		if err := rows.Scan(&level.ItemID, &level.LocationID, &level.LocationName, &level.Quantity, &level.Reserved); err != nil {
+			return nil, fmt.Errorf("error scanning stock level: %w", err)
+		}
+		levels = append(levels, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock levels: %w", err)
+	}
+
+	return levels, nil
+}
+
+// RecordMovement registra entrada, saída ou ajuste (quantity com sinal) e atualiza o saldo
+func (r *inventoryRepository) RecordMovement(ctx context.Context, movement *domain.StockMovement) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	delta := movement.Quantity
+	if movement.Kind == domain.MovementOut {
+		delta = -movement.Quantity
+	}
+
+	if err := applyStockDelta(ctx, tx, movement.ItemID, movement.LocationID, delta, 0); err != nil {
+		return err
+	}
+
+	if err := insertMovement(ctx, tx, movement); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Transfer move saldo livre de um local para outro
+func (r *inventoryRepository) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID, quantity int, note string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := applyStockDelta(ctx, tx, itemID, fromLocationID, -quantity, 0); err != nil {
+		return err
+	}
+	if err := applyStockDelta(ctx, tx, itemID, toLocationID, quantity, 0); err != nil {
+		return err
+	}
+
+	movements := []domain.StockMovement{
+		{ItemID: itemID, LocationID: fromLocationID, Kind: domain.MovementTransferOut, Quantity: quantity, Note: note},
+		{ItemID: itemID, LocationID: toLocationID, Kind: domain.MovementTransferIn, Quantity: quantity, Note: note},
+	}
+	for i := range movements {
+		if err := insertMovement(ctx, tx, &movements[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) ListMovementsByItem(ctx context.Context, itemID, limit, offset int) ([]domain.StockMovement, int, error) {
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_movements WHERE item_id = $1`, itemID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting stock movements: %w", err)
+	}
+
+	query := `SELECT m.id, m.item_id, m.location_id, l.name, m.kind, m.quantity, m.ticket_id, m.note, m.created_at
+			FROM stock_movements m
+			JOIN stock_locations l ON l.id = m.location_id
+			WHERE m.item_id = $1
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT $2 OFFSET $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, itemID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []domain.StockMovement
+	for rows.Next() {
+		var movement domain.StockMovement
+		if err := rows.Scan(
+			&movement.ID,
+			&movement.ItemID,
+			&movement.LocationID,
+			&movement.LocationName,
+			&movement.Kind,
+			&movement.Quantity,
+			&movement.TicketID,
+			&movement.Note,
+			&movement.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error scanning stock movement: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating stock movements: %w", err)
+	}
+
+	return movements, total, nil
+}
+
+// LowStock retorna os itens cujo saldo livre somado em todos os locais está abaixo do mínimo
+func (r *inventoryRepository) LowStock(ctx context.Context) ([]domain.LowStockItem, error) {
+	query := `SELECT i.id, i.sku, i.name, i.unit, i.min_quantity, i.created_at, i.updated_at,
+			COALESCE(SUM(sl.quantity), 0), COALESCE(SUM(sl.reserved), 0)
+			FROM inventory_items i
+			LEFT JOIN stock_levels sl ON sl.item_id = i.id
+			GROUP BY i.id
+			HAVING COALESCE(SUM(sl.quantity - sl.reserved), 0) < i.min_quantity
+			ORDER BY i.name ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing low stock items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []domain.LowStockItem
+	for rows.Next() {
+		var low domain.LowStockItem
+		if err := rows.Scan(
+			&low.Item.ID,
+			&low.Item.SKU,
+			&low.Item.Name,
+			&low.Item.Unit,
+			&low.Item.MinQuantity,
+			&low.Item.CreatedAt,
+			&low.Item.UpdatedAt,
+			&low.Quantity,
+			&low.Reserved,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning low stock item: %w", err)
+		}
+		low.Available = low.Quantity - low.Reserved
+		items = append(items, low)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating low stock items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *inventoryRepository) ListSolutionParts(ctx context.Context, solutionID int) ([]domain.SolutionPart, error) {
+	query := `SELECT sp.solution_id, sp.item_id, i.sku, i.name, sp.quantity
+			FROM solution_parts sp
+			JOIN inventory_items i ON i.id = sp.item_id
+			WHERE sp.solution_id = $1
+			ORDER BY i.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, solutionID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing solution parts: %w", err)
+	}
+	defer rows.Close()
+
+	var parts []domain.SolutionPart
+	for rows.Next() {
+		var part domain.SolutionPart
+		if err := rows.Scan(&part.SolutionID, &part.ItemID, &part.ItemSKU, &part.ItemName, &part.Quantity); err != nil {
+			return nil, fmt.Errorf("error scanning solution part: %w", err)
+		}
+		parts = append(parts, part)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating solution parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// ReplaceSolutionParts substitui a lista de materiais da solution
+func (r *inventoryRepository) ReplaceSolutionParts(ctx context.Context, solutionID int, parts []domain.SolutionPart) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM solution_parts WHERE solution_id = $1`, solutionID); err != nil {
+		return fmt.Errorf("failed to delete solution parts: %w", err)
+	}
+
+	for _, part := range parts {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO solution_parts (solution_id, item_id, quantity) VALUES ($1, $2, $3)`,
+			solutionID, part.ItemID, part.Quantity); err != nil {
+			return fmt.Errorf("failed to insert solution part: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Reserve reserva as peças (quantidade do BOM x multiplier) no primeiro local da lista com saldo livre suficiente.
+// Se algum item não tiver saldo, nada é reservado.
+func (r *inventoryRepository) Reserve(ctx context.Context, ticketID, solutionID int, parts []domain.SolutionPart, multiplier int, locationIDs []int) ([]int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ids []int
+	for _, part := range parts {
+		quantity := part.Quantity * multiplier
+
+		locationID, err := lockLocationWithStock(ctx, tx, part.ItemID, quantity, locationIDs)
+		if err != nil {
+			return nil, err
+		}
+		if locationID == 0 {
+			return nil, fmt.Errorf("%w: item %s needs %d", ErrInsufficientStock, part.ItemSKU, quantity)
+		}
+
+		if err := applyStockDelta(ctx, tx, part.ItemID, locationID, 0, quantity); err != nil {
+			return nil, err
+		}
+
+		var id int
+		if err := tx.QueryRowContext(ctx,
+			`INSERT INTO stock_reservations (ticket_id, solution_id, item_id, location_id, quantity) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			ticketID, solutionID, part.ItemID, locationID, quantity).Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to insert stock reservation: %w", err)
+		}
+		ids = append(ids, id)
+
+		if err := insertMovement(ctx, tx, &domain.StockMovement{
+			ItemID:     part.ItemID,
+			LocationID: locationID,
+			Kind:       domain.MovementReserve,
+			Quantity:   quantity,
+			TicketID:   &ticketID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ReleaseReservations devolve ao saldo livre as reservas ativas do ticket (de uma solution, se informada)
+func (r *inventoryRepository) ReleaseReservations(ctx context.Context, ticketID int, solutionID *int) error {
+	return r.closeReservations(ctx, ticketID, solutionID, domain.ReservationReleased)
+}
+
+// ConsumeReservations baixa do estoque as reservas ativas do ticket
+func (r *inventoryRepository) ConsumeReservations(ctx context.Context, ticketID int) error {
+	return r.closeReservations(ctx, ticketID, nil, domain.ReservationConsumed)
+}
+
+func (r *inventoryRepository) closeReservations(ctx context.Context, ticketID int, solutionID *int, status string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`UPDATE stock_reservations SET status = $1, updated_at = CURRENT_TIMESTAMP
+		 WHERE ticket_id = $2 AND status = $3
+		   AND ($4::INTEGER IS NULL OR solution_id = $4)
+		 RETURNING item_id, location_id, quantity`,
+		status, ticketID, domain.ReservationReserved, solutionID)
+	if err != nil {
+		return fmt.Errorf("failed to update stock reservations: %w", err)
+	}
+
+	var reservations []domain.StockReservation
+	for rows.Next() {
+		var reservation domain.StockReservation
+		if err := rows.Scan(&reservation.ItemID, &reservation.LocationID, &reservation.Quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan stock reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating stock reservations: %w", err)
+	}
+
+	kind := domain.MovementRelease
+	if status == domain.ReservationConsumed {
+		kind = domain.MovementConsume
+	}
+
+	for _, reservation := range reservations {
+		// Consumo baixa o saldo em mãos e o reservado; liberação só o reservado
+		quantityDelta := 0
+		if status == domain.ReservationConsumed {
+			quantityDelta = -reservation.Quantity
+		}
+
+		if err := applyStockDelta(ctx, tx, reservation.ItemID, reservation.LocationID, quantityDelta, -reservation.Quantity); err != nil {
+			return err
+		}
+
+		if err := insertMovement(ctx, tx, &domain.StockMovement{
+			ItemID:     reservation.ItemID,
+			LocationID: reservation.LocationID,
+			Kind:       kind,
+			Quantity:   reservation.Quantity,
+			TicketID:   &ticketID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *inventoryRepository) ListReservationsByTicket(ctx context.Context, ticketID int) ([]domain.StockReservation, error) {
+	filter, args := tenantTicket(ctx, "r.ticket_id", []interface{}{ticketID})
+	query := `SELECT r.id, r.ticket_id, r.solution_id, r.item_id, i.name, r.location_id, l.name, r.quantity, r.status, r.created_at
+			FROM stock_reservations r
+			JOIN inventory_items i ON i.id = r.item_id
+			JOIN stock_locations l ON l.id = r.location_id
+			WHERE r.ticket_id = $1` + filter + `
+			ORDER BY r.created_at, r.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing stock reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []domain.StockReservation
+	for rows.Next() {
+		var reservation domain.StockReservation
+		if err := rows.Scan(
+			&reservation.ID,
+			&reservation.TicketID,
+			&reservation.SolutionID,
+			&reservation.ItemID,
+			&reservation.ItemName,
+			&reservation.LocationID,
+			&reservation.LocationName,
+			&reservation.Quantity,
+			&reservation.Status,
+			&reservation.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning stock reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// applyStockDelta altera saldo em mãos e reservado de um item/local, criando o saldo se não existir.
+// Retorna ErrInsufficientStock se o saldo ficaria negativo ou o reservado acima do saldo.
+func applyStockDelta(ctx context.Context, tx dbConn, itemID, locationID, quantityDelta, reservedDelta int) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE stock_levels SET quantity = quantity + $3, reserved = reserved + $4
+		 WHERE item_id = $1 AND location_id = $2
+		   AND quantity + $3 >= 0
+		   AND reserved + $4 BETWEEN 0 AND quantity + $3`,
+		itemID, locationID, quantityDelta, reservedDelta)
+	if err != nil {
+		return fmt.Errorf("failed to update stock level: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 1 {
+		return nil
+	}
+
+	// Sem saldo registrado no local: só é possível criar com valores válidos
+	if quantityDelta < 0 || reservedDelta < 0 || reservedDelta > quantityDelta {
+		return ErrInsufficientStock
+	}
+
+	result, err = tx.ExecContext(ctx,
+		`INSERT INTO stock_levels (item_id, location_id, quantity, reserved) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (item_id, location_id) DO NOTHING`,
+		itemID, locationID, quantityDelta, reservedDelta)
+	if err != nil {
+		return fmt.Errorf("failed to create stock level: %w", err)
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	// O saldo já existia (e não comportava a alteração)
+	if rowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+// lockLocationWithStock bloqueia e retorna o primeiro local da lista com saldo livre suficiente (0 se nenhum)
+func lockLocationWithStock(ctx context.Context, tx dbConn, itemID, quantity int, locationIDs []int) (int, error) {
+	for _, locationID := range locationIDs {
+		var available int
+		err := tx.QueryRowContext(ctx,
+			`SELECT quantity - reserved FROM stock_levels WHERE item_id = $1 AND location_id = $2 FOR UPDATE`,
+			itemID, locationID).Scan(&available)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return 0, fmt.Errorf("failed to lock stock level: %w", err)
+		}
+
+		if available >= quantity {
+			return locationID, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func insertMovement(ctx context.Context, tx dbConn, movement *domain.StockMovement) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO stock_movements (item_id, location_id, kind, quantity, ticket_id, note) VALUES ($1, $2, $3, $4, $5, $6)`,
+		movement.ItemID, movement.LocationID, movement.Kind, movement.Quantity, movement.TicketID, movement.Note)
+	if err != nil {
+		return fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+func TestInventoryReserveUsesFirstLocationWithStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		available map[int64]int64 // saldo livre por local
+		want      int64
+		wantErr   error
+	}{
+		{"first location has stock", map[int64]int64{3: 5, 1: 5}, 3, nil},
+		{"falls back to the next location", map[int64]int64{3: 1, 1: 5}, 1, nil},
+		{"skips locations without the item", map[int64]int64{1: 2}, 1, nil},
+		{"no location has enough", map[int64]int64{3: 1, 1: 1}, 0, ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				switch {
+				case strings.Contains(query, "FROM stock_levels"):
+					if available, ok := tt.available[int64(args[1].(int))]; ok {
+						return []string{"available"}, [][]driver.Value{{available}}
+					}
+				case strings.Contains(query, "INSERT INTO stock_reservations"):
+					return []string{"id"}, [][]driver.Value{{int64(9)}}
+				}
+				return nil, nil
+			}
+
+			parts := []domain.SolutionPart{{ItemID: 7, ItemSKU: "CABO", Quantity: 1}}
+			_, err := NewInventoryRepository(db).Reserve(tenant.System(context.Background()), 10, 2, parts, 2, []int{3, 1})
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			reservations := fake.calls("INSERT INTO stock_reservations")
+			if tt.wantErr != nil {
+				if len(reservations) != 0 || fake.commits != 0 {
+					t.Fatalf("reservation written without stock")
+				}
+				return
+			}
+			// Quantidade da peça vezes a quantidade da solution, no local escolhido
+			if len(reservations) != 1 {
+				t.Fatalf("got %d reservations, want 1", len(reservations))
+			}
+			if reservations[0].args[3] != int(tt.want) || reservations[0].args[4] != 2 {
+				t.Fatalf("got reservation args %v, want location %d and quantity 2", reservations[0].args, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvoiceNotFound  = errors.New("invoice not found")
+	ErrInvoiceStatus    = errors.New("invoice status does not allow this operation")
+	ErrInvoiceNoTickets = errors.New("no tickets awaiting billing for the client in the period")
+)
+
+type InvoiceRepository interface {
+	Create(ctx context.Context, invoice *domain.Invoice) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.Invoice, error)
+	List(ctx context.Context, clientID *int, status string) ([]domain.Invoice, error)
+	ClaimIssue(ctx context.Context, id int) error
+	ReleaseIssue(ctx context.Context, id int) error
+	MarkIssued(ctx context.Context, invoice *domain.Invoice) error
+	Cancel(ctx context.Context, id int, reason string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type invoiceRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceRepository(db *sql.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// Create grava a fatura em rascunho com os tickets do cliente aguardando faturamento no período,
+// os serviços lançados e o deslocamento das visitas. Os tickets incluídos passam para Emitir Nota.
+func (r *invoiceRepository) Create(ctx context.Context, invoice *domain.Invoice) (int, error) {
+	if err := checkTenant(ctx, invoice.ClientID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO invoices (client_id, period_start, period_end, status, notes)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		invoice.ClientID, invoice.PeriodStart, invoice.PeriodEnd, domain.InvoiceDraft, invoice.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating invoice: %w", err)
+	}
+
+	// Tickets das agências do cliente fechados no período e aguardando faturamento.
+	// O FOR UPDATE impede que duas faturas geradas ao mesmo tempo incluam o mesmo ticket.
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO invoice_tickets (invoice_id, ticket_id)
+		 SELECT $1, t.id
+		 FROM tickets t
+		 JOIN branchs b ON b.id = t.branch_id
+		 WHERE b.client_id = $2
+			AND t.status = $3
+			AND t.close_date::date BETWEEN $4 AND $5
+		 FOR UPDATE OF t`,
+		id, invoice.ClientID, domain.TicketStatusAguardaFaturamento, invoice.PeriodStart, invoice.PeriodEnd)
+	if err != nil {
+		return 0, fmt.Errorf("error adding invoice tickets: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, ErrInvoiceNoTickets
+	}
+
+	// Serviços: linhas de custo do ticket (retrabalhos em garantia têm valor zero e ficam de fora)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO invoice_lines (invoice_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount)
+		 SELECT $1, $2, c.ticket_id, c.visit_id,
+			'Chamado ' || t.number || ': ' || c.problem_name || ' - ' || c.solution_name,
+			c.quantity, c.unit_price, c.subtotal
+		 FROM ticket_costs c
+		 JOIN tickets t ON t.id = c.ticket_id
+		 JOIN invoice_tickets it ON it.ticket_id = c.ticket_id AND it.invoice_id = $1
+		 WHERE c.subtotal > 0
+		 ORDER BY t.number, c.id`,
+		id, domain.InvoiceLineService)
+	if err != nil {
+		return 0, fmt.Errorf("error adding invoice service lines: %w", err)
+	}
+
+	// Deslocamento de cada visita (distância x custo da tabela ou do contrato)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO invoice_lines (invoice_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount)
+		 SELECT $1, $2, v.ticket_id, v.id,
+			'Deslocamento chamado ' || t.number || COALESCE(' (' || v.distance_km || ' km)', ''),
+			1, v.travel_cost, v.travel_cost
+		 FROM ticket_visits v
+		 JOIN tickets t ON t.id = v.ticket_id
+		 JOIN invoice_tickets it ON it.ticket_id = v.ticket_id AND it.invoice_id = $1
+		 WHERE v.travel_cost > 0
+		 ORDER BY t.number, v.starts_at`,
+		id, domain.InvoiceLineTravel)
+	if err != nil {
+		return 0, fmt.Errorf("error adding invoice travel lines: %w", err)
+	}
+
+	if err := updateInvoiceTickets(ctx, tx, id, domain.TicketStatusEmitirNota); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const invoiceSelect = `SELECT i.id, i.client_id, c.name, c.document, i.period_start, i.period_end, i.status, i.notes,
+			i.issuer, i.number, i.verification_code, i.xml, i.issued_at, i.cancelled_at, i.cancel_reason,
+			i.created_at, i.updated_at
+			FROM invoices i
+			JOIN clients c ON c.id = i.client_id`
+
+func (r *invoiceRepository) FindByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	filter, args := tenantClient(ctx, "i.client_id", []interface{}{id})
+	invoice, err := scanInvoice(conn(ctx, r.db).QueryRowContext(ctx, invoiceSelect+` WHERE i.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("error finding invoice: %w", err)
+	}
+
+	invoices := []domain.Invoice{*invoice}
+	if err := r.loadDetails(ctx, invoices); err != nil {
+		return nil, err
+	}
+
+	return &invoices[0], nil
+}
+
+// List retorna as faturas, opcionalmente filtradas por cliente e status
+func (r *invoiceRepository) List(ctx context.Context, clientID *int, status string) ([]domain.Invoice, error) {
+	query := invoiceSelect + " WHERE 1=1"
+	var args []interface{}
+	if clientID != nil {
+		args = append(args, *clientID)
+		query += fmt.Sprintf(" AND i.client_id = $%d", len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND i.status = $%d", len(args))
+	}
+	tenant, args := tenantClient(ctx, "i.client_id", args)
+	query += tenant
+	query += " ORDER BY i.period_start DESC, c.name ASC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []domain.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invoices: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, invoices); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// ClaimIssue passa o rascunho para Emitindo antes da transmissão. O UPDATE condicionado ao
+// status garante que, entre duas emissões simultâneas, só uma chegue ao emissor.
+func (r *invoiceRepository) ClaimIssue(ctx context.Context, id int) error {
+	filter, args := tenantClient(ctx, "client_id", []interface{}{domain.InvoiceIssuing, id, domain.InvoiceDraft})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE invoices SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3`+filter,
+		args...)
+	return checkInvoiceTransition(result, err)
+}
+
+// ReleaseIssue devolve a fatura em Emitindo para rascunho quando a emissão não se completa
+func (r *invoiceRepository) ReleaseIssue(ctx context.Context, id int) error {
+	filter, args := tenantClient(ctx, "client_id", []interface{}{domain.InvoiceDraft, id, domain.InvoiceIssuing})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE invoices SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3`+filter,
+		args...)
+	return checkInvoiceTransition(result, err)
+}
+
+// MarkIssued grava a NFS-e autorizada na fatura em Emitindo e conclui os tickets faturados
+func (r *invoiceRepository) MarkIssued(ctx context.Context, invoice *domain.Invoice) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	filter, args := tenantClient(ctx, "client_id", []interface{}{
+		domain.InvoiceIssued, invoice.Issuer, invoice.Number, invoice.VerificationCode, invoice.XML, invoice.IssuedAt,
+		invoice.ID, domain.InvoiceIssuing,
+	})
+	result, err := tx.ExecContext(ctx,
+		`UPDATE invoices
+		 SET status = $1, issuer = $2, number = $3, verification_code = $4, xml = $5, issued_at = $6, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $7 AND status = $8`+filter,
+		args...)
+	if err := checkInvoiceTransition(result, err); err != nil {
+		return err
+	}
+
+	if err := updateInvoiceTickets(ctx, tx, invoice.ID, domain.TicketStatusConcluido); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Cancel cancela a fatura emitida; os tickets voltam para Aguarda Faturamento
+func (r *invoiceRepository) Cancel(ctx context.Context, id int, reason string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	filter, args := tenantClient(ctx, "client_id", []interface{}{domain.InvoiceCancelled, reason, id, domain.InvoiceIssued})
+	result, err := tx.ExecContext(ctx,
+		`UPDATE invoices
+		 SET status = $1, cancel_reason = $2, cancelled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND status = $4`+filter,
+		args...)
+	if err := checkInvoiceTransition(result, err); err != nil {
+		return err
+	}
+
+	if err := updateInvoiceTickets(ctx, tx, id, domain.TicketStatusAguardaFaturamento); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete descarta a fatura em rascunho; os tickets voltam para Aguarda Faturamento
+func (r *invoiceRepository) Delete(ctx context.Context, id int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
+	err = tx.QueryRowContext(ctx, `SELECT status FROM invoices WHERE id = $1`+filter+` FOR UPDATE`, args...).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvoiceNotFound
+		}
+		return fmt.Errorf("error finding invoice: %w", err)
+	}
+	if status != domain.InvoiceDraft {
+		return ErrInvoiceStatus
+	}
+
+	if err := updateInvoiceTickets(ctx, tx, id, domain.TicketStatusAguardaFaturamento); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM invoices WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("error deleting invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// loadDetails carrega os tickets e itens das faturas informadas
+func (r *invoiceRepository) loadDetails(ctx context.Context, invoices []domain.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(invoices))
+	index := make(map[int]int, len(invoices))
+	for i := range invoices {
+		ids = append(ids, int64(invoices[i].ID))
+		index[invoices[i].ID] = i
+		invoices[i].Tickets = []domain.InvoiceTicket{}
+		invoices[i].Lines = []domain.InvoiceLine{}
+	}
+
+	ticketRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT it.invoice_id, t.id, t.number, b.name, t.close_date
+		 FROM invoice_tickets it
+		 JOIN tickets t ON t.id = it.ticket_id
+		 JOIN branchs b ON b.id = t.branch_id
+		 WHERE it.invoice_id = ANY($1)
+		 ORDER BY t.close_date, t.number`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing invoice tickets: %w", err)
+	}
+	defer ticketRows.Close()
+
+	for ticketRows.Next() {
+		var ticket domain.InvoiceTicket
+		if err := ticketRows.Scan(&ticket.InvoiceID, &ticket.TicketID, &ticket.Number, &ticket.BranchName, &ticket.CloseDate); err != nil {
+			return fmt.Errorf("error scanning invoice ticket: %w", err)
+		}
+		invoice := &invoices[index[ticket.InvoiceID]]
+		invoice.Tickets = append(invoice.Tickets, ticket)
+	}
+
+	if err := ticketRows.Err(); err != nil {
+		return fmt.Errorf("error iterating invoice tickets: %w", err)
+	}
+
+	lineRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, invoice_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount, created_at
+		 FROM invoice_lines
+		 WHERE invoice_id = ANY($1)
+		 ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing invoice lines: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line domain.InvoiceLine
+		if err := lineRows.Scan(
+			&line.ID,
+			&line.InvoiceID,
+			&line.Kind,
+			&line.TicketID,
+			&line.VisitID,
+			&line.Description,
+			&line.Quantity,
+			&line.UnitAmount,
+			&line.Amount,
+			&line.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("error scanning invoice line: %w", err)
+		}
+		invoice := &invoices[index[line.InvoiceID]]
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	if err := lineRows.Err(); err != nil {
+		return fmt.Errorf("error iterating invoice lines: %w", err)
+	}
+
+	return nil
+}
+
+// updateInvoiceTickets move os tickets da fatura para o status informado
+func updateInvoiceTickets(ctx context.Context, tx dbConn, invoiceID, status int) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE tickets SET status = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		 WHERE id IN (SELECT ticket_id FROM invoice_tickets WHERE invoice_id = $2)`,
+		status, invoiceID)
+	if err != nil {
+		return fmt.Errorf("error updating invoice tickets status: %w", err)
+	}
+	return nil
+}
+
+func checkInvoiceTransition(result sql.Result, err error) error {
+	if err != nil {
+		return fmt.Errorf("error updating invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrInvoiceStatus
+	}
+
+	return nil
+}
+
+func scanInvoice(row rowScanner) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.ClientID,
+		&invoice.ClientName,
+		&invoice.ClientDocument,
+		&invoice.PeriodStart,
+		&invoice.PeriodEnd,
+		&invoice.Status,
+		&invoice.Notes,
+		&invoice.Issuer,
+		&invoice.Number,
+		&invoice.VerificationCode,
+		&invoice.XML,
+		&invoice.IssuedAt,
+		&invoice.CancelledAt,
+		&invoice.CancelReason,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// TestInvoiceTransitions confere o fluxo rascunho -> emitindo -> emitida -> cancelada e o status
+// que cada passo grava nos tickets da fatura. O fake só afeta a fatura quando a situação exigida
+// pelo UPDATE (último argumento, sem filtro de cliente) é a atual.
+func TestInvoiceTransitions(t *testing.T) {
+	claim := func(r InvoiceRepository) error { return r.ClaimIssue(tenant.System(context.Background()), 1) }
+	release := func(r InvoiceRepository) error { return r.ReleaseIssue(tenant.System(context.Background()), 1) }
+	issued := func(r InvoiceRepository) error {
+		return r.MarkIssued(tenant.System(context.Background()), &domain.Invoice{ID: 1, Number: "NFSE-1"})
+	}
+	cancel := func(r InvoiceRepository) error {
+		return r.Cancel(tenant.System(context.Background()), 1, "Valor incorreto")
+	}
+
+	tests := []struct {
+		name         string
+		current      string
+		op           func(InvoiceRepository) error
+		want         error
+		ticketStatus int // 0 = tickets não alterados
+	}{
+		{"claim draft", domain.InvoiceDraft, claim, nil, 0},
+		{"claim issuing", domain.InvoiceIssuing, claim, ErrInvoiceStatus, 0},
+		{"release issuing", domain.InvoiceIssuing, release, nil, 0},
+		{"release issued", domain.InvoiceIssued, release, ErrInvoiceStatus, 0},
+		{"mark issuing as issued", domain.InvoiceIssuing, issued, nil, domain.TicketStatusConcluido},
+		// Só a fatura reservada pela emissão pode receber a nota
+		{"mark draft as issued", domain.InvoiceDraft, issued, ErrInvoiceStatus, 0},
+		{"cancel issued", domain.InvoiceIssued, cancel, nil, domain.TicketStatusAguardaFaturamento},
+		{"cancel draft", domain.InvoiceDraft, cancel, ErrInvoiceStatus, 0},
+		{"cancel cancelled", domain.InvoiceCancelled, cancel, ErrInvoiceStatus, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.affected = func(query string, args []driver.Value) int64 {
+				if !strings.Contains(query, "UPDATE invoices") || args[len(args)-1] == tt.current {
+					return 1
+				}
+				return 0
+			}
+
+			if err := tt.op(NewInvoiceRepository(db)); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			tickets := fake.calls("UPDATE tickets")
+			switch {
+			case tt.ticketStatus == 0 && len(tickets) != 0:
+				t.Fatalf("tickets updated: %v", tickets)
+			case tt.ticketStatus != 0 && (len(tickets) != 1 || tickets[0].args[0] != tt.ticketStatus):
+				t.Fatalf("got ticket updates %v, want status %d", tickets, tt.ticketStatus)
+			}
+		})
+	}
+}
+
+func TestInvoiceDeleteOnlyDraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		current [][]driver.Value
+		want    error
+	}{
+		{"draft", [][]driver.Value{{domain.InvoiceDraft}}, nil},
+		{"issued", [][]driver.Value{{domain.InvoiceIssued}}, ErrInvoiceStatus},
+		{"missing", nil, ErrInvoiceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				return []string{"status"}, tt.current
+			}
+
+			if err := NewInvoiceRepository(db).Delete(tenant.System(context.Background()), 1); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			// O rascunho descartado devolve os tickets para Aguarda Faturamento
+			deleted := len(fake.calls("DELETE FROM invoices")) == 1
+			released := len(fake.calls("UPDATE tickets")) == 1
+			if deleted != (tt.want == nil) || released != (tt.want == nil) {
+				t.Fatalf("got deleted %v and tickets released %v", deleted, released)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ func (r *problemRepository) Create(ctx context.Context, problem *domain.Problem)
 	`
 	
 	var id int
-	err := r.db.QueryRowContext(ctx, query, problem.Name, problem.Description).Scan(&id)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, problem.Name, problem.Description).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -46,7 +46,7 @@ func (r *problemRepository) FindByID(ctx context.Context, id int) (*domain.Probl
 	`
 	
 	problem := &domain.Problem{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&problem.ID,
 		&problem.Name,
 		&problem.Description,
@@ -71,7 +71,7 @@ func (r *problemRepository) FindAll(ctx context.Context) ([]domain.Problem, erro
 		ORDER BY name
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -103,12 +103,12 @@ func (r *problemRepository) Update(ctx context.Context, id int, problem *domain.
 		WHERE id = $3
 	`
 	
-	_, err := r.db.ExecContext(ctx, query, problem.Name, problem.Description, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, problem.Name, problem.Description, id)
 	return err
 }
 
 func (r *problemRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM problems WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
@@ -36,7 +36,7 @@ func (r *providerRepository) Create(ctx context.Context, provider *domain.Provid
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
 
 	var id int
-	err := r.db.QueryRowContext(ctx, query,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		provider.Name,
 		provider.Mobile,
 		provider.Zipcode,
@@ -53,9 +53,9 @@ func (r *providerRepository) Create(ctx context.Context, provider *domain.Provid
 }
 
 func (r *providerRepository) List(ctx context.Context) ([]domain.Provider, error) {
-	query := `SELECT id, name, mobile, zipcode, state, city, neighborhood, address, complement FROM providers ORDER BY name DESC`
+	query := `SELECT id, name, mobile, zipcode, state, city, neighborhood, address, complement, version FROM providers ORDER BY name DESC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error listing providers: %w", err)
 	}
@@ -73,7 +73,8 @@ func (r *providerRepository) List(ctx context.Context) ([]domain.Provider, error
 			&provider.City,
 			&provider.Neighborhood,
 			&provider.Address,
-			&provider.Complement); err != nil {
+			&provider.Complement,
+			&provider.Version); err != nil {
 			return nil, fmt.Errorf("error scanning provider: %w", err)
 		}
 		providers = append(providers, provider)
@@ -87,10 +88,10 @@ func (r *providerRepository) List(ctx context.Context) ([]domain.Provider, error
 }
 
 func (r *providerRepository) FindByID(ctx context.Context, id int) (*domain.Provider, error) {
-	query := `SELECT id, name, mobile, zipcode, state, city, neighborhood, address, complement FROM providers WHERE id = $1`
+	query := `SELECT id, name, mobile, zipcode, state, city, neighborhood, address, complement, version FROM providers WHERE id = $1`
 	var provider domain.Provider
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&provider.ID,
 		&provider.Name,
 		&provider.Mobile,
@@ -100,6 +101,7 @@ func (r *providerRepository) FindByID(ctx context.Context, id int) (*domain.Prov
 		&provider.Neighborhood,
 		&provider.Address,
 		&provider.Complement,
+		&provider.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -112,9 +114,9 @@ func (r *providerRepository) FindByID(ctx context.Context, id int) (*domain.Prov
 }
 
 func (r *providerRepository) FindByName(ctx context.Context, name string) (*domain.Provider, error) {
-	query := `SELECT * FROM providers WHERE name = $1 ORDER BY name LIMIT 1`
+	query := `SELECT id, name, mobile, zipcode, state, city, neighborhood, address, complement, version FROM providers WHERE name = $1 ORDER BY name LIMIT 1`
 
-	row := r.db.QueryRowContext(ctx, query, name)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, name)
 
 	var provider domain.Provider
 	if err := row.Scan(
@@ -126,7 +128,8 @@ func (r *providerRepository) FindByName(ctx context.Context, name string) (*doma
 		&provider.City,
 		&provider.Neighborhood,
 		&provider.Address,
-		&provider.Complement); err != nil {
+		&provider.Complement,
+		&provider.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No provider found
 		}
@@ -145,10 +148,14 @@ func (r *providerRepository) Update(ctx context.Context, provider *domain.Provid
 			city = $5, 
 			neighborhood = $6, 
 			address = $7, 
-			complement = $8
-			WHERE id = $9`
-
-	result, err := r.db.ExecContext(ctx, query,
+			complement = $8,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+			WHERE id = $9 AND ($10::INTEGER = 0 OR version = $10)
+			RETURNING version`
+
+	// Com versão informada, só atualiza se o prestador não mudou desde a leitura
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		provider.Name,
 		provider.Mobile,
 		provider.Zipcode,
@@ -157,18 +164,16 @@ func (r *providerRepository) Update(ctx context.Context, provider *domain.Provid
 		provider.Neighborhood,
 		provider.Address,
 		provider.Complement,
-		provider.ID)
-	if err != nil {
-		return fmt.Errorf("error updating provider: %w", err)
+		provider.ID,
+		provider.Version).Scan(&provider.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		if provider.Version > 0 {
+			return ErrVersionConflict
+		}
+		return ErrProviderNotFound
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error checking rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return ErrProviderNotFound
+		return fmt.Errorf("error updating provider: %w", err)
 	}
 
 	return nil
@@ -177,7 +182,7 @@ func (r *providerRepository) Update(ctx context.Context, provider *domain.Provid
 func (r *providerRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM providers WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting provider: %w", err)
 	}
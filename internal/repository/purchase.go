@@ -0,0 +1,446 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSupplierNotFound        = errors.New("supplier not found")
+	ErrPurchaseRequestNotFound = errors.New("purchase request not found")
+	ErrPurchaseRequestStatus   = errors.New("purchase request status does not allow this operation")
+)
+
+type PurchaseRepository interface {
+	// Fornecedores
+	CreateSupplier(ctx context.Context, supplier *domain.Supplier) (int, error)
+	ListSuppliers(ctx context.Context) ([]domain.Supplier, error)
+	FindSupplierByID(ctx context.Context, id int) (*domain.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *domain.Supplier) error
+
+	// Compras
+	Create(ctx context.Context, purchase *domain.PurchaseRequest) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.PurchaseRequest, error)
+	List(ctx context.Context, ticketID *int, status string) ([]domain.PurchaseRequest, error)
+	UpdateQuote(ctx context.Context, purchase *domain.PurchaseRequest) error
+	Decide(ctx context.Context, id int, status string, userID int, note string) error
+	Receive(ctx context.Context, id, locationID int) error
+	CountByTicket(ctx context.Context, ticketID int) (map[string]int, error)
+}
+
+type purchaseRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseRepository(db *sql.DB) PurchaseRepository {
+	return &purchaseRepository{db: db}
+}
+
+func (r *purchaseRepository) CreateSupplier(ctx context.Context, supplier *domain.Supplier) (int, error) {
+	query := `INSERT INTO suppliers (name, document, email, phone) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, supplier.Name, supplier.Document, supplier.Email, supplier.Phone).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating supplier: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *purchaseRepository) ListSuppliers(ctx context.Context) ([]domain.Supplier, error) {
+	query := `SELECT id, name, document, email, phone, created_at FROM suppliers ORDER BY name ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing suppliers: %w", err)
+	}
+	defer rows.Close()
+
+	var suppliers []domain.Supplier
+	for rows.Next() {
+		var supplier domain.Supplier
+		if err := rows.Scan(&supplier.ID, &supplier.Name, &supplier.Document, &supplier.Email, &supplier.Phone, &supplier.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning supplier: %w", err)
+		}
+		suppliers = append(suppliers, supplier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suppliers: %w", err)
+	}
+
+	return suppliers, nil
+}
+
+func (r *purchaseRepository) FindSupplierByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	query := `SELECT id, name, document, email, phone, created_at FROM suppliers WHERE id = $1`
+
+	var supplier domain.Supplier
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&supplier.ID, &supplier.Name, &supplier.Document, &supplier.Email, &supplier.Phone, &supplier.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("error finding supplier: %w", err)
+	}
+
+	return &supplier, nil
+}
+
+func (r *purchaseRepository) UpdateSupplier(ctx context.Context, supplier *domain.Supplier) error {
+	query := `UPDATE suppliers SET name = $1, document = $2, email = $3, phone = $4 WHERE id = $5`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, supplier.Name, supplier.Document, supplier.Email, supplier.Phone, supplier.ID)
+	if err != nil {
+		return fmt.Errorf("error updating supplier: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// Create grava a compra e os itens cotados na mesma transação
+func (r *purchaseRepository) Create(ctx context.Context, purchase *domain.PurchaseRequest) (int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO purchase_requests (ticket_id, supplier_id, status, notes) VALUES ($1, $2, $3, $4) RETURNING id`,
+		purchase.TicketID, purchase.SupplierID, domain.PurchasePending, purchase.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating purchase request: %w", err)
+	}
+
+	if err := insertPurchaseItems(ctx, tx, id, purchase.Items); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const purchaseSelect = `SELECT p.id, p.ticket_id, t.number, p.supplier_id, s.name, p.status, p.notes,
+			p.approved_by, p.decided_at, p.decision_note, p.location_id, p.received_at, p.created_at, p.updated_at
+			FROM purchase_requests p
+			JOIN tickets t ON t.id = p.ticket_id
+			JOIN suppliers s ON s.id = p.supplier_id`
+
+func (r *purchaseRepository) FindByID(ctx context.Context, id int) (*domain.PurchaseRequest, error) {
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{id})
+	purchase, err := scanPurchase(conn(ctx, r.db).QueryRowContext(ctx, purchaseSelect+` WHERE p.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPurchaseRequestNotFound
+		}
+		return nil, fmt.Errorf("error finding purchase request: %w", err)
+	}
+
+	purchases := []domain.PurchaseRequest{*purchase}
+	if err := r.loadItems(ctx, purchases); err != nil {
+		return nil, err
+	}
+
+	return &purchases[0], nil
+}
+
+// List retorna as compras, opcionalmente filtradas por ticket e status
+func (r *purchaseRepository) List(ctx context.Context, ticketID *int, status string) ([]domain.PurchaseRequest, error) {
+	var conditions []string
+	var args []interface{}
+
+	if ticketID != nil {
+		args = append(args, *ticketID)
+		conditions = append(conditions, fmt.Sprintf("p.ticket_id = $%d", len(args)))
+	}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("p.status = $%d", len(args)))
+	}
+
+	query := purchaseSelect + " WHERE TRUE"
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+	tenant, args := tenantBranch(ctx, "t.branch_id", args)
+	query += tenant
+	query += " ORDER BY p.created_at DESC, p.id DESC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing purchase requests: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []domain.PurchaseRequest
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning purchase request: %w", err)
+		}
+		purchases = append(purchases, *purchase)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating purchase requests: %w", err)
+	}
+
+	if err := r.loadItems(ctx, purchases); err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// UpdateQuote substitui fornecedor, observações e itens de uma compra pendente
+func (r *purchaseRepository) UpdateQuote(ctx context.Context, purchase *domain.PurchaseRequest) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE purchase_requests SET supplier_id = $1, notes = $2, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND status = $4`,
+		purchase.SupplierID, purchase.Notes, purchase.ID, domain.PurchasePending)
+	if err != nil {
+		return fmt.Errorf("error updating purchase request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPurchaseRequestStatus
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM purchase_request_items WHERE purchase_request_id = $1`, purchase.ID); err != nil {
+		return fmt.Errorf("error deleting purchase request items: %w", err)
+	}
+
+	if err := insertPurchaseItems(ctx, tx, purchase.ID, purchase.Items); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Decide registra a aprovação ou rejeição de uma compra pendente
+func (r *purchaseRepository) Decide(ctx context.Context, id int, status string, userID int, note string) error {
+	query := `UPDATE purchase_requests
+			SET status = $1, approved_by = $2, decided_at = CURRENT_TIMESTAMP, decision_note = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $4 AND status = $5`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, status, userID, note, id, domain.PurchasePending)
+	if err != nil {
+		return fmt.Errorf("error deciding purchase request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPurchaseRequestStatus
+	}
+
+	return nil
+}
+
+// Receive marca a compra aprovada como recebida e dá entrada dos itens no local informado
+func (r *purchaseRepository) Receive(ctx context.Context, id, locationID int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ticketID int
+	err = tx.QueryRowContext(ctx,
+		`UPDATE purchase_requests
+		 SET status = $1, location_id = $2, received_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND status = $4
+		 RETURNING ticket_id`,
+		domain.PurchaseReceived, locationID, id, domain.PurchaseApproved).Scan(&ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPurchaseRequestStatus
+		}
+		return fmt.Errorf("error receiving purchase request: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT item_id, quantity FROM purchase_request_items WHERE purchase_request_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return fmt.Errorf("error listing purchase request items: %w", err)
+	}
+
+	var items []domain.PurchaseRequestItem
+	for rows.Next() {
+		var item domain.PurchaseRequestItem
+		if err := rows.Scan(&item.ItemID, &item.Quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning purchase request item: %w", err)
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating purchase request items: %w", err)
+	}
+
+	for _, item := range items {
+		if err := applyStockDelta(ctx, tx, item.ItemID, locationID, item.Quantity, 0); err != nil {
+			return err
+		}
+
+		if err := insertMovement(ctx, tx, &domain.StockMovement{
+			ItemID:     item.ItemID,
+			LocationID: locationID,
+			Kind:       domain.MovementIn,
+			Quantity:   item.Quantity,
+			TicketID:   &ticketID,
+			Note:       fmt.Sprintf("Compra #%d", id),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CountByTicket retorna a quantidade de compras do ticket por status
+func (r *purchaseRepository) CountByTicket(ctx context.Context, ticketID int) (map[string]int, error) {
+	query := `SELECT status, COUNT(*) FROM purchase_requests WHERE ticket_id = $1 GROUP BY status`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting purchase requests: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("error scanning purchase request count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating purchase request counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// loadItems preenche os itens cotados das compras informadas
+func (r *purchaseRepository) loadItems(ctx context.Context, purchases []domain.PurchaseRequest) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(purchases))
+	index := make(map[int]int, len(purchases))
+	for i := range purchases {
+		ids = append(ids, int64(purchases[i].ID))
+		index[purchases[i].ID] = i
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT pi.id, pi.purchase_request_id, pi.item_id, i.sku, i.name, pi.quantity, pi.unit_price
+		 FROM purchase_request_items pi
+		 JOIN inventory_items i ON i.id = pi.item_id
+		 WHERE pi.purchase_request_id = ANY($1)
+		 ORDER BY pi.id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing purchase request items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PurchaseRequestItem
+		if err := rows.Scan(&item.ID, &item.PurchaseRequestID, &item.ItemID, &item.ItemSKU, &item.ItemName, &item.Quantity, &item.UnitPrice); err != nil {
+			return fmt.Errorf("error scanning purchase request item: %w", err)
+		}
+		purchase := &purchases[index[item.PurchaseRequestID]]
+		purchase.Items = append(purchase.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating purchase request items: %w", err)
+	}
+
+	return nil
+}
+
+func insertPurchaseItems(ctx context.Context, tx dbConn, purchaseID int, items []domain.PurchaseRequestItem) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO purchase_request_items (purchase_request_id, item_id, quantity, unit_price) VALUES ($1, $2, $3, $4)`,
+			purchaseID, item.ItemID, item.Quantity, item.UnitPrice)
+		if err != nil {
+			return fmt.Errorf("error creating purchase request item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func scanPurchase(row rowScanner) (*domain.PurchaseRequest, error) {
+	var purchase domain.PurchaseRequest
+	err := row.Scan(
+		&purchase.ID,
+		&purchase.TicketID,
+		&purchase.TicketNumber,
+		&purchase.SupplierID,
+		&purchase.SupplierName,
+		&purchase.Status,
+		&purchase.Notes,
+		&purchase.ApprovedBy,
+		&purchase.DecidedAt,
+		&purchase.DecisionNote,
+		&purchase.LocationID,
+		&purchase.ReceivedAt,
+		&purchase.CreatedAt,
+		&purchase.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &purchase, nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteStatus   = errors.New("quote is no longer pending")
+)
+
+type QuoteRepository interface {
+	Create(ctx context.Context, quote *domain.Quote) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.Quote, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.Quote, error)
+	Latest(ctx context.Context, ticketID int) (*domain.Quote, error)
+	MarkSent(ctx context.Context, id int, sentTo []string) error
+	Decide(ctx context.Context, id int, decision *domain.QuoteDecision) error
+}
+
+type quoteRepository struct {
+	db *sql.DB
+}
+
+func NewQuoteRepository(db *sql.DB) QuoteRepository {
+	return &quoteRepository{db: db}
+}
+
+// Create grava uma nova versão do orçamento do ticket com seus itens. A versão pendente
+// anterior, se houver, é substituída; as já decididas ficam no histórico.
+func (r *quoteRepository) Create(ctx context.Context, quote *domain.Quote) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, quote.TicketID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O lock no ticket serializa a numeração das versões
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM tickets WHERE id = $1 FOR UPDATE`, quote.TicketID); err != nil {
+		return 0, fmt.Errorf("error locking ticket: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE ticket_quotes SET status = $1 WHERE ticket_id = $2 AND status = $3`,
+		domain.QuoteSuperseded, quote.TicketID, domain.QuotePending)
+	if err != nil {
+		return 0, fmt.Errorf("error superseding pending quote: %w", err)
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO ticket_quotes (ticket_id, version, status, notes, services_total, travel_cost, total)
+		 SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6
+		 FROM ticket_quotes WHERE ticket_id = $1
+		 RETURNING id`,
+		quote.TicketID, domain.QuotePending, quote.Notes, quote.ServicesTotal, quote.TravelCost, quote.Total).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating quote: %w", err)
+	}
+
+	for _, item := range quote.Items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO ticket_quote_items (quote_id, solution_id, problem_name, solution_name, quantity, unit_price, subtotal, warranty)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			id, item.SolutionID, item.ProblemName, item.SolutionName, item.Quantity, item.UnitPrice, item.Subtotal, item.Warranty)
+		if err != nil {
+			return 0, fmt.Errorf("error creating quote item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const quoteSelect = `SELECT q.id, q.ticket_id, t.number, q.version, q.status, q.notes, q.services_total, q.travel_cost,
+			q.total, q.sent_to, q.sent_at, q.decided_at, q.decided_by, q.decided_by_user_id, q.decision_channel,
+			q.decision_note, q.created_at
+			FROM ticket_quotes q
+			JOIN tickets t ON t.id = q.ticket_id`
+
+func (r *quoteRepository) FindByID(ctx context.Context, id int) (*domain.Quote, error) {
+	filter, args := tenantTicket(ctx, "q.ticket_id", []interface{}{id})
+	quote, err := scanQuote(conn(ctx, r.db).QueryRowContext(ctx, quoteSelect+` WHERE q.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("error finding quote: %w", err)
+	}
+
+	quotes := []domain.Quote{*quote}
+	if err := r.loadItems(ctx, quotes); err != nil {
+		return nil, err
+	}
+
+	return &quotes[0], nil
+}
+
+// ListByTicket retorna as versões do orçamento do ticket, da mais recente para a mais antiga
+func (r *quoteRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.Quote, error) {
+	filter, args := tenantTicket(ctx, "q.ticket_id", []interface{}{ticketID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx, quoteSelect+` WHERE q.ticket_id = $1`+filter+` ORDER BY q.version DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning quote: %w", err)
+		}
+		quotes = append(quotes, *quote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quotes: %w", err)
+	}
+
+	if err := r.loadItems(ctx, quotes); err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// Latest retorna a versão mais recente do orçamento do ticket
+func (r *quoteRepository) Latest(ctx context.Context, ticketID int) (*domain.Quote, error) {
+	filter, args := tenantTicket(ctx, "q.ticket_id", []interface{}{ticketID})
+	quote, err := scanQuote(conn(ctx, r.db).QueryRowContext(ctx,
+		quoteSelect+` WHERE q.ticket_id = $1`+filter+` ORDER BY q.version DESC LIMIT 1`, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("error finding latest quote: %w", err)
+	}
+
+	return quote, nil
+}
+
+// MarkSent registra o envio do link de aprovação do orçamento pendente
+func (r *quoteRepository) MarkSent(ctx context.Context, id int, sentTo []string) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{pq.StringArray(sentTo), id, domain.QuotePending})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE ticket_quotes SET sent_to = $1, sent_at = CURRENT_TIMESTAMP
+		 WHERE id = $2 AND status = $3`+filter,
+		args...)
+	return checkQuoteTransition(result, err)
+}
+
+// Decide grava a aprovação ou rejeição do orçamento pendente
+func (r *quoteRepository) Decide(ctx context.Context, id int, decision *domain.QuoteDecision) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		decision.Status, decision.DecidedBy, decision.UserID, decision.Channel, decision.Note,
+		id, domain.QuotePending,
+	})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE ticket_quotes
+		 SET status = $1, decided_at = CURRENT_TIMESTAMP, decided_by = $2, decided_by_user_id = $3,
+			decision_channel = $4, decision_note = $5
+		 WHERE id = $6 AND status = $7`+filter,
+		args...)
+	return checkQuoteTransition(result, err)
+}
+
+// loadItems carrega os itens dos orçamentos informados
+func (r *quoteRepository) loadItems(ctx context.Context, quotes []domain.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(quotes))
+	index := make(map[int]int, len(quotes))
+	for i := range quotes {
+		ids = append(ids, int64(quotes[i].ID))
+		index[quotes[i].ID] = i
+		quotes[i].Items = []domain.QuoteItem{}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, quote_id, solution_id, problem_name, solution_name, quantity, unit_price, subtotal, warranty
+		 FROM ticket_quote_items
+		 WHERE quote_id = ANY($1)
+		 ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing quote items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.QuoteItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.QuoteID,
+			&item.SolutionID,
+			&item.ProblemName,
+			&item.SolutionName,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.Subtotal,
+			&item.Warranty,
+		); err != nil {
+			return fmt.Errorf("error scanning quote item: %w", err)
+		}
+		quote := &quotes[index[item.QuoteID]]
+		quote.Items = append(quote.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating quote items: %w", err)
+	}
+
+	return nil
+}
+
+func checkQuoteTransition(result sql.Result, err error) error {
+	if err != nil {
+		return fmt.Errorf("error updating quote: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrQuoteStatus
+	}
+
+	return nil
+}
+
+func scanQuote(row rowScanner) (*domain.Quote, error) {
+	var quote domain.Quote
+	var sentTo pq.StringArray
+	err := row.Scan(
+		&quote.ID,
+		&quote.TicketID,
+		&quote.TicketNumber,
+		&quote.Version,
+		&quote.Status,
+		&quote.Notes,
+		&quote.ServicesTotal,
+		&quote.TravelCost,
+		&quote.Total,
+		&sentTo,
+		&quote.SentAt,
+		&quote.DecidedAt,
+		&quote.DecidedBy,
+		&quote.DecidedByUserID,
+		&quote.DecisionChannel,
+		&quote.DecisionNote,
+		&quote.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	quote.SentTo = []string(sentTo)
+
+	return &quote, nil
+}
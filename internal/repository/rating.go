@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+var (
+	ErrRatingNotFound = errors.New("ticket rating not found")
+	ErrTicketRated    = errors.New("ticket has already been rated")
+)
+
+type RatingRepository interface {
+	Create(ctx context.Context, rating *domain.TicketRating) (int, error)
+	FindByTicket(ctx context.Context, ticketID int) (*domain.TicketRating, error)
+}
+
+type ratingRepository struct {
+	db *sql.DB
+}
+
+func NewRatingRepository(db *sql.DB) RatingRepository {
+	return &ratingRepository{db: db}
+}
+
+func (r *ratingRepository) Create(ctx context.Context, rating *domain.TicketRating) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, rating.TicketID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO ticket_ratings (ticket_id, user_id, score, comment)
+		 VALUES ($1, $2, $3, $4) RETURNING id`,
+		rating.TicketID, rating.UserID, rating.Score, rating.Comment).Scan(&id)
+	if err != nil {
+		// Uma avaliação por ticket (UNIQUE ticket_id)
+		if isUniqueViolation(err) {
+			return 0, ErrTicketRated
+		}
+		return 0, fmt.Errorf("error creating ticket rating: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *ratingRepository) FindByTicket(ctx context.Context, ticketID int) (*domain.TicketRating, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+
+	var rating domain.TicketRating
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, ticket_id, user_id, score, comment, created_at
+		 FROM ticket_ratings WHERE ticket_id = $1`+filter,
+		args...).Scan(
+		&rating.ID,
+		&rating.TicketID,
+		&rating.UserID,
+		&rating.Score,
+		&rating.Comment,
+		&rating.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRatingNotFound
+		}
+		return nil, fmt.Errorf("error finding ticket rating: %w", err)
+	}
+
+	return &rating, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// ReportDataRepository consulta as linhas dos relatórios agendados
+type ReportDataRepository interface {
+	CostLines(ctx context.Context, from, to time.Time, clientID *int) ([]domain.CostReportLine, error)
+	ClientTickets(ctx context.Context, from, to time.Time, clientID int) ([]domain.TicketReportLine, error)
+}
+
+type reportDataRepository struct {
+	db *sql.DB
+}
+
+func NewReportDataRepository(db *sql.DB) ReportDataRepository {
+	return &reportDataRepository{db: db}
+}
+
+// CostLines lista as linhas de custo lançadas em [from, to), opcionalmente só das agências do cliente
+func (r *reportDataRepository) CostLines(ctx context.Context, from, to time.Time, clientID *int) ([]domain.CostReportLine, error) {
+	query := `
+		SELECT t.number, c.name, b.name, tc.created_at, tc.problem_name, tc.solution_name,
+			tc.quantity, tc.unit_price, tc.subtotal, tc.warranty
+		FROM ticket_costs tc
+		JOIN tickets t ON t.id = tc.ticket_id
+		JOIN branchs b ON b.id = t.branch_id
+		JOIN clients c ON c.id = b.client_id
+		WHERE tc.created_at >= $1 AND tc.created_at < $2`
+	args := []interface{}{from, to}
+
+	if clientID != nil {
+		args = append(args, *clientID)
+		query += fmt.Sprintf(" AND b.client_id = $%d", len(args))
+	}
+	tenant, args := tenantClient(ctx, "b.client_id", args)
+	query += tenant
+	query += ` ORDER BY tc.created_at ASC, tc.id ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing cost report lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []domain.CostReportLine
+	for rows.Next() {
+		var line domain.CostReportLine
+		if err := rows.Scan(
+			&line.TicketNumber,
+			&line.ClientName,
+			&line.BranchName,
+			&line.CreatedAt,
+			&line.ProblemName,
+			&line.SolutionName,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.Subtotal,
+			&line.Warranty,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning cost report line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cost report lines: %w", err)
+	}
+
+	return lines, nil
+}
+
+// ClientTickets lista os tickets das agências do cliente abertos ou fechados em [from, to)
+func (r *reportDataRepository) ClientTickets(ctx context.Context, from, to time.Time, clientID int) ([]domain.TicketReportLine, error) {
+	tenant, args := tenantClient(ctx, "b.client_id", []interface{}{from, to, clientID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.number, b.name, COALESCE(b.city, ''), COALESCE(b.state, ''), t.status, t.priority,
+			t.open_date, t.close_date,
+			COALESCE((SELECT SUM(tc.subtotal) FROM ticket_costs tc WHERE tc.ticket_id = t.id), 0)
+		 FROM tickets t
+		 JOIN branchs b ON b.id = t.branch_id
+		 WHERE b.client_id = $3
+			AND ((t.open_date >= $1 AND t.open_date < $2) OR (t.close_date >= $1 AND t.close_date < $2))`+tenant+`
+		 ORDER BY t.open_date ASC, t.id ASC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing client ticket report lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []domain.TicketReportLine
+	for rows.Next() {
+		var line domain.TicketReportLine
+		var closeDate sql.NullTime
+		if err := rows.Scan(
+			&line.TicketNumber,
+			&line.BranchName,
+			&line.City,
+			&line.State,
+			&line.Status,
+			&line.Priority,
+			&line.OpenDate,
+			&closeDate,
+			&line.Cost,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning client ticket report line: %w", err)
+		}
+		if closeDate.Valid {
+			line.CloseDate = &closeDate.Time
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating client ticket report lines: %w", err)
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReportDefinitionNotFound = errors.New("report definition not found")
+	ErrReportFileNotFound       = errors.New("report file not found")
+)
+
+type ScheduledReportRepository interface {
+	Create(ctx context.Context, definition *domain.ReportDefinition) (int, error)
+	Update(ctx context.Context, definition *domain.ReportDefinition) error
+	FindByID(ctx context.Context, id int) (*domain.ReportDefinition, error)
+	List(ctx context.Context) ([]domain.ReportDefinition, error)
+	Delete(ctx context.Context, id int) error
+	ListDue(ctx context.Context, now time.Time) ([]domain.ReportDefinition, error)
+	Advance(ctx context.Context, id int, scheduledAt, next time.Time) (bool, error)
+	CreateFile(ctx context.Context, file *domain.ReportFile) (int, error)
+	ListFiles(ctx context.Context, definitionID int) ([]domain.ReportFile, error)
+	FindFile(ctx context.Context, definitionID, id int) (*domain.ReportFile, error)
+}
+
+type scheduledReportRepository struct {
+	db *sql.DB
+}
+
+func NewScheduledReportRepository(db *sql.DB) ScheduledReportRepository {
+	return &scheduledReportRepository{db: db}
+}
+
+const reportDefinitionSelect = `
+	SELECT id, name, type, client_id, format, recipients, frequency, weekday, day_of_month, hour,
+		active, next_run_at, last_run_at, created_at, updated_at
+	FROM report_definitions`
+
+const reportFileSelect = `
+	SELECT id, definition_id, period_start, period_end, format, file_name, content_type, size, path,
+		recipients, status, error, created_at
+	FROM report_files`
+
+func (r *scheduledReportRepository) Create(ctx context.Context, definition *domain.ReportDefinition) (int, error) {
+	if err := checkTenantOptional(ctx, definition.ClientID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO report_definitions (
+			name, type, client_id, format, recipients, frequency, weekday, day_of_month, hour, active, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		definition.Name,
+		definition.Type,
+		definition.ClientID,
+		definition.Format,
+		pq.StringArray(definition.Recipients),
+		definition.Schedule.Frequency,
+		definition.Schedule.Weekday,
+		definition.Schedule.Day,
+		definition.Schedule.Hour,
+		definition.Active,
+		definition.NextRunAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating report definition: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *scheduledReportRepository) Update(ctx context.Context, definition *domain.ReportDefinition) error {
+	if err := checkTenantOptional(ctx, definition.ClientID); err != nil {
+		return err
+	}
+
+	filter, args := tenantClient(ctx, "client_id", []interface{}{
+		definition.Name,
+		definition.Type,
+		definition.ClientID,
+		definition.Format,
+		pq.StringArray(definition.Recipients),
+		definition.Schedule.Frequency,
+		definition.Schedule.Weekday,
+		definition.Schedule.Day,
+		definition.Schedule.Hour,
+		definition.Active,
+		definition.NextRunAt,
+		definition.ID,
+	})
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE report_definitions SET
+			name = $1, type = $2, client_id = $3, format = $4, recipients = $5, frequency = $6,
+			weekday = $7, day_of_month = $8, hour = $9, active = $10, next_run_at = $11,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $12`+filter,
+		args...)
+	if err != nil {
+		return fmt.Errorf("error updating report definition: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking updated report definition: %w", err)
+	}
+	if rows == 0 {
+		return ErrReportDefinitionNotFound
+	}
+
+	return nil
+}
+
+func (r *scheduledReportRepository) FindByID(ctx context.Context, id int) (*domain.ReportDefinition, error) {
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
+	definition, err := scanReportDefinition(conn(ctx, r.db).QueryRowContext(ctx, reportDefinitionSelect+` WHERE id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReportDefinitionNotFound
+		}
+		return nil, fmt.Errorf("error finding report definition: %w", err)
+	}
+
+	return definition, nil
+}
+
+func (r *scheduledReportRepository) List(ctx context.Context) ([]domain.ReportDefinition, error) {
+	filter, args := tenantClient(ctx, "client_id", nil)
+	return r.list(ctx, reportDefinitionSelect+` WHERE TRUE`+filter+` ORDER BY name ASC, id ASC`, args...)
+}
+
+// ListDue retorna as definições ativas com execução vencida
+func (r *scheduledReportRepository) ListDue(ctx context.Context, now time.Time) ([]domain.ReportDefinition, error) {
+	return r.list(ctx, reportDefinitionSelect+` WHERE active = TRUE AND next_run_at <= $1 ORDER BY next_run_at ASC`, now)
+}
+
+func (r *scheduledReportRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.ReportDefinition, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing report definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []domain.ReportDefinition
+	for rows.Next() {
+		definition, err := scanReportDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning report definition: %w", err)
+		}
+		definitions = append(definitions, *definition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report definitions: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// Delete remove a definição e, em cascata, o registro dos arquivos gerados
+func (r *scheduledReportRepository) Delete(ctx context.Context, id int) error {
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM report_definitions WHERE id = $1`+filter, args...)
+	if err != nil {
+		return fmt.Errorf("error deleting report definition: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking deleted report definition: %w", err)
+	}
+	if rows == 0 {
+		return ErrReportDefinitionNotFound
+	}
+
+	return nil
+}
+
+// Advance reserva a execução agendada para scheduledAt, movendo a próxima para next.
+// Retorna false quando outra instância da API já reservou a mesma execução.
+func (r *scheduledReportRepository) Advance(ctx context.Context, id int, scheduledAt, next time.Time) (bool, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE report_definitions SET next_run_at = $1, last_run_at = CURRENT_TIMESTAMP
+		 WHERE id = $2 AND active = TRUE AND next_run_at = $3`,
+		next, id, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("error advancing report schedule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking report schedule: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *scheduledReportRepository) CreateFile(ctx context.Context, file *domain.ReportFile) (int, error) {
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO report_files (
+			definition_id, period_start, period_end, format, file_name, content_type, size, path, recipients, status, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		file.DefinitionID,
+		file.PeriodStart,
+		file.PeriodEnd,
+		file.Format,
+		file.FileName,
+		file.ContentType,
+		file.Size,
+		file.Path,
+		pq.StringArray(file.Recipients),
+		file.Status,
+		file.Error,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating report file: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *scheduledReportRepository) ListFiles(ctx context.Context, definitionID int) ([]domain.ReportFile, error) {
+	filter, args := tenantReportDefinition(ctx, []interface{}{definitionID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		reportFileSelect+` WHERE definition_id = $1`+filter+` ORDER BY created_at DESC, id DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing report files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []domain.ReportFile
+	for rows.Next() {
+		file, err := scanReportFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning report file: %w", err)
+		}
+		files = append(files, *file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report files: %w", err)
+	}
+
+	return files, nil
+}
+
+func (r *scheduledReportRepository) FindFile(ctx context.Context, definitionID, id int) (*domain.ReportFile, error) {
+	filter, args := tenantReportDefinition(ctx, []interface{}{id, definitionID})
+	file, err := scanReportFile(conn(ctx, r.db).QueryRowContext(ctx,
+		reportFileSelect+` WHERE id = $1 AND definition_id = $2`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReportFileNotFound
+		}
+		return nil, fmt.Errorf("error finding report file: %w", err)
+	}
+
+	return file, nil
+}
+
+// tenantReportDefinition restringe os arquivos às definições do cliente do context
+func tenantReportDefinition(ctx context.Context, args []interface{}) (string, []interface{}) {
+	filter, args := tenantClient(ctx, "client_id", args)
+	if filter == "" {
+		return "", args
+	}
+	return ` AND definition_id IN (SELECT id FROM report_definitions WHERE TRUE` + filter + `)`, args
+}
+
+func scanReportDefinition(row rowScanner) (*domain.ReportDefinition, error) {
+	var definition domain.ReportDefinition
+	var clientID sql.NullInt64
+	var recipients pq.StringArray
+	var lastRunAt sql.NullTime
+	if err := row.Scan(
+		&definition.ID,
+		&definition.Name,
+		&definition.Type,
+		&clientID,
+		&definition.Format,
+		&recipients,
+		&definition.Schedule.Frequency,
+		&definition.Schedule.Weekday,
+		&definition.Schedule.Day,
+		&definition.Schedule.Hour,
+		&definition.Active,
+		&definition.NextRunAt,
+		&lastRunAt,
+		&definition.CreatedAt,
+		&definition.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if clientID.Valid {
+		id := int(clientID.Int64)
+		definition.ClientID = &id
+	}
+	definition.Recipients = []string(recipients)
+	if lastRunAt.Valid {
+		definition.LastRunAt = &lastRunAt.Time
+	}
+
+	return &definition, nil
+}
+
+func scanReportFile(row rowScanner) (*domain.ReportFile, error) {
+	var file domain.ReportFile
+	var recipients pq.StringArray
+	if err := row.Scan(
+		&file.ID,
+		&file.DefinitionID,
+		&file.PeriodStart,
+		&file.PeriodEnd,
+		&file.Format,
+		&file.FileName,
+		&file.ContentType,
+		&file.Size,
+		&file.Path,
+		&recipients,
+		&file.Status,
+		&file.Error,
+		&file.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	file.Recipients = []string(recipients)
+	return &file, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+type ScorecardRepository interface {
+	FindByProvider(ctx context.Context, providerID int, filter domain.ScorecardFilter, sla domain.SLAPolicy) (*domain.Scorecard, error)
+	List(ctx context.Context, filter domain.ScorecardFilter, sla domain.SLAPolicy) ([]domain.Scorecard, error)
+}
+
+type scorecardRepository struct {
+	db *sql.DB
+}
+
+func NewScorecardRepository(db *sql.DB) ScorecardRepository {
+	return &scorecardRepository{db: db}
+}
+
+// scorecardQuery agrega por técnico os tickets abertos no período em que ele trabalhou: como
+// técnico atribuído (tickets.provider_id) ou em visitas (ticket_visits.provider_id). Ticket
+// reatribuído ou com visitas de vários técnicos conta para cada um deles, inclusive no SLA e no retrabalho.
+// Tempo de execução: da atribuição (ou abertura, para tickets antigos) ou da primeira visita ao fechamento.
+// SLA: da abertura ao fechamento, com o prazo da prioridade.
+// Retrabalho: ticket fechado seguido de outro na mesma agência, com o mesmo problema, em até N dias.
+// Custos vão para o técnico da visita em que foram lançados ou, sem visita, para o atribuído.
+// Custos e distâncias são somados por ticket e técnico antes do JOIN para não multiplicar linhas.
+// O %s recebe o filtro de cliente dos tickets (tenantBranch).
+const scorecardQuery = `
+	WITH participants AS (
+		SELECT t.id AS ticket_id, t.provider_id, COALESCE(t.assigned_at, t.open_date) AS started_at
+		FROM tickets t
+		WHERE t.provider_id IS NOT NULL
+		UNION ALL
+		SELECT v.ticket_id, v.provider_id, MIN(COALESCE(v.starts_at, v.created_at))
+		FROM ticket_visits v
+		GROUP BY v.ticket_id, v.provider_id
+	),
+	scoped AS (
+		SELECT t.id, t.number, pt.provider_id, t.branch_id, t.priority, t.open_date, t.close_date,
+			MIN(pt.started_at) AS started_at
+		FROM tickets t
+		JOIN participants pt ON pt.ticket_id = t.id
+		WHERE t.open_date >= $1 AND t.open_date < $2%s
+		GROUP BY t.id, pt.provider_id
+	),
+	sla AS (
+		SELECT * FROM UNNEST($3::TEXT[], $4::DOUBLE PRECISION[]) AS sla(priority, hours)
+	),
+	costs AS (
+		SELECT tc.ticket_id, COALESCE(v.provider_id, t.provider_id) AS provider_id, SUM(tc.subtotal) AS cost
+		FROM ticket_costs tc
+		JOIN tickets t ON t.id = tc.ticket_id
+		LEFT JOIN ticket_visits v ON v.id = tc.visit_id
+		GROUP BY tc.ticket_id, COALESCE(v.provider_id, t.provider_id)
+	),
+	km AS (
+		SELECT ticket_number, provider_id, SUM(distance) AS km FROM distances GROUP BY ticket_number, provider_id
+	)
+	SELECT p.id, p.name,
+		COUNT(s.id),
+		COUNT(s.close_date),
+		AVG(EXTRACT(EPOCH FROM (s.close_date - s.started_at)) / 3600),
+		COUNT(*) FILTER (WHERE s.close_date - s.open_date <= COALESCE(sla.hours, $5) * INTERVAL '1 hour'),
+		COUNT(*) FILTER (WHERE s.close_date IS NOT NULL AND EXISTS (
+			SELECT 1
+			FROM tickets r
+			JOIN ticket_problems rp ON rp.ticket_id = r.id
+			JOIN ticket_problems sp ON sp.problem_id = rp.problem_id AND sp.ticket_id = s.id
+			WHERE r.id <> s.id
+				AND r.branch_id = s.branch_id
+				AND r.open_date > s.close_date
+				AND r.open_date <= s.close_date + $6 * INTERVAL '1 day'
+		)),
+		COALESCE(SUM(c.cost), 0),
+		AVG(k.km)
+	FROM providers p
+	LEFT JOIN scoped s ON s.provider_id = p.id
+	LEFT JOIN sla ON sla.priority = LOWER(s.priority)
+	LEFT JOIN costs c ON c.ticket_id = s.id AND c.provider_id = p.id
+	LEFT JOIN km k ON k.ticket_number = s.number AND k.provider_id = p.id`
+
+// scorecardArgs monta os parâmetros comuns de scorecardQuery
+func scorecardArgs(filter domain.ScorecardFilter, sla domain.SLAPolicy) []interface{} {
+	priorities := make([]string, 0, len(sla.Hours))
+	hours := make([]float64, 0, len(sla.Hours))
+	for priority, value := range sla.Hours {
+		priorities = append(priorities, priority)
+		hours = append(hours, value)
+	}
+
+	return []interface{}{
+		filter.From,
+		filter.To,
+		pq.StringArray(priorities),
+		pq.Float64Array(hours),
+		sla.DefaultHours,
+		filter.ReworkDays,
+	}
+}
+
+func (r *scorecardRepository) FindByProvider(ctx context.Context, providerID int, filter domain.ScorecardFilter, sla domain.SLAPolicy) (*domain.Scorecard, error) {
+	scope, args := tenantBranch(ctx, "t.branch_id", append(scorecardArgs(filter, sla), providerID))
+	scorecard, err := scanScorecard(conn(ctx, r.db).QueryRowContext(ctx,
+		fmt.Sprintf(scorecardQuery, scope)+`
+		WHERE p.id = $7
+		GROUP BY p.id, p.name`,
+		args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProviderNotFound
+		}
+		return nil, fmt.Errorf("error calculating provider scorecard: %w", err)
+	}
+
+	scorecard.From = filter.From
+	scorecard.To = filter.To
+	return scorecard, nil
+}
+
+// List retorna o scorecard de cada técnico com tickets no período
+func (r *scorecardRepository) List(ctx context.Context, filter domain.ScorecardFilter, sla domain.SLAPolicy) ([]domain.Scorecard, error) {
+	scope, args := tenantBranch(ctx, "t.branch_id", scorecardArgs(filter, sla))
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		fmt.Sprintf(scorecardQuery, scope)+`
+		GROUP BY p.id, p.name
+		HAVING COUNT(s.id) > 0
+		ORDER BY p.name ASC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing provider scorecards: %w", err)
+	}
+	defer rows.Close()
+
+	var scorecards []domain.Scorecard
+	for rows.Next() {
+		scorecard, err := scanScorecard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning provider scorecard: %w", err)
+		}
+		scorecard.From = filter.From
+		scorecard.To = filter.To
+		scorecards = append(scorecards, *scorecard)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating provider scorecards: %w", err)
+	}
+
+	return scorecards, nil
+}
+
+func scanScorecard(row rowScanner) (*domain.Scorecard, error) {
+	var scorecard domain.Scorecard
+	var avgHours, avgDistance sql.NullFloat64
+	if err := row.Scan(
+		&scorecard.ProviderID,
+		&scorecard.ProviderName,
+		&scorecard.TicketsHandled,
+		&scorecard.TicketsClosed,
+		&avgHours,
+		&scorecard.WithinSLA,
+		&scorecard.ReworkTickets,
+		&scorecard.TotalCost,
+		&avgDistance,
+	); err != nil {
+		return nil, err
+	}
+
+	if avgHours.Valid {
+		scorecard.AvgHoursToComplete = &avgHours.Float64
+	}
+	if avgDistance.Valid {
+		scorecard.AvgDistanceKm = &avgDistance.Float64
+	}
+	scorecard.Calculate()
+
+	return &scorecard, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+var scorecardColumns = []string{"id", "name", "handled", "closed", "avg_hours", "within_sla", "rework", "cost", "avg_km"}
+
+func scorecardFilterForTest() domain.ScorecardFilter {
+	return domain.ScorecardFilter{From: time.Now().AddDate(0, -3, 0), To: time.Now(), ReworkDays: domain.DefaultReworkDays}
+}
+
+func TestScorecardScope(t *testing.T) {
+	sla := domain.SLAPolicy{Hours: map[string]float64{"alta": 8, "baixa": 72}, DefaultHours: 48}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		filters int
+		denied  bool
+	}{
+		{"system", tenant.System(context.Background()), 0, false},
+		{"tenant", tenant.WithClient(context.Background(), ownerClient), 1, false},
+		{"no scope", context.Background(), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			repo := NewScorecardRepository(db)
+
+			if _, err := repo.List(tt.ctx, scorecardFilterForTest(), sla); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := repo.FindByProvider(tt.ctx, 3, scorecardFilterForTest(), sla); !errors.Is(err, ErrProviderNotFound) {
+				t.Fatalf("FindByProvider without rows: got %v, want ErrProviderNotFound", err)
+			}
+
+			calls := fake.calls("FROM providers p")
+			if len(calls) != 2 {
+				t.Fatalf("got %d queries, want 2", len(calls))
+			}
+			assertPlaceholders(t, calls)
+			for _, call := range calls {
+				// O filtro de cliente fica nos tickets do período, não nos técnicos
+				scoped := call.query[strings.Index(call.query, "scoped AS"):strings.Index(call.query, "sla AS")]
+				if got := strings.Count(scoped, "client_id = $"); got != tt.filters {
+					t.Errorf("got %d client filters in the tickets, want %d", got, tt.filters)
+				}
+				if strings.Contains(scoped, tenantDenied) != tt.denied {
+					t.Errorf("denied = %v, want %v", !tt.denied, tt.denied)
+				}
+			}
+		})
+	}
+}
+
+// TestScorecardCreditsVisitingProviders confere que a consulta credita o ticket a todos os
+// técnicos que trabalharam nele e cada custo ao técnico da visita em que foi lançado
+func TestScorecardCreditsVisitingProviders(t *testing.T) {
+	fake, db := newFakeDB(t)
+	if _, err := NewScorecardRepository(db).List(tenant.System(context.Background()), scorecardFilterForTest(), domain.SLAPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	query := fake.calls("FROM providers p")[0].query
+
+	for _, fragment := range []string{
+		"FROM ticket_visits v",
+		"JOIN participants pt ON pt.ticket_id = t.id",
+		"COALESCE(v.provider_id, t.provider_id) AS provider_id",
+		"LEFT JOIN costs c ON c.ticket_id = s.id AND c.provider_id = p.id",
+		"LEFT JOIN km k ON k.ticket_number = s.number AND k.provider_id = p.id",
+	} {
+		if !strings.Contains(query, fragment) {
+			t.Errorf("query is missing %q", fragment)
+		}
+	}
+}
+
+func TestScorecardScan(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		row      []driver.Value
+		avgHours *float64
+		avgKm    *float64
+		sla      *float64
+	}{
+		{"closed tickets", []driver.Value{int64(3), "Ana", int64(4), int64(2), 12.5, int64(1), int64(0), "400.00", 18.0},
+			float(12.5), float(18), float(50)},
+		// Sem fechamentos e sem distâncias os indicadores ficam nulos
+		{"nothing closed", []driver.Value{int64(3), "Ana", int64(1), int64(0), nil, int64(0), int64(0), "0", nil},
+			nil, nil, nil},
+	}
+
+	equal := func(a, b *float64) bool { return (a == nil) == (b == nil) && (a == nil || *a == *b) }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				return scorecardColumns, [][]driver.Value{tt.row}
+			}
+
+			filter := scorecardFilterForTest()
+			scorecard, err := NewScorecardRepository(db).FindByProvider(tenant.System(context.Background()), 3, filter, domain.SLAPolicy{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !scorecard.From.Equal(filter.From) || !scorecard.To.Equal(filter.To) {
+				t.Errorf("period not copied from the filter")
+			}
+			if !equal(scorecard.AvgHoursToComplete, tt.avgHours) || !equal(scorecard.AvgDistanceKm, tt.avgKm) || !equal(scorecard.SLACompliance, tt.sla) {
+				t.Errorf("got hours %v, km %v and sla %v", scorecard.AvgHoursToComplete, scorecard.AvgDistanceKm, scorecard.SLACompliance)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var ErrShipmentNotFound = errors.New("shipment not found")
+
+type ShipmentRepository interface {
+	Create(ctx context.Context, shipment *domain.Shipment) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.Shipment, error)
+	List(ctx context.Context, ticketID *int, serialNumber string) ([]domain.Shipment, error)
+	ListTrackable(ctx context.Context) ([]domain.Shipment, error)
+	Update(ctx context.Context, shipment *domain.Shipment) error
+	UpdateStatus(ctx context.Context, shipment *domain.Shipment) error
+	AddEvents(ctx context.Context, shipmentID int, events []domain.ShipmentEvent) (int, error)
+}
+
+type shipmentRepository struct {
+	db *sql.DB
+}
+
+func NewShipmentRepository(db *sql.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+// Create grava o envio e seus itens na mesma transação
+func (r *shipmentRepository) Create(ctx context.Context, shipment *domain.Shipment) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, shipment.TicketID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO shipments (ticket_id, direction, carrier, tracking_number, status, notes)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		shipment.TicketID, shipment.Direction, shipment.Carrier, shipment.TrackingNumber, domain.ShipmentCreated, shipment.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating shipment: %w", err)
+	}
+
+	for _, item := range shipment.Items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO shipment_items (shipment_id, item_id, description, serial_number, quantity) VALUES ($1, $2, $3, $4, $5)`,
+			id, item.ItemID, item.Description, item.SerialNumber, item.Quantity)
+		if err != nil {
+			return 0, fmt.Errorf("error creating shipment item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const shipmentSelect = `SELECT s.id, s.ticket_id, t.number, s.direction, s.carrier, s.tracking_number, s.status, s.notes,
+			s.shipped_at, s.delivered_at, s.created_at, s.updated_at
+			FROM shipments s
+			JOIN tickets t ON t.id = s.ticket_id`
+
+func (r *shipmentRepository) FindByID(ctx context.Context, id int) (*domain.Shipment, error) {
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{id})
+	shipment, err := scanShipment(conn(ctx, r.db).QueryRowContext(ctx, shipmentSelect+` WHERE s.id = $1`+filter, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShipmentNotFound
+		}
+		return nil, fmt.Errorf("error finding shipment: %w", err)
+	}
+
+	shipments := []domain.Shipment{*shipment}
+	if err := r.loadDetails(ctx, shipments); err != nil {
+		return nil, err
+	}
+
+	return &shipments[0], nil
+}
+
+// List retorna os envios, opcionalmente filtrados por ticket e número de série
+func (r *shipmentRepository) List(ctx context.Context, ticketID *int, serialNumber string) ([]domain.Shipment, error) {
+	var conditions []string
+	var args []interface{}
+
+	if ticketID != nil {
+		args = append(args, *ticketID)
+		conditions = append(conditions, fmt.Sprintf("s.ticket_id = $%d", len(args)))
+	}
+	if serialNumber != "" {
+		args = append(args, serialNumber)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM shipment_items si WHERE si.shipment_id = s.id AND si.serial_number = $%d)", len(args)))
+	}
+
+	query := shipmentSelect + " WHERE TRUE"
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+	tenant, args := tenantBranch(ctx, "t.branch_id", args)
+	query += tenant
+	query += " ORDER BY s.created_at DESC, s.id DESC"
+
+	shipments, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(ctx, shipments); err != nil {
+		return nil, err
+	}
+
+	return shipments, nil
+}
+
+// ListTrackable retorna os envios com rastreio ainda não entregues
+func (r *shipmentRepository) ListTrackable(ctx context.Context) ([]domain.Shipment, error) {
+	query := shipmentSelect + ` WHERE s.carrier <> '' AND s.tracking_number <> '' AND s.status <> $1 ORDER BY s.id`
+
+	return r.query(ctx, query, domain.ShipmentDelivered)
+}
+
+func (r *shipmentRepository) Update(ctx context.Context, shipment *domain.Shipment) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		shipment.Carrier, shipment.TrackingNumber, shipment.Notes, shipment.ID,
+	})
+	query := `UPDATE shipments SET carrier = $1, tracking_number = $2, notes = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4` + filter
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating shipment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrShipmentNotFound
+	}
+
+	return nil
+}
+
+func (r *shipmentRepository) UpdateStatus(ctx context.Context, shipment *domain.Shipment) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		shipment.Status, shipment.ShippedAt, shipment.DeliveredAt, shipment.ID,
+	})
+	query := `UPDATE shipments SET status = $1, shipped_at = $2, delivered_at = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4` + filter
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating shipment status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrShipmentNotFound
+	}
+
+	return nil
+}
+
+// AddEvents grava os eventos ainda não registrados e retorna quantos foram inseridos
+func (r *shipmentRepository) AddEvents(ctx context.Context, shipmentID int, events []domain.ShipmentEvent) (int, error) {
+	inserted := 0
+	for _, event := range events {
+		result, err := conn(ctx, r.db).ExecContext(ctx,
+			`INSERT INTO shipment_events (shipment_id, status, description, location, source, occurred_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 ON CONFLICT (shipment_id, occurred_at, status) DO NOTHING`,
+			shipmentID, event.Status, event.Description, event.Location, event.Source, event.OccurredAt)
+		if err != nil {
+			return inserted, fmt.Errorf("error creating shipment event: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return inserted, fmt.Errorf("error checking rows affected: %w", err)
+		}
+		inserted += int(rowsAffected)
+	}
+
+	return inserted, nil
+}
+
+func (r *shipmentRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Shipment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shipments: %w", err)
+	}
+	defer rows.Close()
+
+	var shipments []domain.Shipment
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning shipment: %w", err)
+		}
+		shipments = append(shipments, *shipment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shipments: %w", err)
+	}
+
+	return shipments, nil
+}
+
+// loadDetails preenche itens e eventos dos envios informados
+func (r *shipmentRepository) loadDetails(ctx context.Context, shipments []domain.Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(shipments))
+	index := make(map[int]int, len(shipments))
+	for i := range shipments {
+		ids = append(ids, int64(shipments[i].ID))
+		index[shipments[i].ID] = i
+	}
+
+	itemRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, shipment_id, item_id, description, serial_number, quantity
+		 FROM shipment_items WHERE shipment_id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing shipment items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item domain.ShipmentItem
+		if err := itemRows.Scan(&item.ID, &item.ShipmentID, &item.ItemID, &item.Description, &item.SerialNumber, &item.Quantity); err != nil {
+			return fmt.Errorf("error scanning shipment item: %w", err)
+		}
+		shipment := &shipments[index[item.ShipmentID]]
+		shipment.Items = append(shipment.Items, item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return fmt.Errorf("error iterating shipment items: %w", err)
+	}
+
+	eventRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, shipment_id, status, description, location, source, occurred_at, created_at
+		 FROM shipment_events WHERE shipment_id = ANY($1) ORDER BY occurred_at, id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing shipment events: %w", err)
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var event domain.ShipmentEvent
+		if err := eventRows.Scan(&event.ID, &event.ShipmentID, &event.Status, &event.Description, &event.Location, &event.Source, &event.OccurredAt, &event.CreatedAt); err != nil {
+			return fmt.Errorf("error scanning shipment event: %w", err)
+		}
+		shipment := &shipments[index[event.ShipmentID]]
+		shipment.Events = append(shipment.Events, event)
+	}
+	if err := eventRows.Err(); err != nil {
+		return fmt.Errorf("error iterating shipment events: %w", err)
+	}
+
+	return nil
+}
+
+func scanShipment(row rowScanner) (*domain.Shipment, error) {
+	var shipment domain.Shipment
+	err := row.Scan(
+		&shipment.ID,
+		&shipment.TicketID,
+		&shipment.TicketNumber,
+		&shipment.Direction,
+		&shipment.Carrier,
+		&shipment.TrackingNumber,
+		&shipment.Status,
+		&shipment.Notes,
+		&shipment.ShippedAt,
+		&shipment.DeliveredAt,
+		&shipment.CreatedAt,
+		&shipment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
@@ -25,13 +25,13 @@ func NewSolutionRepository(db *sql.DB) SolutionRepository {
 
 func (r *solutionRepository) Create(ctx context.Context, solution *domain.Solution) (int, error) {
 	query := `
-		INSERT INTO solutions (name, description, unit_price, problem_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO solutions (name, description, unit_price, problem_id, warranty_days)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 	
 	var id int
-	err := r.db.QueryRowContext(ctx, query, solution.Name, solution.Description, solution.UnitPrice, solution.ProblemID).Scan(&id)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, solution.Name, solution.Description, solution.UnitPrice, solution.ProblemID, solution.WarrantyDays).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -41,18 +41,19 @@ func (r *solutionRepository) Create(ctx context.Context, solution *domain.Soluti
 
 func (r *solutionRepository) FindByID(ctx context.Context, id int) (*domain.Solution, error) {
 	query := `
-		SELECT id, name, description, unit_price, problem_id, created_at, updated_at
+		SELECT id, name, description, unit_price, problem_id, warranty_days, created_at, updated_at
 		FROM solutions
 		WHERE id = $1
 	`
 	
 	solution := &domain.Solution{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&solution.ID,
 		&solution.Name,
 		&solution.Description,
 		&solution.UnitPrice,
 		&solution.ProblemID,
+		&solution.WarrantyDays,
 		&solution.CreatedAt,
 		&solution.UpdatedAt,
 	)
@@ -69,12 +70,12 @@ func (r *solutionRepository) FindByID(ctx context.Context, id int) (*domain.Solu
 
 func (r *solutionRepository) FindAll(ctx context.Context) ([]domain.Solution, error) {
 	query := `
-		SELECT id, name, description, unit_price, problem_id, created_at, updated_at
+		SELECT id, name, description, unit_price, problem_id, warranty_days, created_at, updated_at
 		FROM solutions
 		ORDER BY name
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +90,7 @@ func (r *solutionRepository) FindAll(ctx context.Context) ([]domain.Solution, er
 			&solution.Description,
 			&solution.UnitPrice,
 			&solution.ProblemID,
+			&solution.WarrantyDays,
 			&solution.CreatedAt,
 			&solution.UpdatedAt,
 		)
@@ -103,13 +105,13 @@ func (r *solutionRepository) FindAll(ctx context.Context) ([]domain.Solution, er
 
 func (r *solutionRepository) FindByProblem(ctx context.Context, problemID int) ([]domain.Solution, error) {
 	query := `
-		SELECT id, name, description, unit_price, problem_id, created_at, updated_at
+		SELECT id, name, description, unit_price, problem_id, warranty_days, created_at, updated_at
 		FROM solutions
 		WHERE problem_id = $1
 		ORDER BY name
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query, problemID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, problemID)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +126,7 @@ func (r *solutionRepository) FindByProblem(ctx context.Context, problemID int) (
 			&solution.Description,
 			&solution.UnitPrice,
 			&solution.ProblemID,
+			&solution.WarrantyDays,
 			&solution.CreatedAt,
 			&solution.UpdatedAt,
 		)
@@ -139,16 +142,16 @@ func (r *solutionRepository) FindByProblem(ctx context.Context, problemID int) (
 func (r *solutionRepository) Update(ctx context.Context, id int, solution *domain.Solution) error {
 	query := `
 		UPDATE solutions
-		SET name = $1, description = $2, unit_price = $3, problem_id = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $5
+		SET name = $1, description = $2, unit_price = $3, problem_id = $4, warranty_days = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
 	`
 	
-	_, err := r.db.ExecContext(ctx, query, solution.Name, solution.Description, solution.UnitPrice, solution.ProblemID, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, solution.Name, solution.Description, solution.UnitPrice, solution.ProblemID, solution.WarrantyDays, id)
 	return err
 }
 
 func (r *solutionRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM solutions WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
@@ -0,0 +1,482 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrStatementNotFound     = errors.New("provider statement not found")
+	ErrStatementStatus       = errors.New("provider statement status does not allow this operation")
+	ErrStatementNoTickets    = errors.New("provider has no concluded tickets to settle in this period")
+	ErrStatementLineNotFound = errors.New("statement deduction not found")
+	ErrBankAccountNotFound   = errors.New("provider bank account not found")
+)
+
+type StatementRepository interface {
+	Create(ctx context.Context, statement *domain.ProviderStatement) (int, error)
+	FindByID(ctx context.Context, id int) (*domain.ProviderStatement, error)
+	List(ctx context.Context, providerID *int, status string) ([]domain.ProviderStatement, error)
+	AddDeduction(ctx context.Context, line *domain.StatementLine) (int, error)
+	RemoveDeduction(ctx context.Context, statementID, lineID int) error
+	Approve(ctx context.Context, id, userID int) error
+	Pay(ctx context.Context, id int, reference string) error
+	Delete(ctx context.Context, id int) error
+
+	// Conta bancária do técnico
+	FindBankAccount(ctx context.Context, providerID int) (*domain.ProviderBankAccount, error)
+	SaveBankAccount(ctx context.Context, account *domain.ProviderBankAccount) error
+}
+
+type statementRepository struct {
+	db *sql.DB
+}
+
+func NewStatementRepository(db *sql.DB) StatementRepository {
+	return &statementRepository{db: db}
+}
+
+// Create grava o extrato em rascunho com os tickets concluídos do técnico no período,
+// o deslocamento das visitas, a mão de obra lançada e as despesas aprovadas. Os tickets incluídos ficam travados.
+func (r *statementRepository) Create(ctx context.Context, statement *domain.ProviderStatement) (int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO provider_statements (provider_id, period_start, period_end, status, notes)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		statement.ProviderID, statement.PeriodStart, statement.PeriodEnd, domain.StatementDraft, statement.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating provider statement: %w", err)
+	}
+
+	// Tickets concluídos no período em que o técnico atuou, sem prestação de contas aguardando revisão,
+	// e que ainda não entraram em outro extrato dele
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO provider_statement_tickets (statement_id, ticket_id, provider_id)
+		 SELECT $1, t.id, $2
+		 FROM tickets t
+		 WHERE t.status = $3
+			AND t.close_date::date BETWEEN $4 AND $5
+			AND (t.provider_id = $2 OR EXISTS (SELECT 1 FROM ticket_visits v WHERE v.ticket_id = t.id AND v.provider_id = $2))
+			AND NOT EXISTS (SELECT 1 FROM provider_statement_tickets st WHERE st.ticket_id = t.id AND st.provider_id = $2)
+			AND NOT EXISTS (SELECT 1 FROM ticket_expenses e WHERE e.ticket_id = t.id AND e.status = $6)`,
+		id, statement.ProviderID, domain.TicketStatusConcluido, statement.PeriodStart, statement.PeriodEnd, domain.ExpensePending)
+	if err != nil {
+		return 0, fmt.Errorf("error adding statement tickets: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, ErrStatementNoTickets
+	}
+
+	// Reembolso de deslocamento das visitas do técnico (distância x custo calculado na visita)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO provider_statement_lines (statement_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount)
+		 SELECT $1, $2, v.ticket_id, v.id,
+			'Deslocamento chamado ' || t.number || COALESCE(' (' || v.distance_km || ' km)', ''),
+			1, v.travel_cost, v.travel_cost
+		 FROM ticket_visits v
+		 JOIN tickets t ON t.id = v.ticket_id
+		 JOIN provider_statement_tickets st ON st.ticket_id = v.ticket_id AND st.statement_id = $1
+		 WHERE v.provider_id = $3 AND v.travel_cost > 0
+		 ORDER BY t.number, v.starts_at`,
+		id, domain.StatementLineTravel, statement.ProviderID)
+	if err != nil {
+		return 0, fmt.Errorf("error adding statement travel lines: %w", err)
+	}
+
+	// Mão de obra: custos das visitas do técnico ou, sem visita, do técnico do ticket
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO provider_statement_lines (statement_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount)
+		 SELECT $1, $2, c.ticket_id, c.visit_id,
+			'Chamado ' || t.number || ': ' || c.problem_name || ' - ' || c.solution_name,
+			c.quantity, c.unit_price, c.subtotal
+		 FROM ticket_costs c
+		 JOIN tickets t ON t.id = c.ticket_id
+		 JOIN provider_statement_tickets st ON st.ticket_id = c.ticket_id AND st.statement_id = $1
+		 LEFT JOIN ticket_visits v ON v.id = c.visit_id
+		 WHERE COALESCE(v.provider_id, t.provider_id) = $3
+		 ORDER BY t.number, c.id`,
+		id, domain.StatementLineLabor, statement.ProviderID)
+	if err != nil {
+		return 0, fmt.Errorf("error adding statement labor lines: %w", err)
+	}
+
+	// Despesas aprovadas na prestação de contas do técnico (itens rejeitados não são reembolsados)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO provider_statement_lines (statement_id, kind, ticket_id, description, quantity, unit_amount, amount)
+		 SELECT $1, $2, e.ticket_id,
+			'Despesa chamado ' || t.number || ': ' || i.description,
+			i.quantity, i.unit_price, i.quantity * i.unit_price
+		 FROM ticket_expense_items i
+		 JOIN ticket_expenses e ON e.id = i.expense_id
+		 JOIN tickets t ON t.id = e.ticket_id
+		 JOIN provider_statement_tickets st ON st.ticket_id = e.ticket_id AND st.statement_id = $1
+		 WHERE e.provider_id = $3 AND i.status = $4
+		 ORDER BY t.number, i.id`,
+		id, domain.StatementLineExpense, statement.ProviderID, domain.ExpenseApproved)
+	if err != nil {
+		return 0, fmt.Errorf("error adding statement expense lines: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+const statementSelect = `SELECT s.id, s.provider_id, p.name, s.period_start, s.period_end, s.status, s.notes,
+			s.approved_by, s.approved_at, s.paid_at, s.payment_reference, s.created_at, s.updated_at
+			FROM provider_statements s
+			JOIN providers p ON p.id = s.provider_id`
+
+func (r *statementRepository) FindByID(ctx context.Context, id int) (*domain.ProviderStatement, error) {
+	statement, err := scanStatement(conn(ctx, r.db).QueryRowContext(ctx, statementSelect+` WHERE s.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStatementNotFound
+		}
+		return nil, fmt.Errorf("error finding provider statement: %w", err)
+	}
+
+	statements := []domain.ProviderStatement{*statement}
+	if err := r.loadDetails(ctx, statements); err != nil {
+		return nil, err
+	}
+
+	return &statements[0], nil
+}
+
+// List retorna os extratos, opcionalmente filtrados por técnico e status
+func (r *statementRepository) List(ctx context.Context, providerID *int, status string) ([]domain.ProviderStatement, error) {
+	query := statementSelect + " WHERE 1=1"
+	var args []interface{}
+	if providerID != nil {
+		args = append(args, *providerID)
+		query += fmt.Sprintf(" AND s.provider_id = $%d", len(args))
+	}
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND s.status = $%d", len(args))
+	}
+	query += " ORDER BY s.period_start DESC, p.name ASC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing provider statements: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []domain.ProviderStatement
+	for rows.Next() {
+		statement, err := scanStatement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning provider statement: %w", err)
+		}
+		statements = append(statements, *statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating provider statements: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, statements); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// AddDeduction lança um desconto no extrato em rascunho
+func (r *statementRepository) AddDeduction(ctx context.Context, line *domain.StatementLine) (int, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockDraftStatement(ctx, tx, line.StatementID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO provider_statement_lines (statement_id, kind, ticket_id, description, quantity, unit_amount, amount)
+		 VALUES ($1, $2, $3, $4, 1, $5, $5) RETURNING id`,
+		line.StatementID, domain.StatementLineDeduction, line.TicketID, line.Description, line.Amount).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error creating statement deduction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE provider_statements SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, line.StatementID); err != nil {
+		return 0, fmt.Errorf("error updating provider statement: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// RemoveDeduction remove um desconto do extrato em rascunho
+func (r *statementRepository) RemoveDeduction(ctx context.Context, statementID, lineID int) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockDraftStatement(ctx, tx, statementID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM provider_statement_lines WHERE id = $1 AND statement_id = $2 AND kind = $3`,
+		lineID, statementID, domain.StatementLineDeduction)
+	if err != nil {
+		return fmt.Errorf("error deleting statement deduction: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrStatementLineNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE provider_statements SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, statementID); err != nil {
+		return fmt.Errorf("error updating provider statement: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Approve registra a aprovação do extrato em rascunho
+func (r *statementRepository) Approve(ctx context.Context, id, userID int) error {
+	return r.transition(ctx,
+		`UPDATE provider_statements
+		 SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND status = $4`,
+		domain.StatementApproved, userID, id, domain.StatementDraft)
+}
+
+// Pay marca o extrato aprovado como pago
+func (r *statementRepository) Pay(ctx context.Context, id int, reference string) error {
+	return r.transition(ctx,
+		`UPDATE provider_statements
+		 SET status = $1, payment_reference = $2, paid_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND status = $4`,
+		domain.StatementPaid, reference, id, domain.StatementApproved)
+}
+
+// Delete descarta o extrato em rascunho; os tickets voltam a ficar disponíveis
+func (r *statementRepository) Delete(ctx context.Context, id int) error {
+	return r.transition(ctx,
+		`DELETE FROM provider_statements WHERE id = $1 AND status = $2`,
+		id, domain.StatementDraft)
+}
+
+func (r *statementRepository) transition(ctx context.Context, query string, args ...interface{}) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating provider statement: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrStatementStatus
+	}
+
+	return nil
+}
+
+func (r *statementRepository) FindBankAccount(ctx context.Context, providerID int) (*domain.ProviderBankAccount, error) {
+	var account domain.ProviderBankAccount
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT provider_id, holder_name, document, bank_code, agency, agency_digit, account, account_digit, updated_at
+		 FROM provider_bank_accounts WHERE provider_id = $1`, providerID).Scan(
+		&account.ProviderID,
+		&account.HolderName,
+		&account.Document,
+		&account.BankCode,
+		&account.Agency,
+		&account.AgencyDigit,
+		&account.Account,
+		&account.AccountDigit,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("error finding provider bank account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// SaveBankAccount cadastra ou substitui a conta bancária do técnico
+func (r *statementRepository) SaveBankAccount(ctx context.Context, account *domain.ProviderBankAccount) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO provider_bank_accounts (provider_id, holder_name, document, bank_code, agency, agency_digit, account, account_digit)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (provider_id) DO UPDATE SET
+			holder_name = EXCLUDED.holder_name,
+			document = EXCLUDED.document,
+			bank_code = EXCLUDED.bank_code,
+			agency = EXCLUDED.agency,
+			agency_digit = EXCLUDED.agency_digit,
+			account = EXCLUDED.account,
+			account_digit = EXCLUDED.account_digit,
+			updated_at = CURRENT_TIMESTAMP`,
+		account.ProviderID, account.HolderName, account.Document, account.BankCode,
+		account.Agency, account.AgencyDigit, account.Account, account.AccountDigit)
+	if err != nil {
+		return fmt.Errorf("error saving provider bank account: %w", err)
+	}
+
+	return nil
+}
+
+// loadDetails carrega os tickets e lançamentos dos extratos informados
+func (r *statementRepository) loadDetails(ctx context.Context, statements []domain.ProviderStatement) error {
+	if len(statements) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(statements))
+	index := make(map[int]int, len(statements))
+	for i := range statements {
+		ids = append(ids, int64(statements[i].ID))
+		index[statements[i].ID] = i
+		statements[i].Tickets = []domain.StatementTicket{}
+		statements[i].Lines = []domain.StatementLine{}
+	}
+
+	ticketRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT st.statement_id, t.id, t.number, b.name, t.close_date
+		 FROM provider_statement_tickets st
+		 JOIN tickets t ON t.id = st.ticket_id
+		 JOIN branchs b ON b.id = t.branch_id
+		 WHERE st.statement_id = ANY($1)
+		 ORDER BY t.close_date, t.number`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing statement tickets: %w", err)
+	}
+	defer ticketRows.Close()
+
+	for ticketRows.Next() {
+		var ticket domain.StatementTicket
+		if err := ticketRows.Scan(&ticket.StatementID, &ticket.TicketID, &ticket.Number, &ticket.BranchName, &ticket.CloseDate); err != nil {
+			return fmt.Errorf("error scanning statement ticket: %w", err)
+		}
+		statement := &statements[index[ticket.StatementID]]
+		statement.Tickets = append(statement.Tickets, ticket)
+	}
+
+	if err := ticketRows.Err(); err != nil {
+		return fmt.Errorf("error iterating statement tickets: %w", err)
+	}
+
+	lineRows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, statement_id, kind, ticket_id, visit_id, description, quantity, unit_amount, amount, created_at
+		 FROM provider_statement_lines
+		 WHERE statement_id = ANY($1)
+		 ORDER BY id`, ids)
+	if err != nil {
+		return fmt.Errorf("error listing statement lines: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line domain.StatementLine
+		if err := lineRows.Scan(
+			&line.ID,
+			&line.StatementID,
+			&line.Kind,
+			&line.TicketID,
+			&line.VisitID,
+			&line.Description,
+			&line.Quantity,
+			&line.UnitAmount,
+			&line.Amount,
+			&line.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("error scanning statement line: %w", err)
+		}
+		statement := &statements[index[line.StatementID]]
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	if err := lineRows.Err(); err != nil {
+		return fmt.Errorf("error iterating statement lines: %w", err)
+	}
+
+	return nil
+}
+
+// lockDraftStatement bloqueia o extrato na transação e garante que ainda é rascunho
+func lockDraftStatement(ctx context.Context, tx dbConn, id int) error {
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM provider_statements WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStatementNotFound
+		}
+		return fmt.Errorf("error finding provider statement: %w", err)
+	}
+
+	if status != domain.StatementDraft {
+		return ErrStatementStatus
+	}
+
+	return nil
+}
+
+func scanStatement(row rowScanner) (*domain.ProviderStatement, error) {
+	var statement domain.ProviderStatement
+	err := row.Scan(
+		&statement.ID,
+		&statement.ProviderID,
+		&statement.ProviderName,
+		&statement.PeriodStart,
+		&statement.PeriodEnd,
+		&statement.Status,
+		&statement.Notes,
+		&statement.ApprovedBy,
+		&statement.ApprovedAt,
+		&statement.PaidAt,
+		&statement.PaymentReference,
+		&statement.CreatedAt,
+		&statement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &statement, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+// TestStatementTransitions confere o fluxo rascunho -> aprovado -> pago. O banco de teste não
+// executa SQL, então o fake só afeta a linha quando a situação exigida pelo comando (último
+// argumento) é a situação atual do extrato.
+func TestStatementTransitions(t *testing.T) {
+	approve := func(r StatementRepository) error { return r.Approve(context.Background(), 1, 9) }
+	pay := func(r StatementRepository) error { return r.Pay(context.Background(), 1, "TED-123") }
+	remove := func(r StatementRepository) error { return r.Delete(context.Background(), 1) }
+
+	tests := []struct {
+		name    string
+		current string
+		op      func(StatementRepository) error
+		want    error
+	}{
+		{"approve draft", domain.StatementDraft, approve, nil},
+		{"approve approved", domain.StatementApproved, approve, ErrStatementStatus},
+		{"approve paid", domain.StatementPaid, approve, ErrStatementStatus},
+		{"pay approved", domain.StatementApproved, pay, nil},
+		{"pay draft", domain.StatementDraft, pay, ErrStatementStatus},
+		{"pay paid", domain.StatementPaid, pay, ErrStatementStatus},
+		{"delete draft", domain.StatementDraft, remove, nil},
+		// Aprovado ou pago, o extrato não pode ser descartado: os custos dos tickets seguem travados
+		{"delete approved", domain.StatementApproved, remove, ErrStatementStatus},
+		{"delete paid", domain.StatementPaid, remove, ErrStatementStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.affected = func(query string, args []driver.Value) int64 {
+				if args[len(args)-1] == tt.current {
+					return 1
+				}
+				return 0
+			}
+
+			if err := tt.op(NewStatementRepository(db)); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementDeductionsRequireDraft(t *testing.T) {
+	add := func(r StatementRepository) error {
+		_, err := r.AddDeduction(context.Background(), &domain.StatementLine{StatementID: 1, Description: "Multa", Amount: domain.NewMoney(50)})
+		return err
+	}
+	remove := func(r StatementRepository) error { return r.RemoveDeduction(context.Background(), 1, 3) }
+
+	tests := []struct {
+		name    string
+		current [][]driver.Value
+		op      func(StatementRepository) error
+		want    error
+	}{
+		{"add to draft", [][]driver.Value{{domain.StatementDraft}}, add, nil},
+		{"add to approved", [][]driver.Value{{domain.StatementApproved}}, add, ErrStatementStatus},
+		{"add to missing", nil, add, ErrStatementNotFound},
+		{"remove from draft", [][]driver.Value{{domain.StatementDraft}}, remove, nil},
+		{"remove from paid", [][]driver.Value{{domain.StatementPaid}}, remove, ErrStatementStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				switch {
+				case strings.Contains(query, "SELECT status FROM provider_statements"):
+					return []string{"status"}, tt.current
+				case strings.Contains(query, "INSERT INTO provider_statement_lines"):
+					return []string{"id"}, [][]driver.Value{{int64(3)}}
+				}
+				return nil, nil
+			}
+
+			if err := tt.op(NewStatementRepository(db)); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			// O extrato é travado antes da alteração, e fora do rascunho nada é gravado
+			if len(fake.calls("FOR UPDATE")) != 1 {
+				t.Fatalf("expected the statement locked once")
+			}
+			writes := len(fake.calls("INTO provider_statement_lines")) + len(fake.calls("DELETE FROM provider_statement_lines"))
+			if (tt.want == nil) != (writes == 1) {
+				t.Fatalf("got %d writes, want %v", writes, tt.want == nil)
+			}
+		})
+	}
+}
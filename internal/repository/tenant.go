@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// ErrCrossTenant indica a tentativa de gravar dados de outro cliente
+var ErrCrossTenant = errors.New("record belongs to another client")
+
+// ErrNoTenant indica acesso a dados de clientes com um context sem cliente e sem
+// tenant.System. É um ErrCrossTenant, tratado da mesma forma pelos handlers.
+var ErrNoTenant = fmt.Errorf("%w: no client in context", ErrCrossTenant)
+
+// Isolamento por cliente (tenant).
+//
+// Todo dado de cliente (agências, tickets e seus filhos, ativos, contratos, faturas,
+// relatórios, indicadores e usuários de clientes) é filtrado aqui, pelo cliente do context
+// (tenant.WithClient). Acesso sem filtro precisa ser explícito: o middleware marca os
+// usuários internos com tenant.System, e workers e rotas públicas fazem o mesmo. Sem
+// cliente e sem essa marca, as consultas não devolvem linhas e as gravações falham com
+// ErrNoTenant. Registros de outro cliente se comportam como inexistentes: as buscas
+// retornam o erro de "não encontrado" do repositório e as listas omitem as linhas.
+//
+// Não passam pelo filtro os dados internos, sem cliente: catálogo de problemas e soluções,
+// tabela de custos, extratos dos técnicos (statement.go) e os emails ingeridos
+// (email_message.go). Suas rotas são restritas à equipe interna.
+//
+// Os helpers devolvem a condição com " AND ..." e os args com o parâmetro acrescentado.
+
+// tenantDenied é a condição que não casa com nenhuma linha, para context sem escopo
+const tenantDenied = " AND FALSE"
+
+// tenantFilter monta a condição a partir do formato, com o número do parâmetro do cliente
+func tenantFilter(ctx context.Context, format string, args []interface{}) (string, []interface{}) {
+	clientID, ok := tenant.ClientID(ctx)
+	if !ok {
+		if tenant.IsSystem(ctx) {
+			return "", args
+		}
+		return tenantDenied, args
+	}
+
+	args = append(args, clientID)
+	return fmt.Sprintf(format, len(args)), args
+}
+
+// tenantClient restringe pela coluna com o id do cliente
+func tenantClient(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	return tenantFilter(ctx, " AND "+column+" = $%d", args)
+}
+
+// tenantBranch restringe pela coluna com o id da agência
+func tenantBranch(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	return tenantFilter(ctx, " AND "+column+" IN (SELECT id FROM branchs WHERE client_id = $%d)", args)
+}
+
+// tenantTicket restringe pela coluna com o id do ticket
+func tenantTicket(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	return tenantFilter(ctx, ` AND `+column+` IN (
+		SELECT tt.id FROM tickets tt JOIN branchs tb ON tb.id = tt.branch_id WHERE tb.client_id = $%d)`, args)
+}
+
+// tenantTicketNumber restringe pela coluna com o número do ticket
+func tenantTicketNumber(ctx context.Context, column string, args []interface{}) (string, []interface{}) {
+	return tenantFilter(ctx, ` AND `+column+` IN (
+		SELECT tt.number FROM tickets tt JOIN branchs tb ON tb.id = tt.branch_id WHERE tb.client_id = $%d)`, args)
+}
+
+// checkSystem exige o acesso interno quando o context não tem cliente
+func checkSystem(ctx context.Context) error {
+	if !tenant.IsSystem(ctx) {
+		return ErrNoTenant
+	}
+	return nil
+}
+
+// checkTenant impede que o usuário de um cliente grave dados apontando para outro cliente
+func checkTenant(ctx context.Context, clientID int) error {
+	current, ok := tenant.ClientID(ctx)
+	if !ok {
+		return checkSystem(ctx)
+	}
+	if current != clientID {
+		return ErrCrossTenant
+	}
+	return nil
+}
+
+// checkTenantOptional é checkTenant para cliente opcional: sem cliente o registro é interno
+func checkTenantOptional(ctx context.Context, clientID *int) error {
+	if clientID != nil {
+		return checkTenant(ctx, *clientID)
+	}
+	if _, ok := tenant.ClientID(ctx); ok {
+		return ErrCrossTenant
+	}
+	return checkSystem(ctx)
+}
+
+// checkTenantBranch impede que o usuário de um cliente grave dados em agência de outro cliente
+func checkTenantBranch(ctx context.Context, db *sql.DB, branchID int) error {
+	filter, args := tenantBranch(ctx, "id", []interface{}{branchID})
+	return checkTenantRow(ctx, db, `SELECT EXISTS (SELECT 1 FROM branchs WHERE id = $1`+filter+`)`, filter, args)
+}
+
+// checkTenantTicket impede que o usuário de um cliente grave dados em ticket de outro cliente
+func checkTenantTicket(ctx context.Context, db *sql.DB, ticketID int) error {
+	filter, args := tenantTicket(ctx, "id", []interface{}{ticketID})
+	return checkTenantRow(ctx, db, `SELECT EXISTS (SELECT 1 FROM tickets WHERE id = $1`+filter+`)`, filter, args)
+}
+
+// checkTenantTicketNumber é checkTenantTicket para tabelas que referenciam o número do ticket
+func checkTenantTicketNumber(ctx context.Context, db *sql.DB, number string) error {
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{number})
+	return checkTenantRow(ctx, db, `SELECT EXISTS (SELECT 1 FROM tickets WHERE number = $1`+filter+`)`, filter, args)
+}
+
+func checkTenantRow(ctx context.Context, db *sql.DB, query, filter string, args []interface{}) error {
+	switch filter {
+	case "":
+		return nil
+	case tenantDenied:
+		return ErrNoTenant
+	}
+
+	var exists bool
+	if err := conn(ctx, db).QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking client access: %w", err)
+	}
+	if !exists {
+		return ErrCrossTenant
+	}
+
+	return nil
+}
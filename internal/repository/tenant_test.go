@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+const (
+	ownerClient = 1
+	otherClient = 2
+)
+
+// visibleTo simula o filtro por cliente no banco: a linha pertence a ownerClient e só é
+// vista sem filtro ou quando o cliente do filtro (último argumento) é o dono
+func visibleTo(query string, args []driver.Value) bool {
+	if strings.Contains(query, tenantDenied) {
+		return false
+	}
+	if !strings.Contains(query, "client_id = $") {
+		return true
+	}
+	return len(args) > 0 && args[len(args)-1] == ownerClient
+}
+
+// ownedRow responde a consulta que contém o trecho com a linha de ownerClient
+func ownedRow(fragment string, columns []string, row []driver.Value) func(string, []driver.Value) ([]string, [][]driver.Value) {
+	return func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+		if !strings.Contains(query, fragment) || !visibleTo(query, args) {
+			return columns, nil
+		}
+		return columns, [][]driver.Value{row}
+	}
+}
+
+func ownedAffected(query string, args []driver.Value) int64 {
+	if visibleTo(query, args) {
+		return 1
+	}
+	return 0
+}
+
+func shipmentRow() []driver.Value {
+	now := time.Now()
+	return []driver.Value{int64(10), int64(5), "T-5", domain.ShipmentOutbound, "", "", domain.ShipmentCreated, "", nil, nil, now, now}
+}
+
+var shipmentColumns = []string{"id", "ticket_id", "number", "direction", "carrier", "tracking_number", "status", "notes",
+	"shipped_at", "delivered_at", "created_at", "updated_at"}
+
+func TestShipmentRepository_TenantIsolation(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.respond = ownedRow("FROM shipments s", shipmentColumns, shipmentRow())
+	fake.affected = ownedAffected
+	repo := NewShipmentRepository(db)
+
+	owner := tenant.WithClient(context.Background(), ownerClient)
+	other := tenant.WithClient(context.Background(), otherClient)
+
+	if _, err := repo.FindByID(owner, 10); err != nil {
+		t.Fatalf("owner FindByID: unexpected error %v", err)
+	}
+	if _, err := repo.FindByID(other, 10); !errors.Is(err, ErrShipmentNotFound) {
+		t.Fatalf("other client FindByID: got %v, want ErrShipmentNotFound", err)
+	}
+
+	ticketID := 5
+	if shipments, err := repo.List(owner, &ticketID, ""); err != nil || len(shipments) != 1 {
+		t.Fatalf("owner List: got %d shipments, err %v", len(shipments), err)
+	}
+	if shipments, err := repo.List(other, &ticketID, ""); err != nil || len(shipments) != 0 {
+		t.Fatalf("other client List: got %d shipments, err %v", len(shipments), err)
+	}
+
+	shipment := &domain.Shipment{ID: 10, Carrier: "correios"}
+	if err := repo.Update(other, shipment); !errors.Is(err, ErrShipmentNotFound) {
+		t.Fatalf("other client Update: got %v, want ErrShipmentNotFound", err)
+	}
+	if err := repo.UpdateStatus(other, shipment); !errors.Is(err, ErrShipmentNotFound) {
+		t.Fatalf("other client UpdateStatus: got %v, want ErrShipmentNotFound", err)
+	}
+
+	// Acesso interno (usuários internos, workers) não tem filtro
+	if _, err := repo.FindByID(tenant.System(context.Background()), 10); err != nil {
+		t.Fatalf("internal FindByID: unexpected error %v", err)
+	}
+
+	// Sem cliente e sem acesso interno o registro não aparece
+	if _, err := repo.FindByID(context.Background(), 10); !errors.Is(err, ErrShipmentNotFound) {
+		t.Fatalf("unscoped FindByID: got %v, want ErrShipmentNotFound", err)
+	}
+}
+
+func TestPurchaseRepository_ListTenantIsolation(t *testing.T) {
+	fake, db := newFakeDB(t)
+	now := time.Now()
+	fake.respond = ownedRow("FROM purchase_requests p", []string{"id", "ticket_id", "number", "supplier_id", "name", "status",
+		"notes", "approved_by", "decided_at", "decision_note", "location_id", "received_at", "created_at", "updated_at"},
+		[]driver.Value{int64(3), int64(5), "T-5", int64(1), "Fornecedor", domain.PurchasePending, "", nil, nil, "", nil, nil, now, now})
+	repo := NewPurchaseRepository(db)
+
+	ticketID := 5
+	owner := tenant.WithClient(context.Background(), ownerClient)
+	if purchases, err := repo.List(owner, &ticketID, ""); err != nil || len(purchases) != 1 {
+		t.Fatalf("owner List: got %d purchases, err %v", len(purchases), err)
+	}
+
+	other := tenant.WithClient(context.Background(), otherClient)
+	if purchases, err := repo.List(other, &ticketID, ""); err != nil || len(purchases) != 0 {
+		t.Fatalf("other client List: got %d purchases, err %v", len(purchases), err)
+	}
+	if _, err := repo.FindByID(other, 3); !errors.Is(err, ErrPurchaseRequestNotFound) {
+		t.Fatalf("other client FindByID: got %v, want ErrPurchaseRequestNotFound", err)
+	}
+}
+
+func TestInventoryRepository_ListReservationsByTicketTenantIsolation(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.respond = ownedRow("FROM stock_reservations r", []string{"id", "ticket_id", "solution_id", "item_id", "item_name",
+		"location_id", "location_name", "quantity", "status", "created_at"},
+		[]driver.Value{int64(1), int64(5), int64(2), int64(7), "Cabo", int64(1), "Central", int64(3), domain.ReservationReserved, time.Now()})
+	repo := NewInventoryRepository(db)
+
+	owner := tenant.WithClient(context.Background(), ownerClient)
+	if reservations, err := repo.ListReservationsByTicket(owner, 5); err != nil || len(reservations) != 1 {
+		t.Fatalf("owner ListReservationsByTicket: got %d reservations, err %v", len(reservations), err)
+	}
+
+	other := tenant.WithClient(context.Background(), otherClient)
+	if reservations, err := repo.ListReservationsByTicket(other, 5); err != nil || len(reservations) != 0 {
+		t.Fatalf("other client ListReservationsByTicket: got %d reservations, err %v", len(reservations), err)
+	}
+}
+
+func TestTenantFilters(t *testing.T) {
+	helpers := map[string]func(context.Context, string, []interface{}) (string, []interface{}){
+		"tenantClient":       tenantClient,
+		"tenantBranch":       tenantBranch,
+		"tenantTicket":       tenantTicket,
+		"tenantTicketNumber": tenantTicketNumber,
+	}
+	scopes := []struct {
+		name     string
+		ctx      context.Context
+		wantArgs int
+		want     string
+	}{
+		{"client", tenant.WithClient(context.Background(), ownerClient), 2, "$2"},
+		{"system", tenant.System(context.Background()), 1, ""},
+		// O portal do cliente restringe o context do usuário ao cliente mesmo sobre System
+		{"client over system", tenant.WithClient(tenant.System(context.Background()), ownerClient), 2, "$2"},
+		{"unscoped", context.Background(), 1, tenantDenied},
+	}
+
+	for name, helper := range helpers {
+		for _, scope := range scopes {
+			t.Run(name+"/"+scope.name, func(t *testing.T) {
+				filter, args := helper(scope.ctx, "x.id", []interface{}{10})
+				if len(args) != scope.wantArgs {
+					t.Fatalf("got %d args, want %d", len(args), scope.wantArgs)
+				}
+				if scope.want == "" && filter != "" || scope.want != "" && !strings.Contains(filter, scope.want) {
+					t.Fatalf("got filter %q, want %q", filter, scope.want)
+				}
+			})
+		}
+	}
+}
+
+func TestCheckTenant(t *testing.T) {
+	owner := tenant.WithClient(context.Background(), ownerClient)
+	system := tenant.System(context.Background())
+	unscoped := context.Background()
+	client := ownerClient
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"owner writes own client", checkTenant(owner, ownerClient), nil},
+		{"owner writes other client", checkTenant(owner, otherClient), ErrCrossTenant},
+		{"system writes any client", checkTenant(system, otherClient), nil},
+		{"unscoped write", checkTenant(unscoped, ownerClient), ErrNoTenant},
+		{"owner writes internal record", checkTenantOptional(owner, nil), ErrCrossTenant},
+		{"owner writes own optional client", checkTenantOptional(owner, &client), nil},
+		{"system writes internal record", checkTenantOptional(system, nil), nil},
+		{"unscoped internal record", checkTenantOptional(unscoped, nil), ErrNoTenant},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) || tt.want == nil && tt.err != nil {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+	// Os handlers tratam a falta de escopo como acesso a outro cliente
+	if !errors.Is(ErrNoTenant, ErrCrossTenant) {
+		t.Fatalf("ErrNoTenant must be an ErrCrossTenant")
+	}
+}
+
+func TestAnalyticsRepository_TenantScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+		want    string
+	}{
+		{"client counts only its tickets", tenant.WithClient(context.Background(), ownerClient), nil, "tb.client_id = $1"},
+		{"system counts every ticket", tenant.System(context.Background()), nil, ""},
+		{"unscoped is denied", context.Background(), ErrNoTenant, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			repo := NewAnalyticsRepository(db)
+
+			if _, err := repo.UnusedSolutions(tt.ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnusedSolutions: got %v, want %v", err, tt.wantErr)
+			}
+			calls := fake.calls("FROM solutions sol")
+			if tt.wantErr != nil {
+				if len(calls) != 0 {
+					t.Fatalf("unscoped query reached the database")
+				}
+				return
+			}
+			if len(calls) != 1 {
+				t.Fatalf("got %d queries, want 1", len(calls))
+			}
+			if tt.want == "" && strings.Contains(calls[0].query, "client_id") ||
+				tt.want != "" && (!strings.Contains(calls[0].query, tt.want) || calls[0].args[0] != ownerClient) {
+				t.Fatalf("query %q with args %v does not match scope %q", calls[0].query, calls[0].args, tt.want)
+			}
+
+			// Os indicadores de problemas usam o mesmo escopo do dashboard
+			filter := domain.ProblemAnalyticsFilter{From: time.Now().AddDate(0, -1, 0), To: time.Now(), FollowUpDays: 30}
+			if _, err := repo.Problems(tt.ctx, filter); err != nil {
+				t.Fatalf("Problems: unexpected error %v", err)
+			}
+			problems := fake.calls("FROM problems p")
+			if len(problems) != 1 {
+				t.Fatalf("got %d problem queries, want 1", len(problems))
+			}
+			if tt.want != "" && !strings.Contains(problems[0].query, "b.client_id = $") {
+				t.Fatalf("problem analytics without client filter: %q", problems[0].query)
+			}
+		})
+	}
+}
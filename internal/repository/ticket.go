@@ -13,12 +13,17 @@ type TicketRepository interface {
 	Create(ctx context.Context, ticket *domain.Ticket) (int, error)
 	List(ctx context.Context, limit, offset int) ([]domain.Ticket, int, error)
 	FindByID(ctx context.Context, id int) (*domain.Ticket, error)
+	FindByNumber(ctx context.Context, number string) (*domain.Ticket, error)
 	Update(ctx context.Context, ticket *domain.Ticket) error
 	Delete(ctx context.Context, ticketID int) error
 	GetTicketNumber(ctx context.Context) (int, error)
 	AddProvider(ctx context.Context, ticketID int, providerID int) error
 	RemoveProvider(ctx context.Context, ticketID int) error
 	GetProviderOnTicket(ctx context.Context, ticketID int) (*domain.Provider, error)
+	ListByProvider(ctx context.Context, providerID int, limit, offset int) ([]domain.Ticket, int, error)
+	UpdateAssignmentStatus(ctx context.Context, ticketID int, assignmentStatus string) error
+	UpdateStatus(ctx context.Context, ticketID int, status int) error
+	CostsLocked(ctx context.Context, ticketID int) (bool, error)
 
 	// Ticket Costs methods
 	CreateTicketCosts(ctx context.Context, ticketID int, costs []domain.TicketCost) error
@@ -27,12 +32,12 @@ type TicketRepository interface {
 	DeleteTicketCosts(ctx context.Context, ticketID int) error
 	// Ticket Problems methods
 
-	AddProblemToTicket(ctx context.Context, ticketID int, problemID int) error
+	AddProblemToTicket(ctx context.Context, ticketID int, problemID int, assetID *int) error
 	GetTicketProblems(ctx context.Context, ticketID int) ([]domain.TicketProblem, error)
 	RemoveProblemFromTicket(ctx context.Context, ticketID int, problemID int) error
 
 	// Ticket Solutions methods
-	AddSolutionToTicket(ctx context.Context, ticketID int, solutionID int, quantity int) error
+	AddSolutionToTicket(ctx context.Context, cost *domain.TicketCost) error
 	GetTicketSolutions(ctx context.Context, ticketID int) ([]domain.TicketCost, error)
 	RemoveSolutionFromTicket(ctx context.Context, ticketID int, solutionID int) error
 }
@@ -46,7 +51,11 @@ func NewTicketRepository(db *sql.DB) TicketRepository {
 }
 
 func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) (int, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+	if err := checkTenantBranch(ctx, r.db, ticket.BranchID); err != nil {
+		return 0, err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -63,8 +72,8 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) (i
 	err = tx.QueryRowContext(
 		ctx,
 		`INSERT INTO tickets (
-			number, status, priority, description, open_date, close_date, branch_id, provider_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			number, status, priority, description, open_date, close_date, branch_id, provider_id, asset_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
 		ticket.Number,
 		ticket.Status,
 		ticket.Priority,
@@ -73,6 +82,7 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) (i
 		ticket.CloseDate,
 		ticket.BranchID,
 		ticket.ProviderID,
+		ticket.AssetID,
 	).Scan(&ticketID)
 
 	if err != nil {
@@ -89,19 +99,22 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) (i
 func (r *ticketRepository) List(ctx context.Context, limit, offset int) ([]domain.Ticket, int, error) {
 	var records int
 
-	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tickets").Scan(&records)
+	filter, args := tenantBranch(ctx, "branch_id", nil)
+	err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM tickets WHERE TRUE"+filter, args...).Scan(&records)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count tickets: %w", err)
 	}
 
-	query := `
-		SELECT id, number, status, priority, description, open_date, close_date, branch_id, provider_id
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT id, number, status, priority, description, open_date, close_date, branch_id, provider_id, asset_id, assignment_status, version
 		FROM tickets
+		WHERE TRUE%s
 		ORDER BY id DESC
-		LIMIT $1 OFFSET $2
-	`
+		LIMIT $%d OFFSET $%d
+	`, filter, len(args)-1, len(args))
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list tickets: %w", err)
 	}
@@ -121,6 +134,9 @@ func (r *ticketRepository) List(ctx context.Context, limit, offset int) ([]domai
 			&ticket.CloseDate,
 			&ticket.BranchID,
 			&providerID,
+			&ticket.AssetID,
+			&ticket.AssignmentStatus,
+			&ticket.Version,
 		); err != nil {
 			return nil, 0, fmt.Errorf("error scanning ticket: %w", err)
 		}
@@ -146,12 +162,13 @@ func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket
 	var closeDate sql.NullTime
 	var providerID sql.NullInt64
 
-	err := r.db.QueryRowContext(
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{id})
+	err := conn(ctx, r.db).QueryRowContext(
 		ctx,
-		`SELECT id, number, status, priority, description, open_date, close_date, branch_id, provider_id
+		`SELECT id, number, status, priority, description, open_date, close_date, branch_id, provider_id, asset_id, assignment_status, version
 		FROM tickets
-		WHERE id = $1`,
-		id,
+		WHERE id = $1`+filter,
+		args...,
 	).Scan(
 		&ticket.ID,
 		&ticket.Number,
@@ -162,10 +179,13 @@ func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket
 		&closeDate,
 		&ticket.BranchID,
 		&providerID,
+		&ticket.AssetID,
+		&ticket.AssignmentStatus,
+		&ticket.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("ticket with id %d not found", id)
+			return nil, fmt.Errorf("ticket with id %d: %w", id, ErrNotFound)
 		}
 		return nil, fmt.Errorf("error scanning ticket: %w", err)
 	}
@@ -185,13 +205,26 @@ func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket
 	return &ticket, nil
 }
 
+func (r *ticketRepository) FindByNumber(ctx context.Context, number string) (*domain.Ticket, error) {
+	var id int
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{number})
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT id FROM tickets WHERE number = $1`+filter, args...).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error finding ticket by number: %w", err)
+	}
+
+	return r.FindByID(ctx, id)
+}
+
 func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket) error {
-	_, err := r.db.ExecContext(
-		ctx,
-		`UPDATE tickets SET 
-			number = $1, status = $2, priority = $3, description = $4, 
-			open_date = $5, close_date = $6, branch_id = $7, provider_id = $8
-		WHERE id = $9`,
+	if err := checkTenantBranch(ctx, r.db, ticket.BranchID); err != nil {
+		return err
+	}
+
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{
 		ticket.Number,
 		ticket.Status,
 		ticket.Priority,
@@ -200,8 +233,37 @@ func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket) er
 		ticket.CloseDate,
 		ticket.BranchID,
 		ticket.ProviderID,
+		ticket.AssetID,
 		ticket.ID,
-	)
+		ticket.Version,
+	})
+	// Com versão informada, só atualiza se o ticket não mudou desde a leitura
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		`UPDATE tickets SET 
+			number = $1, status = $2, priority = $3, description = $4, 
+			open_date = $5, close_date = $6, branch_id = $7, provider_id = $8, asset_id = $9,
+			assigned_at = CASE
+				WHEN provider_id IS NOT DISTINCT FROM $8::INTEGER THEN assigned_at
+				WHEN $8::INTEGER IS NULL THEN NULL
+				ELSE CURRENT_TIMESTAMP
+			END,
+			assignment_status = CASE
+				WHEN provider_id IS NOT DISTINCT FROM $8::INTEGER THEN assignment_status
+				WHEN $8::INTEGER IS NULL THEN ''
+				ELSE 'pending'
+			END,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $10 AND ($11::INTEGER = 0 OR version = $11)`+filter+`
+		RETURNING version, assignment_status`,
+		args...,
+	).Scan(&ticket.Version, &ticket.AssignmentStatus)
+	if err == sql.ErrNoRows {
+		if ticket.Version > 0 {
+			return ErrVersionConflict
+		}
+		return fmt.Errorf("ticket with id %d: %w", ticket.ID, ErrNotFound)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update ticket: %w", err)
 	}
@@ -209,7 +271,8 @@ func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket) er
 }
 
 func (r *ticketRepository) Delete(ctx context.Context, ticketID int) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM tickets WHERE id = $1", ticketID)
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{ticketID})
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM tickets WHERE id = $1"+filter, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete ticket: %w", err)
 	}
@@ -219,14 +282,14 @@ func (r *ticketRepository) Delete(ctx context.Context, ticketID int) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("ticket with id %d not found", ticketID)
+		return fmt.Errorf("ticket with id %d: %w", ticketID, ErrNotFound)
 	}
 	return nil
 }
 
 func (r *ticketRepository) GetTicketNumber(ctx context.Context) (int, error) {
 	var maxNumber sql.NullString
-	err := r.db.QueryRowContext(ctx, "SELECT MAX(number) FROM tickets").Scan(&maxNumber)
+	err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT MAX(number) FROM tickets").Scan(&maxNumber)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get max ticket number: %w", err)
 	}
@@ -246,11 +309,11 @@ func (r *ticketRepository) GetTicketNumber(ctx context.Context) (int, error) {
 }
 
 func (r *ticketRepository) AddProvider(ctx context.Context, ticketID int, providerID int) error {
-	_, err := r.db.ExecContext(
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{providerID, ticketID})
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
-		`UPDATE tickets SET provider_id = $1 WHERE id = $2`,
-		providerID,
-		ticketID,
+		`UPDATE tickets SET provider_id = $1, assignment_status = 'pending', assigned_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $2`+filter,
+		args...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to add provider to ticket: %w", err)
@@ -259,10 +322,11 @@ func (r *ticketRepository) AddProvider(ctx context.Context, ticketID int, provid
 }
 
 func (r *ticketRepository) RemoveProvider(ctx context.Context, ticketID int) error {
-	_, err := r.db.ExecContext(
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{ticketID})
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
-		`UPDATE tickets SET provider_id = NULL WHERE id = $1`,
-		ticketID,
+		`UPDATE tickets SET provider_id = NULL, assignment_status = '', assigned_at = NULL, version = version + 1 WHERE id = $1`+filter,
+		args...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to remove provider from ticket: %w", err)
@@ -272,13 +336,14 @@ func (r *ticketRepository) RemoveProvider(ctx context.Context, ticketID int) err
 
 func (r *ticketRepository) GetProviderOnTicket(ctx context.Context, ticketID int) (*domain.Provider, error) {
 	var provider domain.Provider
-	err := r.db.QueryRowContext(
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{ticketID})
+	err := conn(ctx, r.db).QueryRowContext(
 		ctx,
 		`SELECT p.id, p.name, p.mobile, p.zipcode, p.state, p.city, p.neighborhood, p.address, p.complement
 		FROM providers p
 		JOIN tickets t ON p.id = t.provider_id
-		WHERE t.id = $1`,
-		ticketID,
+		WHERE t.id = $1`+filter,
+		args...,
 	).Scan(
 		&provider.ID,
 		&provider.Name,
@@ -299,9 +364,108 @@ func (r *ticketRepository) GetProviderOnTicket(ctx context.Context, ticketID int
 	return &provider, nil
 }
 
+// ListByProvider lista os tickets atribuídos a um prestador
+func (r *ticketRepository) ListByProvider(ctx context.Context, providerID int, limit, offset int) ([]domain.Ticket, int, error) {
+	var records int
+
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{providerID})
+	err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM tickets WHERE provider_id = $1"+filter, args...).Scan(&records)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count provider tickets: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT id, number, status, priority, description, open_date, close_date, branch_id, provider_id, asset_id, assignment_status, version
+		FROM tickets
+		WHERE provider_id = $1%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, filter, len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list provider tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []domain.Ticket
+	for rows.Next() {
+		var ticket domain.Ticket
+		if err := rows.Scan(
+			&ticket.ID,
+			&ticket.Number,
+			&ticket.Status,
+			&ticket.Priority,
+			&ticket.Description,
+			&ticket.OpenDate,
+			&ticket.CloseDate,
+			&ticket.BranchID,
+			&ticket.ProviderID,
+			&ticket.AssetID,
+			&ticket.AssignmentStatus,
+			&ticket.Version,
+		); err != nil {
+			return nil, 0, fmt.Errorf("error scanning ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating tickets: %w", err)
+	}
+
+	return tickets, records, nil
+}
+
+// UpdateAssignmentStatus registra o aceite ou recusa da atribuição pelo técnico
+func (r *ticketRepository) UpdateAssignmentStatus(ctx context.Context, ticketID int, assignmentStatus string) error {
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{assignmentStatus, ticketID})
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		`UPDATE tickets SET assignment_status = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`+filter,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update assignment status: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus altera apenas o status do ticket
+func (r *ticketRepository) UpdateStatus(ctx context.Context, ticketID int, status int) error {
+	filter, args := tenantBranch(ctx, "branch_id", []interface{}{status, ticketID})
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		`UPDATE tickets SET status = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`+filter,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update ticket status: %w", err)
+	}
+	return nil
+}
+
+// CostsLocked indica se o ticket já entrou no extrato de pagamento de algum técnico
+// ou em uma fatura não cancelada
+func (r *ticketRepository) CostsLocked(ctx context.Context, ticketID int) (bool, error) {
+	var locked bool
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM provider_statement_tickets WHERE ticket_id = $1)
+			OR EXISTS (SELECT 1 FROM invoice_tickets it JOIN invoices i ON i.id = it.invoice_id
+				WHERE it.ticket_id = $1 AND i.status <> $2)`,
+		ticketID, domain.InvoiceCancelled,
+	).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ticket statement or invoice: %w", err)
+	}
+	return locked, nil
+}
+
 func (r *ticketRepository) getBranchByID(ctx context.Context, branchID int) (*domain.Branch, error) {
 	var branch domain.Branch
-	err := r.db.QueryRowContext(
+	err := conn(ctx, r.db).QueryRowContext(
 		ctx,
 		`SELECT id, client, name, uniorg, zipcode, state, city, neighborhood, address, complement
 		FROM branches
@@ -334,7 +498,11 @@ func (r *ticketRepository) CreateTicketCosts(ctx context.Context, ticketID int,
 		return nil
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	if err := checkTenantTicket(ctx, r.db, ticketID); err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -346,9 +514,9 @@ func (r *ticketRepository) CreateTicketCosts(ctx context.Context, ticketID int,
 
 	for _, cost := range costs {
 		_, err = tx.ExecContext(ctx,
-			`INSERT INTO ticket_costs (ticket_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal) 
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-			ticketID, cost.ProblemID, cost.ProblemName, cost.SolutionID, cost.SolutionName, cost.Quantity, cost.UnitPrice, cost.Subtotal)
+			`INSERT INTO ticket_costs (ticket_id, visit_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal) 
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			ticketID, cost.VisitID, cost.ProblemID, cost.ProblemName, cost.SolutionID, cost.SolutionName, cost.Quantity, cost.UnitPrice, cost.Subtotal)
 		if err != nil {
 			return fmt.Errorf("failed to insert ticket cost: %w", err)
 		}
@@ -363,10 +531,12 @@ func (r *ticketRepository) CreateTicketCosts(ctx context.Context, ticketID int,
 
 // GetTicketCosts retorna todos os custos de um ticket
 func (r *ticketRepository) GetTicketCosts(ctx context.Context, ticketID int) ([]domain.TicketCost, error) {
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, ticket_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal, created_at 
-		 FROM ticket_costs WHERE ticket_id = $1 ORDER BY created_at`,
-		ticketID)
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, ticket_id, visit_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal,
+			warranty, warranty_ticket_id, created_at 
+		 FROM ticket_costs WHERE ticket_id = $1`+filter+` ORDER BY created_at`,
+		args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query ticket costs: %w", err)
 	}
@@ -378,6 +548,7 @@ func (r *ticketRepository) GetTicketCosts(ctx context.Context, ticketID int) ([]
 		err := rows.Scan(
 			&cost.ID,
 			&cost.TicketID,
+			&cost.VisitID,
 			&cost.ProblemID,
 			&cost.ProblemName,
 			&cost.SolutionID,
@@ -385,6 +556,8 @@ func (r *ticketRepository) GetTicketCosts(ctx context.Context, ticketID int) ([]
 			&cost.Quantity,
 			&cost.UnitPrice,
 			&cost.Subtotal,
+			&cost.Warranty,
+			&cost.WarrantyTicketID,
 			&cost.CreatedAt,
 		)
 		if err != nil {
@@ -402,7 +575,11 @@ func (r *ticketRepository) GetTicketCosts(ctx context.Context, ticketID int) ([]
 
 // UpdateTicketCosts atualiza os custos de um ticket (remove antigos e insere novos)
 func (r *ticketRepository) UpdateTicketCosts(ctx context.Context, ticketID int, costs []domain.TicketCost) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	if err := checkTenantTicket(ctx, r.db, ticketID); err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -421,9 +598,9 @@ func (r *ticketRepository) UpdateTicketCosts(ctx context.Context, ticketID int,
 	// Insere novos custos
 	for _, cost := range costs {
 		_, err = tx.ExecContext(ctx,
-			`INSERT INTO ticket_costs (ticket_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal) 
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-			ticketID, cost.ProblemID, cost.ProblemName, cost.SolutionID, cost.SolutionName, cost.Quantity, cost.UnitPrice, cost.Subtotal)
+			`INSERT INTO ticket_costs (ticket_id, visit_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal) 
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			ticketID, cost.VisitID, cost.ProblemID, cost.ProblemName, cost.SolutionID, cost.SolutionName, cost.Quantity, cost.UnitPrice, cost.Subtotal)
 		if err != nil {
 			return fmt.Errorf("failed to insert ticket cost: %w", err)
 		}
@@ -438,7 +615,8 @@ func (r *ticketRepository) UpdateTicketCosts(ctx context.Context, ticketID int,
 
 // DeleteTicketCosts remove todos os custos de um ticket
 func (r *ticketRepository) DeleteTicketCosts(ctx context.Context, ticketID int) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM ticket_costs WHERE ticket_id = $1", ticketID)
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM ticket_costs WHERE ticket_id = $1"+filter, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete ticket costs: %w", err)
 	}
@@ -446,12 +624,16 @@ func (r *ticketRepository) DeleteTicketCosts(ctx context.Context, ticketID int)
 }
 
 // AddProblemToTicket associa um problema a um ticket
-func (r *ticketRepository) AddProblemToTicket(ctx context.Context, ticketID int, problemID int) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO ticket_problems (ticket_id, problem_id) 
-		VALUES ($1, $2)
-		ON CONFLICT (ticket_id, problem_id) DO NOTHING
-	`, ticketID, problemID)
+func (r *ticketRepository) AddProblemToTicket(ctx context.Context, ticketID int, problemID int, assetID *int) error {
+	if err := checkTenantTicket(ctx, r.db, ticketID); err != nil {
+		return err
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO ticket_problems (ticket_id, problem_id, asset_id) 
+		VALUES ($1, $2, $3)
+		ON CONFLICT (ticket_id, problem_id) DO UPDATE SET asset_id = COALESCE(EXCLUDED.asset_id, ticket_problems.asset_id)
+	`, ticketID, problemID, assetID)
 	if err != nil {
 		return fmt.Errorf("failed to add problem to ticket: %w", err)
 	}
@@ -460,12 +642,13 @@ func (r *ticketRepository) AddProblemToTicket(ctx context.Context, ticketID int,
 
 // GetTicketProblems retorna todos os problemas associados a um ticket
 func (r *ticketRepository) GetTicketProblems(ctx context.Context, ticketID int) ([]domain.TicketProblem, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT tp.id, tp.ticket_id, tp.problem_id, tp.created_at
+	filter, args := tenantTicket(ctx, "tp.ticket_id", []interface{}{ticketID})
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT tp.id, tp.ticket_id, tp.problem_id, tp.asset_id, tp.created_at
 		FROM ticket_problems tp
-		WHERE tp.ticket_id = $1
+		WHERE tp.ticket_id = $1`+filter+`
 		ORDER BY tp.created_at DESC
-	`, ticketID)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ticket problems: %w", err)
 	}
@@ -474,7 +657,7 @@ func (r *ticketRepository) GetTicketProblems(ctx context.Context, ticketID int)
 	var problems []domain.TicketProblem
 	for rows.Next() {
 		var problem domain.TicketProblem
-		err := rows.Scan(&problem.ID, &problem.TicketID, &problem.ProblemID, &problem.CreatedAt)
+		err := rows.Scan(&problem.ID, &problem.TicketID, &problem.ProblemID, &problem.AssetID, &problem.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan ticket problem: %w", err)
 		}
@@ -490,10 +673,11 @@ func (r *ticketRepository) GetTicketProblems(ctx context.Context, ticketID int)
 
 // RemoveProblemFromTicket remove a associação entre um problema e um ticket
 func (r *ticketRepository) RemoveProblemFromTicket(ctx context.Context, ticketID int, problemID int) error {
-	result, err := r.db.ExecContext(ctx, `
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID, problemID})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
 		DELETE FROM ticket_problems 
-		WHERE ticket_id = $1 AND problem_id = $2
-	`, ticketID, problemID)
+		WHERE ticket_id = $1 AND problem_id = $2`+filter,
+		args...)
 	if err != nil {
 		return fmt.Errorf("failed to remove problem from ticket: %w", err)
 	}
@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// newTicketService monta o TicketService com os repositórios reais sobre o banco falso
+func newTicketService(t *testing.T) (*repository.FakeDB, service.TicketService) {
+	t.Helper()
+
+	fake, db := repository.NewFakeDB(t)
+	fake.Respond(respondTicket)
+
+	txManager := repository.NewTxManager(db)
+	ticketRepo := repository.NewTicketRepository(db)
+	branchRepo := repository.NewBranchRepository(db)
+	providerRepo := repository.NewProviderRepository(db)
+	solutionRepo := repository.NewSolutionRepository(db)
+	quoteRepo := repository.NewQuoteRepository(db)
+
+	contractService := service.NewContractService(
+		repository.NewContractRepository(db),
+		repository.NewClientRepository(db),
+		solutionRepo,
+		repository.NewCostRepository(db),
+	)
+	visitService := service.NewVisitService(
+		repository.NewVisitRepository(db), ticketRepo, branchRepo, providerRepo, contractService,
+		time.UTC, []byte("secret"), txManager,
+	)
+
+	return fake, service.NewTicketService(
+		ticketRepo,
+		branchRepo,
+		providerRepo,
+		repository.NewProblemRepository(db),
+		solutionRepo,
+		service.NewDistanceService(repository.NewDistanceRepository(db)),
+		repository.NewCheckinRepository(db),
+		visitService,
+		service.NewInventoryService(repository.NewInventoryRepository(db), solutionRepo, providerRepo),
+		repository.NewAssetRepository(db),
+		service.NewWarrantyService(repository.NewWarrantyRepository(db), ticketRepo),
+		contractService,
+		quoteRepo,
+		txManager,
+	)
+}
+
+// respondTicket devolve o ticket 1, aberto e sem técnico, na agência 2
+func respondTicket(query string, _ []driver.Value) ([]string, [][]driver.Value) {
+	switch {
+	case strings.Contains(query, "FROM tickets") && strings.Contains(query, "assignment_status, version"):
+		return []string{"id", "number", "status", "priority", "description", "open_date", "close_date",
+				"branch_id", "provider_id", "asset_id", "assignment_status", "version"},
+			[][]driver.Value{{int64(1), "CH-1", int64(domain.TicketStatusAgendado), "alta", "Sem rede",
+				time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), nil, int64(2), nil, nil, "", int64(1)}}
+	case strings.Contains(query, "FROM branchs b"):
+		return []string{"id", "client_id", "client", "name", "uniorg", "zipcode", "state", "city", "neighborhood",
+				"address", "complement", "email_domain", "latitude", "longitude", "opens_at", "closes_at", "version"},
+			[][]driver.Value{{int64(2), int64(1), "Banco", "Centro", "0001", "01000-000", "SP", "São Paulo", "Sé",
+				"Rua A, 1", "", "", nil, nil, "08:00", "18:00", int64(1)}}
+	case strings.Contains(query, "FROM provider_statement_tickets"):
+		return []string{"locked"}, [][]driver.Value{{false}}
+	case strings.Contains(query, "UPDATE tickets SET"):
+		return []string{"version", "assignment_status"}, [][]driver.Value{{int64(2), ""}}
+	}
+	return nil, nil
+}
+
+func TestTicketServiceDelete_RollsBackOnFailure(t *testing.T) {
+	fake, ticketService := newTicketService(t)
+	fake.FailOn("DELETE FROM tickets")
+
+	err := ticketService.Delete(tenant.System(context.Background()), 1)
+	if !errors.Is(err, repository.ErrInjected) {
+		t.Fatalf("got %v, want injected failure", err)
+	}
+
+	if fake.Calls("UPDATE stock_reservations") != 1 || fake.Calls("DELETE FROM ticket_costs") != 1 ||
+		fake.Calls("DELETE FROM ticket_visits") != 1 {
+		t.Fatalf("expected reservations released, costs and visits deleted before the failure")
+	}
+	repository.AssertRolledBack(t, fake)
+}
+
+func TestTicketServiceUpdate_RollsBackOnFailure(t *testing.T) {
+	fake, ticketService := newTicketService(t)
+	fake.FailOn("INSERT INTO ticket_costs")
+
+	req := &dto.UpdateTicketRequest{
+		Number:      "CH-1",
+		Status:      domain.TicketStatusAgendado,
+		Priority:    "alta",
+		Description: "Sem rede",
+		OpenDate:    "2025-01-02T09:00:00Z",
+		BranchID:    2,
+		SolutionItems: []dto.SolutionItemRequest{
+			{Description: "Cabo de rede", UnitPrice: domain.NewMoney(25), Quantity: 2},
+		},
+	}
+	_, err := ticketService.Update(tenant.System(context.Background()), 1, 1, req)
+	if !errors.Is(err, repository.ErrInjected) {
+		t.Fatalf("got %v, want injected failure", err)
+	}
+
+	if fake.Calls("UPDATE tickets SET") != 1 || fake.Calls("DELETE FROM ticket_costs") != 1 {
+		t.Fatalf("expected the ticket and old costs written before the failure")
+	}
+	repository.AssertRolledBack(t, fake)
+}
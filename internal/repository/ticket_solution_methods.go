@@ -7,30 +7,42 @@ import (
 	"github.com/ericolvr/maintenance-v2/internal/domain"
 )
 
-// AddSolutionToTicket associa uma solution do catálogo a um ticket
-func (r *ticketRepository) AddSolutionToTicket(ctx context.Context, ticketID int, solutionID int, quantity int) error {
-	// Buscar dados da solution e problema para calcular custo
-	var unitPrice float64
-	var solutionName string
+// AddSolutionToTicket grava a linha de custo de uma solution do catálogo no ticket.
+// O service informa ticket, solution, quantidade, visita, preço unitário e garantia;
+// problema e nomes vêm do catálogo. Linhas em garantia não são cobradas.
+func (r *ticketRepository) AddSolutionToTicket(ctx context.Context, cost *domain.TicketCost) error {
+	if cost.SolutionID == nil {
+		return fmt.Errorf("solution is required")
+	}
+
+	if err := checkTenantTicket(ctx, r.db, cost.TicketID); err != nil {
+		return err
+	}
+
+	// Buscar dados da solution e problema
 	var problemID int
-	var problemName string
-	err := r.db.QueryRowContext(ctx, `
-		SELECT s.name, s.unit_price, s.problem_id, p.name 
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT s.name, s.problem_id, p.name 
 		FROM solutions s 
 		JOIN problems p ON s.problem_id = p.id 
 		WHERE s.id = $1
-	`, solutionID).Scan(&solutionName, &unitPrice, &problemID, &problemName)
+	`, *cost.SolutionID).Scan(&cost.SolutionName, &problemID, &cost.ProblemName)
 	if err != nil {
 		return fmt.Errorf("failed to get solution data: %w", err)
 	}
+	cost.ProblemID = &problemID
 
-	subtotal := float64(quantity) * unitPrice
+	cost.Subtotal = cost.UnitPrice.Mul(cost.Quantity)
+	if cost.Warranty {
+		cost.Subtotal = 0
+	}
 
 	// Inserir na tabela ticket_costs
-	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO ticket_costs (ticket_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, ticketID, problemID, problemName, solutionID, solutionName, quantity, unitPrice, subtotal)
+	err = conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO ticket_costs (ticket_id, visit_id, problem_id, problem_name, solution_id, solution_name, quantity, unit_price, subtotal, warranty, warranty_ticket_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`, cost.TicketID, cost.VisitID, cost.ProblemID, cost.ProblemName, cost.SolutionID, cost.SolutionName, cost.Quantity, cost.UnitPrice, cost.Subtotal, cost.Warranty, cost.WarrantyTicketID).Scan(&cost.ID, &cost.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to add solution to ticket: %w", err)
 	}
@@ -45,10 +57,11 @@ func (r *ticketRepository) GetTicketSolutions(ctx context.Context, ticketID int)
 
 // RemoveSolutionFromTicket remove uma solution de um ticket
 func (r *ticketRepository) RemoveSolutionFromTicket(ctx context.Context, ticketID int, solutionID int) error {
-	result, err := r.db.ExecContext(ctx, `
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID, solutionID})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
 		DELETE FROM ticket_costs 
-		WHERE ticket_id = $1 AND solution_id = $2
-	`, ticketID, solutionID)
+		WHERE ticket_id = $1 AND solution_id = $2`+filter,
+		args...)
 	if err != nil {
 		return fmt.Errorf("failed to remove solution from ticket: %w", err)
 	}
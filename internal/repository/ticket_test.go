@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// TestTicketUpdateResetsAssignmentOnProviderChange confere que a atualização do ticket trata
+// assignment_status como assigned_at: novo técnico volta para pending, sem técnico fica vazio
+// e o mesmo técnico mantém o aceite. O banco de teste não executa SQL, então o fake aplica a
+// regra do CASE sobre a linha atual e o teste confere que o UPDATE a envia e devolve o resultado.
+func TestTicketUpdateResetsAssignmentOnProviderChange(t *testing.T) {
+	current := 3
+	other := 4
+
+	tests := []struct {
+		name     string
+		provider *int
+		want     string
+	}{
+		{"same provider keeps the decision", &current, domain.AssignmentAccepted},
+		{"new provider starts pending", &other, domain.AssignmentPending},
+		{"provider removed", nil, ""},
+	}
+
+	for _, tt := range tests {
+		fake, db := newFakeDB(t)
+		fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+			if !strings.HasPrefix(strings.TrimSpace(query), "UPDATE tickets") {
+				return nil, nil
+			}
+			// Linha atual: técnico 3 com a atribuição aceita
+			status := domain.AssignmentAccepted
+			switch provider, _ := args[7].(*int); {
+			case provider == nil:
+				status = ""
+			case *provider != current:
+				status = domain.AssignmentPending
+			}
+			return []string{"version", "assignment_status"}, [][]driver.Value{{int64(2), status}}
+		}
+
+		ticket := &domain.Ticket{ID: 1, BranchID: 1, ProviderID: tt.provider, AssignmentStatus: domain.AssignmentAccepted}
+		if err := NewTicketRepository(db).Update(tenant.System(context.Background()), ticket); err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.name, err)
+		}
+		if ticket.AssignmentStatus != tt.want {
+			t.Errorf("%s: got assignment status %q, want %q", tt.name, ticket.AssignmentStatus, tt.want)
+		}
+
+		update := fake.calls("UPDATE tickets")
+		if len(update) != 1 {
+			t.Fatalf("%s: got %d updates, want 1", tt.name, len(update))
+		}
+		query := strings.Join(strings.Fields(update[0].query), " ")
+		for _, fragment := range []string{
+			"assignment_status = CASE WHEN provider_id IS NOT DISTINCT FROM $8::INTEGER THEN assignment_status",
+			"WHEN $8::INTEGER IS NULL THEN ''",
+			"ELSE 'pending' END",
+		} {
+			if !strings.Contains(query, fragment) {
+				t.Fatalf("%s: update does not reset assignment status: missing %q", tt.name, fragment)
+			}
+		}
+	}
+}
+
+func TestTicketFindByIDNotFoundWrapsErrNotFound(t *testing.T) {
+	_, db := newFakeDB(t)
+
+	_, err := NewTicketRepository(db).FindByID(tenant.System(context.Background()), 99)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
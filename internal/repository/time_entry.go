@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+type TimeEntryRepository interface {
+	Create(ctx context.Context, entry *domain.TicketTimeEntry) (int, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketTimeEntry, error)
+}
+
+type timeEntryRepository struct {
+	db *sql.DB
+}
+
+func NewTimeEntryRepository(db *sql.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(ctx context.Context, entry *domain.TicketTimeEntry) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, entry.TicketID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO ticket_time_entries (ticket_id, provider_id, started_at, ended_at, minutes, note)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		entry.TicketID,
+		entry.ProviderID,
+		entry.StartedAt,
+		entry.EndedAt,
+		entry.Minutes,
+		entry.Note).Scan(&id, &entry.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("error creating time entry: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *timeEntryRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketTimeEntry, error) {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	query := `SELECT id, ticket_id, provider_id, started_at, ended_at, minutes, note, created_at
+			FROM ticket_time_entries WHERE ticket_id = $1` + filter + ` ORDER BY started_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.TicketTimeEntry
+	for rows.Next() {
+		var entry domain.TicketTimeEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TicketID,
+			&entry.ProviderID,
+			&entry.StartedAt,
+			&entry.EndedAt,
+			&entry.Minutes,
+			&entry.Note,
+			&entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// assertRolledBack confere que a unidade de trabalho abriu uma única transação, desfeita
+// por inteiro, e que nenhuma gravação rodou fora dela
+func assertRolledBack(t *testing.T, fake *fakeDB) {
+	t.Helper()
+
+	if fake.begins != 1 || fake.commits != 0 || fake.rollbacks != 1 {
+		t.Fatalf("got %d begins, %d commits, %d rollbacks; want 1, 0, 1", fake.begins, fake.commits, fake.rollbacks)
+	}
+	for _, call := range fake.execs {
+		// Leituras de validação podem rodar antes da unidade de trabalho
+		if !call.inTx && !strings.HasPrefix(strings.TrimSpace(call.query), "SELECT") {
+			t.Fatalf("command ran outside the unit of work: %s", call.query)
+		}
+	}
+}
+
+func TestWithinTx_RollsBackReservationWhenSolutionFails(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.failOn = "INSERT INTO ticket_costs"
+	fake.respond = func(query string, _ []driver.Value) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "FOR UPDATE"):
+			return []string{"available"}, [][]driver.Value{{int64(10)}}
+		case strings.Contains(query, "INSERT INTO stock_reservations"):
+			return []string{"id"}, [][]driver.Value{{int64(1)}}
+		case strings.Contains(query, "FROM solutions s"):
+			return []string{"name", "problem_id", "name"}, [][]driver.Value{{"Troca de cabo", int64(2), "Sem rede"}}
+		}
+		return nil, nil
+	}
+	txManager := NewTxManager(db)
+	ticketRepo := NewTicketRepository(db)
+	inventoryRepo := NewInventoryRepository(db)
+
+	// Mesma sequência de TicketService.AddSolutionToTicket
+	solutionID := 3
+	err := txManager.WithinTx(tenant.System(context.Background()), func(ctx context.Context) error {
+		parts := []domain.SolutionPart{{ItemID: 7, Quantity: 2}}
+		if _, err := inventoryRepo.Reserve(ctx, 1, solutionID, parts, 1, []int{1}); err != nil {
+			return err
+		}
+		return ticketRepo.AddSolutionToTicket(ctx, &domain.TicketCost{TicketID: 1, SolutionID: &solutionID, Quantity: 1})
+	})
+	if !errors.Is(err, errInjected) {
+		t.Fatalf("got %v, want injected failure", err)
+	}
+
+	if len(fake.calls("INSERT INTO stock_reservations")) != 1 || len(fake.calls("INSERT INTO stock_movements")) != 1 {
+		t.Fatalf("expected the reservation to be written before the failure")
+	}
+	assertRolledBack(t, fake)
+}
+
+func TestWithinTx_NestedUnitOfWorkCommitsOnce(t *testing.T) {
+	fake, db := newFakeDB(t)
+	txManager := NewTxManager(db)
+	ticketRepo := NewTicketRepository(db)
+	inventoryRepo := NewInventoryRepository(db)
+
+	err := txManager.WithinTx(tenant.System(context.Background()), func(ctx context.Context) error {
+		if err := ticketRepo.DeleteTicketCosts(ctx, 1); err != nil {
+			return err
+		}
+		// Unidade de trabalho aninhada e repositório com transação própria participam da de fora
+		return txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return inventoryRepo.ConsumeReservations(ctx, 1)
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fake.begins != 1 || fake.commits != 1 || fake.rollbacks != 0 {
+		t.Fatalf("got %d begins, %d commits, %d rollbacks; want 1, 1, 0", fake.begins, fake.commits, fake.rollbacks)
+	}
+}
+
+func TestWithinTx_ErrorAfterWritesRollsBack(t *testing.T) {
+	fake, db := newFakeDB(t)
+	txManager := NewTxManager(db)
+	ticketRepo := NewTicketRepository(db)
+
+	// Falha da regra de negócio depois das gravações também desfaz tudo
+	errBusiness := errors.New("business rule failed")
+	err := txManager.WithinTx(tenant.System(context.Background()), func(ctx context.Context) error {
+		if err := ticketRepo.UpdateStatus(ctx, 1, domain.TicketStatusConcluido); err != nil {
+			return err
+		}
+		return errBusiness
+	})
+	if !errors.Is(err, errBusiness) {
+		t.Fatalf("got %v, want business error", err)
+	}
+
+	assertRolledBack(t, fake)
+}
+
+func TestRepositoryTransactionOutsideUnitOfWork(t *testing.T) {
+	fake, db := newFakeDB(t)
+	fake.respond = func(query string, _ []driver.Value) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "FOR UPDATE") {
+			return []string{"available"}, [][]driver.Value{{int64(10)}}
+		}
+		if strings.Contains(query, "INSERT INTO stock_reservations") {
+			return []string{"id"}, [][]driver.Value{{int64(1)}}
+		}
+		return nil, nil
+	}
+	inventoryRepo := NewInventoryRepository(db)
+
+	// Sem unidade de trabalho o método confirma a própria transação
+	parts := []domain.SolutionPart{{ItemID: 7, Quantity: 1}}
+	if _, err := inventoryRepo.Reserve(tenant.System(context.Background()), 1, 3, parts, 1, []int{1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fake.begins != 1 || fake.commits != 1 {
+		t.Fatalf("got %d begins, %d commits; want 1, 1", fake.begins, fake.commits)
+	}
+}
@@ -33,17 +33,23 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) (int, error) {
-	query := `INSERT INTO users (name, mobile, password, role, status) 
-            VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	if err := checkTenantOptional(ctx, user.ClientID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO users (name, mobile, password, role, status, provider_id, client_id) 
+            VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 
 	var id int
 	var err error
-	err = r.db.QueryRowContext(ctx, query,
+	err = conn(ctx, r.db).QueryRowContext(ctx, query,
 		user.Name,
 		user.Mobile,
 		user.Password,
 		user.Role,
-		user.Status).Scan(&id)
+		user.Status,
+		user.ProviderID,
+		user.ClientID).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("error creating user: %w", err)
 	}
@@ -52,9 +58,11 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) (int, er
 }
 
 func (r *userRepository) List(ctx context.Context) ([]domain.User, error) {
-	query := `SELECT id, name, mobile, password, role, status FROM users ORDER BY name ASC`
+	query := `SELECT id, name, mobile, password, role, status, provider_id, client_id FROM users WHERE TRUE`
+	filter, args := tenantClient(ctx, "client_id", nil)
+	query += filter + ` ORDER BY name ASC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error listing users: %w", err)
 	}
@@ -69,7 +77,9 @@ func (r *userRepository) List(ctx context.Context) ([]domain.User, error) {
 			&user.Mobile,
 			&user.Password,
 			&user.Role,
-			&user.Status); err != nil {
+			&user.Status,
+			&user.ProviderID,
+			&user.ClientID); err != nil {
 			return nil, fmt.Errorf("error scanning user: %w", err)
 		}
 		users = append(users, user)
@@ -83,16 +93,19 @@ func (r *userRepository) List(ctx context.Context) ([]domain.User, error) {
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
-	query := `SELECT id, name, mobile, password, role, status FROM users WHERE id = $1`
+	query := `SELECT id, name, mobile, password, role, status, provider_id, client_id FROM users WHERE id = $1`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
 	var user domain.User
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query+filter, args...).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Mobile,
 		&user.Password,
 		&user.Role,
 		&user.Status,
+		&user.ProviderID,
+		&user.ClientID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -105,9 +118,10 @@ func (r *userRepository) FindByID(ctx context.Context, id int) (*domain.User, er
 }
 
 func (r *userRepository) FindByName(ctx context.Context, name string) ([]domain.User, error) {
-	query := `SELECT id, name, mobile, password, role, status FROM users WHERE name = $1`
+	query := `SELECT id, name, mobile, password, role, status, provider_id, client_id FROM users WHERE name = $1`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{name})
 
-	rows, err := r.db.QueryContext(ctx, query, name)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+filter, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error finding users by name: %w", err)
 	}
@@ -122,7 +136,9 @@ func (r *userRepository) FindByName(ctx context.Context, name string) ([]domain.
 			&user.Mobile,
 			&user.Password,
 			&user.Role,
-			&user.Status); err != nil {
+			&user.Status,
+			&user.ProviderID,
+			&user.ClientID); err != nil {
 			return nil, fmt.Errorf("error scanning user: %w", err)
 		}
 		users = append(users, user)
@@ -136,9 +152,10 @@ func (r *userRepository) FindByName(ctx context.Context, name string) ([]domain.
 }
 
 func (r *userRepository) FindByMobile(ctx context.Context, mobile string) ([]domain.User, error) {
-	query := `SELECT id, name, mobile, password, role, status FROM users WHERE mobile = $1`
+	query := `SELECT id, name, mobile, password, role, status, provider_id, client_id FROM users WHERE mobile = $1`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{mobile})
 
-	rows, err := r.db.QueryContext(ctx, query, mobile)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+filter, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error finding users by mobile: %w", err)
 	}
@@ -153,7 +170,9 @@ func (r *userRepository) FindByMobile(ctx context.Context, mobile string) ([]dom
 			&user.Mobile,
 			&user.Password,
 			&user.Role,
-			&user.Status); err != nil {
+			&user.Status,
+			&user.ProviderID,
+			&user.ClientID); err != nil {
 			return nil, fmt.Errorf("error scanning user: %w", err)
 		}
 		users = append(users, user)
@@ -167,21 +186,32 @@ func (r *userRepository) FindByMobile(ctx context.Context, mobile string) ([]dom
 }
 
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	if err := checkTenantOptional(ctx, user.ClientID); err != nil {
+		return err
+	}
+
+	// Senha vazia mantém a atual
 	query := `UPDATE users SET 
 			name = $1, 
 			mobile = $2, 
-			password = $3, 
+			password = COALESCE(NULLIF($3, ''), password), 
 			role = $4, 
-			status = $5 
-		WHERE id = $6`
-
-	result, err := r.db.ExecContext(ctx, query,
+			status = $5, 
+			provider_id = $6, 
+			client_id = $7 
+		WHERE id = $8`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{
 		user.Name,
 		user.Mobile,
 		user.Password,
 		user.Role,
 		user.Status,
-		user.ID)
+		user.ProviderID,
+		user.ClientID,
+		user.ID,
+	})
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query+filter, args...)
 	if err != nil {
 		return fmt.Errorf("error updating user: %w", err)
 	}
@@ -200,8 +230,9 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 
 func (r *userRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM users WHERE id = $1`
+	filter, args := tenantClient(ctx, "client_id", []interface{}{id})
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query+filter, args...)
 	if err != nil {
 		return fmt.Errorf("error deleting user: %w", err)
 	}
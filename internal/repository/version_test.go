@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// TestVersionedUpdates confere o If-Match nos UPDATEs de ticket, agência e prestador. O banco de
+// teste não executa SQL: o fake aplica a condição "($N = 0 OR version = $N)" sobre o registro 1,
+// que está na versão 3, e devolve a versão incrementada.
+func TestVersionedUpdates(t *testing.T) {
+	updates := []struct {
+		table    string
+		notFound error
+		update   func(ctx context.Context, db *sql.DB, id, version int) (int, error)
+	}{
+		{"tickets", ErrNotFound, func(ctx context.Context, db *sql.DB, id, version int) (int, error) {
+			ticket := &domain.Ticket{ID: id, BranchID: 1, Version: version}
+			err := NewTicketRepository(db).Update(ctx, ticket)
+			return ticket.Version, err
+		}},
+		{"branchs", ErrBranchNotFound, func(ctx context.Context, db *sql.DB, id, version int) (int, error) {
+			branch := &domain.Branch{ID: id, Version: version}
+			err := NewBranchRepository(db).Update(ctx, branch)
+			return branch.Version, err
+		}},
+		{"providers", ErrProviderNotFound, func(ctx context.Context, db *sql.DB, id, version int) (int, error) {
+			provider := &domain.Provider{ID: id, Version: version}
+			err := NewProviderRepository(db).Update(ctx, provider)
+			return provider.Version, err
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		id      int
+		version int
+		want    error
+		missing bool // Registro inexistente: espera o erro de não encontrado da tabela
+	}{
+		// Sem If-Match a versão não é conferida
+		{"without version", 1, 0, nil, false},
+		{"current version", 1, 3, nil, false},
+		{"stale version", 1, 2, ErrVersionConflict, false},
+		{"missing record", 2, 0, nil, true},
+	}
+
+	for _, update := range updates {
+		for _, tt := range tests {
+			t.Run(update.table+" "+tt.name, func(t *testing.T) {
+				fake, db := newFakeDB(t)
+				fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+					if !strings.HasPrefix(strings.TrimSpace(query), "UPDATE "+update.table) {
+						return nil, nil
+					}
+					// Sem tenant, o id e a versão são os dois últimos argumentos
+					id, version := fmt.Sprint(args[len(args)-2]), fmt.Sprint(args[len(args)-1])
+					if id != "1" || (version != "0" && version != "3") {
+						return nil, nil
+					}
+					if update.table == "tickets" {
+						return []string{"version", "assignment_status"}, [][]driver.Value{{int64(4), ""}}
+					}
+					return []string{"version"}, [][]driver.Value{{int64(4)}}
+				}
+
+				want := tt.want
+				if tt.missing {
+					want = update.notFound
+				}
+				version, err := update.update(tenant.System(context.Background()), db, tt.id, tt.version)
+				if !errors.Is(err, want) {
+					t.Fatalf("got %v, want %v", err, want)
+				}
+				if err == nil && version != 4 {
+					t.Fatalf("got version %d, want 4", version)
+				}
+				assertPlaceholders(t, fake.calls("UPDATE "+update.table))
+			})
+		}
+	}
+}
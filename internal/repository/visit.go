@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrVisitNotFound = errors.New("visit not found")
+	ErrVisitOverlap  = errors.New("visit overlaps another one for the same provider")
+)
+
+// Código do PostgreSQL para violação de exclusion constraint
+const exclusionViolation = "23P01"
+
+type VisitRepository interface {
+	Create(ctx context.Context, visit *domain.Visit) (int, error)
+	Update(ctx context.Context, visit *domain.Visit) error
+	FindByID(ctx context.Context, id int) (*domain.Visit, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.Visit, error)
+	ListByProvider(ctx context.Context, providerID int, from, to time.Time) ([]domain.Visit, error)
+	FindConflicts(ctx context.Context, providerID int, start, end time.Time, excludeID int) ([]domain.Visit, error)
+	Delete(ctx context.Context, id int) error
+	DeleteOpenByProvider(ctx context.Context, ticketID, providerID int) error
+	DeleteByTicket(ctx context.Context, ticketID int) error
+}
+
+type visitRepository struct {
+	db *sql.DB
+}
+
+func NewVisitRepository(db *sql.DB) VisitRepository {
+	return &visitRepository{db: db}
+}
+
+const visitSelect = `SELECT v.id, v.ticket_id, v.provider_id, v.starts_at, v.ends_at, v.distance_km, v.travel_cost, v.notes, v.created_at,
+	p.name, t.number, b.id, b.name, COALESCE(b.address, ''), COALESCE(b.city, ''),
+	(SELECT COUNT(*) FROM ticket_costs c WHERE c.visit_id = v.id),
+	COALESCE((SELECT SUM(c.subtotal) FROM ticket_costs c WHERE c.visit_id = v.id), 0)
+	FROM ticket_visits v
+	JOIN providers p ON p.id = v.provider_id
+	JOIN tickets t ON t.id = v.ticket_id
+	JOIN branchs b ON b.id = t.branch_id`
+
+func (r *visitRepository) Create(ctx context.Context, visit *domain.Visit) (int, error) {
+	if err := checkTenantTicket(ctx, r.db, visit.TicketID); err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO ticket_visits (ticket_id, provider_id, starts_at, ends_at, distance_km, travel_cost, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		visit.TicketID,
+		visit.ProviderID,
+		visit.StartsAt,
+		visit.EndsAt,
+		visit.DistanceKm,
+		visit.TravelCost,
+		visit.Notes).Scan(&id)
+	if err != nil {
+		if isExclusionViolation(err) {
+			return 0, ErrVisitOverlap
+		}
+		return 0, fmt.Errorf("error creating visit: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *visitRepository) Update(ctx context.Context, visit *domain.Visit) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{
+		visit.StartsAt,
+		visit.EndsAt,
+		visit.DistanceKm,
+		visit.TravelCost,
+		visit.Notes,
+		visit.ID,
+	})
+	query := `UPDATE ticket_visits SET
+			starts_at = $1,
+			ends_at = $2,
+			distance_km = $3,
+			travel_cost = $4,
+			notes = $5,
+			updated_at = CURRENT_TIMESTAMP
+			WHERE id = $6` + filter
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		if isExclusionViolation(err) {
+			return ErrVisitOverlap
+		}
+		return fmt.Errorf("error updating visit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrVisitNotFound
+	}
+
+	return nil
+}
+
+func (r *visitRepository) FindByID(ctx context.Context, id int) (*domain.Visit, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{id})
+	query := visitSelect + ` WHERE v.id = $1` + filter
+
+	visit, err := scanVisit(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVisitNotFound
+		}
+		return nil, fmt.Errorf("error finding visit: %w", err)
+	}
+
+	return visit, nil
+}
+
+func (r *visitRepository) ListByTicket(ctx context.Context, ticketID int) ([]domain.Visit, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{ticketID})
+	query := visitSelect + ` WHERE v.ticket_id = $1` + filter + ` ORDER BY v.starts_at NULLS LAST, v.id`
+
+	return r.list(ctx, query, args...)
+}
+
+// ListByProvider retorna as visitas agendadas do técnico que tocam o intervalo [from, to)
+func (r *visitRepository) ListByProvider(ctx context.Context, providerID int, from, to time.Time) ([]domain.Visit, error) {
+	filter, args := tenantClient(ctx, "b.client_id", []interface{}{providerID, from, to})
+	query := visitSelect + ` WHERE v.provider_id = $1 AND v.starts_at < $3 AND v.ends_at > $2` + filter + ` ORDER BY v.starts_at`
+
+	return r.list(ctx, query, args...)
+}
+
+// FindConflicts retorna as visitas do técnico que se sobrepõem ao intervalo, ignorando excludeID.
+// Não é filtrado por cliente: a agenda do técnico é uma só para todos os clientes.
+func (r *visitRepository) FindConflicts(ctx context.Context, providerID int, start, end time.Time, excludeID int) ([]domain.Visit, error) {
+	query := visitSelect + ` WHERE v.provider_id = $1 AND v.starts_at < $3 AND v.ends_at > $2 AND v.id <> $4 ORDER BY v.starts_at`
+
+	return r.list(ctx, query, providerID, start, end, excludeID)
+}
+
+func (r *visitRepository) Delete(ctx context.Context, id int) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{id})
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM ticket_visits WHERE id = $1`+filter, args...)
+	if err != nil {
+		return fmt.Errorf("error deleting visit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrVisitNotFound
+	}
+
+	return nil
+}
+
+// DeleteOpenByProvider remove as visitas do técnico no ticket que ainda não aconteceram
+// (sem data ou com início futuro) e não têm soluções aplicadas
+func (r *visitRepository) DeleteOpenByProvider(ctx context.Context, ticketID, providerID int) error {
+	filter, args := tenantTicket(ctx, "v.ticket_id", []interface{}{ticketID, providerID})
+	query := `DELETE FROM ticket_visits v
+			WHERE v.ticket_id = $1 AND v.provider_id = $2
+			AND (v.starts_at IS NULL OR v.starts_at > CURRENT_TIMESTAMP)
+			AND NOT EXISTS (SELECT 1 FROM ticket_costs c WHERE c.visit_id = v.id)` + filter
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error deleting open visits: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByTicket remove todas as visitas do ticket, liberando a agenda do técnico
+func (r *visitRepository) DeleteByTicket(ctx context.Context, ticketID int) error {
+	filter, args := tenantTicket(ctx, "ticket_id", []interface{}{ticketID})
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM ticket_visits WHERE ticket_id = $1`+filter, args...); err != nil {
+		return fmt.Errorf("error deleting ticket visits: %w", err)
+	}
+
+	return nil
+}
+
+func (r *visitRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Visit, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing visits: %w", err)
+	}
+	defer rows.Close()
+
+	var visits []domain.Visit
+	for rows.Next() {
+		visit, err := scanVisit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning visit: %w", err)
+		}
+		visits = append(visits, *visit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating visits: %w", err)
+	}
+
+	return visits, nil
+}
+
+func scanVisit(row rowScanner) (*domain.Visit, error) {
+	var visit domain.Visit
+
+	err := row.Scan(
+		&visit.ID,
+		&visit.TicketID,
+		&visit.ProviderID,
+		&visit.StartsAt,
+		&visit.EndsAt,
+		&visit.DistanceKm,
+		&visit.TravelCost,
+		&visit.Notes,
+		&visit.CreatedAt,
+		&visit.ProviderName,
+		&visit.TicketNumber,
+		&visit.BranchID,
+		&visit.BranchName,
+		&visit.BranchAddress,
+		&visit.BranchCity,
+		&visit.SolutionsCount,
+		&visit.SolutionsTotal,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &visit, nil
+}
+
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == exclusionViolation
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+)
+
+type WarrantyRepository interface {
+	FindActiveWarranty(ctx context.Context, ticket *domain.Ticket, problemID int, assetID *int) (*int, error)
+	ReturnsByProvider(ctx context.Context, from, to time.Time) ([]domain.WarrantyReturn, error)
+}
+
+type warrantyRepository struct {
+	db *sql.DB
+}
+
+func NewWarrantyRepository(db *sql.DB) WarrantyRepository {
+	return &warrantyRepository{db: db}
+}
+
+// FindActiveWarranty retorna o ticket anterior cuja solução ainda está na garantia na abertura do ticket:
+// mesmo equipamento, ou mesma agência e mesmo problema. Linhas já em garantia não renovam o prazo.
+func (r *warrantyRepository) FindActiveWarranty(ctx context.Context, ticket *domain.Ticket, problemID int, assetID *int) (*int, error) {
+	var originalTicketID int
+	filter, args := tenantBranch(ctx, "t.branch_id", []interface{}{ticket.ID, ticket.OpenDate, assetID, ticket.BranchID, problemID})
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT tc.ticket_id
+		 FROM ticket_costs tc
+		 JOIN tickets t ON t.id = tc.ticket_id
+		 JOIN solutions s ON s.id = tc.solution_id
+		 WHERE tc.ticket_id <> $1
+			AND tc.warranty = FALSE
+			AND s.warranty_days > 0
+			AND tc.created_at <= $2
+			AND tc.created_at + s.warranty_days * INTERVAL '1 day' >= $2
+			AND (
+				($3::INTEGER IS NOT NULL AND (
+					t.asset_id = $3
+					OR EXISTS (SELECT 1 FROM ticket_problems tp WHERE tp.ticket_id = t.id AND tp.asset_id = $3)
+				))
+				OR (t.branch_id = $4 AND tc.problem_id = $5)
+			)`+filter+`
+		 ORDER BY tc.created_at DESC
+		 LIMIT 1`,
+		args...).Scan(&originalTicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding active warranty: %w", err)
+	}
+
+	return &originalTicketID, nil
+}
+
+// ReturnsByProvider agrupa os retrabalhos em garantia lançados no período pelo técnico do ticket original
+func (r *warrantyRepository) ReturnsByProvider(ctx context.Context, from, to time.Time) ([]domain.WarrantyReturn, error) {
+	filter, args := tenantBranch(ctx, "original.branch_id", []interface{}{from, to})
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT p.id, p.name,
+			COUNT(*),
+			COUNT(DISTINCT tc.warranty_ticket_id),
+			COUNT(DISTINCT tc.ticket_id),
+			COALESCE(SUM(tc.quantity * tc.unit_price), 0)
+		 FROM ticket_costs tc
+		 JOIN tickets original ON original.id = tc.warranty_ticket_id
+		 JOIN providers p ON p.id = original.provider_id
+		 WHERE tc.warranty = TRUE
+			AND tc.created_at >= $1
+			AND tc.created_at < $2`+filter+`
+		 GROUP BY p.id, p.name
+		 ORDER BY COUNT(*) DESC, p.name ASC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing warranty returns: %w", err)
+	}
+	defer rows.Close()
+
+	var returns []domain.WarrantyReturn
+	for rows.Next() {
+		var item domain.WarrantyReturn
+		if err := rows.Scan(
+			&item.ProviderID,
+			&item.ProviderName,
+			&item.Returns,
+			&item.OriginalTickets,
+			&item.ReworkTickets,
+			&item.WaivedAmount,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning warranty return: %w", err)
+		}
+		returns = append(returns, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating warranty returns: %w", err)
+	}
+
+	return returns, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+func TestWarrantyFindActiveWarranty(t *testing.T) {
+	original := 40
+	ticket := &domain.Ticket{ID: 41, BranchID: 2, OpenDate: time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name string
+		rows [][]driver.Value
+		want *int
+	}{
+		{"within warranty", [][]driver.Value{{int64(40)}}, &original},
+		// Sem garantia vigente o ticket é cobrado normalmente
+		{"no warranty", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				return []string{"ticket_id"}, tt.rows
+			}
+
+			got, err := NewWarrantyRepository(db).FindActiveWarranty(tenant.System(context.Background()), ticket, 1, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+
+			query := fake.calls("FROM ticket_costs")
+			if len(query) != 1 {
+				t.Fatalf("got %d queries, want 1", len(query))
+			}
+			// Linhas já em garantia não renovam o prazo
+			if !strings.Contains(query[0].query, "tc.warranty = FALSE") {
+				t.Fatalf("query must ignore warranty rows: %s", query[0].query)
+			}
+		})
+	}
+}
+
+func TestTicketAddSolutionZeroesWarrantyRework(t *testing.T) {
+	solutionID, original := 5, 40
+
+	tests := []struct {
+		name     string
+		warranty bool
+		subtotal domain.Money
+	}{
+		{"charged", false, domain.NewMoney(300)},
+		{"warranty rework", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, db := newFakeDB(t)
+			fake.respond = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+				switch {
+				case strings.Contains(query, "FROM solutions s"):
+					return []string{"name", "problem_id", "name"}, [][]driver.Value{{"Troca de fonte", int64(1), "Sem energia"}}
+				case strings.Contains(query, "INSERT INTO ticket_costs"):
+					return []string{"id", "created_at"}, [][]driver.Value{{int64(9), time.Now()}}
+				}
+				return nil, nil
+			}
+
+			cost := &domain.TicketCost{TicketID: 41, SolutionID: &solutionID, Quantity: 2, UnitPrice: domain.NewMoney(150), Warranty: tt.warranty}
+			if tt.warranty {
+				cost.WarrantyTicketID = &original
+			}
+			if err := NewTicketRepository(db).AddSolutionToTicket(tenant.System(context.Background()), cost); err != nil {
+				t.Fatal(err)
+			}
+
+			if cost.Subtotal != tt.subtotal {
+				t.Fatalf("got subtotal %v, want %v", cost.Subtotal, tt.subtotal)
+			}
+			// O preço unitário é gravado mesmo em garantia, para o relatório de retornos
+			insert := fake.calls("INSERT INTO ticket_costs")
+			if len(insert) != 1 || insert[0].args[7] != domain.NewMoney(150) || insert[0].args[8] != tt.subtotal {
+				t.Fatalf("got insert %v, want unit price 150.00 and subtotal %v", insert, tt.subtotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func AnalyticsRoutes(router *gin.Engine, handler *handlers.AnalyticsHandler) {
+	reports := router.Group("/api/v1/reports")
+	{
+		reports.GET("/problems", handler.GetProblemAnalytics)
+		reports.GET("/solutions/unused", handler.GetUnusedSolutions)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func AssetRoutes(router *gin.Engine, handler *handlers.AssetHandler) {
+	assets := router.Group("/api/v1/assets")
+	{
+		assets.POST("", handler.CreateAsset)
+		assets.GET("", handler.ListAssets)
+		assets.GET("/:id", handler.GetAsset)
+		assets.PUT("/:id", handler.UpdateAsset)
+		assets.DELETE("/:id", handler.DeleteAsset)
+		assets.GET("/:id/history", handler.GetAssetHistory)
+	}
+
+	branchs := router.Group("/api/v1/branchs")
+	{
+		branchs.GET("/:id/assets", handler.GetBranchAssets)
+	}
+}
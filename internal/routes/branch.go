@@ -16,6 +16,7 @@ func BranchRoutes(
 		routes.GET("/:id", branchHandler.FindByID)
 		routes.GET("/client/:client", branchHandler.GetByClient)
 		routes.PUT("/:id", branchHandler.Update)
+		routes.PATCH("/:id", branchHandler.Patch)
 		routes.DELETE("/:id", branchHandler.Delete)
 	}
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func ClientPortalRoutes(
+	router *gin.Engine,
+	portalHandler *handlers.ClientPortalHandler,
+	jwtSecret []byte,
+) {
+	portal := router.Group("/api/v1/client")
+	portal.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleCliente))
+	{
+		portal.GET("/branchs", portalHandler.ListBranches)
+		portal.GET("/tickets", portalHandler.ListTickets)
+		portal.POST("/tickets", portalHandler.OpenTicket)
+		portal.GET("/tickets/:id", portalHandler.GetTicket)
+		portal.GET("/tickets/:id/comments", portalHandler.ListComments)
+		portal.POST("/tickets/:id/comments", portalHandler.AddComment)
+		portal.GET("/tickets/:id/attachments", portalHandler.ListAttachments)
+		portal.POST("/tickets/:id/attachments", portalHandler.UploadAttachment)
+		portal.GET("/tickets/:id/attachments/:attachment_id", portalHandler.DownloadAttachment)
+		portal.POST("/tickets/:id/rating", portalHandler.RateTicket)
+		portal.GET("/tickets/:id/quotes", portalHandler.ListQuotes)
+		portal.POST("/tickets/:id/quotes/:quote_id/approve", portalHandler.ApproveQuote)
+		portal.POST("/tickets/:id/quotes/:quote_id/reject", portalHandler.RejectQuote)
+	}
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func ContractRoutes(router *gin.Engine, handler *handlers.ContractHandler) {
+	contracts := router.Group("/api/v1/contracts")
+	{
+		contracts.POST("", handler.CreateContract)
+		contracts.GET("", handler.ListContracts)
+		contracts.GET("/:id", handler.GetContract)
+		contracts.PUT("/:id", handler.UpdateContract)
+		contracts.DELETE("/:id", handler.DeleteContract)
+	}
+
+	clients := router.Group("/api/v1/clients")
+	{
+		clients.GET("/:id/contracts", handler.GetClientContracts)
+	}
+
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.GET("/:id/contract", handler.GetTicketContract)
+	}
+}
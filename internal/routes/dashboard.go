@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func DashboardRoutes(router *gin.Engine, handler *handlers.DashboardHandler) {
+	router.GET("/api/v1/dashboard", handler.GetDashboard)
+}
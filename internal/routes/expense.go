@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// ExpenseRoutes registra a consulta das prestações de contas e a revisão pelo Financeiro
+func ExpenseRoutes(router *gin.Engine, handler *handlers.ExpenseHandler, jwtSecret []byte) {
+	expenses := router.Group("/api/v1/expenses")
+	{
+		expenses.GET("", handler.ListExpenses)
+		expenses.GET("/:id", handler.GetExpense)
+	}
+
+	review := router.Group("/api/v1/expenses")
+	review.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleFinanceiro, domain.RoleAdmin))
+	{
+		review.POST("/:id/review", handler.ReviewExpense)
+	}
+
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.GET("/:id/expenses", handler.ListTicketExpenses)
+	}
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func InventoryRoutes(router *gin.Engine, handler *handlers.InventoryHandler) {
+	inventory := router.Group("/api/v1/inventory")
+	{
+		inventory.POST("/items", handler.CreateItem)
+		inventory.GET("/items", handler.ListItems)
+		inventory.GET("/items/:id", handler.GetItem)
+		inventory.PUT("/items/:id", handler.UpdateItem)
+		inventory.GET("/items/:id/movements", handler.GetItemMovements)
+
+		inventory.POST("/locations", handler.CreateLocation)
+		inventory.GET("/locations", handler.ListLocations)
+
+		inventory.POST("/movements", handler.RecordMovement)
+		inventory.POST("/transfers", handler.Transfer)
+
+		inventory.GET("/reports/low-stock", handler.GetLowStock)
+	}
+
+	solutions := router.Group("/api/v1/solutions")
+	{
+		solutions.GET("/:id/parts", handler.GetSolutionParts)
+		solutions.PUT("/:id/parts", handler.SetSolutionParts)
+	}
+
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.GET("/:id/reservations", handler.GetTicketReservations)
+	}
+}
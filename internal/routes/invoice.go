@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceRoutes registra o faturamento de clientes e a emissão de NFS-e, restritos a Financeiro e Admin
+func InvoiceRoutes(router *gin.Engine, handler *handlers.InvoiceHandler, jwtSecret []byte) {
+	invoices := router.Group("/api/v1/invoices")
+	invoices.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleFinanceiro, domain.RoleAdmin))
+	{
+		invoices.POST("", handler.CreateInvoice)
+		invoices.GET("", handler.ListInvoices)
+		invoices.GET("/:id", handler.GetInvoice)
+		invoices.DELETE("/:id", handler.DeleteInvoice)
+		invoices.POST("/:id/issue", handler.IssueInvoice)
+		invoices.POST("/:id/cancel", handler.CancelInvoice)
+		invoices.GET("/:id/xml", handler.GetInvoiceXML)
+	}
+}
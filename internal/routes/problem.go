@@ -12,6 +12,7 @@ func ProblemRoutes(router *gin.Engine, handler *handlers.ProblemHandler) {
 		v1.GET("/problems", handler.List)
 		v1.GET("/problems/:id", handler.GetByID)
 		v1.PUT("/problems/:id", handler.Update)
+		v1.PATCH("/problems/:id", handler.Patch)
 		v1.DELETE("/problems/:id", handler.Delete)
 	}
 }
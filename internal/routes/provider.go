@@ -16,6 +16,7 @@ func ProviderRoutes(
 		routes.GET("/:id", providerHandler.FindByID)
 		routes.GET("/name/:name", providerHandler.FindByName)
 		routes.PUT("/:id", providerHandler.Update)
+		routes.PATCH("/:id", providerHandler.Patch)
 		routes.DELETE("/:id", providerHandler.Delete)
 	}
 }
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func ProviderPortalRoutes(
+	router *gin.Engine,
+	portalHandler *handlers.ProviderPortalHandler,
+	jwtSecret []byte,
+) {
+	me := router.Group("/api/v1/me/tickets")
+	me.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleTecnico))
+	{
+		me.GET("", portalHandler.ListMyTickets)
+		me.GET("/:id", portalHandler.GetMyTicket)
+		me.POST("/:id/accept", portalHandler.AcceptTicket)
+		me.POST("/:id/decline", portalHandler.DeclineTicket)
+		me.POST("/:id/status", portalHandler.UpdateTicketStatus)
+		me.POST("/:id/attachments", portalHandler.UploadAttachment)
+		me.POST("/:id/time", portalHandler.RecordTime)
+		me.GET("/:id/time", portalHandler.ListTimeEntries)
+		me.POST("/:id/expenses", portalHandler.SubmitExpense)
+		me.GET("/:id/expenses", portalHandler.ListExpenses)
+	}
+
+	calendar := router.Group("/api/v1/me/calendar")
+	calendar.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleTecnico))
+	{
+		calendar.GET("/feed", portalHandler.GetCalendarFeedURL)
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func PurchaseRoutes(router *gin.Engine, handler *handlers.PurchaseHandler, jwtSecret []byte) {
+	suppliers := router.Group("/api/v1/suppliers")
+	{
+		suppliers.POST("", handler.CreateSupplier)
+		suppliers.GET("", handler.ListSuppliers)
+		suppliers.GET("/:id", handler.GetSupplier)
+		suppliers.PUT("/:id", handler.UpdateSupplier)
+	}
+
+	purchases := router.Group("/api/v1/purchase-requests")
+	{
+		purchases.POST("", handler.CreatePurchaseRequest)
+		purchases.GET("", handler.ListPurchaseRequests)
+		purchases.GET("/:id", handler.GetPurchaseRequest)
+		purchases.PUT("/:id", handler.UpdatePurchaseRequest)
+		purchases.POST("/:id/receive", handler.ReceivePurchaseRequest)
+	}
+
+	// Aprovação restrita ao Financeiro
+	approvals := router.Group("/api/v1/purchase-requests")
+	approvals.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleFinanceiro))
+	{
+		approvals.POST("/:id/approve", handler.ApprovePurchaseRequest)
+		approvals.POST("/:id/reject", handler.RejectPurchaseRequest)
+	}
+
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.GET("/:id/purchase-requests", handler.GetTicketPurchaseRequests)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteRoutes registra os orçamentos do ticket e a aprovação pelo link assinado enviado ao cliente
+func QuoteRoutes(router *gin.Engine, handler *handlers.QuoteHandler) {
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.POST("/:id/quotes", handler.GenerateQuote)
+		tickets.GET("/:id/quotes", handler.ListQuotes)
+		tickets.GET("/:id/quotes/:quote_id", handler.GetQuote)
+		tickets.POST("/:id/quotes/:quote_id/send", handler.SendQuote)
+	}
+
+	// O token do link identifica o orçamento; não há login
+	quotes := router.Group("/api/v1/quotes")
+	{
+		quotes.GET("/:token", handler.GetQuoteByToken)
+		quotes.POST("/:token/approve", handler.ApproveQuoteByToken)
+		quotes.POST("/:token/reject", handler.RejectQuoteByToken)
+	}
+}
@@ -0,0 +1,221 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+var testSecret = []byte("test-secret")
+
+func testToken(t *testing.T, role int) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": 1, "name": "Teste", "role": role}).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestInternalTicketAndUserRoutesRejectTechnicians(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Handlers vazios: um acesso liberado chega ao handler e vira 500 pelo Recovery
+	router.Use(gin.Recovery())
+	TicketRoutes(router, &handlers.TicketHandler{}, &handlers.TicketProblemHandler{}, &handlers.TicketSolutionHandler{}, testSecret)
+	UserRoutes(router, &handlers.UserHandler{}, testSecret)
+
+	technician := testToken(t, domain.RoleTecnico)
+	support := testToken(t, domain.RoleSuporte)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		auth      string
+		forbidden bool
+	}{
+		{"technician lists every ticket", http.MethodGet, "/api/v1/tickets", technician, true},
+		{"technician updates a ticket", http.MethodPut, "/api/v1/tickets/1", technician, true},
+		{"technician patches a ticket", http.MethodPatch, "/api/v1/tickets/1", technician, true},
+		{"technician adds a solution", http.MethodPost, "/api/v1/tickets/1/solutions", technician, true},
+		{"technician changes own user", http.MethodPut, "/api/v1/users/3", technician, true},
+		{"technician patches own provider link", http.MethodPatch, "/api/v1/users/3", technician, true},
+		{"support lists tickets", http.MethodGet, "/api/v1/tickets", support, false},
+		{"support updates a user", http.MethodPut, "/api/v1/users/3", support, false},
+		{"login stays open to technicians", http.MethodPost, "/api/v1/users/auth", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if forbidden := w.Code == http.StatusForbidden; forbidden != tt.forbidden {
+				t.Fatalf("got status %d, want forbidden=%v", w.Code, tt.forbidden)
+			}
+		})
+	}
+}
+
+// fakeVersionTicketService guarda o ticket 1 na versão 3 e confere a versão como o ticketService
+type fakeVersionTicketService struct {
+	service.TicketService
+	updates  int
+	received int                      // Versão recebida na última atualização
+	request  *dto.UpdateTicketRequest // Request recebido na última atualização
+}
+
+func (f *fakeVersionTicketService) FindByID(ctx context.Context, id int) (*dto.TicketResponse, error) {
+	return &dto.TicketResponse{ID: id, Number: "T-1", Status: 1, Priority: "alta", Description: "Porta travada", BranchID: 1, Version: 3}, nil
+}
+
+func (f *fakeVersionTicketService) Update(ctx context.Context, id int, version int, req *dto.UpdateTicketRequest) (*dto.TicketResponse, error) {
+	f.updates++
+	f.received = version
+	f.request = req
+	if version > 0 && version != 3 {
+		return nil, repository.ErrVersionConflict
+	}
+	return &dto.TicketResponse{ID: id, Number: req.Number, Version: 4}, nil
+}
+
+func TestTicketUpdateIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"number":"T-1","status":1,"priority":"alta","description":"Porta travada","open_date":"2026-01-05T10:00:00Z","branch_id":1}`
+
+	tests := []struct {
+		name     string
+		method   string
+		ifMatch  string
+		status   int
+		etag     string
+		received int // Versão que chega ao service; -1 quando o service não é chamado
+	}{
+		// Sem If-Match (ou com "*") o PUT não confere a versão
+		{"put without header", http.MethodPut, "", http.StatusOK, `"4"`, 0},
+		{"put any version", http.MethodPut, "*", http.StatusOK, `"4"`, 0},
+		{"put current version", http.MethodPut, `"3"`, http.StatusOK, `"4"`, 3},
+		{"put weak etag", http.MethodPut, `W/"3"`, http.StatusOK, `"4"`, 3},
+		{"put stale version", http.MethodPut, `"2"`, http.StatusPreconditionFailed, `"3"`, 2},
+		{"put invalid header", http.MethodPut, `"abc"`, http.StatusBadRequest, "", -1},
+		{"put version zero", http.MethodPut, `"0"`, http.StatusBadRequest, "", -1},
+		// O patch é aplicado sobre a versão lida, que vai para o service mesmo sem If-Match
+		{"patch without header", http.MethodPatch, "", http.StatusOK, `"4"`, 3},
+		{"patch stale version", http.MethodPatch, `"2"`, http.StatusPreconditionFailed, `"3"`, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticketService := &fakeVersionTicketService{}
+			router := gin.New()
+			TicketRoutes(router, handlers.NewTicketHandler(ticketService), &handlers.TicketProblemHandler{}, &handlers.TicketSolutionHandler{}, testSecret)
+
+			payload := body
+			if tt.method == http.MethodPatch {
+				payload = `{"priority":"baixa"}`
+			}
+			req := httptest.NewRequest(tt.method, "/api/v1/tickets/1", strings.NewReader(payload))
+			req.Header.Set("Authorization", testToken(t, domain.RoleSuporte))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if etag := w.Header().Get("ETag"); etag != tt.etag {
+				t.Fatalf("got ETag %q, want %q", etag, tt.etag)
+			}
+			if tt.received < 0 && ticketService.updates > 0 {
+				t.Fatalf("service updated with version %d", ticketService.received)
+			}
+			if tt.received >= 0 && ticketService.received != tt.received {
+				t.Fatalf("service got version %d, want %d", ticketService.received, tt.received)
+			}
+
+			// O 412 traz a representação atual do ticket
+			if tt.status == http.StatusPreconditionFailed {
+				var conflict struct {
+					Current dto.TicketResponse `json:"current"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &conflict); err != nil {
+					t.Fatal(err)
+				}
+				if conflict.Current.Version != 3 || conflict.Current.ID != 1 {
+					t.Fatalf("got current %+v, want ticket 1 at version 3", conflict.Current)
+				}
+			}
+		})
+	}
+}
+
+func TestTicketPatchMergesFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		status      int
+	}{
+		{"merge patch", "application/merge-patch+json", `{"priority":"baixa"}`, http.StatusOK},
+		{"plain json", "application/json", `{"priority":"baixa"}`, http.StatusOK},
+		{"unsupported content type", "text/plain", `{"priority":"baixa"}`, http.StatusUnsupportedMediaType},
+		// null remove o campo, e o request resultante passa pelas validações do PUT
+		{"null on a required field", "application/merge-patch+json", `{"priority":"baixa","description":null}`, http.StatusBadRequest},
+		{"costs are not patched", "application/merge-patch+json", `{"solution_items":[]}`, http.StatusBadRequest},
+		{"patch is not an object", "application/merge-patch+json", `[]`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticketService := &fakeVersionTicketService{}
+			router := gin.New()
+			TicketRoutes(router, handlers.NewTicketHandler(ticketService), &handlers.TicketProblemHandler{}, &handlers.TicketSolutionHandler{}, testSecret)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/tickets/1", strings.NewReader(tt.patch))
+			req.Header.Set("Authorization", testToken(t, domain.RoleSuporte))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				if ticketService.updates > 0 {
+					t.Fatal("rejected patch reached the service")
+				}
+				return
+			}
+
+			// Os campos ausentes no patch mantêm o valor atual e os custos não são enviados
+			got := ticketService.request
+			if got.Priority != "baixa" || got.Number != "T-1" || got.Description != "Porta travada" || got.BranchID != 1 {
+				t.Fatalf("got %+v, want the current ticket with priority baixa", got)
+			}
+			if got.SolutionItems != nil {
+				t.Fatalf("got solution items %v, want costs untouched", got.SolutionItems)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduledReportRoutes registra os relatórios agendados e o arquivo dos gerados, restritos a Financeiro e Admin
+func ScheduledReportRoutes(router *gin.Engine, handler *handlers.ScheduledReportHandler, jwtSecret []byte) {
+	reports := router.Group("/api/v1/scheduled-reports")
+	reports.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RoleFinanceiro, domain.RoleAdmin))
+	{
+		reports.POST("", handler.CreateReport)
+		reports.GET("", handler.ListReports)
+		reports.GET("/:id", handler.GetReport)
+		reports.PUT("/:id", handler.UpdateReport)
+		reports.DELETE("/:id", handler.DeleteReport)
+		reports.POST("/:id/run", handler.RunReport)
+		reports.GET("/:id/files", handler.ListReportFiles)
+		reports.GET("/:id/files/:file_id", handler.DownloadReportFile)
+	}
+}
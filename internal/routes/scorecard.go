@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func ScorecardRoutes(router *gin.Engine, handler *handlers.ScorecardHandler) {
+	providers := router.Group("/api/v1/providers")
+	{
+		providers.GET("/ranking", handler.GetProviderRanking)
+		providers.GET("/:id/scorecard", handler.GetProviderScorecard)
+	}
+}
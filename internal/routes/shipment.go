@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func ShipmentRoutes(router *gin.Engine, handler *handlers.ShipmentHandler) {
+	shipments := router.Group("/api/v1/shipments")
+	{
+		shipments.POST("", handler.CreateShipment)
+		shipments.GET("", handler.ListShipments)
+		shipments.GET("/:id", handler.GetShipment)
+		shipments.PUT("/:id", handler.UpdateShipment)
+		shipments.POST("/:id/events", handler.AddShipmentEvent)
+		shipments.POST("/:id/track", handler.TrackShipment)
+	}
+
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.GET("/:id/shipments", handler.GetTicketShipments)
+	}
+}
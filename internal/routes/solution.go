@@ -12,6 +12,7 @@ func SolutionRoutes(router *gin.Engine, handler *handlers.SolutionHandler) {
 		v1.GET("/solutions", handler.List)
 		v1.GET("/solutions/:id", handler.GetByID)
 		v1.PUT("/solutions/:id", handler.Update)
+		v1.PATCH("/solutions/:id", handler.Patch)
 		v1.DELETE("/solutions/:id", handler.Delete)
 	}
 }
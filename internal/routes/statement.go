@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// StatementRoutes registra os extratos de pagamento de técnicos, restritos a Pagamentos e Admin
+func StatementRoutes(router *gin.Engine, handler *handlers.StatementHandler, jwtSecret []byte) {
+	statements := router.Group("/api/v1/provider-statements")
+	statements.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RolePagamentos, domain.RoleAdmin))
+	{
+		statements.POST("", handler.CreateStatement)
+		statements.GET("", handler.ListStatements)
+		statements.GET("/:id", handler.GetStatement)
+		statements.DELETE("/:id", handler.DeleteStatement)
+		statements.POST("/:id/deductions", handler.AddDeduction)
+		statements.DELETE("/:id/deductions/:line_id", handler.RemoveDeduction)
+		statements.POST("/:id/approve", handler.ApproveStatement)
+		statements.POST("/:id/pay", handler.PayStatement)
+		statements.GET("/:id/pdf", handler.GetStatementPDF)
+		statements.GET("/:id/cnab", handler.GetStatementPaymentFile)
+	}
+
+	providers := router.Group("/api/v1/providers")
+	providers.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.RolePagamentos, domain.RoleAdmin))
+	{
+		providers.GET("/:id/bank-account", handler.GetBankAccount)
+		providers.PUT("/:id/bank-account", handler.SaveBankAccount)
+	}
+}
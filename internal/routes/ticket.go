@@ -1,18 +1,23 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
+	"github.com/gin-gonic/gin"
 )
 
-func TicketRoutes(router *gin.Engine, ticketHandler *handlers.TicketHandler, ticketProblemHandler *handlers.TicketProblemHandler, ticketSolutionHandler *handlers.TicketSolutionHandler) {
+func TicketRoutes(router *gin.Engine, ticketHandler *handlers.TicketHandler, ticketProblemHandler *handlers.TicketProblemHandler, ticketSolutionHandler *handlers.TicketSolutionHandler, jwtSecret []byte) {
+	// Técnicos veem e alteram apenas os próprios tickets, pelo portal (/api/v1/me/tickets)
 	tickets := router.Group("/api/v1/tickets")
+	tickets.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.StaffRoles...))
 	{
 		// CRUD básico
 		tickets.POST("", ticketHandler.CreateTicket)
 		tickets.GET("", ticketHandler.ListTickets)
 		tickets.GET("/:id", ticketHandler.FindTicketByID)
 		tickets.PUT("/:id", ticketHandler.UpdateTicket)
+		tickets.PATCH("/:id", ticketHandler.PatchTicket)
 		tickets.DELETE("/:id", ticketHandler.DeleteTicket)
 
 		// Utilitários
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func TicketAttachmentRoutes(
+	router *gin.Engine,
+	attachmentHandler *handlers.TicketAttachmentHandler,
+	commentHandler *handlers.TicketCommentHandler,
+) {
+	tickets := router.Group("/api/v1/tickets")
+	{
+		// Anexos
+		tickets.POST("/:id/attachments", attachmentHandler.UploadAttachment)
+		tickets.GET("/:id/attachments", attachmentHandler.GetTicketAttachments)
+		tickets.GET("/:id/attachments/:attachment_id", attachmentHandler.DownloadAttachment)
+
+		// Comentários
+		tickets.POST("/:id/comments", commentHandler.AddCommentToTicket)
+		tickets.GET("/:id/comments", commentHandler.GetTicketComments)
+	}
+}
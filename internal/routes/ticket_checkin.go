@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func TicketCheckinRoutes(router *gin.Engine, handler *handlers.TicketCheckinHandler) {
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.POST("/:id/checkin", handler.Checkin)
+		tickets.POST("/:id/checkout", handler.Checkout)
+		tickets.GET("/:id/checkins", handler.GetTicketCheckins)
+	}
+}
@@ -1,21 +1,31 @@
 package routes
 
 import (
+	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/ericolvr/maintenance-v2/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func UserRoutes(
 	router *gin.Engine,
 	userHandler *handlers.UserHandler,
+	jwtSecret []byte,
 ) {
+	// Técnicos não administram usuários (nem o próprio vínculo com o prestador)
 	routes := router.Group("/api/v1/users")
+	routes.Use(middleware.AuthMiddleware(jwtSecret), middleware.RequireRole(domain.StaffRoles...))
 	{
 		routes.POST("", userHandler.Create)
 		routes.GET("", userHandler.List)
 		routes.GET("/:id", userHandler.FindByID)
 		routes.PUT("/:id", userHandler.Update)
+		routes.PATCH("/:id", userHandler.Patch)
 		routes.DELETE("/:id", userHandler.Delete)
-		routes.POST("/auth", userHandler.Authenticate)
+	}
+
+	auth := router.Group("/api/v1/users")
+	{
+		auth.POST("/auth", userHandler.Authenticate)
 	}
 }
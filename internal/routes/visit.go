@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func VisitRoutes(router *gin.Engine, handler *handlers.VisitHandler) {
+	tickets := router.Group("/api/v1/tickets")
+	{
+		tickets.POST("/:id/visits", handler.ScheduleVisit)
+		tickets.GET("/:id/visits", handler.GetTicketVisits)
+		tickets.PUT("/:id/visits/:visit_id", handler.UpdateVisit)
+		tickets.DELETE("/:id/visits/:visit_id", handler.CancelVisit)
+	}
+
+	providers := router.Group("/api/v1/providers")
+	{
+		providers.GET("/:id/calendar", handler.GetProviderCalendar)
+		providers.GET("/:id/calendar/feed", handler.GetProviderCalendarFeedURL)
+		providers.GET("/:id/calendar.ics", handler.GetProviderCalendarFeed)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/ericolvr/maintenance-v2/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func WarrantyRoutes(router *gin.Engine, handler *handlers.WarrantyHandler) {
+	reports := router.Group("/api/v1/reports")
+	{
+		reports.GET("/warranty-returns", handler.GetWarrantyReturns)
+	}
+}
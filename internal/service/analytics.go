@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type AnalyticsService interface {
+	Problems(ctx context.Context, filter domain.ProblemAnalyticsFilter) ([]domain.ProblemAnalytics, error)
+	UnusedSolutions(ctx context.Context) ([]domain.UnusedSolution, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	clientRepo    repository.ClientRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, clientRepo repository.ClientRepository) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		clientRepo:    clientRepo,
+	}
+}
+
+func (s *analyticsService) Problems(ctx context.Context, filter domain.ProblemAnalyticsFilter) ([]domain.ProblemAnalytics, error) {
+	// Validar se client existe
+	if filter.ClientID != nil {
+		if _, err := s.clientRepo.FindByID(ctx, *filter.ClientID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.analyticsRepo.Problems(ctx, filter)
+}
+
+func (s *analyticsService) UnusedSolutions(ctx context.Context) ([]domain.UnusedSolution, error) {
+	return s.analyticsRepo.UnusedSolutions(ctx)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var ErrAssetBranchMismatch = errors.New("asset is not installed at the ticket branch")
+
+type AssetService interface {
+	Create(ctx context.Context, asset *domain.Asset) (*domain.Asset, error)
+	List(ctx context.Context, branchID *int) ([]domain.Asset, error)
+	FindByID(ctx context.Context, id int) (*domain.Asset, error)
+	Update(ctx context.Context, asset *domain.Asset) (*domain.Asset, error)
+	Delete(ctx context.Context, id int) error
+	History(ctx context.Context, id int) (*dto.AssetHistoryResponse, error)
+}
+
+type assetService struct {
+	assetRepo   repository.AssetRepository
+	branchRepo  repository.BranchRepository
+	ticketRepo  repository.TicketRepository
+	problemRepo repository.ProblemRepository
+}
+
+func NewAssetService(
+	assetRepo repository.AssetRepository,
+	branchRepo repository.BranchRepository,
+	ticketRepo repository.TicketRepository,
+	problemRepo repository.ProblemRepository,
+) AssetService {
+	return &assetService{
+		assetRepo:   assetRepo,
+		branchRepo:  branchRepo,
+		ticketRepo:  ticketRepo,
+		problemRepo: problemRepo,
+	}
+}
+
+func (s *assetService) Create(ctx context.Context, asset *domain.Asset) (*domain.Asset, error) {
+	// Validar se branch existe
+	if _, err := s.branchRepo.FindByID(ctx, asset.BranchID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.assetRepo.Create(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.assetRepo.FindByID(ctx, id)
+}
+
+func (s *assetService) List(ctx context.Context, branchID *int) ([]domain.Asset, error) {
+	return s.assetRepo.List(ctx, branchID)
+}
+
+func (s *assetService) FindByID(ctx context.Context, id int) (*domain.Asset, error) {
+	return s.assetRepo.FindByID(ctx, id)
+}
+
+func (s *assetService) Update(ctx context.Context, asset *domain.Asset) (*domain.Asset, error) {
+	// Validar se branch existe
+	if _, err := s.branchRepo.FindByID(ctx, asset.BranchID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assetRepo.Update(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	return s.assetRepo.FindByID(ctx, asset.ID)
+}
+
+func (s *assetService) Delete(ctx context.Context, id int) error {
+	return s.assetRepo.Delete(ctx, id)
+}
+
+// History monta o histórico de atendimento do equipamento: tickets, problemas, soluções e custos
+func (s *assetService) History(ctx context.Context, id int) (*dto.AssetHistoryResponse, error) {
+	asset, err := s.assetRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.assetRepo.ListTickets(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &dto.AssetHistoryResponse{
+		Asset:   dto.ToAssetResponse(asset),
+		Tickets: []dto.AssetTicketResponse{},
+	}
+
+	for _, ticket := range tickets {
+		entry := dto.AssetTicketResponse{
+			TicketID:    ticket.ID,
+			Number:      ticket.Number,
+			Status:      ticket.Status,
+			Description: ticket.Description,
+			OpenDate:    ticket.OpenDate,
+			CloseDate:   ticket.CloseDate,
+			ProviderID:  ticket.ProviderID,
+			Problems:    []string{},
+			Costs:       []dto.SolutionItemResponse{},
+		}
+
+		// Problemas do ticket (todos quando o ticket é do equipamento, senão só os vinculados a ele)
+		problems, err := s.ticketRepo.GetTicketProblems(ctx, ticket.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get problems for ticket %d: %w", ticket.ID, err)
+		}
+		for _, tp := range problems {
+			if !assetOwnsProblem(&ticket, &tp, id) {
+				continue
+			}
+			problem, err := s.problemRepo.FindByID(ctx, tp.ProblemID)
+			if err != nil {
+				continue
+			}
+			entry.Problems = append(entry.Problems, problem.Name)
+		}
+
+		// Custos lançados no ticket
+		costs, err := s.ticketRepo.GetTicketCosts(ctx, ticket.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get costs for ticket %d: %w", ticket.ID, err)
+		}
+		for _, cost := range costs {
+			entry.Costs = append(entry.Costs, dto.SolutionItemResponse{
+				VisitID:          cost.VisitID,
+				ProblemName:      cost.ProblemName,
+				SolutionName:     cost.SolutionName,
+				UnitPrice:        cost.UnitPrice,
+				Subtotal:         cost.Subtotal,
+				Warranty:         cost.Warranty,
+				WarrantyTicketID: cost.WarrantyTicketID,
+			})
+			entry.TotalCost += cost.Subtotal
+		}
+
+		history.TotalCost += entry.TotalCost
+		history.Tickets = append(history.Tickets, entry)
+	}
+
+	return history, nil
+}
+
+// assetOwnsProblem indica se o problema do ticket diz respeito ao equipamento
+func assetOwnsProblem(ticket *domain.Ticket, problem *domain.TicketProblem, assetID int) bool {
+	if problem.AssetID != nil {
+		return *problem.AssetID == assetID
+	}
+	return ticket.AssetID != nil && *ticket.AssetID == assetID
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeAssetRepo struct {
+	repository.AssetRepository
+	assets  map[int]*domain.Asset
+	tickets []domain.Ticket
+}
+
+func (f *fakeAssetRepo) FindByID(ctx context.Context, id int) (*domain.Asset, error) {
+	asset, ok := f.assets[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *asset
+	return &copied, nil
+}
+
+func (f *fakeAssetRepo) ListTickets(ctx context.Context, assetID int) ([]domain.Ticket, error) {
+	return f.tickets, nil
+}
+
+type fakeAssetTicketRepo struct {
+	repository.TicketRepository
+	problems map[int][]domain.TicketProblem
+	costs    map[int][]domain.TicketCost
+}
+
+func (f *fakeAssetTicketRepo) GetTicketProblems(ctx context.Context, ticketID int) ([]domain.TicketProblem, error) {
+	return f.problems[ticketID], nil
+}
+
+func (f *fakeAssetTicketRepo) GetTicketCosts(ctx context.Context, ticketID int) ([]domain.TicketCost, error) {
+	return f.costs[ticketID], nil
+}
+
+type fakeAssetProblemRepo struct {
+	repository.ProblemRepository
+}
+
+func (f *fakeAssetProblemRepo) FindByID(ctx context.Context, id int) (*domain.Problem, error) {
+	names := map[int]string{1: "Câmera sem imagem", 2: "Nobreak desligando"}
+	name, ok := names[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &domain.Problem{ID: id, Name: name}, nil
+}
+
+func TestAssetOwnsProblem(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name         string
+		ticketAsset  *int
+		problemAsset *int
+		want         bool
+	}{
+		{"ticket of the asset", intPtr(7), nil, true},
+		{"ticket of another asset", intPtr(8), nil, false},
+		{"ticket without asset", nil, nil, false},
+		// O equipamento do problema prevalece sobre o do ticket
+		{"problem of the asset", intPtr(8), intPtr(7), true},
+		{"problem of another asset", intPtr(7), intPtr(8), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := &domain.Ticket{AssetID: tt.ticketAsset}
+			problem := &domain.TicketProblem{AssetID: tt.problemAsset}
+			if got := assetOwnsProblem(ticket, problem, 7); got != tt.want {
+				t.Fatalf("assetOwnsProblem = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetHistorySumsCostsPerTicket(t *testing.T) {
+	assetID, otherAsset := 7, 8
+	svc := &assetService{
+		assetRepo: &fakeAssetRepo{
+			assets: map[int]*domain.Asset{7: {ID: 7, BranchID: 2, Type: "camera"}},
+			tickets: []domain.Ticket{
+				{ID: 10, Number: "CH-10", AssetID: &assetID},
+				{ID: 11, Number: "CH-11"},
+			},
+		},
+		ticketRepo: &fakeAssetTicketRepo{
+			problems: map[int][]domain.TicketProblem{
+				10: {{TicketID: 10, ProblemID: 1}},
+				11: {
+					{TicketID: 11, ProblemID: 1, AssetID: &assetID},
+					{TicketID: 11, ProblemID: 2, AssetID: &otherAsset},
+				},
+			},
+			costs: map[int][]domain.TicketCost{
+				10: {{TicketID: 10, Subtotal: domain.NewMoney(100)}, {TicketID: 10, Subtotal: domain.NewMoney(50.5)}},
+				11: {{TicketID: 11, Subtotal: 0, Warranty: true}},
+			},
+		},
+		problemRepo: &fakeAssetProblemRepo{},
+	}
+
+	history, err := svc.History(context.Background(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history.Tickets) != 2 {
+		t.Fatalf("got %d tickets, want 2", len(history.Tickets))
+	}
+	tests := []struct {
+		number   string
+		problems []string
+		total    domain.Money
+	}{
+		{"CH-10", []string{"Câmera sem imagem"}, domain.NewMoney(150.5)},
+		// Só o problema vinculado ao equipamento entra no histórico
+		{"CH-11", []string{"Câmera sem imagem"}, 0},
+	}
+	for i, tt := range tests {
+		entry := history.Tickets[i]
+		if entry.Number != tt.number || entry.TotalCost != tt.total {
+			t.Fatalf("ticket %d = %s total %v, want %s total %v", i, entry.Number, entry.TotalCost, tt.number, tt.total)
+		}
+		if len(entry.Problems) != len(tt.problems) || entry.Problems[0] != tt.problems[0] {
+			t.Fatalf("ticket %s problems = %v, want %v", entry.Number, entry.Problems, tt.problems)
+		}
+	}
+	if history.TotalCost != domain.NewMoney(150.5) {
+		t.Fatalf("total = %v, want 150.50", history.TotalCost)
+	}
+}
+
+func TestTicketValidateAsset(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	svc := &ticketService{
+		assetRepo: &fakeAssetRepo{assets: map[int]*domain.Asset{7: {ID: 7, BranchID: 2}}},
+	}
+
+	tests := []struct {
+		name     string
+		assetID  *int
+		branchID int
+		want     error
+	}{
+		{"no asset", nil, 2, nil},
+		{"asset at the branch", intPtr(7), 2, nil},
+		{"asset at another branch", intPtr(7), 3, ErrAssetBranchMismatch},
+		{"unknown asset", intPtr(9), 2, repository.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.validateAsset(context.Background(), tt.assetID, tt.branchID); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type AttachmentService interface {
+	Save(ctx context.Context, ticketID int, fileName, contentType string, public bool, content io.Reader) (*domain.Attachment, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.Attachment, error)
+	FindByID(ctx context.Context, ticketID, id int) (*domain.Attachment, error)
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	ticketRepo     repository.TicketRepository
+	uploadDir      string
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, ticketRepo repository.TicketRepository, uploadDir string) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		ticketRepo:     ticketRepo,
+		uploadDir:      uploadDir,
+	}
+}
+
+// Save grava o arquivo em disco (uploads/tickets/<id>/) e registra o anexo no ticket.
+// Anexos públicos ficam visíveis ao cliente no portal.
+func (s *attachmentService) Save(ctx context.Context, ticketID int, fileName, contentType string, public bool, content io.Reader) (*domain.Attachment, error) {
+	// Verificar se ticket existe
+	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	fileName = sanitizeFileName(fileName)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	dir := filepath.Join(s.uploadDir, "tickets", strconv.Itoa(ticketID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), fileName))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment file: %w", err)
+	}
+
+	size, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write attachment file: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		TicketID:    ticketID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Path:        path,
+		Public:      public,
+	}
+
+	id, err := s.attachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return s.attachmentRepo.FindByID(ctx, id)
+}
+
+func (s *attachmentService) ListByTicket(ctx context.Context, ticketID int) ([]domain.Attachment, error) {
+	// Verificar se ticket existe
+	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	return s.attachmentRepo.ListByTicket(ctx, ticketID)
+}
+
+func (s *attachmentService) FindByID(ctx context.Context, ticketID, id int) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachment.TicketID != ticketID {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == ".." {
+		return "anexo"
+	}
+	return name
+}
@@ -2,31 +2,43 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/repository"
 )
 
 type BranchService interface {
-	Create(ctx context.Context, branch *domain.Branch) (int, error)
+	Create(ctx context.Context, branch *domain.Branch) (*domain.Branch, error)
 	List(ctx context.Context) ([]domain.Branch, error)
 	FindByID(ctx context.Context, id int) (*domain.Branch, error)
 	FindByUniorg(ctx context.Context, uniorg string) (*domain.Branch, error)
-	GetByClient(ctx context.Context, client string) ([]domain.Branch, error)
-	Update(ctx context.Context, branch *domain.Branch) error
+	GetByClient(ctx context.Context, clientID int) ([]domain.Branch, error)
+	Update(ctx context.Context, branch *domain.Branch) (*domain.Branch, error)
 	Delete(ctx context.Context, id int) error
 }
 
 type branchService struct {
-	repo repository.BranchRepository
+	repo       repository.BranchRepository
+	clientRepo repository.ClientRepository
 }
 
-func NewBranchService(repo repository.BranchRepository) BranchService {
-	return &branchService{repo: repo}
+func NewBranchService(repo repository.BranchRepository, clientRepo repository.ClientRepository) BranchService {
+	return &branchService{repo: repo, clientRepo: clientRepo}
 }
 
-func (s *branchService) Create(ctx context.Context, branch *domain.Branch) (int, error) {
-	return s.repo.Create(ctx, branch)
+func (s *branchService) Create(ctx context.Context, branch *domain.Branch) (*domain.Branch, error) {
+	// Validar se client existe
+	if _, err := s.clientRepo.FindByID(ctx, branch.ClientID); err != nil {
+		return nil, fmt.Errorf("client not found: %w", err)
+	}
+
+	id, err := s.repo.Create(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, id)
 }
 
 func (s *branchService) List(ctx context.Context) ([]domain.Branch, error) {
@@ -41,12 +53,21 @@ func (s *branchService) FindByUniorg(ctx context.Context, uniorg string) (*domai
 	return s.repo.FindByUniorg(ctx, uniorg)
 }
 
-func (s *branchService) GetByClient(ctx context.Context, client string) ([]domain.Branch, error) {
-	return s.repo.GetByClient(ctx, client)
+func (s *branchService) GetByClient(ctx context.Context, clientID int) ([]domain.Branch, error) {
+	return s.repo.GetByClient(ctx, clientID)
 }
 
-func (s *branchService) Update(ctx context.Context, branch *domain.Branch) error {
-	return s.repo.Update(ctx, branch)
+func (s *branchService) Update(ctx context.Context, branch *domain.Branch) (*domain.Branch, error) {
+	// Validar se client existe
+	if _, err := s.clientRepo.FindByID(ctx, branch.ClientID); err != nil {
+		return nil, fmt.Errorf("client not found: %w", err)
+	}
+
+	if err := s.repo.Update(ctx, branch); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, branch.ID)
 }
 
 func (s *branchService) Delete(ctx context.Context, id int) error {
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrTicketWithoutProvider = errors.New("ticket has no provider assigned")
+	ErrCheckinAlreadyOpen    = errors.New("ticket already has an open checkin")
+	ErrNoOpenCheckin         = errors.New("ticket has no open checkin")
+	ErrCheckinNotAssigned    = errors.New("only the provider assigned to the ticket can check in or out")
+)
+
+// Raio médio da Terra em metros (usado no cálculo de haversine)
+const earthRadiusMeters = 6371000
+
+type CheckinService interface {
+	Checkin(ctx context.Context, userID, ticketID int, latitude, longitude float64) (*domain.TicketCheckin, error)
+	Checkout(ctx context.Context, userID, ticketID int, latitude, longitude float64, reportedKm *float64) (*domain.TicketCheckin, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketCheckin, error)
+}
+
+type checkinService struct {
+	checkinRepo           repository.CheckinRepository
+	ticketRepo            repository.TicketRepository
+	branchRepo            repository.BranchRepository
+	userRepo              repository.UserRepository
+	visitRepo             repository.VisitRepository
+	radiusMeters          float64
+	routeTolerancePercent float64
+}
+
+func NewCheckinService(
+	checkinRepo repository.CheckinRepository,
+	ticketRepo repository.TicketRepository,
+	branchRepo repository.BranchRepository,
+	userRepo repository.UserRepository,
+	visitRepo repository.VisitRepository,
+	radiusMeters float64,
+	routeTolerancePercent float64,
+) CheckinService {
+	return &checkinService{
+		checkinRepo:           checkinRepo,
+		ticketRepo:            ticketRepo,
+		branchRepo:            branchRepo,
+		userRepo:              userRepo,
+		visitRepo:             visitRepo,
+		radiusMeters:          radiusMeters,
+		routeTolerancePercent: routeTolerancePercent,
+	}
+}
+
+func (s *checkinService) Checkin(ctx context.Context, userID, ticketID int, latitude, longitude float64) (*domain.TicketCheckin, error) {
+	// Verificar se ticket existe e tem técnico associado
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+	if ticket.ProviderID == nil {
+		return nil, ErrTicketWithoutProvider
+	}
+
+	// O check-in comprova a presença para o pagamento: só o próprio técnico registra
+	if err := s.ensureProvider(ctx, userID, *ticket.ProviderID); err != nil {
+		return nil, err
+	}
+
+	// Não permitir dois check-ins abertos para o mesmo ticket
+	_, err = s.checkinRepo.FindOpenByTicket(ctx, ticketID)
+	if err == nil {
+		return nil, ErrCheckinAlreadyOpen
+	}
+	if !errors.Is(err, repository.ErrCheckinNotFound) {
+		return nil, err
+	}
+
+	branch, err := s.branchRepo.FindByID(ctx, ticket.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("branch not found: %w", err)
+	}
+
+	checkin := &domain.TicketCheckin{
+		TicketID:         ticketID,
+		ProviderID:       *ticket.ProviderID,
+		CheckinAt:        time.Now(),
+		CheckinLatitude:  latitude,
+		CheckinLongitude: longitude,
+		Anomalies:        []string{},
+	}
+
+	// Validar posição contra a localização da agência
+	checkin.CheckinDistanceMeters = s.distanceToBranch(branch, latitude, longitude)
+	if checkin.CheckinDistanceMeters == nil {
+		checkin.Anomalies = append(checkin.Anomalies, domain.AnomalyBranchNotGeocoded)
+	} else if *checkin.CheckinDistanceMeters > s.radiusMeters {
+		checkin.Anomalies = append(checkin.Anomalies, domain.AnomalyCheckinOutOfRadius)
+	}
+
+	id, err := s.checkinRepo.Create(ctx, checkin)
+	if err != nil {
+		return nil, err
+	}
+	checkin.ID = id
+
+	return checkin, nil
+}
+
+func (s *checkinService) Checkout(ctx context.Context, userID, ticketID int, latitude, longitude float64, reportedKm *float64) (*domain.TicketCheckin, error) {
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	checkin, err := s.checkinRepo.FindOpenByTicket(ctx, ticketID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckinNotFound) {
+			return nil, ErrNoOpenCheckin
+		}
+		return nil, err
+	}
+
+	// A saída é registrada pelo técnico que fez o check-in
+	if err := s.ensureProvider(ctx, userID, checkin.ProviderID); err != nil {
+		return nil, err
+	}
+
+	branch, err := s.branchRepo.FindByID(ctx, ticket.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("branch not found: %w", err)
+	}
+
+	now := time.Now()
+	checkin.CheckoutAt = &now
+	checkin.CheckoutLatitude = &latitude
+	checkin.CheckoutLongitude = &longitude
+	checkin.ReportedKm = reportedKm
+
+	// Validar posição de saída (branch_not_geocoded já foi registrado no check-in)
+	checkin.CheckoutDistanceMeters = s.distanceToBranch(branch, latitude, longitude)
+	if checkin.CheckoutDistanceMeters != nil && *checkin.CheckoutDistanceMeters > s.radiusMeters {
+		checkin.Anomalies = append(checkin.Anomalies, domain.AnomalyCheckoutOutOfRadius)
+	}
+
+	// Conciliar a quilometragem informada com a distância da visita do técnico
+	if reportedKm != nil {
+		anomalies, err := s.reconcileRoute(ctx, checkin, *reportedKm)
+		if err != nil {
+			return nil, err
+		}
+		checkin.Anomalies = append(checkin.Anomalies, anomalies...)
+	}
+
+	if err := s.checkinRepo.Checkout(ctx, checkin); err != nil {
+		return nil, err
+	}
+
+	return checkin, nil
+}
+
+func (s *checkinService) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketCheckin, error) {
+	if _, err := s.ticketRepo.FindByID(ctx, ticketID); err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	return s.checkinRepo.ListByTicket(ctx, ticketID)
+}
+
+// ensureProvider garante que o usuário logado é o técnico vinculado ao prestador informado
+func (s *checkinService) ensureProvider(ctx context.Context, userID, providerID int) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.ProviderID == nil || *user.ProviderID != providerID {
+		return ErrCheckinNotAssigned
+	}
+
+	return nil
+}
+
+// distanceToBranch retorna a distância em metros até a agência, ou nil se a agência não tem coordenadas
+func (s *checkinService) distanceToBranch(branch *domain.Branch, latitude, longitude float64) *float64 {
+	if branch.Latitude == nil || branch.Longitude == nil {
+		return nil
+	}
+
+	distance := haversineMeters(*branch.Latitude, *branch.Longitude, latitude, longitude)
+	return &distance
+}
+
+// reconcileRoute compara o km informado no check-out com o distance_km da visita do técnico.
+// Com várias visitas, vale a de início mais próximo do check-in
+func (s *checkinService) reconcileRoute(ctx context.Context, checkin *domain.TicketCheckin, reportedKm float64) ([]string, error) {
+	visits, err := s.visitRepo.ListByTicket(ctx, checkin.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list visits: %w", err)
+	}
+
+	var visit *domain.Visit
+	otherProvider := false
+	for i := range visits {
+		if visits[i].DistanceKm == nil {
+			continue
+		}
+		if visits[i].ProviderID != checkin.ProviderID {
+			otherProvider = true
+			continue
+		}
+		if visit == nil || closerToCheckin(&visits[i], visit, checkin.CheckinAt) {
+			visit = &visits[i]
+		}
+	}
+
+	if visit == nil {
+		if otherProvider {
+			return []string{domain.AnomalyRouteProviderMismatch}, nil
+		}
+		return []string{domain.AnomalyDistanceNotRegistered}, nil
+	}
+
+	distance := *visit.DistanceKm
+	tolerance := distance * s.routeTolerancePercent / 100
+	if math.Abs(reportedKm-distance) > tolerance {
+		return []string{domain.AnomalyRouteMismatch}, nil
+	}
+
+	return nil, nil
+}
+
+// closerToCheckin indica se a visita a começa mais perto do check-in que b (visitas sem data por último)
+func closerToCheckin(a, b *domain.Visit, checkinAt time.Time) bool {
+	if b.StartsAt == nil {
+		return a.StartsAt != nil
+	}
+	if a.StartsAt == nil {
+		return false
+	}
+	return absDuration(a.StartsAt.Sub(checkinAt)) < absDuration(b.StartsAt.Sub(checkinAt))
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
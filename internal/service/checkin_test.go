@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeCheckinVisitRepo struct {
+	repository.VisitRepository
+	visits []domain.Visit
+}
+
+func (f *fakeCheckinVisitRepo) ListByTicket(ctx context.Context, ticketID int) ([]domain.Visit, error) {
+	return f.visits, nil
+}
+
+func TestReconcileRouteUsesProviderVisitDistance(t *testing.T) {
+	checkinAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	at := func(day int) *time.Time {
+		starts := time.Date(2026, 10, day, 9, 0, 0, 0, time.UTC)
+		return &starts
+	}
+	km := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name   string
+		visits []domain.Visit
+		want   []string
+	}{
+		{"within tolerance", []domain.Visit{{ProviderID: 3, StartsAt: at(19), DistanceKm: km(45)}}, nil},
+		{"out of tolerance", []domain.Visit{{ProviderID: 3, StartsAt: at(19), DistanceKm: km(20)}}, []string{domain.AnomalyRouteMismatch}},
+		{"visit closest to checkin", []domain.Visit{
+			{ProviderID: 3, StartsAt: at(12), DistanceKm: km(10)},
+			{ProviderID: 3, StartsAt: at(19), DistanceKm: km(50)},
+			{ProviderID: 3, DistanceKm: km(10)},
+		}, nil},
+		{"only other provider", []domain.Visit{{ProviderID: 4, StartsAt: at(19), DistanceKm: km(50)}}, []string{domain.AnomalyRouteProviderMismatch}},
+		{"no distance", []domain.Visit{{ProviderID: 3, StartsAt: at(19)}}, []string{domain.AnomalyDistanceNotRegistered}},
+	}
+
+	for _, tt := range tests {
+		service := &checkinService{visitRepo: &fakeCheckinVisitRepo{visits: tt.visits}, routeTolerancePercent: 20}
+		checkin := &domain.TicketCheckin{TicketID: 1, ProviderID: 3, CheckinAt: checkinAt}
+
+		got, err := service.reconcileRoute(context.Background(), checkin, 48)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type fakeCheckinRepo struct {
+	repository.CheckinRepository
+	open *domain.TicketCheckin
+}
+
+func (f *fakeCheckinRepo) FindOpenByTicket(ctx context.Context, ticketID int) (*domain.TicketCheckin, error) {
+	if f.open == nil {
+		return nil, repository.ErrCheckinNotFound
+	}
+	checkin := *f.open
+	return &checkin, nil
+}
+
+func (f *fakeCheckinRepo) Create(ctx context.Context, checkin *domain.TicketCheckin) (int, error) {
+	f.open = checkin
+	return 1, nil
+}
+
+func (f *fakeCheckinRepo) Checkout(ctx context.Context, checkin *domain.TicketCheckin) error {
+	f.open = nil
+	return nil
+}
+
+type fakeCheckinBranchRepo struct {
+	repository.BranchRepository
+}
+
+func (f *fakeCheckinBranchRepo) FindByID(ctx context.Context, id int) (*domain.Branch, error) {
+	return &domain.Branch{ID: id}, nil
+}
+
+// fakeCheckinUserRepo vincula o usuário de id N ao prestador N; o usuário 1 é interno
+type fakeCheckinUserRepo struct {
+	repository.UserRepository
+}
+
+func (f *fakeCheckinUserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	user := &domain.User{ID: id}
+	if id != 1 {
+		user.ProviderID = &id
+	}
+	return user, nil
+}
+
+func TestCheckinOnlyByAssignedProvider(t *testing.T) {
+	assigned := 3
+	tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, BranchID: 7, ProviderID: &assigned}}
+	checkins := &fakeCheckinRepo{}
+	service := NewCheckinService(checkins, tickets, &fakeCheckinBranchRepo{}, &fakeCheckinUserRepo{}, nil, 300, 20)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		userID int
+		want   error
+	}{
+		{"internal user", 1, ErrCheckinNotAssigned},
+		{"another provider", 4, ErrCheckinNotAssigned},
+		{"assigned provider", 3, nil},
+	}
+	for _, tt := range tests {
+		if _, err := service.Checkin(ctx, tt.userID, 5, -23.5, -46.6); !errors.Is(err, tt.want) {
+			t.Errorf("checkin by %s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	for _, tt := range tests {
+		if _, err := service.Checkout(ctx, tt.userID, 5, -23.5, -46.6, nil); !errors.Is(err, tt.want) {
+			t.Errorf("checkout by %s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+var (
+	ErrNotClientUser      = errors.New("user is not linked to a client")
+	ErrClientTicketAccess = errors.New("ticket not found for this client")
+	ErrTicketNotClosed    = errors.New("only closed tickets can be rated")
+)
+
+// ClientPortalService expõe ao usuário de cliente apenas os tickets das agências do seu cliente
+type ClientPortalService interface {
+	ListBranches(ctx context.Context, userID int) ([]domain.Branch, error)
+	ListTickets(ctx context.Context, userID int, limit, offset int) ([]dto.ClientTicketResponse, int, error)
+	GetTicket(ctx context.Context, userID, ticketID int) (*dto.ClientTicketResponse, error)
+	OpenTicket(ctx context.Context, userID int, req *dto.ClientTicketRequest) (*dto.ClientTicketResponse, error)
+	ListComments(ctx context.Context, userID, ticketID int) ([]domain.TicketComment, error)
+	AddComment(ctx context.Context, userID, ticketID int, req *dto.ClientCommentRequest) (*domain.TicketComment, error)
+	ListAttachments(ctx context.Context, userID, ticketID int) ([]domain.Attachment, error)
+	UploadAttachment(ctx context.Context, userID, ticketID int, fileName, contentType string, content io.Reader) (*domain.Attachment, error)
+	FindAttachment(ctx context.Context, userID, ticketID, id int) (*domain.Attachment, error)
+	RateTicket(ctx context.Context, userID, ticketID int, req *dto.TicketRatingRequest) (*dto.TicketRatingResponse, error)
+	ListQuotes(ctx context.Context, userID, ticketID int) ([]domain.Quote, error)
+	DecideQuote(ctx context.Context, userID, ticketID, quoteID int, status string, req *dto.QuoteDecisionRequest) (*domain.Quote, error)
+}
+
+type clientPortalService struct {
+	userRepo          repository.UserRepository
+	ticketRepo        repository.TicketRepository
+	branchRepo        repository.BranchRepository
+	contractRepo      repository.ContractRepository
+	checkinRepo       repository.CheckinRepository
+	ratingRepo        repository.RatingRepository
+	ticketService     TicketService
+	commentService    CommentService
+	attachmentService AttachmentService
+	quoteService      QuoteService
+}
+
+func NewClientPortalService(
+	userRepo repository.UserRepository,
+	ticketRepo repository.TicketRepository,
+	branchRepo repository.BranchRepository,
+	contractRepo repository.ContractRepository,
+	checkinRepo repository.CheckinRepository,
+	ratingRepo repository.RatingRepository,
+	ticketService TicketService,
+	commentService CommentService,
+	attachmentService AttachmentService,
+	quoteService QuoteService,
+) ClientPortalService {
+	return &clientPortalService{
+		userRepo:          userRepo,
+		ticketRepo:        ticketRepo,
+		branchRepo:        branchRepo,
+		contractRepo:      contractRepo,
+		checkinRepo:       checkinRepo,
+		ratingRepo:        ratingRepo,
+		ticketService:     ticketService,
+		commentService:    commentService,
+		attachmentService: attachmentService,
+		quoteService:      quoteService,
+	}
+}
+
+func (s *clientPortalService) ListBranches(ctx context.Context, userID int) ([]domain.Branch, error) {
+	ctx, user, err := s.clientUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.branchRepo.GetByClient(ctx, *user.ClientID)
+}
+
+func (s *clientPortalService) ListTickets(ctx context.Context, userID int, limit, offset int) ([]dto.ClientTicketResponse, int, error) {
+	ctx, _, err := s.clientUser(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// O context restringe a listagem aos tickets das agências do cliente
+	tickets, total, err := s.ticketRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tickets: %w", err)
+	}
+
+	responses := make([]dto.ClientTicketResponse, 0, len(tickets))
+	for _, ticket := range tickets {
+		response, err := s.ticketResponse(ctx, ticket.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, total, nil
+}
+
+// GetTicket retorna o ticket com a linha do tempo visível ao cliente
+func (s *clientPortalService) GetTicket(ctx context.Context, userID, ticketID int) (*dto.ClientTicketResponse, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.ticketResponse(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyTimeline(ctx, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// OpenTicket abre um ticket Novo em uma agência do cliente
+func (s *clientPortalService) OpenTicket(ctx context.Context, userID int, req *dto.ClientTicketRequest) (*dto.ClientTicketResponse, error) {
+	ctx, user, err := s.clientUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validar se a agência é do cliente
+	branch, err := s.branchRepo.FindByID(ctx, req.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("branch not found: %w", err)
+	}
+	if branch.ClientID != *user.ClientID {
+		return nil, repository.ErrCrossTenant
+	}
+
+	number, err := s.ticketService.GetTicketNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+
+	ticket, err := s.ticketService.Create(ctx, &dto.TicketRequest{
+		Number:      strconv.Itoa(number),
+		Status:      domain.TicketStatusNovo,
+		Priority:    priority,
+		Description: req.Description,
+		OpenDate:    time.Now().UTC().Format(time.RFC3339),
+		BranchID:    branch.ID,
+		AssetID:     req.AssetID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ticketResponse(ctx, ticket.ID)
+}
+
+// ListComments retorna apenas os comentários públicos do ticket
+func (s *clientPortalService) ListComments(ctx context.Context, userID, ticketID int) ([]domain.TicketComment, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publicComments(ctx, ticketID)
+}
+
+// AddComment registra um comentário público do cliente no ticket
+func (s *clientPortalService) AddComment(ctx context.Context, userID, ticketID int, req *dto.ClientCommentRequest) (*domain.TicketComment, error) {
+	ctx, user, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentService.Create(ctx, &domain.TicketComment{
+		TicketID: ticketID,
+		Author:   user.Name,
+		Body:     req.Body,
+		Source:   domain.CommentSourceAPI,
+		Public:   true,
+	})
+}
+
+// ListAttachments retorna apenas os anexos públicos do ticket
+func (s *clientPortalService) ListAttachments(ctx context.Context, userID, ticketID int) ([]domain.Attachment, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.publicAttachments(ctx, ticketID)
+}
+
+// UploadAttachment anexa um arquivo enviado pelo cliente, sempre público
+func (s *clientPortalService) UploadAttachment(ctx context.Context, userID, ticketID int, fileName, contentType string, content io.Reader) (*domain.Attachment, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attachmentService.Save(ctx, ticketID, fileName, contentType, true, content)
+}
+
+// FindAttachment retorna um anexo público do ticket para download
+func (s *clientPortalService) FindAttachment(ctx context.Context, userID, ticketID, id int) (*domain.Attachment, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentService.FindByID(ctx, ticketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !attachment.Public {
+		return nil, repository.ErrAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+// RateTicket registra a avaliação do atendimento; só tickets fechados, uma vez por ticket
+func (s *clientPortalService) RateTicket(ctx context.Context, userID, ticketID int, req *dto.TicketRatingRequest) (*dto.TicketRatingResponse, error) {
+	ctx, user, ticket, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.CloseDate == nil {
+		return nil, ErrTicketNotClosed
+	}
+
+	_, err = s.ratingRepo.Create(ctx, &domain.TicketRating{
+		TicketID: ticketID,
+		UserID:   user.ID,
+		Score:    req.Score,
+		Comment:  req.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := s.ratingRepo.FindByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTicketRatingResponse(rating), nil
+}
+
+// ListQuotes retorna as versões do orçamento do ticket, com valores, para aprovação
+func (s *clientPortalService) ListQuotes(ctx context.Context, userID, ticketID int) ([]domain.Quote, error) {
+	ctx, _, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.quoteService.List(ctx, ticketID)
+}
+
+// DecideQuote aprova ou rejeita o orçamento pendente em nome do usuário logado
+func (s *clientPortalService) DecideQuote(ctx context.Context, userID, ticketID, quoteID int, status string, req *dto.QuoteDecisionRequest) (*domain.Quote, error) {
+	ctx, user, _, err := s.clientTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.quoteService.Decide(ctx, ticketID, quoteID, &domain.QuoteDecision{
+		Status:    status,
+		DecidedBy: user.Name,
+		UserID:    &user.ID,
+		Channel:   domain.QuoteChannelPortal,
+		Note:      req.Note,
+	})
+}
+
+// ticketResponse monta a visão do cliente do ticket, com custos apenas se o contrato permitir
+func (s *clientPortalService) ticketResponse(ctx context.Context, ticketID int) (*dto.ClientTicketResponse, error) {
+	ticket, err := s.ticketService.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := s.contractRepo.FindForTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToClientTicketResponse(ticket, contract != nil && contract.ShowCosts)
+
+	rating, err := s.ratingRepo.FindByTicket(ctx, ticketID)
+	if err != nil && !errors.Is(err, repository.ErrRatingNotFound) {
+		return nil, err
+	}
+	response.Rating = dto.ToTicketRatingResponse(rating)
+
+	return response, nil
+}
+
+// applyTimeline monta a linha do tempo com abertura, visitas, presença do técnico, orçamentos,
+// comentários e anexos públicos, fechamento e avaliação, em ordem cronológica
+func (s *clientPortalService) applyTimeline(ctx context.Context, response *dto.ClientTicketResponse) error {
+	events := []dto.TimelineEvent{{At: response.OpenDate, Type: dto.TimelineOpened, Description: "Ticket aberto"}}
+
+	for _, visit := range response.Visits {
+		if visit.StartsAt != nil {
+			events = append(events, dto.TimelineEvent{
+				At:          *visit.StartsAt,
+				Type:        dto.TimelineVisit,
+				Description: "Visita agendada com " + visit.ProviderName,
+			})
+		}
+	}
+
+	checkins, err := s.checkinRepo.ListByTicket(ctx, response.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket checkins: %w", err)
+	}
+	for _, checkin := range checkins {
+		events = append(events, dto.TimelineEvent{At: checkin.CheckinAt, Type: dto.TimelineArrived, Description: "Técnico chegou à agência"})
+		if checkin.CheckoutAt != nil {
+			events = append(events, dto.TimelineEvent{At: *checkin.CheckoutAt, Type: dto.TimelineLeft, Description: "Técnico saiu da agência"})
+		}
+	}
+
+	quotes, err := s.quoteService.List(ctx, response.ID)
+	if err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		if quote.SentAt != nil {
+			events = append(events, dto.TimelineEvent{
+				At:          *quote.SentAt,
+				Type:        dto.TimelineQuote,
+				Description: fmt.Sprintf("Orçamento versão %d enviado", quote.Version),
+			})
+		}
+		if quote.DecidedAt != nil {
+			action := "aprovado"
+			if quote.Status == domain.QuoteRejected {
+				action = "rejeitado"
+			}
+			events = append(events, dto.TimelineEvent{
+				At:          *quote.DecidedAt,
+				Type:        dto.TimelineQuote,
+				Description: fmt.Sprintf("Orçamento versão %d %s por %s", quote.Version, action, quote.DecidedBy),
+			})
+		}
+	}
+
+	comments, err := s.publicComments(ctx, response.ID)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		events = append(events, dto.TimelineEvent{At: comment.CreatedAt, Type: dto.TimelineComment, Description: "Comentário de " + comment.Author})
+	}
+
+	attachments, err := s.publicAttachments(ctx, response.ID)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		events = append(events, dto.TimelineEvent{At: attachment.CreatedAt, Type: dto.TimelineAttachment, Description: "Anexo " + attachment.FileName})
+	}
+
+	if response.CloseDate != nil {
+		events = append(events, dto.TimelineEvent{At: *response.CloseDate, Type: dto.TimelineClosed, Description: "Ticket fechado"})
+	}
+	if response.Rating != nil {
+		events = append(events, dto.TimelineEvent{
+			At:          response.Rating.CreatedAt,
+			Type:        dto.TimelineRated,
+			Description: fmt.Sprintf("Atendimento avaliado com nota %d", response.Rating.Score),
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	response.Timeline = events
+
+	return nil
+}
+
+func (s *clientPortalService) publicComments(ctx context.Context, ticketID int) ([]domain.TicketComment, error) {
+	comments, err := s.commentService.ListByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	public := make([]domain.TicketComment, 0, len(comments))
+	for _, comment := range comments {
+		if comment.Public {
+			public = append(public, comment)
+		}
+	}
+	return public, nil
+}
+
+func (s *clientPortalService) publicAttachments(ctx context.Context, ticketID int) ([]domain.Attachment, error) {
+	attachments, err := s.attachmentService.ListByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	public := make([]domain.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment.Public {
+			public = append(public, attachment)
+		}
+	}
+	return public, nil
+}
+
+// clientUser busca o usuário logado, garante que ele está vinculado a um cliente e
+// restringe o context a esse cliente, mesmo que o token não traga o client_id
+func (s *clientPortalService) clientUser(ctx context.Context, userID int) (context.Context, *domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.ClientID == nil {
+		return nil, nil, ErrNotClientUser
+	}
+
+	return tenant.WithClient(ctx, *user.ClientID), user, nil
+}
+
+// clientTicket busca o ticket e garante que ele é de uma agência do cliente do usuário logado
+func (s *clientPortalService) clientTicket(ctx context.Context, userID, ticketID int) (context.Context, *domain.User, *domain.Ticket, error) {
+	ctx, user, err := s.clientUser(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, nil, nil, ErrClientTicketAccess
+	}
+
+	return ctx, user, ticket, nil
+}
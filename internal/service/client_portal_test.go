@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+// Usuário 10 é do cliente 1; usuário 11 é interno, sem cliente
+type fakeClientPortalUserRepo struct {
+	repository.UserRepository
+}
+
+func (f *fakeClientPortalUserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	user := &domain.User{ID: id, Name: "Maria"}
+	if id == 10 {
+		clientID := 1
+		user.ClientID = &clientID
+	}
+	return user, nil
+}
+
+// fakeClientPortalTicketRepo só devolve o ticket ao cliente dono, como o filtro de tenant
+type fakeClientPortalTicketRepo struct {
+	repository.TicketRepository
+	tickets map[int]domain.Ticket
+	owners  map[int]int
+}
+
+func (f *fakeClientPortalTicketRepo) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
+	ticket, ok := f.tickets[id]
+	clientID, scoped := tenant.ClientID(ctx)
+	if !ok || !scoped || f.owners[id] != clientID {
+		return nil, repository.ErrNotFound
+	}
+	return &ticket, nil
+}
+
+type fakeClientPortalBranchRepo struct {
+	repository.BranchRepository
+}
+
+// A agência 2 é do cliente 1 e a agência 3 do cliente 2
+func (f *fakeClientPortalBranchRepo) FindByID(ctx context.Context, id int) (*domain.Branch, error) {
+	return &domain.Branch{ID: id, ClientID: id - 1}, nil
+}
+
+type fakeClientPortalTicketService struct {
+	TicketService
+	created  *dto.TicketRequest
+	response dto.TicketResponse
+}
+
+func (f *fakeClientPortalTicketService) GetTicketNumber(ctx context.Context) (int, error) {
+	return 77, nil
+}
+
+func (f *fakeClientPortalTicketService) Create(ctx context.Context, req *dto.TicketRequest) (*dto.TicketResponse, error) {
+	f.created = req
+	return &dto.TicketResponse{ID: 50}, nil
+}
+
+func (f *fakeClientPortalTicketService) FindByID(ctx context.Context, id int) (*dto.TicketResponse, error) {
+	response := f.response
+	response.ID = id
+	return &response, nil
+}
+
+type fakeClientPortalRatingRepo struct {
+	repository.RatingRepository
+	rating *domain.TicketRating
+}
+
+func (f *fakeClientPortalRatingRepo) Create(ctx context.Context, rating *domain.TicketRating) (int, error) {
+	f.rating = rating
+	return 1, nil
+}
+
+func (f *fakeClientPortalRatingRepo) FindByTicket(ctx context.Context, ticketID int) (*domain.TicketRating, error) {
+	if f.rating == nil {
+		return nil, repository.ErrRatingNotFound
+	}
+	return f.rating, nil
+}
+
+type fakeClientPortalCheckinRepo struct {
+	repository.CheckinRepository
+	checkins []domain.TicketCheckin
+}
+
+func (f *fakeClientPortalCheckinRepo) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketCheckin, error) {
+	return f.checkins, nil
+}
+
+type fakeClientPortalCommentService struct {
+	CommentService
+	comments []domain.TicketComment
+}
+
+func (f *fakeClientPortalCommentService) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketComment, error) {
+	return f.comments, nil
+}
+
+type fakeClientPortalAttachmentService struct {
+	AttachmentService
+	attachments []domain.Attachment
+}
+
+func (f *fakeClientPortalAttachmentService) ListByTicket(ctx context.Context, ticketID int) ([]domain.Attachment, error) {
+	return f.attachments, nil
+}
+
+func (f *fakeClientPortalAttachmentService) FindByID(ctx context.Context, ticketID, id int) (*domain.Attachment, error) {
+	for _, attachment := range f.attachments {
+		if attachment.ID == id {
+			return &attachment, nil
+		}
+	}
+	return nil, repository.ErrAttachmentNotFound
+}
+
+type fakeClientPortalQuoteService struct {
+	QuoteService
+	quotes []domain.Quote
+}
+
+func (f *fakeClientPortalQuoteService) List(ctx context.Context, ticketID int) ([]domain.Quote, error) {
+	return f.quotes, nil
+}
+
+// newClientPortal monta o portal com o ticket 5 do cliente 1 e o ticket 6 do cliente 2
+func newClientPortal() *clientPortalService {
+	opened := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	return &clientPortalService{
+		userRepo: &fakeClientPortalUserRepo{},
+		ticketRepo: &fakeClientPortalTicketRepo{
+			tickets: map[int]domain.Ticket{5: {ID: 5, BranchID: 2, OpenDate: opened}, 6: {ID: 6, BranchID: 3, OpenDate: opened}},
+			owners:  map[int]int{5: 1, 6: 2},
+		},
+		branchRepo:        &fakeClientPortalBranchRepo{},
+		contractRepo:      &fakeContractRepo{},
+		checkinRepo:       &fakeClientPortalCheckinRepo{},
+		ratingRepo:        &fakeClientPortalRatingRepo{},
+		ticketService:     &fakeClientPortalTicketService{response: dto.TicketResponse{OpenDate: opened}},
+		commentService:    &fakeClientPortalCommentService{},
+		attachmentService: &fakeClientPortalAttachmentService{},
+		quoteService:      &fakeClientPortalQuoteService{},
+	}
+}
+
+func TestClientPortalTicketAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		ticketID int
+		want     error
+	}{
+		{"own ticket", 10, 5, nil},
+		{"ticket of another client", 10, 6, ErrClientTicketAccess},
+		{"missing ticket", 10, 99, ErrClientTicketAccess},
+		// Usuário interno não usa o portal, mesmo com um token de sistema
+		{"user without client", 11, 5, ErrNotClientUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := newClientPortal()
+			ctx := tenant.System(context.Background())
+
+			if _, err := portal.ListComments(ctx, tt.userID, tt.ticketID); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientPortalOpenTicket(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.ClientTicketRequest
+		want     error
+		priority string
+	}{
+		{"default priority", dto.ClientTicketRequest{BranchID: 2, Description: "Sem rede"}, nil, "medium"},
+		{"informed priority", dto.ClientTicketRequest{BranchID: 2, Description: "Sem rede", Priority: "alta"}, nil, "alta"},
+		{"branch of another client", dto.ClientTicketRequest{BranchID: 3, Description: "Sem rede"}, repository.ErrCrossTenant, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := newClientPortal()
+			tickets := portal.ticketService.(*fakeClientPortalTicketService)
+
+			_, err := portal.OpenTicket(context.Background(), 10, &tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if tickets.created != nil {
+					t.Fatalf("ticket created on another client's branch")
+				}
+				return
+			}
+
+			created := tickets.created
+			if created.Status != domain.TicketStatusNovo || created.Priority != tt.priority || created.Number != "77" || created.BranchID != 2 {
+				t.Fatalf("got ticket %+v", created)
+			}
+		})
+	}
+}
+
+func TestClientPortalRateTicket(t *testing.T) {
+	closed := time.Date(2026, 10, 3, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		closeDate *time.Time
+		want      error
+	}{
+		{"open ticket", nil, ErrTicketNotClosed},
+		{"closed ticket", &closed, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := newClientPortal()
+			tickets := portal.ticketRepo.(*fakeClientPortalTicketRepo)
+			ticket := tickets.tickets[5]
+			ticket.CloseDate = tt.closeDate
+			tickets.tickets[5] = ticket
+
+			rating, err := portal.RateTicket(context.Background(), 10, 5, &dto.TicketRatingRequest{Score: 4})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (rating.Score != 4 || portal.ratingRepo.(*fakeClientPortalRatingRepo).rating.UserID != 10) {
+				t.Fatalf("got rating %+v", rating)
+			}
+		})
+	}
+}
+
+func TestClientPortalFindAttachmentHidesPrivate(t *testing.T) {
+	portal := newClientPortal()
+	portal.attachmentService = &fakeClientPortalAttachmentService{attachments: []domain.Attachment{
+		{ID: 1, TicketID: 5, FileName: "foto.jpg", Public: true},
+		{ID: 2, TicketID: 5, FileName: "nota-interna.pdf"},
+	}}
+
+	tests := []struct {
+		name string
+		id   int
+		want error
+	}{
+		{"public", 1, nil},
+		{"private", 2, repository.ErrAttachmentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := portal.FindAttachment(context.Background(), 10, 5, tt.id); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	attachments, err := portal.ListAttachments(context.Background(), 10, 5)
+	if err != nil || len(attachments) != 1 || attachments[0].ID != 1 {
+		t.Fatalf("got %v (%v), want only the public attachment", attachments, err)
+	}
+}
+
+func TestClientPortalCostsFollowContract(t *testing.T) {
+	tests := []struct {
+		name      string
+		contract  *domain.Contract
+		showCosts bool
+	}{
+		{"no contract", nil, false},
+		{"contract hides costs", &domain.Contract{}, false},
+		{"contract shows costs", &domain.Contract{ShowCosts: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := newClientPortal()
+			portal.contractRepo = &fakeContractRepo{forTicket: tt.contract}
+			portal.ticketService.(*fakeClientPortalTicketService).response.TotalCost = domain.NewMoney(350)
+
+			ticket, err := portal.GetTicket(context.Background(), 10, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (ticket.TotalCost != nil) != tt.showCosts {
+				t.Fatalf("got total cost %v, want shown = %v", ticket.TotalCost, tt.showCosts)
+			}
+			if tt.showCosts && *ticket.TotalCost != domain.NewMoney(350) {
+				t.Fatalf("got total cost %v, want 350.00", *ticket.TotalCost)
+			}
+		})
+	}
+}
+
+func TestClientPortalTimelineIsChronological(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC) }
+	sent, checkout, closed := at(1, 14), at(2, 12), at(3, 17)
+
+	portal := newClientPortal()
+	portal.ticketService.(*fakeClientPortalTicketService).response.CloseDate = &closed
+	portal.checkinRepo = &fakeClientPortalCheckinRepo{checkins: []domain.TicketCheckin{{CheckinAt: at(2, 10), CheckoutAt: &checkout}}}
+	portal.quoteService = &fakeClientPortalQuoteService{quotes: []domain.Quote{{Version: 1, SentAt: &sent}}}
+	portal.commentService = &fakeClientPortalCommentService{comments: []domain.TicketComment{
+		{Author: "Maria", Public: true, CreatedAt: at(1, 11)},
+		// Comentário interno não aparece para o cliente
+		{Author: "Suporte", CreatedAt: at(1, 12)},
+	}}
+	portal.ratingRepo = &fakeClientPortalRatingRepo{rating: &domain.TicketRating{Score: 5, CreatedAt: at(4, 9)}}
+
+	ticket, err := portal.GetTicket(context.Background(), 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{dto.TimelineOpened, dto.TimelineComment, dto.TimelineQuote, dto.TimelineArrived, dto.TimelineLeft, dto.TimelineClosed, dto.TimelineRated}
+	if len(ticket.Timeline) != len(want) {
+		t.Fatalf("got %d events %+v, want %v", len(ticket.Timeline), ticket.Timeline, want)
+	}
+	for i, event := range ticket.Timeline {
+		if event.Type != want[i] {
+			t.Fatalf("event %d: got %q, want %q", i, event.Type, want[i])
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, comment *domain.TicketComment) (*domain.TicketComment, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketComment, error)
+}
+
+type commentService struct {
+	commentRepo repository.CommentRepository
+	ticketRepo  repository.TicketRepository
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, ticketRepo repository.TicketRepository) CommentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		ticketRepo:  ticketRepo,
+	}
+}
+
+func (s *commentService) Create(ctx context.Context, comment *domain.TicketComment) (*domain.TicketComment, error) {
+	if comment.Body == "" {
+		return nil, fmt.Errorf("comment body is required")
+	}
+
+	// Verificar se ticket existe
+	_, err := s.ticketRepo.FindByID(ctx, comment.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	if comment.Source == "" {
+		comment.Source = domain.CommentSourceAPI
+	}
+
+	id, err := s.commentRepo.Create(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	comment.ID = id
+	return comment, nil
+}
+
+func (s *commentService) ListByTicket(ctx context.Context, ticketID int) ([]domain.TicketComment, error) {
+	// Verificar se ticket existe
+	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	return s.commentRepo.ListByTicket(ctx, ticketID)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidContractPeriod  = errors.New("contract ends_on must not be before starts_on")
+	ErrContractOverlap        = errors.New("client already has a contract in this period")
+	ErrDuplicateContractPrice = errors.New("contract prices must reference distinct solutions")
+)
+
+type ContractService interface {
+	Create(ctx context.Context, contract *domain.Contract) (*domain.Contract, error)
+	FindByID(ctx context.Context, id int) (*domain.Contract, error)
+	List(ctx context.Context, clientID *int) ([]domain.Contract, error)
+	Update(ctx context.Context, contract *domain.Contract) (*domain.Contract, error)
+	Delete(ctx context.Context, id int) error
+
+	// Resolução de preços pelo contrato vigente na abertura do ticket
+	ForTicket(ctx context.Context, ticketID int) (*domain.Contract, error)
+	SolutionPrice(ctx context.Context, ticketID int, solution *domain.Solution) (domain.Money, error)
+	TravelCostTable(ctx context.Context, ticketID int) (*domain.Cost, error)
+}
+
+type contractService struct {
+	contractRepo repository.ContractRepository
+	clientRepo   repository.ClientRepository
+	solutionRepo repository.SolutionRepository
+	costRepo     repository.CostRepository
+}
+
+func NewContractService(
+	contractRepo repository.ContractRepository,
+	clientRepo repository.ClientRepository,
+	solutionRepo repository.SolutionRepository,
+	costRepo repository.CostRepository,
+) ContractService {
+	return &contractService{
+		contractRepo: contractRepo,
+		clientRepo:   clientRepo,
+		solutionRepo: solutionRepo,
+		costRepo:     costRepo,
+	}
+}
+
+func (s *contractService) Create(ctx context.Context, contract *domain.Contract) (*domain.Contract, error) {
+	if err := s.validate(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	id, err := s.contractRepo.Create(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.contractRepo.FindByID(ctx, id)
+}
+
+func (s *contractService) FindByID(ctx context.Context, id int) (*domain.Contract, error) {
+	return s.contractRepo.FindByID(ctx, id)
+}
+
+func (s *contractService) List(ctx context.Context, clientID *int) ([]domain.Contract, error) {
+	return s.contractRepo.List(ctx, clientID)
+}
+
+func (s *contractService) Update(ctx context.Context, contract *domain.Contract) (*domain.Contract, error) {
+	if _, err := s.contractRepo.FindByID(ctx, contract.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	return s.contractRepo.FindByID(ctx, contract.ID)
+}
+
+func (s *contractService) Delete(ctx context.Context, id int) error {
+	return s.contractRepo.Delete(ctx, id)
+}
+
+// validate confere cliente, vigência, soluções e sobreposição com outros contratos do cliente
+func (s *contractService) validate(ctx context.Context, contract *domain.Contract) error {
+	if _, err := s.clientRepo.FindByID(ctx, contract.ClientID); err != nil {
+		return fmt.Errorf("client not found: %w", err)
+	}
+
+	if contract.EndsOn != nil && contract.EndsOn.Before(contract.StartsOn) {
+		return ErrInvalidContractPeriod
+	}
+
+	seen := make(map[int]bool, len(contract.Prices))
+	for _, price := range contract.Prices {
+		if seen[price.SolutionID] {
+			return ErrDuplicateContractPrice
+		}
+		seen[price.SolutionID] = true
+
+		if _, err := s.solutionRepo.FindByID(ctx, price.SolutionID); err != nil {
+			return fmt.Errorf("solution %d not found: %w", price.SolutionID, err)
+		}
+	}
+
+	// Só um contrato vigente por cliente em cada data
+	existing, err := s.contractRepo.List(ctx, &contract.ClientID)
+	if err != nil {
+		return err
+	}
+	for i := range existing {
+		if existing[i].ID != contract.ID && existing[i].Overlaps(contract) {
+			return ErrContractOverlap
+		}
+	}
+
+	return nil
+}
+
+func (s *contractService) ForTicket(ctx context.Context, ticketID int) (*domain.Contract, error) {
+	return s.contractRepo.FindForTicket(ctx, ticketID)
+}
+
+// SolutionPrice retorna o preço negociado no contrato do ticket ou, sem ele, o preço do catálogo
+func (s *contractService) SolutionPrice(ctx context.Context, ticketID int, solution *domain.Solution) (domain.Money, error) {
+	contract, err := s.contractRepo.FindForTicket(ctx, ticketID)
+	if err != nil {
+		return 0, err
+	}
+
+	if contract != nil {
+		if price, ok := contract.SolutionPrice(solution.ID); ok {
+			return price, nil
+		}
+	}
+
+	return solution.UnitPrice, nil
+}
+
+// TravelCostTable retorna a tabela de custos vigente com os valores de deslocamento do contrato do ticket
+func (s *contractService) TravelCostTable(ctx context.Context, ticketID int) (*domain.Cost, error) {
+	cost, err := s.costRepo.FindCurrent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current cost table: %w", err)
+	}
+
+	contract, err := s.contractRepo.FindForTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if contract != nil {
+		return contract.ApplyTo(cost), nil
+	}
+
+	return cost, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeContractRepo struct {
+	repository.ContractRepository
+	contracts []domain.Contract
+	forTicket *domain.Contract
+}
+
+func (f *fakeContractRepo) List(ctx context.Context, clientID *int) ([]domain.Contract, error) {
+	var contracts []domain.Contract
+	for _, contract := range f.contracts {
+		if clientID == nil || contract.ClientID == *clientID {
+			contracts = append(contracts, contract)
+		}
+	}
+	return contracts, nil
+}
+
+func (f *fakeContractRepo) FindForTicket(ctx context.Context, ticketID int) (*domain.Contract, error) {
+	return f.forTicket, nil
+}
+
+type fakeContractClientRepo struct {
+	repository.ClientRepository
+}
+
+func (f *fakeContractClientRepo) FindByID(ctx context.Context, id int) (*domain.Client, error) {
+	if id != 1 {
+		return nil, repository.ErrNotFound
+	}
+	return &domain.Client{ID: id}, nil
+}
+
+type fakeContractSolutionRepo struct {
+	repository.SolutionRepository
+}
+
+func (f *fakeContractSolutionRepo) FindByID(ctx context.Context, id int) (*domain.Solution, error) {
+	if id > 10 {
+		return nil, repository.ErrNotFound
+	}
+	return &domain.Solution{ID: id}, nil
+}
+
+type fakeContractCostRepo struct {
+	repository.CostRepository
+}
+
+func (f *fakeContractCostRepo) FindCurrent(ctx context.Context) (*domain.Cost, error) {
+	return &domain.Cost{ValuePerKm: domain.NewMoney(1.5), InitialValue: domain.NewMoney(40)}, nil
+}
+
+func newContractService(contractRepo *fakeContractRepo) *contractService {
+	return &contractService{
+		contractRepo: contractRepo,
+		clientRepo:   &fakeContractClientRepo{},
+		solutionRepo: &fakeContractSolutionRepo{},
+		costRepo:     &fakeContractCostRepo{},
+	}
+}
+
+func TestContractValidate(t *testing.T) {
+	day := func(month, d int) *time.Time {
+		date := time.Date(2026, time.Month(month), d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	// Contrato vigente do cliente 1 no primeiro semestre
+	svc := newContractService(&fakeContractRepo{contracts: []domain.Contract{
+		{ID: 1, ClientID: 1, StartsOn: *day(1, 1), EndsOn: day(6, 30)},
+	}})
+
+	tests := []struct {
+		name     string
+		contract domain.Contract
+		want     error
+	}{
+		{"next semester", domain.Contract{ClientID: 1, StartsOn: *day(7, 1)}, nil},
+		{"overlapping period", domain.Contract{ClientID: 1, StartsOn: *day(6, 30)}, ErrContractOverlap},
+		// Na atualização o próprio contrato não conta como sobreposição
+		{"updating itself", domain.Contract{ID: 1, ClientID: 1, StartsOn: *day(1, 1), EndsOn: day(3, 31)}, nil},
+		{"ends before it starts", domain.Contract{ClientID: 1, StartsOn: *day(8, 1), EndsOn: day(7, 31)}, ErrInvalidContractPeriod},
+		{"duplicated solution", domain.Contract{ClientID: 1, StartsOn: *day(7, 1), Prices: []domain.ContractPrice{
+			{SolutionID: 2, UnitPrice: domain.NewMoney(100)}, {SolutionID: 2, UnitPrice: domain.NewMoney(90)},
+		}}, ErrDuplicateContractPrice},
+		{"unknown solution", domain.Contract{ClientID: 1, StartsOn: *day(7, 1), Prices: []domain.ContractPrice{
+			{SolutionID: 11, UnitPrice: domain.NewMoney(100)},
+		}}, repository.ErrNotFound},
+		{"unknown client", domain.Contract{ClientID: 2, StartsOn: *day(7, 1)}, repository.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.validate(context.Background(), &tt.contract); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestContractSolutionPrice(t *testing.T) {
+	withPrice := &domain.Contract{Prices: []domain.ContractPrice{{SolutionID: 2, UnitPrice: domain.NewMoney(80)}}}
+	solution := &domain.Solution{ID: 2, UnitPrice: domain.NewMoney(100)}
+
+	tests := []struct {
+		name     string
+		contract *domain.Contract
+		want     domain.Money
+	}{
+		{"no contract", nil, domain.NewMoney(100)},
+		{"negotiated price", withPrice, domain.NewMoney(80)},
+		// Contrato sem preço para a solution usa o catálogo
+		{"solution not in contract", &domain.Contract{}, domain.NewMoney(100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newContractService(&fakeContractRepo{forTicket: tt.contract})
+			price, err := svc.SolutionPrice(context.Background(), 1, solution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price != tt.want {
+				t.Fatalf("got %v, want %v", price, tt.want)
+			}
+		})
+	}
+}
+
+func TestContractTravelCostTable(t *testing.T) {
+	perKm := domain.NewMoney(1.2)
+
+	tests := []struct {
+		name     string
+		contract *domain.Contract
+		want     domain.Money
+	}{
+		{"no contract", nil, domain.NewMoney(1.5)},
+		{"contract without travel values", &domain.Contract{}, domain.NewMoney(1.5)},
+		{"contract value per km", &domain.Contract{ValuePerKm: &perKm}, domain.NewMoney(1.2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newContractService(&fakeContractRepo{forTicket: tt.contract})
+			cost, err := svc.TravelCostTable(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cost.ValuePerKm != tt.want || cost.InitialValue != domain.NewMoney(40) {
+				t.Fatalf("got %+v, want value per km %v and initial value 40.00", cost, tt.want)
+			}
+		})
+	}
+}
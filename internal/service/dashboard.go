@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type DashboardService interface {
+	Dashboard(ctx context.Context, filter domain.DashboardFilter) (*domain.Dashboard, error)
+}
+
+type dashboardService struct {
+	dashboardRepo repository.DashboardRepository
+	clientRepo    repository.ClientRepository
+}
+
+func NewDashboardService(dashboardRepo repository.DashboardRepository, clientRepo repository.ClientRepository) DashboardService {
+	return &dashboardService{
+		dashboardRepo: dashboardRepo,
+		clientRepo:    clientRepo,
+	}
+}
+
+func (s *dashboardService) Dashboard(ctx context.Context, filter domain.DashboardFilter) (*domain.Dashboard, error) {
+	// Validar se client existe
+	if filter.ClientID != nil {
+		if _, err := s.clientRepo.FindByID(ctx, *filter.ClientID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.dashboardRepo.Dashboard(ctx, filter)
+}
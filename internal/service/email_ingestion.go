@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/mailbox"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var ErrBranchNotMatched = errors.New("could not match email to a branch")
+
+var (
+	// "Re: Chamado #123", "Ticket 123", "[#123]"
+	ticketNumberPattern = regexp.MustCompile(`(?i)(?:ticket|chamado)\s*(?:n[º°o]\.?\s*)?#?\s*(\d+)|\[#(\d+)\]`)
+	// Uniorg no formato "001-0001"
+	uniorgPattern = regexp.MustCompile(`\b\d{3}-\d{4}\b`)
+)
+
+// EmailIngestionService transforma emails recebidos das agências em tickets
+type EmailIngestionService interface {
+	// Poll lê as mensagens pendentes da caixa e processa uma a uma
+	Poll(ctx context.Context) error
+	// Process cria um ticket (ou comentário, se for resposta) a partir da mensagem.
+	// Mensagem já ingerida (mesmo Message-ID) é ignorada.
+	Process(ctx context.Context, msg *mailbox.Message) error
+}
+
+type emailIngestionService struct {
+	source            mailbox.Source
+	ticketService     TicketService
+	commentService    CommentService
+	attachmentService AttachmentService
+	ticketRepo        repository.TicketRepository
+	branchRepo        repository.BranchRepository
+	emailMessageRepo  repository.EmailMessageRepository
+	txManager         repository.TxManager
+}
+
+func NewEmailIngestionService(
+	source mailbox.Source,
+	ticketService TicketService,
+	commentService CommentService,
+	attachmentService AttachmentService,
+	ticketRepo repository.TicketRepository,
+	branchRepo repository.BranchRepository,
+	emailMessageRepo repository.EmailMessageRepository,
+	txManager repository.TxManager,
+) EmailIngestionService {
+	return &emailIngestionService{
+		source:            source,
+		ticketService:     ticketService,
+		commentService:    commentService,
+		attachmentService: attachmentService,
+		ticketRepo:        ticketRepo,
+		branchRepo:        branchRepo,
+		emailMessageRepo:  emailMessageRepo,
+		txManager:         txManager,
+	}
+}
+
+func (s *emailIngestionService) Poll(ctx context.Context) error {
+	// Com falha no meio da leitura, as mensagens já lidas são processadas mesmo assim
+	messages, fetchErr := s.source.Fetch(ctx)
+
+	for i := range messages {
+		msg := &messages[i]
+
+		if err := s.Process(ctx, msg); err != nil {
+			// A mensagem fica na caixa: é lida de novo no próximo poll ou tratada manualmente
+			log.Printf("Email %s from %s not ingested: %v", msg.ID, msg.From, err)
+			continue
+		}
+
+		if err := s.source.MarkProcessed(ctx, msg.ID); err != nil {
+			// Lida de novo, a mensagem é ignorada pelo Message-ID registrado
+			log.Printf("Email %s not marked as processed: %v", msg.ID, err)
+		}
+	}
+
+	if fetchErr != nil {
+		return fmt.Errorf("failed to fetch emails: %w", fetchErr)
+	}
+
+	return nil
+}
+
+func (s *emailIngestionService) Process(ctx context.Context, msg *mailbox.Message) error {
+	if msg.MessageID != "" {
+		record, err := s.emailMessageRepo.FindByMessageID(ctx, msg.MessageID)
+		if err == nil {
+			// Já ingerida: só faltam os anexos que falharam na leitura anterior
+			return s.saveAttachments(ctx, record.TicketID, record.AttachmentsSaved, msg)
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+	}
+
+	// O ticket (ou comentário) e o Message-ID são gravados juntos
+	var ticketID int
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if ticketID, err = s.ingest(ctx, msg); err != nil {
+			return err
+		}
+
+		if msg.MessageID == "" {
+			return nil
+		}
+		return s.emailMessageRepo.Create(ctx, msg.MessageID, ticketID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Anexos vão para o disco, fora da transação
+	return s.saveAttachments(ctx, ticketID, 0, msg)
+}
+
+// ingest cria o ticket (ou o comentário, se for resposta) e retorna o ticket
+func (s *emailIngestionService) ingest(ctx context.Context, msg *mailbox.Message) (int, error) {
+	// Resposta a um ticket existente vira comentário
+	if number := ticketNumberFromSubject(msg.Subject); number != "" {
+		ticket, err := s.ticketRepo.FindByNumber(ctx, number)
+		if err == nil {
+			return ticket.ID, s.addReply(ctx, ticket, msg)
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return 0, fmt.Errorf("failed to find ticket %s: %w", number, err)
+		}
+	}
+
+	branch, err := s.matchBranch(ctx, msg)
+	if err != nil {
+		return 0, err
+	}
+
+	number, err := s.ticketService.GetTicketNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	description := msg.Subject
+	if msg.Body != "" {
+		description = msg.Subject + "\n\n" + msg.Body
+	}
+
+	ticket, err := s.ticketService.Create(ctx, &dto.TicketRequest{
+		Number:      strconv.Itoa(number),
+		Status:      domain.TicketStatusNovo,
+		Priority:    "medium",
+		Description: description,
+		OpenDate:    msg.Date.UTC().Format(time.RFC3339),
+		BranchID:    branch.ID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create ticket from email: %w", err)
+	}
+
+	return ticket.ID, nil
+}
+
+func (s *emailIngestionService) addReply(ctx context.Context, ticket *domain.Ticket, msg *mailbox.Message) error {
+	author := msg.From
+	if msg.FromName != "" {
+		author = fmt.Sprintf("%s <%s>", msg.FromName, msg.From)
+	}
+
+	body := msg.Body
+	if body == "" {
+		body = msg.Subject
+	}
+
+	_, err := s.commentService.Create(ctx, &domain.TicketComment{
+		TicketID: ticket.ID,
+		Author:   author,
+		Body:     body,
+		Source:   domain.CommentSourceEmail,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add email reply to ticket %s: %w", ticket.Number, err)
+	}
+
+	return nil
+}
+
+// saveAttachments grava os anexos da mensagem a partir de from, na ordem, e registra quantos
+// já estão no ticket. Com falha, a mensagem volta para a caixa e a próxima leitura retoma do
+// anexo que falhou, sem criar outro ticket. Sem Message-ID não há como retomar: a falha fica
+// registrada em um comentário no ticket.
+func (s *emailIngestionService) saveAttachments(ctx context.Context, ticketID, from int, msg *mailbox.Message) error {
+	var failed []string
+	for i := from; i < len(msg.Attachments); i++ {
+		attachment := msg.Attachments[i]
+		_, err := s.attachmentService.Save(ctx, ticketID, attachment.FileName, attachment.ContentType, false, bytes.NewReader(attachment.Data))
+		if err != nil {
+			err = fmt.Errorf("attachment %s not saved on ticket %d: %w", attachment.FileName, ticketID, err)
+			if msg.MessageID != "" {
+				return err
+			}
+			log.Printf("Email %s: %v", msg.ID, err)
+			failed = append(failed, attachment.FileName)
+			continue
+		}
+
+		if msg.MessageID != "" {
+			if err := s.emailMessageRepo.UpdateAttachmentsSaved(ctx, msg.MessageID, i+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	_, err := s.commentService.Create(ctx, &domain.TicketComment{
+		TicketID: ticketID,
+		Author:   msg.From,
+		Body:     fmt.Sprintf("Anexos do email não foram gravados: %s", strings.Join(failed, ", ")),
+		Source:   domain.CommentSourceEmail,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record attachment failure on ticket %d: %w", ticketID, err)
+	}
+
+	return nil
+}
+
+// matchBranch identifica a agência pelo uniorg citado no email ou,
+// na falta dele, pelo domínio do remetente quando só uma agência o utiliza
+func (s *emailIngestionService) matchBranch(ctx context.Context, msg *mailbox.Message) (*domain.Branch, error) {
+	for _, uniorg := range uniorgPattern.FindAllString(msg.Subject+"\n"+msg.Body, -1) {
+		branch, err := s.branchRepo.FindByUniorg(ctx, uniorg)
+		if err == nil {
+			return branch, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	senderDomain := msg.SenderDomain()
+	if senderDomain == "" {
+		return nil, ErrBranchNotMatched
+	}
+
+	branches, err := s.branchRepo.FindByEmailDomain(ctx, senderDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(branches) != 1 {
+		return nil, fmt.Errorf("%w: %d branches use domain %s", ErrBranchNotMatched, len(branches), senderDomain)
+	}
+
+	return &branches[0], nil
+}
+
+func ticketNumberFromSubject(subject string) string {
+	match := ticketNumberPattern.FindStringSubmatch(subject)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1] + match[2])
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/mailbox"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+// Fakes: embutem a interface e implementam só o que a ingestão usa
+
+type fakeMailSource struct {
+	messages  []mailbox.Message
+	fetchErr  error
+	markErr   error
+	processed []string
+}
+
+func (f *fakeMailSource) Fetch(ctx context.Context) ([]mailbox.Message, error) {
+	return f.messages, f.fetchErr
+}
+
+func (f *fakeMailSource) MarkProcessed(ctx context.Context, id string) error {
+	if f.markErr != nil {
+		return f.markErr
+	}
+	f.processed = append(f.processed, id)
+	return nil
+}
+
+func (f *fakeMailSource) Close() error { return nil }
+
+type fakeIngestionTicketService struct {
+	TicketService
+	created []dto.TicketRequest
+}
+
+func (f *fakeIngestionTicketService) GetTicketNumber(ctx context.Context) (int, error) {
+	return 100 + len(f.created), nil
+}
+
+func (f *fakeIngestionTicketService) Create(ctx context.Context, req *dto.TicketRequest) (*dto.TicketResponse, error) {
+	f.created = append(f.created, *req)
+	return &dto.TicketResponse{ID: len(f.created), Number: req.Number}, nil
+}
+
+type fakeIngestionAttachmentService struct {
+	AttachmentService
+	err error
+	// failFile limita a falha a um anexo; vazio falha todos
+	failFile string
+	saved    []string
+}
+
+func (f *fakeIngestionAttachmentService) Save(ctx context.Context, ticketID int, fileName, contentType string, public bool, content io.Reader) (*domain.Attachment, error) {
+	if f.err != nil && (f.failFile == "" || f.failFile == fileName) {
+		return nil, f.err
+	}
+	f.saved = append(f.saved, fileName)
+	return &domain.Attachment{TicketID: ticketID, FileName: fileName}, nil
+}
+
+type fakeIngestionTicketRepo struct {
+	repository.TicketRepository
+}
+
+func (f *fakeIngestionTicketRepo) FindByNumber(ctx context.Context, number string) (*domain.Ticket, error) {
+	return nil, repository.ErrNotFound
+}
+
+type fakeIngestionBranchRepo struct {
+	repository.BranchRepository
+}
+
+func (f *fakeIngestionBranchRepo) FindByUniorg(ctx context.Context, uniorg string) (*domain.Branch, error) {
+	return &domain.Branch{ID: 7, Uniorg: uniorg}, nil
+}
+
+type fakeEmailMessageRepo struct {
+	messages map[string]*domain.EmailMessage
+}
+
+func (f *fakeEmailMessageRepo) FindByMessageID(ctx context.Context, messageID string) (*domain.EmailMessage, error) {
+	message, ok := f.messages[messageID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return message, nil
+}
+
+func (f *fakeEmailMessageRepo) Create(ctx context.Context, messageID string, ticketID int) error {
+	f.messages[messageID] = &domain.EmailMessage{MessageID: messageID, TicketID: ticketID}
+	return nil
+}
+
+func (f *fakeEmailMessageRepo) UpdateAttachmentsSaved(ctx context.Context, messageID string, saved int) error {
+	f.messages[messageID].AttachmentsSaved = saved
+	return nil
+}
+
+type fakeIngestionCommentService struct {
+	CommentService
+	created []domain.TicketComment
+}
+
+func (f *fakeIngestionCommentService) Create(ctx context.Context, comment *domain.TicketComment) (*domain.TicketComment, error) {
+	f.created = append(f.created, *comment)
+	return comment, nil
+}
+
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type ingestionFixture struct {
+	source      *fakeMailSource
+	tickets     *fakeIngestionTicketService
+	attachments *fakeIngestionAttachmentService
+	comments    *fakeIngestionCommentService
+	service     EmailIngestionService
+}
+
+func newIngestionFixture(messages ...mailbox.Message) *ingestionFixture {
+	f := &ingestionFixture{
+		source:      &fakeMailSource{messages: messages},
+		tickets:     &fakeIngestionTicketService{},
+		attachments: &fakeIngestionAttachmentService{},
+		comments:    &fakeIngestionCommentService{},
+	}
+	f.service = NewEmailIngestionService(f.source, f.tickets, f.comments, f.attachments, &fakeIngestionTicketRepo{},
+		&fakeIngestionBranchRepo{}, &fakeEmailMessageRepo{messages: map[string]*domain.EmailMessage{}}, fakeTxManager{})
+	return f
+}
+
+func ingestionMessage(id string) mailbox.Message {
+	return mailbox.Message{
+		ID:          id,
+		MessageID:   "<" + id + "@banco.com.br>",
+		From:        "centro@banco.com.br",
+		Subject:     "Agência 001-0001 sem energia",
+		Attachments: []mailbox.Attachment{{FileName: "foto.jpg", ContentType: "image/jpeg", Data: []byte("jpg")}},
+	}
+}
+
+func TestEmailIngestion_AttachmentFailureRetriesWithoutDuplicatingTicket(t *testing.T) {
+	msg := ingestionMessage("1")
+	msg.Attachments = append(msg.Attachments, mailbox.Attachment{FileName: "laudo.pdf", ContentType: "application/pdf", Data: []byte("pdf")})
+	f := newIngestionFixture(msg)
+	f.attachments.err = errors.New("disk full")
+	f.attachments.failFile = "laudo.pdf"
+
+	// O segundo anexo falha: a mensagem fica na caixa para ser lida de novo
+	if err := f.service.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(f.source.processed) != 0 {
+		t.Fatalf("message marked as processed with an attachment missing: %v", f.source.processed)
+	}
+
+	f.attachments.err = nil
+	if err := f.service.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(f.tickets.created) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(f.tickets.created))
+	}
+	// A nova leitura retoma do anexo que falhou
+	if len(f.attachments.saved) != 2 || f.attachments.saved[0] != "foto.jpg" || f.attachments.saved[1] != "laudo.pdf" {
+		t.Fatalf("got attachments %v, want [foto.jpg laudo.pdf]", f.attachments.saved)
+	}
+	if len(f.source.processed) != 1 || f.source.processed[0] != "1" {
+		t.Fatalf("message not marked as processed: %v", f.source.processed)
+	}
+}
+
+func TestEmailIngestion_AttachmentFailureWithoutMessageIDIsRecordedOnTicket(t *testing.T) {
+	msg := ingestionMessage("1")
+	msg.MessageID = ""
+	f := newIngestionFixture(msg)
+	f.attachments.err = errors.New("disk full")
+
+	// Sem Message-ID uma nova leitura duplicaria o ticket: a falha vai para o ticket
+	if err := f.service.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(f.source.processed) != 1 {
+		t.Fatalf("message not marked as processed: %v", f.source.processed)
+	}
+	if len(f.comments.created) != 1 || f.comments.created[0].TicketID != 1 ||
+		!strings.Contains(f.comments.created[0].Body, "foto.jpg") {
+		t.Fatalf("attachment failure not recorded on the ticket: %+v", f.comments.created)
+	}
+}
+
+func TestEmailIngestion_MarkFailureDoesNotDuplicateTicket(t *testing.T) {
+	f := newIngestionFixture(ingestionMessage("1"))
+	f.source.markErr = errors.New("imap connection lost")
+
+	// A mensagem continua na caixa e é lida de novo a cada poll
+	for i := 0; i < 3; i++ {
+		if err := f.service.Poll(context.Background()); err != nil {
+			t.Fatalf("poll %d: unexpected error %v", i, err)
+		}
+	}
+
+	if len(f.tickets.created) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(f.tickets.created))
+	}
+	if len(f.attachments.saved) != 1 {
+		t.Fatalf("got %d attachments saved, want 1", len(f.attachments.saved))
+	}
+}
+
+func TestEmailIngestion_PollProcessesMessagesReadBeforeFetchError(t *testing.T) {
+	f := newIngestionFixture(ingestionMessage("1"), ingestionMessage("2"))
+	f.source.fetchErr = context.DeadlineExceeded
+
+	err := f.service.Poll(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the fetch error", err)
+	}
+	if len(f.tickets.created) != 2 || len(f.source.processed) != 2 {
+		t.Fatalf("got %d tickets and %d processed, want 2 and 2", len(f.tickets.created), len(f.source.processed))
+	}
+}
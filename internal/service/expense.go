@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrReceiptNotFound         = errors.New("receipt must be an attachment of the same ticket")
+	ErrInvalidExpenseReview    = errors.New("review must decide every expense item exactly once")
+	ErrRejectionReasonRequired = errors.New("rejected expense items require a reason")
+)
+
+type ExpenseService interface {
+	Submit(ctx context.Context, ticketID, providerID int, req *dto.ExpenseRequest) (*domain.Expense, error)
+	List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error)
+	FindByID(ctx context.Context, id int) (*domain.Expense, error)
+	Review(ctx context.Context, id, userID int, req *dto.ExpenseReviewRequest) (*domain.Expense, error)
+}
+
+type expenseService struct {
+	expenseRepo    repository.ExpenseRepository
+	ticketRepo     repository.TicketRepository
+	attachmentRepo repository.AttachmentRepository
+	txManager      repository.TxManager
+}
+
+func NewExpenseService(
+	expenseRepo repository.ExpenseRepository,
+	ticketRepo repository.TicketRepository,
+	attachmentRepo repository.AttachmentRepository,
+	txManager repository.TxManager,
+) ExpenseService {
+	return &expenseService{
+		expenseRepo:    expenseRepo,
+		ticketRepo:     ticketRepo,
+		attachmentRepo: attachmentRepo,
+		txManager:      txManager,
+	}
+}
+
+// Submit registra a prestação de contas do técnico e coloca o ticket em Prestação de Contas
+func (s *expenseService) Submit(ctx context.Context, ticketID, providerID int, req *dto.ExpenseRequest) (*domain.Expense, error) {
+	// Verificar se ticket existe e ainda está aberto
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+	if ticket.Status == domain.TicketStatusConcluido {
+		return nil, ErrTicketConcluded
+	}
+
+	// Ticket em extrato de pagamento não recebe novas despesas
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticketID); err != nil {
+		return nil, err
+	}
+
+	expense := &domain.Expense{
+		TicketID:   ticketID,
+		ProviderID: providerID,
+		Notes:      req.Notes,
+	}
+	for _, item := range req.Items {
+		// Comprovante precisa ser um anexo do próprio ticket
+		if item.AttachmentID != nil {
+			attachment, err := s.attachmentRepo.FindByID(ctx, *item.AttachmentID)
+			if err != nil || attachment.TicketID != ticketID {
+				return nil, ErrReceiptNotFound
+			}
+		}
+
+		expense.Items = append(expense.Items, domain.ExpenseItem{
+			Description:  item.Description,
+			Quantity:     item.Quantity,
+			UnitPrice:    item.UnitPrice,
+			AttachmentID: item.AttachmentID,
+		})
+	}
+
+	var id int
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if id, err = s.expenseRepo.Create(ctx, expense); err != nil {
+			return err
+		}
+
+		if ticket.Status != domain.TicketStatusPrestacaoDeContas {
+			if err := s.ticketRepo.UpdateStatus(ctx, ticketID, domain.TicketStatusPrestacaoDeContas); err != nil {
+				return fmt.Errorf("failed to update ticket status: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.expenseRepo.FindByID(ctx, id)
+}
+
+func (s *expenseService) List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error) {
+	return s.expenseRepo.List(ctx, ticketID, status)
+}
+
+func (s *expenseService) FindByID(ctx context.Context, id int) (*domain.Expense, error) {
+	return s.expenseRepo.FindByID(ctx, id)
+}
+
+// Review decide cada item da prestação pendente. Com algum item rejeitado, o ticket vai para
+// Contas Não Aprovadas e o técnico reapresenta as despesas; com tudo aprovado, segue para faturamento.
+func (s *expenseService) Review(ctx context.Context, id, userID int, req *dto.ExpenseReviewRequest) (*domain.Expense, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if expense.Status != domain.ExpensePending {
+		return nil, repository.ErrExpenseStatus
+	}
+
+	decisions := make(map[int]dto.ExpenseItemDecision, len(req.Items))
+	for _, decision := range req.Items {
+		if _, ok := decisions[decision.ItemID]; ok {
+			return nil, ErrInvalidExpenseReview
+		}
+		decisions[decision.ItemID] = decision
+	}
+	if len(decisions) != len(expense.Items) {
+		return nil, ErrInvalidExpenseReview
+	}
+
+	expense.Status = domain.ExpenseApproved
+	expense.DecisionNote = req.Note
+	for i := range expense.Items {
+		item := &expense.Items[i]
+		decision, ok := decisions[item.ID]
+		if !ok {
+			return nil, ErrInvalidExpenseReview
+		}
+
+		if decision.Approved {
+			item.Status = domain.ExpenseApproved
+			item.RejectionReason = ""
+			continue
+		}
+
+		if strings.TrimSpace(decision.Reason) == "" {
+			return nil, ErrRejectionReasonRequired
+		}
+		item.Status = domain.ExpenseRejected
+		item.RejectionReason = decision.Reason
+		expense.Status = domain.ExpenseRejected
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.Review(ctx, expense, userID); err != nil {
+			return err
+		}
+
+		ticket, err := s.ticketRepo.FindByID(ctx, expense.TicketID)
+		if err != nil {
+			return fmt.Errorf("ticket not found: %w", err)
+		}
+
+		// Ticket concluído não volta no fluxo
+		if ticket.Status == domain.TicketStatusConcluido {
+			return nil
+		}
+
+		next := domain.TicketStatusContasNaoAprovadas
+		if expense.Status == domain.ExpenseApproved {
+			// Só segue para faturamento quando não restam outras prestações aguardando revisão
+			pending, err := s.expenseRepo.List(ctx, &ticket.ID, domain.ExpensePending)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return nil
+			}
+			next = domain.TicketStatusAguardaFaturamento
+		}
+
+		if err := s.ticketRepo.UpdateStatus(ctx, ticket.ID, next); err != nil {
+			return fmt.Errorf("failed to update ticket status: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.expenseRepo.FindByID(ctx, id)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeExpenseRepo struct {
+	repository.ExpenseRepository
+	expenses map[int]*domain.Expense
+}
+
+func (f *fakeExpenseRepo) FindByID(ctx context.Context, id int) (*domain.Expense, error) {
+	expense, ok := f.expenses[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *expense
+	copied.Items = append([]domain.ExpenseItem(nil), expense.Items...)
+	return &copied, nil
+}
+
+func (f *fakeExpenseRepo) List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error) {
+	var expenses []domain.Expense
+	for _, expense := range f.expenses {
+		if (ticketID == nil || expense.TicketID == *ticketID) && (status == "" || expense.Status == status) {
+			expenses = append(expenses, *expense)
+		}
+	}
+	return expenses, nil
+}
+
+func (f *fakeExpenseRepo) Review(ctx context.Context, expense *domain.Expense, userID int) error {
+	stored := *expense
+	f.expenses[expense.ID] = &stored
+	return nil
+}
+
+type fakeTicketRepo struct {
+	repository.TicketRepository
+	ticket domain.Ticket
+}
+
+func (f *fakeTicketRepo) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
+	ticket := f.ticket
+	return &ticket, nil
+}
+
+func (f *fakeTicketRepo) UpdateStatus(ctx context.Context, ticketID int, status int) error {
+	f.ticket.Status = status
+	return nil
+}
+
+func TestExpenseReviewWaitsForPendingSubmissions(t *testing.T) {
+	pending := func(id, itemID int) *domain.Expense {
+		return &domain.Expense{ID: id, TicketID: 5, ProviderID: 3, Status: domain.ExpensePending,
+			Items: []domain.ExpenseItem{{ID: itemID, ExpenseID: id, Quantity: 1, UnitPrice: 1000}}}
+	}
+	expenses := &fakeExpenseRepo{expenses: map[int]*domain.Expense{1: pending(1, 10), 2: pending(2, 20)}}
+	tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, Status: domain.TicketStatusPrestacaoDeContas}}
+	service := NewExpenseService(expenses, tickets, nil, fakeTxManager{})
+	ctx := context.Background()
+
+	approve := func(id, itemID int) {
+		t.Helper()
+		req := &dto.ExpenseReviewRequest{Items: []dto.ExpenseItemDecision{{ItemID: itemID, Approved: true}}}
+		if _, err := service.Review(ctx, id, 1, req); err != nil {
+			t.Fatalf("review %d: unexpected error %v", id, err)
+		}
+	}
+
+	// A outra prestação ainda aguarda revisão: o ticket não segue para faturamento
+	approve(1, 10)
+	if tickets.ticket.Status != domain.TicketStatusPrestacaoDeContas {
+		t.Fatalf("got ticket status %d with a pending expense, want Prestação de Contas", tickets.ticket.Status)
+	}
+
+	approve(2, 20)
+	if tickets.ticket.Status != domain.TicketStatusAguardaFaturamento {
+		t.Fatalf("got ticket status %d, want Aguarda Faturamento", tickets.ticket.Status)
+	}
+}
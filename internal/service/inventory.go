@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidMovement      = errors.New("invalid stock movement")
+	ErrInvalidLocationKind  = errors.New("location kind must be warehouse or van")
+	ErrInvalidSolutionParts = errors.New("solution parts must reference distinct items with positive quantity")
+)
+
+type InventoryService interface {
+	// Itens e locais
+	CreateItem(ctx context.Context, item *domain.InventoryItem) (*domain.InventoryItem, error)
+	ListItems(ctx context.Context) ([]domain.InventoryItem, error)
+	FindItemByID(ctx context.Context, id int) (*domain.InventoryItem, []domain.StockLevel, error)
+	UpdateItem(ctx context.Context, item *domain.InventoryItem) (*domain.InventoryItem, error)
+	CreateLocation(ctx context.Context, location *domain.StockLocation) (*domain.StockLocation, error)
+	ListLocations(ctx context.Context) ([]domain.StockLocation, error)
+
+	// Movimentações e relatórios
+	RecordMovement(ctx context.Context, movement *domain.StockMovement) error
+	Transfer(ctx context.Context, itemID, fromLocationID, toLocationID, quantity int, note string) error
+	ListMovements(ctx context.Context, itemID, limit, offset int) ([]domain.StockMovement, int, error)
+	LowStock(ctx context.Context) ([]domain.LowStockItem, error)
+
+	// Lista de materiais (BOM)
+	GetSolutionParts(ctx context.Context, solutionID int) ([]domain.SolutionPart, error)
+	SetSolutionParts(ctx context.Context, solutionID int, parts []domain.SolutionPart) ([]domain.SolutionPart, error)
+
+	// Reservas do ticket
+	ReserveForSolution(ctx context.Context, ticket *domain.Ticket, solutionID, quantity int) ([]int, error)
+	ReleaseForSolution(ctx context.Context, ticketID, solutionID int) error
+	ReleaseTicket(ctx context.Context, ticketID int) error
+	ConsumeTicket(ctx context.Context, ticketID int) error
+	ListTicketReservations(ctx context.Context, ticketID int) ([]domain.StockReservation, error)
+}
+
+type inventoryService struct {
+	inventoryRepo repository.InventoryRepository
+	solutionRepo  repository.SolutionRepository
+	providerRepo  repository.ProviderRepository
+}
+
+func NewInventoryService(
+	inventoryRepo repository.InventoryRepository,
+	solutionRepo repository.SolutionRepository,
+	providerRepo repository.ProviderRepository,
+) InventoryService {
+	return &inventoryService{
+		inventoryRepo: inventoryRepo,
+		solutionRepo:  solutionRepo,
+		providerRepo:  providerRepo,
+	}
+}
+
+func (s *inventoryService) CreateItem(ctx context.Context, item *domain.InventoryItem) (*domain.InventoryItem, error) {
+	if item.Unit == "" {
+		item.Unit = "un"
+	}
+
+	id, err := s.inventoryRepo.CreateItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.FindItemByID(ctx, id)
+}
+
+func (s *inventoryService) ListItems(ctx context.Context) ([]domain.InventoryItem, error) {
+	return s.inventoryRepo.ListItems(ctx)
+}
+
+func (s *inventoryService) FindItemByID(ctx context.Context, id int) (*domain.InventoryItem, []domain.StockLevel, error) {
+	item, err := s.inventoryRepo.FindItemByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	levels, err := s.inventoryRepo.ListLevelsByItem(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return item, levels, nil
+}
+
+func (s *inventoryService) UpdateItem(ctx context.Context, item *domain.InventoryItem) (*domain.InventoryItem, error) {
+	if item.Unit == "" {
+		item.Unit = "un"
+	}
+
+	if err := s.inventoryRepo.UpdateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.FindItemByID(ctx, item.ID)
+}
+
+func (s *inventoryService) CreateLocation(ctx context.Context, location *domain.StockLocation) (*domain.StockLocation, error) {
+	switch location.Kind {
+	case domain.StockLocationWarehouse:
+		location.ProviderID = nil
+	case domain.StockLocationVan:
+		// Carro precisa pertencer a um técnico
+		if location.ProviderID == nil {
+			return nil, fmt.Errorf("%w: van requires provider_id", ErrInvalidLocationKind)
+		}
+		if _, err := s.providerRepo.FindByID(ctx, *location.ProviderID); err != nil {
+			return nil, fmt.Errorf("provider not found: %w", err)
+		}
+	default:
+		return nil, ErrInvalidLocationKind
+	}
+
+	id, err := s.inventoryRepo.CreateLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.FindLocationByID(ctx, id)
+}
+
+func (s *inventoryService) ListLocations(ctx context.Context) ([]domain.StockLocation, error) {
+	return s.inventoryRepo.ListLocations(ctx)
+}
+
+// RecordMovement registra entrada/saída (quantidade positiva) ou ajuste (quantidade com sinal)
+func (s *inventoryService) RecordMovement(ctx context.Context, movement *domain.StockMovement) error {
+	switch movement.Kind {
+	case domain.MovementIn, domain.MovementOut:
+		if movement.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive", ErrInvalidMovement)
+		}
+	case domain.MovementAdjust:
+		if movement.Quantity == 0 {
+			return fmt.Errorf("%w: quantity must not be zero", ErrInvalidMovement)
+		}
+	default:
+		return fmt.Errorf("%w: kind must be in, out or adjust", ErrInvalidMovement)
+	}
+
+	if _, err := s.inventoryRepo.FindItemByID(ctx, movement.ItemID); err != nil {
+		return err
+	}
+	if _, err := s.inventoryRepo.FindLocationByID(ctx, movement.LocationID); err != nil {
+		return err
+	}
+
+	return s.inventoryRepo.RecordMovement(ctx, movement)
+}
+
+func (s *inventoryService) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID, quantity int, note string) error {
+	if quantity <= 0 || fromLocationID == toLocationID {
+		return fmt.Errorf("%w: transfer needs positive quantity and distinct locations", ErrInvalidMovement)
+	}
+
+	if _, err := s.inventoryRepo.FindItemByID(ctx, itemID); err != nil {
+		return err
+	}
+	for _, locationID := range []int{fromLocationID, toLocationID} {
+		if _, err := s.inventoryRepo.FindLocationByID(ctx, locationID); err != nil {
+			return err
+		}
+	}
+
+	return s.inventoryRepo.Transfer(ctx, itemID, fromLocationID, toLocationID, quantity, note)
+}
+
+func (s *inventoryService) ListMovements(ctx context.Context, itemID, limit, offset int) ([]domain.StockMovement, int, error) {
+	if _, err := s.inventoryRepo.FindItemByID(ctx, itemID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.inventoryRepo.ListMovementsByItem(ctx, itemID, limit, offset)
+}
+
+func (s *inventoryService) LowStock(ctx context.Context) ([]domain.LowStockItem, error) {
+	return s.inventoryRepo.LowStock(ctx)
+}
+
+func (s *inventoryService) GetSolutionParts(ctx context.Context, solutionID int) ([]domain.SolutionPart, error) {
+	if _, err := s.solutionRepo.FindByID(ctx, solutionID); err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.ListSolutionParts(ctx, solutionID)
+}
+
+func (s *inventoryService) SetSolutionParts(ctx context.Context, solutionID int, parts []domain.SolutionPart) ([]domain.SolutionPart, error) {
+	if _, err := s.solutionRepo.FindByID(ctx, solutionID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range parts {
+		if part.Quantity <= 0 || seen[part.ItemID] {
+			return nil, ErrInvalidSolutionParts
+		}
+		seen[part.ItemID] = true
+
+		if _, err := s.inventoryRepo.FindItemByID(ctx, part.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.inventoryRepo.ReplaceSolutionParts(ctx, solutionID, parts); err != nil {
+		return nil, err
+	}
+
+	return s.inventoryRepo.ListSolutionParts(ctx, solutionID)
+}
+
+// ReserveForSolution reserva as peças do BOM da solution para o ticket.
+// O carro do técnico do ticket tem prioridade sobre o almoxarifado central.
+func (s *inventoryService) ReserveForSolution(ctx context.Context, ticket *domain.Ticket, solutionID, quantity int) ([]int, error) {
+	parts, err := s.inventoryRepo.ListSolutionParts(ctx, solutionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	locations, err := s.inventoryRepo.ListLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var vans, warehouses []int
+	for _, location := range locations {
+		switch {
+		case location.Kind == domain.StockLocationVan && ticket.ProviderID != nil &&
+			location.ProviderID != nil && *location.ProviderID == *ticket.ProviderID:
+			vans = append(vans, location.ID)
+		case location.Kind == domain.StockLocationWarehouse:
+			warehouses = append(warehouses, location.ID)
+		}
+	}
+
+	return s.inventoryRepo.Reserve(ctx, ticket.ID, solutionID, parts, quantity, append(vans, warehouses...))
+}
+
+func (s *inventoryService) ReleaseForSolution(ctx context.Context, ticketID, solutionID int) error {
+	return s.inventoryRepo.ReleaseReservations(ctx, ticketID, &solutionID)
+}
+
+func (s *inventoryService) ReleaseTicket(ctx context.Context, ticketID int) error {
+	return s.inventoryRepo.ReleaseReservations(ctx, ticketID, nil)
+}
+
+func (s *inventoryService) ConsumeTicket(ctx context.Context, ticketID int) error {
+	return s.inventoryRepo.ConsumeReservations(ctx, ticketID)
+}
+
+func (s *inventoryService) ListTicketReservations(ctx context.Context, ticketID int) ([]domain.StockReservation, error) {
+	return s.inventoryRepo.ListReservationsByTicket(ctx, ticketID)
+}
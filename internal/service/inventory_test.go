@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeInventoryRepo struct {
+	repository.InventoryRepository
+	locations []domain.StockLocation
+	parts     []domain.SolutionPart
+
+	// Argumentos da última chamada gravada
+	recorded    *domain.StockMovement
+	transferred bool
+	reservedIn  []int
+	replaced    []domain.SolutionPart
+}
+
+func (f *fakeInventoryRepo) FindItemByID(ctx context.Context, id int) (*domain.InventoryItem, error) {
+	return &domain.InventoryItem{ID: id}, nil
+}
+
+func (f *fakeInventoryRepo) FindLocationByID(ctx context.Context, id int) (*domain.StockLocation, error) {
+	return &domain.StockLocation{ID: id}, nil
+}
+
+func (f *fakeInventoryRepo) CreateLocation(ctx context.Context, location *domain.StockLocation) (int, error) {
+	f.locations = append(f.locations, *location)
+	return len(f.locations), nil
+}
+
+func (f *fakeInventoryRepo) ListLocations(ctx context.Context) ([]domain.StockLocation, error) {
+	return f.locations, nil
+}
+
+func (f *fakeInventoryRepo) RecordMovement(ctx context.Context, movement *domain.StockMovement) error {
+	f.recorded = movement
+	return nil
+}
+
+func (f *fakeInventoryRepo) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID, quantity int, note string) error {
+	f.transferred = true
+	return nil
+}
+
+func (f *fakeInventoryRepo) ListSolutionParts(ctx context.Context, solutionID int) ([]domain.SolutionPart, error) {
+	return f.parts, nil
+}
+
+func (f *fakeInventoryRepo) ReplaceSolutionParts(ctx context.Context, solutionID int, parts []domain.SolutionPart) error {
+	f.replaced = parts
+	return nil
+}
+
+func (f *fakeInventoryRepo) Reserve(ctx context.Context, ticketID, solutionID int, parts []domain.SolutionPart, multiplier int, locationIDs []int) ([]int, error) {
+	f.reservedIn = locationIDs
+	return []int{1}, nil
+}
+
+type fakeInventorySolutionRepo struct {
+	repository.SolutionRepository
+}
+
+func (f *fakeInventorySolutionRepo) FindByID(ctx context.Context, id int) (*domain.Solution, error) {
+	return &domain.Solution{ID: id}, nil
+}
+
+type fakeInventoryProviderRepo struct {
+	repository.ProviderRepository
+}
+
+func (f *fakeInventoryProviderRepo) FindByID(ctx context.Context, id int) (*domain.Provider, error) {
+	return &domain.Provider{ID: id}, nil
+}
+
+func newInventoryService(repo *fakeInventoryRepo) InventoryService {
+	return NewInventoryService(repo, &fakeInventorySolutionRepo{}, &fakeInventoryProviderRepo{})
+}
+
+func TestInventoryRecordMovementValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		quantity int
+		wantErr  error
+	}{
+		{"stock in", domain.MovementIn, 5, nil},
+		{"stock out", domain.MovementOut, 2, nil},
+		{"negative adjustment", domain.MovementAdjust, -3, nil},
+		{"stock in without quantity", domain.MovementIn, 0, ErrInvalidMovement},
+		{"negative stock out", domain.MovementOut, -1, ErrInvalidMovement},
+		{"zero adjustment", domain.MovementAdjust, 0, ErrInvalidMovement},
+		// Reservas e baixas só nascem dos tickets
+		{"manual reservation", domain.MovementReserve, 1, ErrInvalidMovement},
+		{"manual consumption", domain.MovementConsume, 1, ErrInvalidMovement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepo{}
+			err := newInventoryService(repo).RecordMovement(context.Background(),
+				&domain.StockMovement{ItemID: 1, LocationID: 1, Kind: tt.kind, Quantity: tt.quantity})
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if (repo.recorded != nil) != (tt.wantErr == nil) {
+				t.Fatalf("movement recorded: %v, want %v", repo.recorded != nil, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestInventoryTransferValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		quantity int
+		wantErr  error
+	}{
+		{"between locations", 1, 2, 3, nil},
+		{"same location", 1, 1, 3, ErrInvalidMovement},
+		{"without quantity", 1, 2, 0, ErrInvalidMovement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepo{}
+			err := newInventoryService(repo).Transfer(context.Background(), 7, tt.from, tt.to, tt.quantity, "")
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if repo.transferred != (tt.wantErr == nil) {
+				t.Fatalf("transferred: %v, want %v", repo.transferred, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestInventorySetSolutionPartsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		parts   []domain.SolutionPart
+		wantErr error
+	}{
+		{"distinct items", []domain.SolutionPart{{ItemID: 1, Quantity: 2}, {ItemID: 2, Quantity: 1}}, nil},
+		{"empty list clears the bom", nil, nil},
+		{"repeated item", []domain.SolutionPart{{ItemID: 1, Quantity: 2}, {ItemID: 1, Quantity: 1}}, ErrInvalidSolutionParts},
+		{"zero quantity", []domain.SolutionPart{{ItemID: 1, Quantity: 0}}, ErrInvalidSolutionParts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepo{}
+			_, err := newInventoryService(repo).SetSolutionParts(context.Background(), 3, tt.parts)
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInventoryCreateLocation(t *testing.T) {
+	provider := 4
+
+	tests := []struct {
+		name         string
+		location     domain.StockLocation
+		wantErr      error
+		wantProvider bool
+	}{
+		{"warehouse", domain.StockLocation{Name: "Central", Kind: domain.StockLocationWarehouse}, nil, false},
+		{"warehouse ignores provider", domain.StockLocation{Name: "Central", Kind: domain.StockLocationWarehouse, ProviderID: &provider}, nil, false},
+		{"provider van", domain.StockLocation{Name: "Carro", Kind: domain.StockLocationVan, ProviderID: &provider}, nil, true},
+		{"van without provider", domain.StockLocation{Name: "Carro", Kind: domain.StockLocationVan}, ErrInvalidLocationKind, false},
+		{"unknown kind", domain.StockLocation{Name: "Loja", Kind: "store"}, ErrInvalidLocationKind, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepo{}
+			location := tt.location
+			_, err := newInventoryService(repo).CreateLocation(context.Background(), &location)
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (repo.locations[0].ProviderID != nil) != tt.wantProvider {
+				t.Fatalf("got provider %v, want provider set: %v", repo.locations[0].ProviderID, tt.wantProvider)
+			}
+		})
+	}
+}
+
+func TestInventoryReserveForSolutionPrefersProviderVan(t *testing.T) {
+	provider, other := 4, 5
+	locations := []domain.StockLocation{
+		{ID: 1, Kind: domain.StockLocationWarehouse},
+		{ID: 2, Kind: domain.StockLocationVan, ProviderID: &other},
+		{ID: 3, Kind: domain.StockLocationVan, ProviderID: &provider},
+		{ID: 4, Kind: domain.StockLocationWarehouse},
+	}
+
+	tests := []struct {
+		name     string
+		provider *int
+		parts    []domain.SolutionPart
+		want     []int
+	}{
+		{"provider van before warehouses", &provider, []domain.SolutionPart{{ItemID: 7, Quantity: 1}}, []int{3, 1, 4}},
+		{"without provider only warehouses", nil, []domain.SolutionPart{{ItemID: 7, Quantity: 1}}, []int{1, 4}},
+		{"solution without bom reserves nothing", &provider, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInventoryRepo{locations: locations, parts: tt.parts}
+			ticket := &domain.Ticket{ID: 10, ProviderID: tt.provider}
+			if _, err := newInventoryService(repo).ReserveForSolution(context.Background(), ticket, 3, 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(repo.reservedIn, tt.want) {
+				t.Fatalf("got locations %v, want %v", repo.reservedIn, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/nfse"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidInvoicePeriod = errors.New("invoice period must end on or after its start")
+	ErrNothingToInvoice     = errors.New("invoice total must be greater than zero")
+	ErrIssuerNotConfigured  = errors.New("nfs-e issuer is not configured")
+	ErrInvoiceNotIssued     = errors.New("invoice has no issued nfs-e")
+)
+
+type InvoiceService interface {
+	Create(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	List(ctx context.Context, clientID *int, status string) ([]domain.Invoice, error)
+	FindByID(ctx context.Context, id int) (*domain.Invoice, error)
+	Issue(ctx context.Context, id int) (*domain.Invoice, error)
+	Cancel(ctx context.Context, id int, reason string) (*domain.Invoice, error)
+	Delete(ctx context.Context, id int) error
+	XML(ctx context.Context, id int) (string, error)
+}
+
+type invoiceService struct {
+	invoiceRepo      repository.InvoiceRepository
+	clientRepo       repository.ClientRepository
+	inventoryService InventoryService // Baixa das peças reservadas dos tickets concluídos
+	issuer           nfse.Issuer      // nil quando a emissão de NFS-e está desabilitada
+	company          nfse.Party       // Prestador do serviço (empresa)
+	serviceCode      string           // Item da lista de serviços informado no RPS
+	txManager        repository.TxManager
+}
+
+func NewInvoiceService(
+	invoiceRepo repository.InvoiceRepository,
+	clientRepo repository.ClientRepository,
+	inventoryService InventoryService,
+	issuer nfse.Issuer,
+	company nfse.Party,
+	serviceCode string,
+	txManager repository.TxManager,
+) InvoiceService {
+	return &invoiceService{
+		invoiceRepo:      invoiceRepo,
+		clientRepo:       clientRepo,
+		inventoryService: inventoryService,
+		issuer:           issuer,
+		company:          company,
+		serviceCode:      serviceCode,
+		txManager:        txManager,
+	}
+}
+
+// Create gera a fatura em rascunho com os tickets do cliente aguardando faturamento no ciclo
+func (s *invoiceService) Create(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	if invoice.PeriodEnd.Before(invoice.PeriodStart) {
+		return nil, ErrInvalidInvoicePeriod
+	}
+
+	// Validar se client existe
+	if _, err := s.clientRepo.FindByID(ctx, invoice.ClientID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.invoiceRepo.Create(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.FindByID(ctx, id)
+}
+
+func (s *invoiceService) List(ctx context.Context, clientID *int, status string) ([]domain.Invoice, error) {
+	return s.invoiceRepo.List(ctx, clientID, status)
+}
+
+func (s *invoiceService) FindByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	return s.invoiceRepo.FindByID(ctx, id)
+}
+
+// Issue transmite o rascunho para a prefeitura e grava o número e o XML da NFS-e autorizada.
+// A fatura fica em Emitindo durante a transmissão; se a gravação falhar depois da nota
+// autorizada, a nota é cancelada no emissor e a fatura volta para rascunho.
+func (s *invoiceService) Issue(ctx context.Context, id int) (*domain.Invoice, error) {
+	if s.issuer == nil {
+		return nil, ErrIssuerNotConfigured
+	}
+
+	invoice, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != domain.InvoiceDraft {
+		return nil, repository.ErrInvoiceStatus
+	}
+
+	if invoice.Total() <= 0 {
+		return nil, ErrNothingToInvoice
+	}
+
+	// Reservar a fatura antes de chamar o emissor: uma emissão simultânea recebe 409
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return s.invoiceRepo.ClaimIssue(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.issuer.Issue(ctx, nfse.Request{
+		Reference: strconv.Itoa(invoice.ID),
+		IssuedAt:  time.Now(),
+		Provider:  s.company,
+		Taker: nfse.Party{
+			Name:     invoice.ClientName,
+			Document: invoice.ClientDocument,
+		},
+		ServiceCode: s.serviceCode,
+		Description: invoiceDescription(invoice),
+		Amount:      invoice.Total().Cents(),
+	})
+	if err != nil {
+		s.releaseIssue(ctx, id)
+		return nil, fmt.Errorf("failed to issue nfs-e: %w", err)
+	}
+
+	invoice.Issuer = s.issuer.Name()
+	invoice.Number = result.Number
+	invoice.VerificationCode = result.VerificationCode
+	invoice.XML = result.XML
+	invoice.IssuedAt = &result.IssuedAt
+
+	// A fatura emitida conclui os tickets; como na conclusão pelo ticket, as peças reservadas
+	// são baixadas do estoque na mesma transação
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.invoiceRepo.MarkIssued(ctx, invoice); err != nil {
+			return err
+		}
+
+		for _, ticket := range invoice.Tickets {
+			if err := s.inventoryService.ConsumeTicket(ctx, ticket.TicketID); err != nil {
+				return fmt.Errorf("failed to consume stock: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// A nota existe na prefeitura mas não na fatura: cancelar para que uma nova tentativa
+		// não gere um segundo documento fiscal
+		ctx := context.WithoutCancel(ctx)
+		if cancelErr := s.issuer.Cancel(ctx, result.Number, "Falha ao registrar a emissão"); cancelErr != nil {
+			// Sem o cancelamento a fatura fica em Emitindo, bloqueando nova emissão até a conferência manual
+			log.Printf("Invoice %d: nfs-e %s issued but not recorded and not cancelled: %v", id, result.Number, cancelErr)
+			return nil, errors.Join(err, fmt.Errorf("failed to cancel nfs-e %s: %w", result.Number, cancelErr))
+		}
+		s.releaseIssue(ctx, id)
+		return nil, err
+	}
+
+	return s.invoiceRepo.FindByID(ctx, id)
+}
+
+// releaseIssue devolve a fatura para rascunho depois de uma emissão que não se completou
+func (s *invoiceService) releaseIssue(ctx context.Context, id int) {
+	if err := s.invoiceRepo.ReleaseIssue(context.WithoutCancel(ctx), id); err != nil {
+		log.Printf("Invoice %d: failed to return to draft after issue failure: %v", id, err)
+	}
+}
+
+// Cancel cancela a NFS-e na prefeitura e devolve os tickets para Aguarda Faturamento
+func (s *invoiceService) Cancel(ctx context.Context, id int, reason string) (*domain.Invoice, error) {
+	if s.issuer == nil {
+		return nil, ErrIssuerNotConfigured
+	}
+
+	invoice, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != domain.InvoiceIssued {
+		return nil, repository.ErrInvoiceStatus
+	}
+
+	if err := s.issuer.Cancel(ctx, invoice.Number, reason); err != nil {
+		return nil, fmt.Errorf("failed to cancel nfs-e: %w", err)
+	}
+
+	if err := s.invoiceRepo.Cancel(ctx, id, reason); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.FindByID(ctx, id)
+}
+
+func (s *invoiceService) Delete(ctx context.Context, id int) error {
+	return s.invoiceRepo.Delete(ctx, id)
+}
+
+// XML retorna o XML da NFS-e devolvido pela prefeitura
+func (s *invoiceService) XML(ctx context.Context, id int) (string, error) {
+	invoice, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if invoice.XML == "" {
+		return "", ErrInvoiceNotIssued
+	}
+
+	return invoice.XML, nil
+}
+
+// invoiceDescription monta a discriminação do serviço com o período e os chamados faturados
+func invoiceDescription(invoice *domain.Invoice) string {
+	numbers := make([]string, 0, len(invoice.Tickets))
+	for _, ticket := range invoice.Tickets {
+		numbers = append(numbers, ticket.Number)
+	}
+
+	return fmt.Sprintf("Serviços de manutenção no período de %s a %s. Chamados: %s.",
+		invoice.PeriodStart.Format("02/01/2006"),
+		invoice.PeriodEnd.Format("02/01/2006"),
+		strings.Join(numbers, ", "))
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/nfse"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+// fakeInvoiceRepo guarda uma fatura em memória e aplica as transições de status como o
+// UPDATE condicionado do repositório
+type fakeInvoiceRepo struct {
+	repository.InvoiceRepository
+	mu      sync.Mutex
+	invoice domain.Invoice
+}
+
+func (f *fakeInvoiceRepo) FindByID(ctx context.Context, id int) (*domain.Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	invoice := f.invoice
+	return &invoice, nil
+}
+
+func (f *fakeInvoiceRepo) transition(from, to string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.invoice.Status != from {
+		return repository.ErrInvoiceStatus
+	}
+	f.invoice.Status = to
+	return nil
+}
+
+func (f *fakeInvoiceRepo) ClaimIssue(ctx context.Context, id int) error {
+	return f.transition(domain.InvoiceDraft, domain.InvoiceIssuing)
+}
+
+func (f *fakeInvoiceRepo) ReleaseIssue(ctx context.Context, id int) error {
+	return f.transition(domain.InvoiceIssuing, domain.InvoiceDraft)
+}
+
+func (f *fakeInvoiceRepo) MarkIssued(ctx context.Context, invoice *domain.Invoice) error {
+	if err := f.transition(domain.InvoiceIssuing, domain.InvoiceIssued); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.invoice.Number = invoice.Number
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeInvoiceRepo) Cancel(ctx context.Context, id int, reason string) error {
+	return f.transition(domain.InvoiceIssued, domain.InvoiceCancelled)
+}
+
+// invoiceTxManager desfaz as alterações da fatura quando a unidade de trabalho falha
+type invoiceTxManager struct {
+	repo *fakeInvoiceRepo
+}
+
+func (m invoiceTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.repo.mu.Lock()
+	snapshot := m.repo.invoice
+	m.repo.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		m.repo.mu.Lock()
+		m.repo.invoice = snapshot
+		m.repo.mu.Unlock()
+	}
+	return err
+}
+
+type fakeInvoiceInventory struct {
+	InventoryService
+	err error
+}
+
+func (f *fakeInvoiceInventory) ConsumeTicket(ctx context.Context, ticketID int) error {
+	return f.err
+}
+
+// recordingIssuer conta as chamadas ao emissor e pode segurar a emissão até ser liberado
+type recordingIssuer struct {
+	*nfse.Fake
+	mu        sync.Mutex
+	issued    []string
+	cancelled []string
+	entered   chan struct{}
+	release   chan struct{}
+}
+
+func (r *recordingIssuer) Issue(ctx context.Context, req nfse.Request) (*nfse.Result, error) {
+	if r.entered != nil {
+		r.entered <- struct{}{}
+		<-r.release
+	}
+	result, err := r.Fake.Issue(ctx, req)
+	if err == nil {
+		r.mu.Lock()
+		r.issued = append(r.issued, result.Number)
+		r.mu.Unlock()
+	}
+	return result, err
+}
+
+func (r *recordingIssuer) Cancel(ctx context.Context, number, reason string) error {
+	r.mu.Lock()
+	r.cancelled = append(r.cancelled, number)
+	r.mu.Unlock()
+	return r.Fake.Cancel(ctx, number, reason)
+}
+
+func draftInvoice() domain.Invoice {
+	return domain.Invoice{
+		ID: 1, ClientID: 2, Status: domain.InvoiceDraft, ClientName: "Banco", ClientDocument: "00000000000191",
+		Tickets: []domain.InvoiceTicket{{TicketID: 5, Number: "2026-0005"}},
+		Lines:   []domain.InvoiceLine{{Kind: domain.InvoiceLineService, TicketID: 5, Quantity: 1, Amount: 15000}},
+	}
+}
+
+func TestInvoiceIssueCancelsNfseWhenRecordingFails(t *testing.T) {
+	invoices := &fakeInvoiceRepo{invoice: draftInvoice()}
+	inventory := &fakeInvoiceInventory{err: repository.ErrInsufficientStock}
+	issuer := &recordingIssuer{Fake: nfse.NewFake()}
+	service := NewInvoiceService(invoices, nil, inventory, issuer, nfse.Party{}, "14.01", invoiceTxManager{invoices})
+	ctx := context.Background()
+
+	if _, err := service.Issue(ctx, 1); !errors.Is(err, repository.ErrInsufficientStock) {
+		t.Fatalf("got %v, want the stock error", err)
+	}
+	if len(issuer.issued) != 1 || len(issuer.cancelled) != 1 || issuer.cancelled[0] != issuer.issued[0] {
+		t.Fatalf("got issued %v and cancelled %v, want the nfs-e cancelled", issuer.issued, issuer.cancelled)
+	}
+	if invoices.invoice.Status != domain.InvoiceDraft {
+		t.Fatalf("got status %q, want the invoice back to draft", invoices.invoice.Status)
+	}
+
+	// Com o estoque resolvido, a nova tentativa emite uma única nota válida
+	inventory.err = nil
+	invoice, err := service.Issue(ctx, 1)
+	if err != nil {
+		t.Fatalf("retry: unexpected error %v", err)
+	}
+	if invoice.Status != domain.InvoiceIssued || len(issuer.issued) != 2 || invoice.Number != issuer.issued[1] {
+		t.Fatalf("got status %q, nfs-e %q and issued %v", invoice.Status, invoice.Number, issuer.issued)
+	}
+}
+
+func TestInvoiceIssueFailureReturnsToDraft(t *testing.T) {
+	invoice := draftInvoice()
+	invoice.ClientDocument = "" // Prefeitura recusa tomador sem documento
+	invoices := &fakeInvoiceRepo{invoice: invoice}
+	service := NewInvoiceService(invoices, nil, &fakeInvoiceInventory{}, &recordingIssuer{Fake: nfse.NewFake()},
+		nfse.Party{}, "14.01", invoiceTxManager{invoices})
+
+	if _, err := service.Issue(context.Background(), 1); !errors.Is(err, nfse.ErrRejected) {
+		t.Fatalf("got %v, want ErrRejected", err)
+	}
+	if invoices.invoice.Status != domain.InvoiceDraft {
+		t.Fatalf("got status %q, want draft", invoices.invoice.Status)
+	}
+}
+
+func TestInvoiceConcurrentIssueCallsIssuerOnce(t *testing.T) {
+	invoices := &fakeInvoiceRepo{invoice: draftInvoice()}
+	issuer := &recordingIssuer{Fake: nfse.NewFake(), entered: make(chan struct{}), release: make(chan struct{})}
+	service := NewInvoiceService(invoices, nil, &fakeInvoiceInventory{}, issuer, nfse.Party{}, "14.01", invoiceTxManager{invoices})
+	ctx := context.Background()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := service.Issue(ctx, 1)
+		first <- err
+	}()
+
+	// Com a primeira emissão no emissor, a segunda encontra a fatura em Emitindo
+	<-issuer.entered
+	if _, err := service.Issue(ctx, 1); !errors.Is(err, repository.ErrInvoiceStatus) {
+		t.Fatalf("second issue: got %v, want ErrInvoiceStatus", err)
+	}
+	close(issuer.release)
+
+	if err := <-first; err != nil {
+		t.Fatalf("first issue: unexpected error %v", err)
+	}
+	if len(issuer.issued) != 1 || invoices.invoice.Status != domain.InvoiceIssued {
+		t.Fatalf("got %d nfs-e and status %q, want 1 and issued", len(issuer.issued), invoices.invoice.Status)
+	}
+}
+
+func TestInvoiceIssueGuards(t *testing.T) {
+	withStatus := func(status string) domain.Invoice {
+		invoice := draftInvoice()
+		invoice.Status = status
+		return invoice
+	}
+	withoutLines := draftInvoice()
+	withoutLines.Lines = nil
+
+	tests := []struct {
+		name    string
+		invoice domain.Invoice
+		issuer  nfse.Issuer
+		want    error
+	}{
+		{"issuer not configured", draftInvoice(), nil, ErrIssuerNotConfigured},
+		{"issuing", withStatus(domain.InvoiceIssuing), nfse.NewFake(), repository.ErrInvoiceStatus},
+		{"issued", withStatus(domain.InvoiceIssued), nfse.NewFake(), repository.ErrInvoiceStatus},
+		{"cancelled", withStatus(domain.InvoiceCancelled), nfse.NewFake(), repository.ErrInvoiceStatus},
+		{"nothing to invoice", withoutLines, nfse.NewFake(), ErrNothingToInvoice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := &fakeInvoiceRepo{invoice: tt.invoice}
+			service := NewInvoiceService(invoices, nil, &fakeInvoiceInventory{}, tt.issuer, nfse.Party{}, "14.01", invoiceTxManager{invoices})
+
+			if _, err := service.Issue(context.Background(), 1); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			// A fatura recusada não muda de status
+			if invoices.invoice.Status != tt.invoice.Status {
+				t.Fatalf("got status %q, want %q", invoices.invoice.Status, tt.invoice.Status)
+			}
+		})
+	}
+}
+
+func TestInvoiceCancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		number     string
+		want       error
+		wantStatus string
+	}{
+		{"issued", domain.InvoiceIssued, "", nil, domain.InvoiceCancelled},
+		{"draft", domain.InvoiceDraft, "", repository.ErrInvoiceStatus, domain.InvoiceDraft},
+		{"already cancelled", domain.InvoiceCancelled, "", repository.ErrInvoiceStatus, domain.InvoiceCancelled},
+		// Se a prefeitura recusa o cancelamento, a fatura continua emitida
+		{"rejected by the issuer", domain.InvoiceIssued, "999", nfse.ErrNotFound, domain.InvoiceIssued},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := &fakeInvoiceRepo{invoice: draftInvoice()}
+			issuer := &recordingIssuer{Fake: nfse.NewFake()}
+			service := NewInvoiceService(invoices, nil, &fakeInvoiceInventory{}, issuer, nfse.Party{}, "14.01", invoiceTxManager{invoices})
+			ctx := context.Background()
+
+			// Emitir de verdade para que o emissor conheça a nota
+			if _, err := service.Issue(ctx, 1); err != nil {
+				t.Fatal(err)
+			}
+			invoices.invoice.Status = tt.status
+			if tt.number != "" {
+				invoices.invoice.Number = tt.number
+			}
+
+			if _, err := service.Cancel(ctx, 1, "Valor incorreto"); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if invoices.invoice.Status != tt.wantStatus {
+				t.Fatalf("got status %q, want %q", invoices.invoice.Status, tt.wantStatus)
+			}
+			if tt.want == nil && (len(issuer.cancelled) != 1 || issuer.cancelled[0] != issuer.issued[0]) {
+				t.Fatalf("got cancelled %v, want the issued nfs-e %v", issuer.cancelled, issuer.issued)
+			}
+			if errors.Is(tt.want, repository.ErrInvoiceStatus) && len(issuer.cancelled) != 0 {
+				t.Fatalf("issuer called for an invoice that is not issued")
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrNotProviderUser         = errors.New("user is not linked to a provider")
+	ErrTicketNotAssigned       = errors.New("ticket is not assigned to this provider")
+	ErrAssignmentNotAccepted   = errors.New("ticket assignment must be accepted first")
+	ErrInvalidStatusTransition = errors.New("status transition not allowed")
+)
+
+// Transições de status permitidas ao técnico
+var providerStatusTransitions = map[int][]int{
+	domain.TicketStatusAgendado:           {domain.TicketStatusEmAtendimento},
+	domain.TicketStatusAguardaAtendimento: {domain.TicketStatusEmAtendimento},
+	domain.TicketStatusEmAtendimento:      {domain.TicketStatusAguardaFinalizacao},
+}
+
+// ProviderPortalService expõe ao técnico logado apenas os tickets atribuídos ao seu prestador
+type ProviderPortalService interface {
+	ListTickets(ctx context.Context, userID int, limit, offset int) ([]dto.TicketResponse, int, error)
+	GetTicket(ctx context.Context, userID, ticketID int) (*dto.TicketResponse, error)
+	AcceptTicket(ctx context.Context, userID, ticketID int) error
+	DeclineTicket(ctx context.Context, userID, ticketID int, req *dto.DeclineTicketRequest) error
+	UpdateStatus(ctx context.Context, userID, ticketID int, req *dto.TicketStatusRequest) error
+	UploadAttachment(ctx context.Context, userID, ticketID int, fileName, contentType string, content io.Reader) (*domain.Attachment, error)
+	RecordTime(ctx context.Context, userID, ticketID int, req *dto.TimeEntryRequest) (*dto.TimeEntryResponse, error)
+	ListTimeEntries(ctx context.Context, userID, ticketID int) ([]dto.TimeEntryResponse, error)
+	SubmitExpense(ctx context.Context, userID, ticketID int, req *dto.ExpenseRequest) (*domain.Expense, error)
+	ListExpenses(ctx context.Context, userID, ticketID int) ([]domain.Expense, error)
+	CalendarFeedToken(ctx context.Context, userID int) (int, string, error)
+}
+
+type providerPortalService struct {
+	userRepo          repository.UserRepository
+	ticketRepo        repository.TicketRepository
+	timeEntryRepo     repository.TimeEntryRepository
+	ticketService     TicketService
+	attachmentService AttachmentService
+	commentService    CommentService
+	expenseService    ExpenseService
+	visitService      VisitService
+	contractService   ContractService
+	quoteRepo         repository.QuoteRepository
+	txManager         repository.TxManager
+}
+
+func NewProviderPortalService(
+	userRepo repository.UserRepository,
+	ticketRepo repository.TicketRepository,
+	timeEntryRepo repository.TimeEntryRepository,
+	ticketService TicketService,
+	attachmentService AttachmentService,
+	commentService CommentService,
+	expenseService ExpenseService,
+	visitService VisitService,
+	contractService ContractService,
+	quoteRepo repository.QuoteRepository,
+	txManager repository.TxManager,
+) ProviderPortalService {
+	return &providerPortalService{
+		userRepo:          userRepo,
+		ticketRepo:        ticketRepo,
+		timeEntryRepo:     timeEntryRepo,
+		ticketService:     ticketService,
+		attachmentService: attachmentService,
+		commentService:    commentService,
+		expenseService:    expenseService,
+		visitService:      visitService,
+		contractService:   contractService,
+		quoteRepo:         quoteRepo,
+		txManager:         txManager,
+	}
+}
+
+func (s *providerPortalService) ListTickets(ctx context.Context, userID int, limit, offset int) ([]dto.TicketResponse, int, error) {
+	user, err := s.providerUser(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tickets, total, err := s.ticketRepo.ListByProvider(ctx, *user.ProviderID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tickets: %w", err)
+	}
+
+	responses := make([]dto.TicketResponse, 0, len(tickets))
+	for _, ticket := range tickets {
+		response, err := s.ticketService.FindByID(ctx, ticket.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, total, nil
+}
+
+func (s *providerPortalService) GetTicket(ctx context.Context, userID, ticketID int) (*dto.TicketResponse, error) {
+	if _, _, err := s.assignedTicket(ctx, userID, ticketID); err != nil {
+		return nil, err
+	}
+
+	return s.ticketService.FindByID(ctx, ticketID)
+}
+
+func (s *providerPortalService) AcceptTicket(ctx context.Context, userID, ticketID int) error {
+	_, ticket, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return err
+	}
+
+	if ticket.AssignmentStatus == domain.AssignmentAccepted {
+		return nil
+	}
+
+	return s.ticketRepo.UpdateAssignmentStatus(ctx, ticketID, domain.AssignmentAccepted)
+}
+
+func (s *providerPortalService) DeclineTicket(ctx context.Context, userID, ticketID int, req *dto.DeclineTicketRequest) error {
+	user, _, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return err
+	}
+
+	// O ticket volta para o suporte sem prestador, marcado como recusado
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ticketService.RemoveProvider(ctx, ticketID); err != nil {
+			return err
+		}
+
+		if err := s.ticketRepo.UpdateAssignmentStatus(ctx, ticketID, domain.AssignmentDeclined); err != nil {
+			return err
+		}
+
+		_, err := s.commentService.Create(ctx, &domain.TicketComment{
+			TicketID: ticketID,
+			Author:   user.Name,
+			Body:     "Atribuição recusada: " + req.Reason,
+			Source:   domain.CommentSourceAPI,
+		})
+		return err
+	})
+}
+
+func (s *providerPortalService) UpdateStatus(ctx context.Context, userID, ticketID int, req *dto.TicketStatusRequest) error {
+	_, ticket, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return err
+	}
+
+	if ticket.AssignmentStatus != domain.AssignmentAccepted {
+		return ErrAssignmentNotAccepted
+	}
+
+	allowed := false
+	for _, next := range providerStatusTransitions[ticket.Status] {
+		if next == req.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %d -> %d", ErrInvalidStatusTransition, ticket.Status, req.Status)
+	}
+
+	if req.Status == domain.TicketStatusEmAtendimento {
+		if err := ensureQuoteApproved(ctx, s.contractService, s.quoteRepo, ticketID); err != nil {
+			return err
+		}
+	}
+
+	return s.ticketRepo.UpdateStatus(ctx, ticketID, req.Status)
+}
+
+func (s *providerPortalService) UploadAttachment(ctx context.Context, userID, ticketID int, fileName, contentType string, content io.Reader) (*domain.Attachment, error) {
+	if _, _, err := s.assignedTicket(ctx, userID, ticketID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentService.Save(ctx, ticketID, fileName, contentType, false, content)
+}
+
+func (s *providerPortalService) RecordTime(ctx context.Context, userID, ticketID int, req *dto.TimeEntryRequest) (*dto.TimeEntryResponse, error) {
+	user, ticket, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.AssignmentStatus != domain.AssignmentAccepted {
+		return nil, ErrAssignmentNotAccepted
+	}
+
+	startedAt, err := time.Parse(time.RFC3339, req.StartedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid started_at format: %w", err)
+	}
+
+	endedAt, err := time.Parse(time.RFC3339, req.EndedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ended_at format: %w", err)
+	}
+
+	if !endedAt.After(startedAt) {
+		return nil, fmt.Errorf("ended_at must be after started_at")
+	}
+
+	entry := &domain.TicketTimeEntry{
+		TicketID:   ticketID,
+		ProviderID: *user.ProviderID,
+		StartedAt:  startedAt,
+		EndedAt:    endedAt,
+		Minutes:    int(endedAt.Sub(startedAt).Minutes()),
+		Note:       req.Note,
+	}
+
+	id, err := s.timeEntryRepo.Create(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record time: %w", err)
+	}
+	entry.ID = id
+
+	return dto.ToTimeEntryResponse(entry), nil
+}
+
+func (s *providerPortalService) ListTimeEntries(ctx context.Context, userID, ticketID int) ([]dto.TimeEntryResponse, error) {
+	if _, _, err := s.assignedTicket(ctx, userID, ticketID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.timeEntryRepo.ListByTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToTimeEntryResponseList(entries), nil
+}
+
+// SubmitExpense envia a prestação de contas do técnico para revisão do Financeiro
+func (s *providerPortalService) SubmitExpense(ctx context.Context, userID, ticketID int, req *dto.ExpenseRequest) (*domain.Expense, error) {
+	user, ticket, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.AssignmentStatus != domain.AssignmentAccepted {
+		return nil, ErrAssignmentNotAccepted
+	}
+
+	return s.expenseService.Submit(ctx, ticketID, *user.ProviderID, req)
+}
+
+// ListExpenses retorna as prestações do técnico no ticket, com o motivo de cada item rejeitado.
+// Prestações de técnicos anteriores do ticket não aparecem
+func (s *providerPortalService) ListExpenses(ctx context.Context, userID, ticketID int) ([]domain.Expense, error) {
+	user, _, err := s.assignedTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.expenseService.List(ctx, &ticketID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	own := make([]domain.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		if expense.ProviderID == *user.ProviderID {
+			own = append(own, expense)
+		}
+	}
+
+	return own, nil
+}
+
+// providerUser busca o usuário logado e garante que ele está vinculado a um prestador
+// CalendarFeedToken retorna o prestador do técnico logado e o token do feed .ics da agenda dele
+func (s *providerPortalService) CalendarFeedToken(ctx context.Context, userID int) (int, string, error) {
+	user, err := s.providerUser(ctx, userID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return *user.ProviderID, s.visitService.FeedToken(*user.ProviderID), nil
+}
+
+func (s *providerPortalService) providerUser(ctx context.Context, userID int) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.ProviderID == nil {
+		return nil, ErrNotProviderUser
+	}
+
+	return user, nil
+}
+
+// assignedTicket busca o ticket e garante que ele está atribuído ao prestador do usuário logado
+func (s *providerPortalService) assignedTicket(ctx context.Context, userID, ticketID int) (*domain.User, *domain.Ticket, error) {
+	user, err := s.providerUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, nil, ErrTicketNotAssigned
+	}
+
+	if ticket.ProviderID == nil || *ticket.ProviderID != *user.ProviderID {
+		return nil, nil, ErrTicketNotAssigned
+	}
+
+	return user, ticket, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakePortalUserRepo struct {
+	repository.UserRepository
+	providerID int
+}
+
+func (f *fakePortalUserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	return &domain.User{ID: id, ProviderID: &f.providerID}, nil
+}
+
+type fakePortalExpenseService struct {
+	ExpenseService
+	expenses []domain.Expense
+}
+
+func (f *fakePortalExpenseService) List(ctx context.Context, ticketID *int, status string) ([]domain.Expense, error) {
+	return f.expenses, nil
+}
+
+func TestProviderPortalListExpensesOnlyReturnsOwn(t *testing.T) {
+	currentProvider := 3
+	tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, ProviderID: &currentProvider}}
+	expenses := &fakePortalExpenseService{expenses: []domain.Expense{
+		{ID: 1, TicketID: 5, ProviderID: 2}, // técnico anterior do ticket
+		{ID: 2, TicketID: 5, ProviderID: 3},
+	}}
+	service := NewProviderPortalService(&fakePortalUserRepo{providerID: 3}, tickets, nil, nil, nil, nil, expenses, nil, nil, nil, fakeTxManager{})
+
+	got, err := service.ListExpenses(context.Background(), 10, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("got %+v, want only the provider's own expense", got)
+	}
+}
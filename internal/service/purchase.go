@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidPurchaseItems = errors.New("purchase items must reference distinct inventory items")
+	ErrTicketConcluded      = errors.New("ticket is already concluded")
+	ErrNoWarehouse          = errors.New("no warehouse location registered")
+)
+
+type PurchaseService interface {
+	// Fornecedores
+	CreateSupplier(ctx context.Context, supplier *domain.Supplier) (*domain.Supplier, error)
+	ListSuppliers(ctx context.Context) ([]domain.Supplier, error)
+	FindSupplierByID(ctx context.Context, id int) (*domain.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *domain.Supplier) (*domain.Supplier, error)
+
+	// Compras
+	Create(ctx context.Context, req *dto.PurchaseRequestRequest) (*domain.PurchaseRequest, error)
+	FindByID(ctx context.Context, id int) (*domain.PurchaseRequest, error)
+	List(ctx context.Context, ticketID *int, status string) ([]domain.PurchaseRequest, error)
+	UpdateQuote(ctx context.Context, id int, req *dto.UpdatePurchaseRequestRequest) (*domain.PurchaseRequest, error)
+	Approve(ctx context.Context, id, userID int, note string) (*domain.PurchaseRequest, error)
+	Reject(ctx context.Context, id, userID int, note string) (*domain.PurchaseRequest, error)
+	Receive(ctx context.Context, id int, locationID *int) (*domain.PurchaseRequest, error)
+}
+
+type purchaseService struct {
+	purchaseRepo  repository.PurchaseRepository
+	ticketRepo    repository.TicketRepository
+	inventoryRepo repository.InventoryRepository
+	txManager     repository.TxManager
+}
+
+func NewPurchaseService(
+	purchaseRepo repository.PurchaseRepository,
+	ticketRepo repository.TicketRepository,
+	inventoryRepo repository.InventoryRepository,
+	txManager repository.TxManager,
+) PurchaseService {
+	return &purchaseService{
+		purchaseRepo:  purchaseRepo,
+		ticketRepo:    ticketRepo,
+		inventoryRepo: inventoryRepo,
+		txManager:     txManager,
+	}
+}
+
+func (s *purchaseService) CreateSupplier(ctx context.Context, supplier *domain.Supplier) (*domain.Supplier, error) {
+	id, err := s.purchaseRepo.CreateSupplier(ctx, supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindSupplierByID(ctx, id)
+}
+
+func (s *purchaseService) ListSuppliers(ctx context.Context) ([]domain.Supplier, error) {
+	return s.purchaseRepo.ListSuppliers(ctx)
+}
+
+func (s *purchaseService) FindSupplierByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	return s.purchaseRepo.FindSupplierByID(ctx, id)
+}
+
+func (s *purchaseService) UpdateSupplier(ctx context.Context, supplier *domain.Supplier) (*domain.Supplier, error) {
+	if err := s.purchaseRepo.UpdateSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindSupplierByID(ctx, supplier.ID)
+}
+
+// Create abre uma compra para o ticket e o coloca em Compras
+func (s *purchaseService) Create(ctx context.Context, req *dto.PurchaseRequestRequest) (*domain.PurchaseRequest, error) {
+	// Verificar se ticket existe e ainda está aberto
+	ticket, err := s.ticketRepo.FindByID(ctx, req.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+	if ticket.Status == domain.TicketStatusConcluido {
+		return nil, ErrTicketConcluded
+	}
+
+	items, err := s.toItems(ctx, req.SupplierID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = s.purchaseRepo.Create(ctx, &domain.PurchaseRequest{
+			TicketID:   req.TicketID,
+			SupplierID: req.SupplierID,
+			Notes:      req.Notes,
+			Items:      items,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Ticket aguarda as peças em Compras
+		if ticket.Status != domain.TicketStatusCompras {
+			if err := s.ticketRepo.UpdateStatus(ctx, ticket.ID, domain.TicketStatusCompras); err != nil {
+				return fmt.Errorf("failed to update ticket status: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindByID(ctx, id)
+}
+
+func (s *purchaseService) FindByID(ctx context.Context, id int) (*domain.PurchaseRequest, error) {
+	return s.purchaseRepo.FindByID(ctx, id)
+}
+
+func (s *purchaseService) List(ctx context.Context, ticketID *int, status string) ([]domain.PurchaseRequest, error) {
+	return s.purchaseRepo.List(ctx, ticketID, status)
+}
+
+// UpdateQuote revisa fornecedor e itens enquanto a compra aguarda aprovação
+func (s *purchaseService) UpdateQuote(ctx context.Context, id int, req *dto.UpdatePurchaseRequestRequest) (*domain.PurchaseRequest, error) {
+	if _, err := s.purchaseRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	items, err := s.toItems(ctx, req.SupplierID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.purchaseRepo.UpdateQuote(ctx, &domain.PurchaseRequest{
+		ID:         id,
+		SupplierID: req.SupplierID,
+		Notes:      req.Notes,
+		Items:      items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindByID(ctx, id)
+}
+
+func (s *purchaseService) Approve(ctx context.Context, id, userID int, note string) (*domain.PurchaseRequest, error) {
+	return s.decide(ctx, id, domain.PurchaseApproved, userID, note)
+}
+
+func (s *purchaseService) Reject(ctx context.Context, id, userID int, note string) (*domain.PurchaseRequest, error) {
+	var purchase *domain.PurchaseRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if purchase, err = s.decide(ctx, id, domain.PurchaseRejected, userID, note); err != nil {
+			return err
+		}
+
+		// A compra rejeitada pode ser a última pendente do ticket
+		return s.resumeTicket(ctx, purchase.TicketID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase, nil
+}
+
+// Receive dá entrada das peças no estoque e libera o ticket quando todas as compras chegaram
+func (s *purchaseService) Receive(ctx context.Context, id int, locationID *int) (*domain.PurchaseRequest, error) {
+	purchase, err := s.purchaseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem local informado, as peças vão para o almoxarifado central
+	var target int
+	if locationID != nil {
+		if _, err := s.inventoryRepo.FindLocationByID(ctx, *locationID); err != nil {
+			return nil, err
+		}
+		target = *locationID
+	} else {
+		target, err = s.defaultWarehouse(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Entrada no estoque e liberação do ticket na mesma transação
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.purchaseRepo.Receive(ctx, id, target); err != nil {
+			return err
+		}
+
+		return s.resumeTicket(ctx, purchase.TicketID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindByID(ctx, id)
+}
+
+func (s *purchaseService) decide(ctx context.Context, id int, status string, userID int, note string) (*domain.PurchaseRequest, error) {
+	if _, err := s.purchaseRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.purchaseRepo.Decide(ctx, id, status, userID, note); err != nil {
+		return nil, err
+	}
+
+	return s.purchaseRepo.FindByID(ctx, id)
+}
+
+// resumeTicket devolve o ticket de Compras para Agendado quando não há compras em aberto
+// e ao menos uma foi recebida
+func (s *purchaseService) resumeTicket(ctx context.Context, ticketID int) error {
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return fmt.Errorf("ticket not found: %w", err)
+	}
+	if ticket.Status != domain.TicketStatusCompras {
+		return nil
+	}
+
+	counts, err := s.purchaseRepo.CountByTicket(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	if counts[domain.PurchasePending] > 0 || counts[domain.PurchaseApproved] > 0 || counts[domain.PurchaseReceived] == 0 {
+		return nil
+	}
+
+	if err := s.ticketRepo.UpdateStatus(ctx, ticketID, domain.TicketStatusAgendado); err != nil {
+		return fmt.Errorf("failed to update ticket status: %w", err)
+	}
+
+	return nil
+}
+
+func (s *purchaseService) defaultWarehouse(ctx context.Context) (int, error) {
+	locations, err := s.inventoryRepo.ListLocations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, location := range locations {
+		if location.Kind == domain.StockLocationWarehouse {
+			return location.ID, nil
+		}
+	}
+
+	return 0, ErrNoWarehouse
+}
+
+// toItems valida fornecedor e itens cotados
+func (s *purchaseService) toItems(ctx context.Context, supplierID int, reqItems []dto.PurchaseItemRequest) ([]domain.PurchaseRequestItem, error) {
+	if _, err := s.purchaseRepo.FindSupplierByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	items := make([]domain.PurchaseRequestItem, 0, len(reqItems))
+	for _, reqItem := range reqItems {
+		if seen[reqItem.ItemID] {
+			return nil, ErrInvalidPurchaseItems
+		}
+		seen[reqItem.ItemID] = true
+
+		if _, err := s.inventoryRepo.FindItemByID(ctx, reqItem.ItemID); err != nil {
+			return nil, err
+		}
+
+		items = append(items, domain.PurchaseRequestItem{
+			ItemID:    reqItem.ItemID,
+			Quantity:  reqItem.Quantity,
+			UnitPrice: reqItem.UnitPrice,
+		})
+	}
+
+	return items, nil
+}
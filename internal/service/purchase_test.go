@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+// fakePurchaseRepo aplica as mesmas guardas de status dos UPDATEs do repositório
+type fakePurchaseRepo struct {
+	repository.PurchaseRepository
+	purchases  map[int]*domain.PurchaseRequest
+	receivedIn int
+}
+
+func (f *fakePurchaseRepo) FindSupplierByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	return &domain.Supplier{ID: id}, nil
+}
+
+func (f *fakePurchaseRepo) Create(ctx context.Context, purchase *domain.PurchaseRequest) (int, error) {
+	purchase.ID = len(f.purchases) + 1
+	purchase.Status = domain.PurchasePending
+	f.purchases[purchase.ID] = purchase
+	return purchase.ID, nil
+}
+
+func (f *fakePurchaseRepo) FindByID(ctx context.Context, id int) (*domain.PurchaseRequest, error) {
+	purchase, ok := f.purchases[id]
+	if !ok {
+		return nil, repository.ErrPurchaseRequestNotFound
+	}
+	copied := *purchase
+	return &copied, nil
+}
+
+func (f *fakePurchaseRepo) Decide(ctx context.Context, id int, status string, userID int, note string) error {
+	if f.purchases[id].Status != domain.PurchasePending {
+		return repository.ErrPurchaseRequestStatus
+	}
+	f.purchases[id].Status = status
+	return nil
+}
+
+func (f *fakePurchaseRepo) Receive(ctx context.Context, id, locationID int) error {
+	if f.purchases[id].Status != domain.PurchaseApproved {
+		return repository.ErrPurchaseRequestStatus
+	}
+	f.purchases[id].Status = domain.PurchaseReceived
+	f.receivedIn = locationID
+	return nil
+}
+
+func (f *fakePurchaseRepo) CountByTicket(ctx context.Context, ticketID int) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, purchase := range f.purchases {
+		if purchase.TicketID == ticketID {
+			counts[purchase.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func TestPurchaseStatusTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		action  func(s PurchaseService) error
+		wantErr error
+		want    string
+	}{
+		{"approve pending", domain.PurchasePending, func(s PurchaseService) error {
+			_, err := s.Approve(context.Background(), 1, 2, "")
+			return err
+		}, nil, domain.PurchaseApproved},
+		{"reject pending", domain.PurchasePending, func(s PurchaseService) error {
+			_, err := s.Reject(context.Background(), 1, 2, "caro demais")
+			return err
+		}, nil, domain.PurchaseRejected},
+		{"receive approved", domain.PurchaseApproved, func(s PurchaseService) error {
+			_, err := s.Receive(context.Background(), 1, nil)
+			return err
+		}, nil, domain.PurchaseReceived},
+		{"approve twice", domain.PurchaseApproved, func(s PurchaseService) error {
+			_, err := s.Approve(context.Background(), 1, 2, "")
+			return err
+		}, repository.ErrPurchaseRequestStatus, domain.PurchaseApproved},
+		{"reject received", domain.PurchaseReceived, func(s PurchaseService) error {
+			_, err := s.Reject(context.Background(), 1, 2, "")
+			return err
+		}, repository.ErrPurchaseRequestStatus, domain.PurchaseReceived},
+		{"receive pending", domain.PurchasePending, func(s PurchaseService) error {
+			_, err := s.Receive(context.Background(), 1, nil)
+			return err
+		}, repository.ErrPurchaseRequestStatus, domain.PurchasePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchases := &fakePurchaseRepo{purchases: map[int]*domain.PurchaseRequest{1: {ID: 1, TicketID: 5, Status: tt.from}}}
+			tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, Status: domain.TicketStatusCompras}}
+			inventory := &fakeInventoryRepo{locations: []domain.StockLocation{{ID: 4, Kind: domain.StockLocationWarehouse}}}
+			service := NewPurchaseService(purchases, tickets, inventory, fakeTxManager{})
+
+			if err := tt.action(service); !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if purchases.purchases[1].Status != tt.want {
+				t.Fatalf("got status %s, want %s", purchases.purchases[1].Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseResumesTicketWhenLastPurchaseCloses(t *testing.T) {
+	tests := []struct {
+		name   string
+		others []string // status das outras compras do ticket
+		reject bool
+		want   int
+	}{
+		{"only purchase received", nil, false, domain.TicketStatusAgendado},
+		{"another purchase pending", []string{domain.PurchasePending}, false, domain.TicketStatusCompras},
+		{"another purchase approved", []string{domain.PurchaseApproved}, false, domain.TicketStatusCompras},
+		{"other purchase rejected", []string{domain.PurchaseRejected}, false, domain.TicketStatusAgendado},
+		// Nenhuma peça chegou: o ticket continua em Compras para uma nova cotação
+		{"only purchase rejected", nil, true, domain.TicketStatusCompras},
+		{"rejected after another received", []string{domain.PurchaseReceived}, true, domain.TicketStatusAgendado},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := domain.PurchaseApproved
+			if tt.reject {
+				from = domain.PurchasePending
+			}
+			purchases := &fakePurchaseRepo{purchases: map[int]*domain.PurchaseRequest{1: {ID: 1, TicketID: 5, Status: from}}}
+			for i, status := range tt.others {
+				purchases.purchases[i+2] = &domain.PurchaseRequest{ID: i + 2, TicketID: 5, Status: status}
+			}
+			tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, Status: domain.TicketStatusCompras}}
+			inventory := &fakeInventoryRepo{locations: []domain.StockLocation{{ID: 4, Kind: domain.StockLocationWarehouse}}}
+			service := NewPurchaseService(purchases, tickets, inventory, fakeTxManager{})
+
+			var err error
+			if tt.reject {
+				_, err = service.Reject(context.Background(), 1, 2, "")
+			} else {
+				_, err = service.Receive(context.Background(), 1, nil)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tickets.ticket.Status != tt.want {
+				t.Fatalf("got ticket status %d, want %d", tickets.ticket.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseReceiveLocation(t *testing.T) {
+	van := 9
+
+	tests := []struct {
+		name      string
+		locations []domain.StockLocation
+		location  *int
+		want      int
+		wantErr   error
+	}{
+		{"defaults to the warehouse", []domain.StockLocation{{ID: 3, Kind: domain.StockLocationVan}, {ID: 4, Kind: domain.StockLocationWarehouse}}, nil, 4, nil},
+		{"informed location", nil, &van, 9, nil},
+		{"no warehouse registered", []domain.StockLocation{{ID: 3, Kind: domain.StockLocationVan}}, nil, 0, ErrNoWarehouse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchases := &fakePurchaseRepo{purchases: map[int]*domain.PurchaseRequest{1: {ID: 1, TicketID: 5, Status: domain.PurchaseApproved}}}
+			tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, Status: domain.TicketStatusCompras}}
+			service := NewPurchaseService(purchases, tickets, &fakeInventoryRepo{locations: tt.locations}, fakeTxManager{})
+
+			_, err := service.Receive(context.Background(), 1, tt.location)
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if purchases.receivedIn != tt.want {
+				t.Fatalf("received in location %d, want %d", purchases.receivedIn, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		items      []dto.PurchaseItemRequest
+		wantErr    error
+		wantStatus int
+	}{
+		{"moves the ticket to purchasing", domain.TicketStatusAgendado, []dto.PurchaseItemRequest{{ItemID: 1, Quantity: 2}}, nil, domain.TicketStatusCompras},
+		{"concluded ticket", domain.TicketStatusConcluido, []dto.PurchaseItemRequest{{ItemID: 1, Quantity: 2}}, ErrTicketConcluded, domain.TicketStatusConcluido},
+		{"repeated item", domain.TicketStatusAgendado, []dto.PurchaseItemRequest{{ItemID: 1, Quantity: 2}, {ItemID: 1, Quantity: 1}}, ErrInvalidPurchaseItems, domain.TicketStatusAgendado},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchases := &fakePurchaseRepo{purchases: map[int]*domain.PurchaseRequest{}}
+			tickets := &fakeTicketRepo{ticket: domain.Ticket{ID: 5, Status: tt.status}}
+			service := NewPurchaseService(purchases, tickets, &fakeInventoryRepo{}, fakeTxManager{})
+
+			_, err := service.Create(context.Background(), &dto.PurchaseRequestRequest{TicketID: 5, SupplierID: 1, Items: tt.items})
+			if !errors.Is(err, tt.wantErr) || tt.wantErr == nil && err != nil {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if tickets.ticket.Status != tt.wantStatus {
+				t.Fatalf("got ticket status %d, want %d", tickets.ticket.Status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/mailer"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+var (
+	ErrQuoteWithoutSolutions = errors.New("ticket has no planned solutions to quote")
+	ErrInvalidQuoteLink      = errors.New("quote approval link is invalid or expired")
+	ErrSenderNotConfigured   = errors.New("mail sender is not configured")
+	ErrQuoteApprovalRequired = errors.New("client contract requires an approved quote before service starts")
+)
+
+// QuoteService gera os orçamentos do ticket e registra a aprovação do cliente
+type QuoteService interface {
+	Generate(ctx context.Context, ticketID int, req *dto.QuoteRequest) (*domain.Quote, error)
+	List(ctx context.Context, ticketID int) ([]domain.Quote, error)
+	FindByID(ctx context.Context, ticketID, quoteID int) (*domain.Quote, error)
+	Send(ctx context.Context, ticketID, quoteID int, req *dto.QuoteSendRequest) (*dto.QuoteLinkResponse, error)
+	Decide(ctx context.Context, ticketID, quoteID int, decision *domain.QuoteDecision) (*domain.Quote, error)
+
+	// Aprovação pelo link assinado, sem login
+	FindByToken(ctx context.Context, token string) (*domain.Quote, error)
+	DecideByToken(ctx context.Context, token string, decision *domain.QuoteDecision) (*domain.Quote, error)
+}
+
+type quoteService struct {
+	quoteRepo       repository.QuoteRepository
+	ticketRepo      repository.TicketRepository
+	ticketService   TicketService
+	contractService ContractService
+	sender          mailer.Sender // nil quando o envio de emails está desabilitado
+	linkKey         []byte        // Chave de assinatura dos links de aprovação
+	approvalURL     string        // Página que recebe o token do link
+	linkTTL         time.Duration // Validade do link
+}
+
+func NewQuoteService(
+	quoteRepo repository.QuoteRepository,
+	ticketRepo repository.TicketRepository,
+	ticketService TicketService,
+	contractService ContractService,
+	sender mailer.Sender,
+	jwtSecret []byte,
+	approvalURL string,
+	linkTTL time.Duration,
+) QuoteService {
+	return &quoteService{
+		quoteRepo:       quoteRepo,
+		ticketRepo:      ticketRepo,
+		ticketService:   ticketService,
+		contractService: contractService,
+		sender:          sender,
+		linkKey:         quoteLinkKey(jwtSecret),
+		approvalURL:     strings.TrimRight(approvalURL, "/"),
+		linkTTL:         linkTTL,
+	}
+}
+
+// Generate cria uma nova versão do orçamento com as solutions planejadas no ticket e o deslocamento.
+// A versão pendente anterior é substituída e precisa ser enviada de novo.
+func (s *quoteService) Generate(ctx context.Context, ticketID int, req *dto.QuoteRequest) (*domain.Quote, error) {
+	ticket, err := s.ticketService.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	costs, err := s.ticketRepo.GetTicketSolutions(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticket solutions: %w", err)
+	}
+
+	quote := &domain.Quote{
+		TicketID: ticketID,
+		Notes:    req.Notes,
+	}
+
+	// Só solutions do catálogo; itens customizados não entram no orçamento
+	for _, cost := range costs {
+		if cost.SolutionID == nil {
+			continue
+		}
+		quote.Items = append(quote.Items, domain.QuoteItem{
+			SolutionID:   cost.SolutionID,
+			ProblemName:  cost.ProblemName,
+			SolutionName: cost.SolutionName,
+			Quantity:     cost.Quantity,
+			UnitPrice:    cost.UnitPrice,
+			Subtotal:     cost.Subtotal,
+			Warranty:     cost.Warranty,
+		})
+		quote.ServicesTotal += cost.Subtotal
+	}
+	if len(quote.Items) == 0 {
+		return nil, ErrQuoteWithoutSolutions
+	}
+
+	// Deslocamento das visitas agendadas; sem visitas, estimado pela distância do ticket
+	quote.TravelCost = ticket.TravelCost
+	if quote.TravelCost == 0 && ticket.Distance != nil {
+		cost, err := s.contractService.TravelCostTable(ctx, ticketID)
+		if err != nil {
+			return nil, err
+		}
+		quote.TravelCost = cost.TravelCost(*ticket.Distance)
+	}
+	quote.Total = quote.ServicesTotal + quote.TravelCost
+
+	id, err := s.quoteRepo.Create(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.quoteRepo.FindByID(ctx, id)
+}
+
+func (s *quoteService) List(ctx context.Context, ticketID int) ([]domain.Quote, error) {
+	if _, err := s.ticketRepo.FindByID(ctx, ticketID); err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	return s.quoteRepo.ListByTicket(ctx, ticketID)
+}
+
+// FindByID retorna o orçamento, desde que seja do ticket informado
+func (s *quoteService) FindByID(ctx context.Context, ticketID, quoteID int) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.FindByID(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.TicketID != ticketID {
+		return nil, repository.ErrQuoteNotFound
+	}
+
+	return quote, nil
+}
+
+// Send gera o link assinado de aprovação do orçamento pendente e o envia por email aos destinatários
+func (s *quoteService) Send(ctx context.Context, ticketID, quoteID int, req *dto.QuoteSendRequest) (*dto.QuoteLinkResponse, error) {
+	quote, err := s.FindByID(ctx, ticketID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.Status != domain.QuotePending {
+		return nil, repository.ErrQuoteStatus
+	}
+
+	if len(req.Recipients) > 0 && s.sender == nil {
+		return nil, ErrSenderNotConfigured
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL)
+	token, err := s.signLink(quote, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	link := s.approvalURL + "/" + token
+
+	if len(req.Recipients) > 0 {
+		err := s.sender.Send(ctx, mailer.Message{
+			To:      req.Recipients,
+			Subject: fmt.Sprintf("Orçamento do chamado %s (versão %d)", quote.TicketNumber, quote.Version),
+			Body:    quoteMessage(quote, link, expiresAt),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to send quote: %w", err)
+		}
+	}
+
+	if err := s.quoteRepo.MarkSent(ctx, quote.ID, req.Recipients); err != nil {
+		return nil, err
+	}
+
+	sentTo := req.Recipients
+	if sentTo == nil {
+		sentTo = []string{}
+	}
+
+	return &dto.QuoteLinkResponse{
+		QuoteID:   quote.ID,
+		Version:   quote.Version,
+		URL:       link,
+		Token:     token,
+		ExpiresAt: expiresAt,
+		SentTo:    sentTo,
+	}, nil
+}
+
+// Decide registra a aprovação ou rejeição do orçamento pendente do ticket
+func (s *quoteService) Decide(ctx context.Context, ticketID, quoteID int, decision *domain.QuoteDecision) (*domain.Quote, error) {
+	if _, err := s.FindByID(ctx, ticketID, quoteID); err != nil {
+		return nil, err
+	}
+
+	if err := s.quoteRepo.Decide(ctx, quoteID, decision); err != nil {
+		return nil, err
+	}
+
+	return s.quoteRepo.FindByID(ctx, quoteID)
+}
+
+func (s *quoteService) FindByToken(ctx context.Context, token string) (*domain.Quote, error) {
+	quoteID, err := s.parseLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// O link assinado dá acesso só a este orçamento
+	return s.quoteRepo.FindByID(tenant.System(ctx), quoteID)
+}
+
+// DecideByToken registra a decisão tomada pelo link assinado
+func (s *quoteService) DecideByToken(ctx context.Context, token string, decision *domain.QuoteDecision) (*domain.Quote, error) {
+	quoteID, err := s.parseLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	decision.Channel = domain.QuoteChannelLink
+	decision.UserID = nil
+	ctx = tenant.System(ctx)
+	if err := s.quoteRepo.Decide(ctx, quoteID, decision); err != nil {
+		return nil, err
+	}
+
+	return s.quoteRepo.FindByID(ctx, quoteID)
+}
+
+// signLink assina o link de aprovação de uma versão do orçamento
+func (s *quoteService) signLink(quote *domain.Quote, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"quote_id": quote.ID,
+		"exp":      expiresAt.Unix(),
+	})
+
+	signed, err := token.SignedString(s.linkKey)
+	if err != nil {
+		return "", fmt.Errorf("error signing quote link: %w", err)
+	}
+
+	return signed, nil
+}
+
+// parseLink valida a assinatura e a validade do link e retorna o orçamento
+func (s *quoteService) parseLink(tokenString string) (int, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.linkKey, nil
+	})
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidQuoteLink
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, ErrInvalidQuoteLink
+	}
+
+	quoteID, ok := claims["quote_id"].(float64)
+	if !ok {
+		return 0, ErrInvalidQuoteLink
+	}
+
+	return int(quoteID), nil
+}
+
+// quoteLinkKey deriva a chave dos links a partir do segredo do JWT, para que um link de
+// aprovação não seja aceito como token de login (e vice-versa)
+func quoteLinkKey(jwtSecret []byte) []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("quote-approval"))
+	return mac.Sum(nil)
+}
+
+// quoteMessage monta o corpo do email com os itens e o link de aprovação
+func quoteMessage(quote *domain.Quote, link string, expiresAt time.Time) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Orçamento do chamado %s (versão %d):\n\n", quote.TicketNumber, quote.Version)
+	for _, item := range quote.Items {
+		fmt.Fprintf(&body, "- %s - %s: %d x %s = %s\n",
+			item.ProblemName, item.SolutionName, item.Quantity, item.UnitPrice, item.Subtotal)
+	}
+	fmt.Fprintf(&body, "\nServiços: %s\nDeslocamento: %s\nTotal: %s\n", quote.ServicesTotal, quote.TravelCost, quote.Total)
+	if quote.Notes != "" {
+		fmt.Fprintf(&body, "\n%s\n", quote.Notes)
+	}
+	fmt.Fprintf(&body, "\nPara aprovar ou rejeitar, acesse até %s:\n%s\n", expiresAt.Format("02/01/2006 15:04"), link)
+
+	return body.String()
+}
+
+// ensureQuoteApproved bloqueia o início do atendimento (Em Atendimento) enquanto a última
+// versão do orçamento não for aprovada, quando o contrato do cliente exige orçamento
+func ensureQuoteApproved(ctx context.Context, contractService ContractService, quoteRepo repository.QuoteRepository, ticketID int) error {
+	contract, err := contractService.ForTicket(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	if contract == nil || !contract.RequireQuote {
+		return nil
+	}
+
+	quote, err := quoteRepo.Latest(ctx, ticketID)
+	if errors.Is(err, repository.ErrQuoteNotFound) {
+		return ErrQuoteApprovalRequired
+	}
+	if err != nil {
+		return err
+	}
+	if quote.Status != domain.QuoteApproved {
+		return ErrQuoteApprovalRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+type fakeQuoteRepo struct {
+	repository.QuoteRepository
+	decided *domain.QuoteDecision
+	system  bool // Decide foi chamado fora de qualquer tenant
+}
+
+func (f *fakeQuoteRepo) FindByID(ctx context.Context, id int) (*domain.Quote, error) {
+	if !tenant.IsSystem(ctx) {
+		return nil, repository.ErrQuoteNotFound
+	}
+	return &domain.Quote{ID: id, Status: domain.QuotePending}, nil
+}
+
+func (f *fakeQuoteRepo) Decide(ctx context.Context, id int, decision *domain.QuoteDecision) error {
+	f.decided = decision
+	f.system = tenant.IsSystem(ctx)
+	return nil
+}
+
+func TestQuoteParseLink(t *testing.T) {
+	secret := []byte("secret")
+	svc := &quoteService{linkKey: quoteLinkKey(secret)}
+	other := &quoteService{linkKey: quoteLinkKey([]byte("other"))}
+	quote := &domain.Quote{ID: 7}
+
+	sign := func(s *quoteService, expiresAt time.Time) string {
+		token, err := s.signLink(quote, expiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	// Token assinado com a chave informada, fora do signLink
+	raw := func(key []byte, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := sign(svc, time.Now().Add(time.Hour))
+	// Troca um caractere no meio da assinatura
+	tampered := []byte(valid)
+	if tampered[len(tampered)-10] == 'A' {
+		tampered[len(tampered)-10] = 'B'
+	} else {
+		tampered[len(tampered)-10] = 'A'
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"quote_id": 7, "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"valid", valid, nil},
+		{"expired", sign(svc, time.Now().Add(-time.Minute)), ErrInvalidQuoteLink},
+		{"tampered signature", string(tampered), ErrInvalidQuoteLink},
+		{"another secret", sign(other, time.Now().Add(time.Hour)), ErrInvalidQuoteLink},
+		// O token de login usa o segredo sem derivação e não vale como link
+		{"login secret", raw(secret, jwt.MapClaims{"quote_id": 7, "exp": time.Now().Add(time.Hour).Unix()}), ErrInvalidQuoteLink},
+		{"without quote", raw(svc.linkKey, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}), ErrInvalidQuoteLink},
+		{"unsigned", unsigned, ErrInvalidQuoteLink},
+		{"malformed", "not-a-token", ErrInvalidQuoteLink},
+		{"empty", "", ErrInvalidQuoteLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := svc.parseLink(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && id != quote.ID {
+				t.Fatalf("got quote %d, want %d", id, quote.ID)
+			}
+		})
+	}
+}
+
+func TestQuoteDecideByToken(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	svc := &quoteService{quoteRepo: repo, linkKey: quoteLinkKey([]byte("secret"))}
+	token, err := svc.signLink(&domain.Quote{ID: 7}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Canal e usuário vêm do link, não da requisição
+	userID := 10
+	decision := &domain.QuoteDecision{Status: domain.QuoteApproved, DecidedBy: "Maria", UserID: &userID, Channel: domain.QuoteChannelPortal}
+	quote, err := svc.DecideByToken(context.Background(), token, decision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.ID != 7 {
+		t.Fatalf("got quote %d, want 7", quote.ID)
+	}
+	if !repo.system {
+		t.Fatal("decision recorded inside a tenant scope")
+	}
+	if repo.decided.Channel != domain.QuoteChannelLink || repo.decided.UserID != nil {
+		t.Fatalf("got channel %q and user %v, want link and no user", repo.decided.Channel, repo.decided.UserID)
+	}
+
+	if _, err := svc.DecideByToken(context.Background(), "not-a-token", decision); !errors.Is(err, ErrInvalidQuoteLink) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidQuoteLink)
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/mailer"
+	"github.com/ericolvr/maintenance-v2/internal/report"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidReportType     = errors.New("report type must be costs or client_tickets")
+	ErrInvalidReportSchedule = errors.New("invalid report schedule")
+	ErrReportClientRequired  = errors.New("client_tickets reports require client_id")
+	ErrReportFileMissing     = errors.New("report file is no longer available")
+)
+
+type ScheduledReportService interface {
+	Create(ctx context.Context, definition *domain.ReportDefinition) (*domain.ReportDefinition, error)
+	Update(ctx context.Context, definition *domain.ReportDefinition) (*domain.ReportDefinition, error)
+	FindByID(ctx context.Context, id int) (*domain.ReportDefinition, error)
+	List(ctx context.Context) ([]domain.ReportDefinition, error)
+	Delete(ctx context.Context, id int) error
+	Run(ctx context.Context, id int) (*domain.ReportFile, error)
+	RunDue(ctx context.Context) error
+	ListFiles(ctx context.Context, definitionID int) ([]domain.ReportFile, error)
+	FindFile(ctx context.Context, definitionID, id int) (*domain.ReportFile, error)
+}
+
+type scheduledReportService struct {
+	reportRepo repository.ScheduledReportRepository
+	dataRepo   repository.ReportDataRepository
+	clientRepo repository.ClientRepository
+	sender     mailer.Sender // nil quando o envio de emails está desabilitado
+	uploadDir  string
+}
+
+func NewScheduledReportService(
+	reportRepo repository.ScheduledReportRepository,
+	dataRepo repository.ReportDataRepository,
+	clientRepo repository.ClientRepository,
+	sender mailer.Sender,
+	uploadDir string,
+) ScheduledReportService {
+	return &scheduledReportService{
+		reportRepo: reportRepo,
+		dataRepo:   dataRepo,
+		clientRepo: clientRepo,
+		sender:     sender,
+		uploadDir:  uploadDir,
+	}
+}
+
+func (s *scheduledReportService) Create(ctx context.Context, definition *domain.ReportDefinition) (*domain.ReportDefinition, error) {
+	if err := s.validate(ctx, definition); err != nil {
+		return nil, err
+	}
+
+	definition.NextRunAt = definition.Schedule.Next(time.Now())
+
+	id, err := s.reportRepo.Create(ctx, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.FindByID(ctx, id)
+}
+
+// Update altera a definição e recalcula a próxima execução a partir de agora
+func (s *scheduledReportService) Update(ctx context.Context, definition *domain.ReportDefinition) (*domain.ReportDefinition, error) {
+	if _, err := s.reportRepo.FindByID(ctx, definition.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(ctx, definition); err != nil {
+		return nil, err
+	}
+
+	definition.NextRunAt = definition.Schedule.Next(time.Now())
+
+	if err := s.reportRepo.Update(ctx, definition); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.FindByID(ctx, definition.ID)
+}
+
+func (s *scheduledReportService) FindByID(ctx context.Context, id int) (*domain.ReportDefinition, error) {
+	return s.reportRepo.FindByID(ctx, id)
+}
+
+func (s *scheduledReportService) List(ctx context.Context) ([]domain.ReportDefinition, error) {
+	return s.reportRepo.List(ctx)
+}
+
+// Delete remove a definição, o registro dos arquivos gerados e os arquivos em disco
+func (s *scheduledReportService) Delete(ctx context.Context, id int) error {
+	files, err := s.reportRepo.ListFiles(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reportRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		os.Remove(file.Path)
+	}
+
+	return nil
+}
+
+// Run gera e envia o relatório sob demanda, com o último período completo, sem alterar o agendamento
+func (s *scheduledReportService) Run(ctx context.Context, id int) (*domain.ReportFile, error) {
+	definition, err := s.reportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := definition.Schedule.Period(time.Now())
+	return s.generate(ctx, definition, from, to)
+}
+
+// RunDue gera os relatórios com execução vencida. Cada execução é reservada antes de gerar,
+// para que várias instâncias da API não enviem o mesmo relatório; execuções perdidas
+// (ex: API parada) geram um único relatório, do período da execução mais antiga.
+func (s *scheduledReportService) RunDue(ctx context.Context) error {
+	now := time.Now()
+	definitions, err := s.reportRepo.ListDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range definitions {
+		definition := &definitions[i]
+
+		claimed, err := s.reportRepo.Advance(ctx, definition.ID, definition.NextRunAt, definition.Schedule.Next(now))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		from, to := definition.Schedule.Period(definition.NextRunAt)
+		file, err := s.generate(ctx, definition, from, to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("report %d: %w", definition.ID, err))
+			continue
+		}
+		if file.Status == domain.ReportFileFailed {
+			errs = append(errs, fmt.Errorf("report %d: %s", definition.ID, file.Error))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *scheduledReportService) ListFiles(ctx context.Context, definitionID int) ([]domain.ReportFile, error) {
+	if _, err := s.reportRepo.FindByID(ctx, definitionID); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.ListFiles(ctx, definitionID)
+}
+
+// FindFile retorna um arquivo gerado, verificando se ele ainda está em disco
+func (s *scheduledReportService) FindFile(ctx context.Context, definitionID, id int) (*domain.ReportFile, error) {
+	file, err := s.reportRepo.FindFile(ctx, definitionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(file.Path); err != nil {
+		return nil, ErrReportFileMissing
+	}
+
+	return file, nil
+}
+
+func (s *scheduledReportService) validate(ctx context.Context, definition *domain.ReportDefinition) error {
+	switch definition.Type {
+	case domain.ReportTypeCosts, domain.ReportTypeClientTickets:
+	default:
+		return ErrInvalidReportType
+	}
+
+	if !report.ValidFormat(definition.Format) {
+		return report.ErrUnknownFormat
+	}
+
+	if !definition.Schedule.Valid() {
+		return ErrInvalidReportSchedule
+	}
+
+	if definition.Type == domain.ReportTypeClientTickets && definition.ClientID == nil {
+		return ErrReportClientRequired
+	}
+
+	// Validar se client existe
+	if definition.ClientID != nil {
+		if _, err := s.clientRepo.FindByID(ctx, *definition.ClientID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generate monta o relatório do período, grava o arquivo (uploads/reports/<id>/), envia por email
+// quando há envio configurado e registra o resultado no arquivo de relatórios
+func (s *scheduledReportService) generate(ctx context.Context, definition *domain.ReportDefinition, from, to time.Time) (*domain.ReportFile, error) {
+	table, err := s.table(ctx, definition, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, definition.Format, table); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+
+	dir := filepath.Join(s.uploadDir, "reports", strconv.Itoa(definition.ID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	fileName := sanitizeFileName(fmt.Sprintf("%s_%s.%s", strings.ReplaceAll(definition.Name, " ", "_"), from.Format("2006-01-02"), definition.Format))
+	path := filepath.Join(dir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), fileName))
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	file := &domain.ReportFile{
+		DefinitionID: definition.ID,
+		PeriodStart:  from,
+		PeriodEnd:    to,
+		Format:       definition.Format,
+		FileName:     fileName,
+		ContentType:  report.ContentType(definition.Format),
+		Size:         int64(buf.Len()),
+		Path:         path,
+		Recipients:   definition.Recipients,
+		Status:       domain.ReportFileGenerated,
+	}
+
+	if s.sender != nil {
+		err := s.sender.Send(ctx, mailer.Message{
+			To:      definition.Recipients,
+			Subject: fmt.Sprintf("%s - %s", definition.Name, table.Subtitle),
+			Body: fmt.Sprintf("Segue em anexo o relatório \"%s\".\n%s.\n",
+				definition.Name, table.Subtitle),
+			Attachments: []mailer.Attachment{{
+				Name:        fileName,
+				ContentType: file.ContentType,
+				Data:        buf.Bytes(),
+			}},
+		})
+		if err != nil {
+			file.Status = domain.ReportFileFailed
+			file.Error = err.Error()
+		} else {
+			file.Status = domain.ReportFileSent
+		}
+	}
+
+	id, err := s.reportRepo.CreateFile(ctx, file)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	return s.reportRepo.FindFile(ctx, definition.ID, id)
+}
+
+// table consulta as linhas do tipo de relatório e formata a tabela (valores com ponto decimal)
+func (s *scheduledReportService) table(ctx context.Context, definition *domain.ReportDefinition, from, to time.Time) (*report.Table, error) {
+	table := &report.Table{
+		Title: definition.Name,
+		Subtitle: fmt.Sprintf("Período de %s a %s",
+			from.Format("02/01/2006"), to.AddDate(0, 0, -1).Format("02/01/2006")),
+	}
+
+	switch definition.Type {
+	case domain.ReportTypeCosts:
+		lines, err := s.dataRepo.CostLines(ctx, from, to, definition.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		table.Columns = []report.Column{
+			{Name: "Ticket", Width: 9},
+			{Name: "Cliente", Width: 10},
+			{Name: "Agência", Width: 12},
+			{Name: "Data", Width: 10},
+			{Name: "Problema", Width: 14},
+			{Name: "Solução", Width: 16},
+			{Name: "Qtd", Width: 3, Numeric: true},
+			{Name: "Unitário", Width: 9, Numeric: true},
+			{Name: "Subtotal", Width: 9, Numeric: true},
+			{Name: "Gar", Width: 3},
+		}
+
+		var total domain.Money
+		for _, line := range lines {
+			warranty := ""
+			if line.Warranty {
+				warranty = "Sim"
+			}
+			table.Rows = append(table.Rows, []string{
+				line.TicketNumber,
+				line.ClientName,
+				line.BranchName,
+				line.CreatedAt.Format("02/01/2006"),
+				line.ProblemName,
+				line.SolutionName,
+				strconv.Itoa(line.Quantity),
+				line.UnitPrice.String(),
+				line.Subtotal.String(),
+				warranty,
+			})
+			total += line.Subtotal
+		}
+		table.Rows = append(table.Rows, []string{"Total", "", "", "", "", "", "", "", total.String(), ""})
+
+	case domain.ReportTypeClientTickets:
+		if definition.ClientID == nil {
+			return nil, ErrReportClientRequired
+		}
+		lines, err := s.dataRepo.ClientTickets(ctx, from, to, *definition.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		table.Columns = []report.Column{
+			{Name: "Ticket", Width: 9},
+			{Name: "Agência", Width: 16},
+			{Name: "Cidade/UF", Width: 16},
+			{Name: "Status", Width: 18},
+			{Name: "Prioridade", Width: 10},
+			{Name: "Abertura", Width: 10},
+			{Name: "Fechamento", Width: 10},
+			{Name: "Custo", Width: 9, Numeric: true},
+		}
+
+		var total domain.Money
+		for _, line := range lines {
+			closed := ""
+			if line.CloseDate != nil {
+				closed = line.CloseDate.Format("02/01/2006")
+			}
+			table.Rows = append(table.Rows, []string{
+				line.TicketNumber,
+				line.BranchName,
+				strings.Trim(line.City+"/"+line.State, "/"),
+				domain.TicketStatusLabels[line.Status],
+				line.Priority,
+				line.OpenDate.Format("02/01/2006"),
+				closed,
+				line.Cost.String(),
+			})
+			total += line.Cost
+		}
+		table.Rows = append(table.Rows, []string{"Total", fmt.Sprintf("%d tickets", len(lines)), "", "", "", "", "", total.String()})
+
+	default:
+		return nil, ErrInvalidReportType
+	}
+
+	return table, nil
+}
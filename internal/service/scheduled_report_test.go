@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/report"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+func TestScheduledReportValidate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	daily := domain.ReportSchedule{Frequency: domain.ScheduleDaily, Hour: 6}
+	svc := &scheduledReportService{clientRepo: &fakeContractClientRepo{}}
+
+	tests := []struct {
+		name       string
+		definition domain.ReportDefinition
+		want       error
+	}{
+		{"costs", domain.ReportDefinition{Type: domain.ReportTypeCosts, Format: report.FormatCSV, Schedule: daily}, nil},
+		{"client tickets", domain.ReportDefinition{Type: domain.ReportTypeClientTickets, ClientID: intPtr(1), Format: report.FormatPDF, Schedule: daily}, nil},
+		{"unknown type", domain.ReportDefinition{Type: "sales", Format: report.FormatCSV, Schedule: daily}, ErrInvalidReportType},
+		{"unknown format", domain.ReportDefinition{Type: domain.ReportTypeCosts, Format: "doc", Schedule: daily}, report.ErrUnknownFormat},
+		{"invalid schedule", domain.ReportDefinition{Type: domain.ReportTypeCosts, Format: report.FormatCSV,
+			Schedule: domain.ReportSchedule{Frequency: domain.ScheduleMonthly, Day: 31}}, ErrInvalidReportSchedule},
+		{"client tickets without client", domain.ReportDefinition{Type: domain.ReportTypeClientTickets, Format: report.FormatCSV, Schedule: daily}, ErrReportClientRequired},
+		{"unknown client", domain.ReportDefinition{Type: domain.ReportTypeCosts, ClientID: intPtr(2), Format: report.FormatCSV, Schedule: daily}, repository.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.validate(context.Background(), &tt.definition); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var ErrInvalidRankingSort = errors.New("sort must be one of: sla, rework, time, cost, distance, tickets")
+
+// Critério padrão do ranking de técnicos
+const DefaultRankingSort = "sla"
+
+type ScorecardService interface {
+	FindByProvider(ctx context.Context, providerID int, filter domain.ScorecardFilter) (*domain.Scorecard, error)
+	Ranking(ctx context.Context, filter domain.ScorecardFilter, sortBy string) ([]domain.Scorecard, error)
+}
+
+type scorecardService struct {
+	scorecardRepo repository.ScorecardRepository
+	sla           domain.SLAPolicy
+}
+
+func NewScorecardService(scorecardRepo repository.ScorecardRepository, sla domain.SLAPolicy) ScorecardService {
+	return &scorecardService{
+		scorecardRepo: scorecardRepo,
+		sla:           sla,
+	}
+}
+
+func (s *scorecardService) FindByProvider(ctx context.Context, providerID int, filter domain.ScorecardFilter) (*domain.Scorecard, error) {
+	return s.scorecardRepo.FindByProvider(ctx, providerID, filter, s.sla)
+}
+
+// Ranking ordena os técnicos com tickets no período pelo indicador escolhido, do melhor para o pior.
+// Técnicos sem o indicador (ex: nenhum ticket fechado) ficam no fim.
+func (s *scorecardService) Ranking(ctx context.Context, filter domain.ScorecardFilter, sortBy string) ([]domain.Scorecard, error) {
+	better, ok := rankingCriteria[sortBy]
+	if !ok {
+		return nil, ErrInvalidRankingSort
+	}
+
+	scorecards, err := s.scorecardRepo.List(ctx, filter, s.sla)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(scorecards, func(i, j int) bool {
+		return better(&scorecards[i], &scorecards[j])
+	})
+
+	return scorecards, nil
+}
+
+// rankingCriteria indica, para cada critério, se a é melhor que b
+var rankingCriteria = map[string]func(a, b *domain.Scorecard) bool{
+	"sla": func(a, b *domain.Scorecard) bool {
+		return higherFirst(a.SLACompliance, b.SLACompliance)
+	},
+	"rework": func(a, b *domain.Scorecard) bool {
+		return lowerFirst(a.ReworkRate, b.ReworkRate)
+	},
+	"time": func(a, b *domain.Scorecard) bool {
+		return lowerFirst(a.AvgHoursToComplete, b.AvgHoursToComplete)
+	},
+	"cost": func(a, b *domain.Scorecard) bool {
+		return a.AvgCostPerTicket < b.AvgCostPerTicket
+	},
+	"distance": func(a, b *domain.Scorecard) bool {
+		return lowerFirst(a.AvgDistanceKm, b.AvgDistanceKm)
+	},
+	"tickets": func(a, b *domain.Scorecard) bool {
+		return a.TicketsHandled > b.TicketsHandled
+	},
+}
+
+func higherFirst(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	return *a > *b
+}
+
+func lowerFirst(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	return *a < *b
+}
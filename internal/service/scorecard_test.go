@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeScorecardRepo struct {
+	repository.ScorecardRepository
+	scorecards []domain.Scorecard
+}
+
+func (f *fakeScorecardRepo) List(ctx context.Context, filter domain.ScorecardFilter, sla domain.SLAPolicy) ([]domain.Scorecard, error) {
+	return append([]domain.Scorecard(nil), f.scorecards...), nil
+}
+
+func TestScorecardRanking(t *testing.T) {
+	percent := func(v float64) *float64 { return &v }
+	repo := &fakeScorecardRepo{scorecards: []domain.Scorecard{
+		{ProviderID: 1, TicketsHandled: 2, SLACompliance: percent(50), ReworkRate: percent(10), AvgCostPerTicket: domain.NewMoney(200)},
+		// Técnico sem tickets fechados: sem SLA, retrabalho nem tempo
+		{ProviderID: 2, TicketsHandled: 5, AvgCostPerTicket: domain.NewMoney(100)},
+		{ProviderID: 3, TicketsHandled: 3, SLACompliance: percent(90), ReworkRate: percent(30), AvgCostPerTicket: domain.NewMoney(300)},
+	}}
+	svc := NewScorecardService(repo, domain.SLAPolicy{DefaultHours: 48})
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{"sla", []int{3, 1, 2}},
+		{"rework", []int{1, 3, 2}},
+		{"cost", []int{2, 1, 3}},
+		{"tickets", []int{2, 3, 1}},
+		// Sem o indicador em ninguém, a ordem original é mantida
+		{"distance", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			scorecards, err := svc.Ranking(context.Background(), domain.ScorecardFilter{}, tt.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, scorecard := range scorecards {
+				if scorecard.ProviderID != tt.want[i] {
+					t.Fatalf("position %d: got provider %d, want order %v", i, scorecard.ProviderID, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := svc.Ranking(context.Background(), domain.ScorecardFilter{}, "name"); !errors.Is(err, ErrInvalidRankingSort) {
+		t.Fatalf("got %v, want ErrInvalidRankingSort", err)
+	}
+}
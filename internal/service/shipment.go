@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/carrier"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidShipmentDirection = errors.New("direction must be outbound or return")
+	ErrInvalidShipmentStatus    = errors.New("status must be posted, in_transit, out_for_delivery, delivered or failed")
+	ErrInvalidShipmentItems     = errors.New("items need a description or item_id, and serialized items must have quantity 1")
+	ErrTrackingUnavailable      = errors.New("shipment has no tracking number or its carrier is not integrated")
+)
+
+type ShipmentService interface {
+	Create(ctx context.Context, req *dto.ShipmentRequest) (*domain.Shipment, error)
+	FindByID(ctx context.Context, id int) (*domain.Shipment, error)
+	List(ctx context.Context, ticketID *int, serialNumber string) ([]domain.Shipment, error)
+	Update(ctx context.Context, id int, req *dto.UpdateShipmentRequest) (*domain.Shipment, error)
+	AddEvent(ctx context.Context, id int, req *dto.ShipmentEventRequest) (*domain.Shipment, error)
+	Track(ctx context.Context, id int) (*domain.Shipment, error)
+	SyncAll(ctx context.Context) error
+}
+
+type shipmentService struct {
+	shipmentRepo  repository.ShipmentRepository
+	ticketRepo    repository.TicketRepository
+	inventoryRepo repository.InventoryRepository
+	tracker       carrier.Tracker // nil quando não há transportadora integrada
+	txManager     repository.TxManager
+}
+
+func NewShipmentService(
+	shipmentRepo repository.ShipmentRepository,
+	ticketRepo repository.TicketRepository,
+	inventoryRepo repository.InventoryRepository,
+	tracker carrier.Tracker,
+	txManager repository.TxManager,
+) ShipmentService {
+	return &shipmentService{
+		shipmentRepo:  shipmentRepo,
+		ticketRepo:    ticketRepo,
+		inventoryRepo: inventoryRepo,
+		tracker:       tracker,
+		txManager:     txManager,
+	}
+}
+
+func (s *shipmentService) Create(ctx context.Context, req *dto.ShipmentRequest) (*domain.Shipment, error) {
+	// Verificar se ticket existe
+	if _, err := s.ticketRepo.FindByID(ctx, req.TicketID); err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	if req.Direction != domain.ShipmentOutbound && req.Direction != domain.ShipmentReturn {
+		return nil, ErrInvalidShipmentDirection
+	}
+
+	items := make([]domain.ShipmentItem, 0, len(req.Items))
+	for _, reqItem := range req.Items {
+		item := domain.ShipmentItem{
+			ItemID:       reqItem.ItemID,
+			Description:  strings.TrimSpace(reqItem.Description),
+			SerialNumber: strings.TrimSpace(reqItem.SerialNumber),
+			Quantity:     reqItem.Quantity,
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+
+		// Item do estoque empresta o nome quando a descrição não é informada
+		if item.ItemID != nil {
+			inventoryItem, err := s.inventoryRepo.FindItemByID(ctx, *item.ItemID)
+			if err != nil {
+				return nil, err
+			}
+			if item.Description == "" {
+				item.Description = inventoryItem.Name
+			}
+		}
+
+		if item.Description == "" || (item.SerialNumber != "" && item.Quantity != 1) {
+			return nil, ErrInvalidShipmentItems
+		}
+		items = append(items, item)
+	}
+
+	id, err := s.shipmentRepo.Create(ctx, &domain.Shipment{
+		TicketID:       req.TicketID,
+		Direction:      req.Direction,
+		Carrier:        s.carrierName(req.Carrier),
+		TrackingNumber: strings.ToUpper(strings.TrimSpace(req.TrackingNumber)),
+		Notes:          req.Notes,
+		Items:          items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.shipmentRepo.FindByID(ctx, id)
+}
+
+func (s *shipmentService) FindByID(ctx context.Context, id int) (*domain.Shipment, error) {
+	return s.shipmentRepo.FindByID(ctx, id)
+}
+
+func (s *shipmentService) List(ctx context.Context, ticketID *int, serialNumber string) ([]domain.Shipment, error) {
+	return s.shipmentRepo.List(ctx, ticketID, strings.TrimSpace(serialNumber))
+}
+
+// Update altera transportadora, código de rastreio e observações
+func (s *shipmentService) Update(ctx context.Context, id int, req *dto.UpdateShipmentRequest) (*domain.Shipment, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment.Carrier = s.carrierName(req.Carrier)
+	shipment.TrackingNumber = strings.ToUpper(strings.TrimSpace(req.TrackingNumber))
+	shipment.Notes = req.Notes
+
+	if err := s.shipmentRepo.Update(ctx, shipment); err != nil {
+		return nil, err
+	}
+
+	return s.shipmentRepo.FindByID(ctx, id)
+}
+
+// AddEvent registra manualmente um evento (transportadoras sem integração)
+func (s *shipmentService) AddEvent(ctx context.Context, id int, req *dto.ShipmentEventRequest) (*domain.Shipment, error) {
+	if _, err := s.shipmentRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if !validShipmentEventStatus(req.Status) {
+		return nil, ErrInvalidShipmentStatus
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid occurred_at format: %w", err)
+		}
+		occurredAt = parsed
+	}
+
+	_, err := s.shipmentRepo.AddEvents(ctx, id, []domain.ShipmentEvent{{
+		Status:      req.Status,
+		Description: req.Description,
+		Location:    req.Location,
+		Source:      domain.ShipmentEventManual,
+		OccurredAt:  occurredAt,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.refresh(ctx, id)
+}
+
+// Track consulta a transportadora e atualiza envio e ticket
+func (s *shipmentService) Track(ctx context.Context, id int) (*domain.Shipment, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.track(ctx, shipment); err != nil {
+		return nil, err
+	}
+
+	return s.refresh(ctx, id)
+}
+
+// SyncAll consulta todos os envios em trânsito (usado pelo worker)
+func (s *shipmentService) SyncAll(ctx context.Context) error {
+	shipments, err := s.shipmentRepo.ListTrackable(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range shipments {
+		shipment := &shipments[i]
+		if s.tracker == nil || shipment.Carrier != s.tracker.Name() {
+			continue
+		}
+
+		if err := s.track(ctx, shipment); err != nil {
+			// Um objeto com problema não impede a consulta dos demais
+			log.Printf("Shipment %d (%s) not tracked: %v", shipment.ID, shipment.TrackingNumber, err)
+			continue
+		}
+
+		if _, err := s.refresh(ctx, shipment.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *shipmentService) track(ctx context.Context, shipment *domain.Shipment) error {
+	if s.tracker == nil || shipment.TrackingNumber == "" || shipment.Carrier != s.tracker.Name() {
+		return ErrTrackingUnavailable
+	}
+
+	carrierEvents, err := s.tracker.Track(ctx, shipment.TrackingNumber)
+	if err != nil {
+		return fmt.Errorf("failed to track shipment: %w", err)
+	}
+
+	// Os status normalizados da transportadora são os mesmos do envio
+	events := make([]domain.ShipmentEvent, 0, len(carrierEvents))
+	for _, event := range carrierEvents {
+		events = append(events, domain.ShipmentEvent{
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    event.Location,
+			Source:      s.tracker.Name(),
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+
+	_, err = s.shipmentRepo.AddEvents(ctx, shipment.ID, events)
+	return err
+}
+
+// refresh recalcula o status do envio a partir dos eventos e, se ele mudou, avança o ticket
+func (s *shipmentService) refresh(ctx context.Context, id int) (*domain.Shipment, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Eventos já vêm em ordem cronológica; o status é o do último
+	status := domain.ShipmentCreated
+	var shippedAt, deliveredAt *time.Time
+	for i := range shipment.Events {
+		event := &shipment.Events[i]
+		status = event.Status
+		if shippedAt == nil {
+			shippedAt = &event.OccurredAt
+		}
+		if event.Status == domain.ShipmentDelivered {
+			deliveredAt = &event.OccurredAt
+		}
+	}
+	if status != domain.ShipmentDelivered {
+		deliveredAt = nil
+	}
+
+	statusChanged := status != shipment.Status
+	if !statusChanged && sameTime(shippedAt, shipment.ShippedAt) && sameTime(deliveredAt, shipment.DeliveredAt) {
+		return shipment, nil
+	}
+
+	// Status do envio e do ticket na mesma transação
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		shipment.Status = status
+		shipment.ShippedAt = shippedAt
+		shipment.DeliveredAt = deliveredAt
+		if err := s.shipmentRepo.UpdateStatus(ctx, shipment); err != nil {
+			return err
+		}
+
+		// O ticket só avança quando o envio muda de status: uma consulta sem novidade não
+		// desfaz a mudança de status feita manualmente no ticket
+		if !statusChanged {
+			return nil
+		}
+		return s.advanceTicket(ctx, shipment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// advanceTicket reflete o envio no status do ticket:
+// envio em trânsito -> Enviado, envio entregue -> Equipamento Entregue,
+// retorno em andamento -> Logística Reversa
+func (s *shipmentService) advanceTicket(ctx context.Context, shipment *domain.Shipment) error {
+	if shipment.Status == domain.ShipmentCreated {
+		return nil
+	}
+
+	ticket, err := s.ticketRepo.FindByID(ctx, shipment.TicketID)
+	if err != nil {
+		return fmt.Errorf("ticket not found: %w", err)
+	}
+	if ticket.Status == domain.TicketStatusConcluido {
+		return nil
+	}
+
+	var target int
+	switch shipment.Direction {
+	case domain.ShipmentOutbound:
+		if shipment.Status == domain.ShipmentDelivered {
+			target = domain.TicketStatusEquipamentoEntregue
+		} else if ticket.Status != domain.TicketStatusEquipamentoEntregue {
+			target = domain.TicketStatusEnviado
+		}
+	case domain.ShipmentReturn:
+		target = domain.TicketStatusLogisticaReversa
+	}
+
+	if target == 0 || ticket.Status == target {
+		return nil
+	}
+
+	if err := s.ticketRepo.UpdateStatus(ctx, ticket.ID, target); err != nil {
+		return fmt.Errorf("failed to update ticket status: %w", err)
+	}
+
+	return nil
+}
+
+// carrierName normaliza a transportadora; sem informação, assume a integrada
+func (s *shipmentService) carrierName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" && s.tracker != nil {
+		return s.tracker.Name()
+	}
+	return name
+}
+
+func validShipmentEventStatus(status string) bool {
+	switch status {
+	case domain.ShipmentPosted,
+		domain.ShipmentInTransit,
+		domain.ShipmentOutForDelivery,
+		domain.ShipmentDelivered,
+		domain.ShipmentFailed:
+		return true
+	}
+	return false
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/carrier"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeShipmentRepo struct {
+	repository.ShipmentRepository
+	shipment domain.Shipment
+}
+
+func (f *fakeShipmentRepo) ListTrackable(ctx context.Context) ([]domain.Shipment, error) {
+	return []domain.Shipment{f.shipment}, nil
+}
+
+func (f *fakeShipmentRepo) FindByID(ctx context.Context, id int) (*domain.Shipment, error) {
+	shipment := f.shipment
+	return &shipment, nil
+}
+
+func (f *fakeShipmentRepo) AddEvents(ctx context.Context, shipmentID int, events []domain.ShipmentEvent) (int, error) {
+	inserted := 0
+	for _, event := range events {
+		duplicate := false
+		for _, existing := range f.shipment.Events {
+			if existing.Status == event.Status && existing.OccurredAt.Equal(event.OccurredAt) {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			f.shipment.Events = append(f.shipment.Events, event)
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+func (f *fakeShipmentRepo) UpdateStatus(ctx context.Context, shipment *domain.Shipment) error {
+	f.shipment.Status = shipment.Status
+	f.shipment.ShippedAt = shipment.ShippedAt
+	f.shipment.DeliveredAt = shipment.DeliveredAt
+	return nil
+}
+
+type fakeShipmentTicketRepo struct {
+	repository.TicketRepository
+	ticket  domain.Ticket
+	updates int
+}
+
+func (f *fakeShipmentTicketRepo) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
+	ticket := f.ticket
+	return &ticket, nil
+}
+
+func (f *fakeShipmentTicketRepo) UpdateStatus(ctx context.Context, ticketID int, status int) error {
+	f.ticket.Status = status
+	f.updates++
+	return nil
+}
+
+type fakeTracker struct {
+	events []carrier.Event
+}
+
+func (f *fakeTracker) Name() string { return "correios" }
+
+func (f *fakeTracker) Track(ctx context.Context, trackingNumber string) ([]carrier.Event, error) {
+	return f.events, nil
+}
+
+func TestShipmentSyncAdvancesTicketOnlyOnStatusChange(t *testing.T) {
+	shipments := &fakeShipmentRepo{shipment: domain.Shipment{
+		ID: 1, TicketID: 5, Direction: domain.ShipmentOutbound, Carrier: "correios",
+		TrackingNumber: "AA123BR", Status: domain.ShipmentCreated,
+	}}
+	tickets := &fakeShipmentTicketRepo{ticket: domain.Ticket{ID: 5, Status: domain.TicketStatusAgendado}}
+	tracker := &fakeTracker{events: []carrier.Event{
+		{Status: domain.ShipmentPosted, OccurredAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+	}}
+	service := NewShipmentService(shipments, tickets, nil, tracker, fakeTxManager{})
+	ctx := context.Background()
+
+	if err := service.SyncAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tickets.ticket.Status != domain.TicketStatusEnviado {
+		t.Fatalf("got ticket status %d, want Enviado", tickets.ticket.Status)
+	}
+
+	// Mudança manual do ticket não é desfeita por uma consulta sem novidades
+	tickets.ticket.Status = domain.TicketStatusEmAtendimento
+	for i := 0; i < 2; i++ {
+		if err := service.SyncAll(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if tickets.ticket.Status != domain.TicketStatusEmAtendimento || tickets.updates != 1 {
+		t.Fatalf("got ticket status %d after %d updates, want the manual status kept", tickets.ticket.Status, tickets.updates)
+	}
+
+	// Novo status do envio avança o ticket
+	tracker.events = append(tracker.events, carrier.Event{
+		Status: domain.ShipmentDelivered, OccurredAt: time.Date(2026, 10, 20, 14, 0, 0, 0, time.UTC),
+	})
+	if err := service.SyncAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tickets.ticket.Status != domain.TicketStatusEquipamentoEntregue {
+		t.Fatalf("got ticket status %d, want Equipamento Entregue", tickets.ticket.Status)
+	}
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/cnab"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/pdf"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+var (
+	ErrInvalidStatementPeriod = errors.New("statement period must end on or after its start")
+	ErrDeductionTicket        = errors.New("deduction ticket is not part of the statement")
+	ErrStatementNotApproved   = errors.New("statement must be approved before exporting the payment file")
+	ErrNothingToPay           = errors.New("statement total must be greater than zero")
+	ErrPayerNotConfigured     = errors.New("payer bank account is not configured")
+	ErrTicketCostsLocked      = errors.New("ticket is in a provider statement or an invoice and its costs are locked")
+)
+
+type StatementService interface {
+	Create(ctx context.Context, statement *domain.ProviderStatement) (*domain.ProviderStatement, error)
+	List(ctx context.Context, providerID *int, status string) ([]domain.ProviderStatement, error)
+	FindByID(ctx context.Context, id int) (*domain.ProviderStatement, error)
+	AddDeduction(ctx context.Context, statementID int, req *dto.StatementDeductionRequest) (*domain.ProviderStatement, error)
+	RemoveDeduction(ctx context.Context, statementID, lineID int) (*domain.ProviderStatement, error)
+	Approve(ctx context.Context, id, userID int) (*domain.ProviderStatement, error)
+	Pay(ctx context.Context, id int, reference string) (*domain.ProviderStatement, error)
+	Delete(ctx context.Context, id int) error
+	PDF(ctx context.Context, id int) ([]byte, error)
+	PaymentFile(ctx context.Context, id int) ([]byte, error)
+
+	// Conta bancária do técnico
+	FindBankAccount(ctx context.Context, providerID int) (*domain.ProviderBankAccount, error)
+	SaveBankAccount(ctx context.Context, account *domain.ProviderBankAccount) (*domain.ProviderBankAccount, error)
+}
+
+type statementService struct {
+	statementRepo repository.StatementRepository
+	providerRepo  repository.ProviderRepository
+	payer         cnab.Account // Conta da empresa que paga os extratos
+}
+
+func NewStatementService(
+	statementRepo repository.StatementRepository,
+	providerRepo repository.ProviderRepository,
+	payer cnab.Account,
+) StatementService {
+	return &statementService{
+		statementRepo: statementRepo,
+		providerRepo:  providerRepo,
+		payer:         payer,
+	}
+}
+
+// Create gera o extrato em rascunho com os tickets concluídos do técnico no período
+func (s *statementService) Create(ctx context.Context, statement *domain.ProviderStatement) (*domain.ProviderStatement, error) {
+	if statement.PeriodEnd.Before(statement.PeriodStart) {
+		return nil, ErrInvalidStatementPeriod
+	}
+
+	// Validar se provider existe
+	if _, err := s.providerRepo.FindByID(ctx, statement.ProviderID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.statementRepo.Create(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindByID(ctx, id)
+}
+
+func (s *statementService) List(ctx context.Context, providerID *int, status string) ([]domain.ProviderStatement, error) {
+	return s.statementRepo.List(ctx, providerID, status)
+}
+
+func (s *statementService) FindByID(ctx context.Context, id int) (*domain.ProviderStatement, error) {
+	return s.statementRepo.FindByID(ctx, id)
+}
+
+// AddDeduction lança um desconto enquanto o extrato está em rascunho
+func (s *statementService) AddDeduction(ctx context.Context, statementID int, req *dto.StatementDeductionRequest) (*domain.ProviderStatement, error) {
+	statement, err := s.statementRepo.FindByID(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O desconto só pode citar um ticket do próprio extrato
+	if req.TicketID != nil && !statementHasTicket(statement, *req.TicketID) {
+		return nil, ErrDeductionTicket
+	}
+
+	_, err = s.statementRepo.AddDeduction(ctx, &domain.StatementLine{
+		StatementID: statementID,
+		TicketID:    req.TicketID,
+		Description: req.Description,
+		Amount:      req.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindByID(ctx, statementID)
+}
+
+func (s *statementService) RemoveDeduction(ctx context.Context, statementID, lineID int) (*domain.ProviderStatement, error) {
+	if err := s.statementRepo.RemoveDeduction(ctx, statementID, lineID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindByID(ctx, statementID)
+}
+
+func (s *statementService) Approve(ctx context.Context, id, userID int) (*domain.ProviderStatement, error) {
+	if _, err := s.statementRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.statementRepo.Approve(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindByID(ctx, id)
+}
+
+func (s *statementService) Pay(ctx context.Context, id int, reference string) (*domain.ProviderStatement, error) {
+	if _, err := s.statementRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.statementRepo.Pay(ctx, id, reference); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindByID(ctx, id)
+}
+
+// Delete descarta o rascunho e destrava os custos dos tickets incluídos
+func (s *statementService) Delete(ctx context.Context, id int) error {
+	if _, err := s.statementRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.statementRepo.Delete(ctx, id)
+}
+
+// PDF monta o extrato para conferência e envio ao técnico
+func (s *statementService) PDF(ctx context.Context, id int) ([]byte, error) {
+	statement, err := s.statementRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	doc.Title(fmt.Sprintf("Extrato de pagamento #%d", statement.ID))
+	doc.Text("Técnico: " + statement.ProviderName)
+	doc.Text(fmt.Sprintf("Período: %s a %s", statement.PeriodStart.Format("02/01/2006"), statement.PeriodEnd.Format("02/01/2006")))
+	doc.Text("Situação: " + statementStatusLabel(statement.Status))
+	if statement.Notes != "" {
+		doc.Text("Observações: " + statement.Notes)
+	}
+	doc.Rule()
+
+	doc.Row(11, true, pdf.Cell{Text: "Chamados concluídos"})
+	for _, ticket := range statement.Tickets {
+		closed := ""
+		if ticket.CloseDate != nil {
+			closed = ticket.CloseDate.Format("02/01/2006")
+		}
+		doc.Row(9, false,
+			pdf.Cell{Text: ticket.Number},
+			pdf.Cell{Text: truncate(ticket.BranchName, 60), X: 90},
+			pdf.Cell{Text: closed, X: 515, Right: true},
+		)
+	}
+	doc.Rule()
+
+	sections := []struct {
+		kind  string
+		title string
+	}{
+		{domain.StatementLineTravel, "Deslocamento"},
+		{domain.StatementLineLabor, "Mão de obra"},
+		{domain.StatementLineExpense, "Despesas"},
+		{domain.StatementLineDeduction, "Descontos"},
+	}
+	for _, section := range sections {
+		doc.Row(11, true, pdf.Cell{Text: section.title})
+		for _, line := range statement.Lines {
+			if line.Kind != section.kind {
+				continue
+			}
+			doc.Row(9, false,
+				pdf.Cell{Text: truncate(line.Description, 62)},
+				pdf.Cell{Text: fmt.Sprint(line.Quantity), X: 370, Right: true},
+				pdf.Cell{Text: formatMoney(line.UnitAmount), X: 440, Right: true},
+				pdf.Cell{Text: formatMoney(line.Amount), X: 515, Right: true},
+			)
+		}
+		doc.Space(6)
+	}
+	doc.Rule()
+
+	totals := []struct {
+		label string
+		value domain.Money
+	}{
+		{"Deslocamento", statement.TravelTotal()},
+		{"Mão de obra", statement.LaborTotal()},
+		{"Despesas", statement.ExpensesTotal()},
+		{"Descontos", -statement.DeductionsTotal()},
+	}
+	for _, total := range totals {
+		doc.Row(10, false,
+			pdf.Cell{Text: total.label, X: 300},
+			pdf.Cell{Text: formatMoney(total.value), X: 515, Right: true},
+		)
+	}
+	doc.Row(11, true,
+		pdf.Cell{Text: "Total a pagar", X: 300},
+		pdf.Cell{Text: formatMoney(statement.Total()), X: 515, Right: true},
+	)
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PaymentFile gera a remessa CNAB 240 com o crédito do extrato aprovado na conta do técnico
+func (s *statementService) PaymentFile(ctx context.Context, id int) ([]byte, error) {
+	if s.payer.BankCode == "" {
+		return nil, ErrPayerNotConfigured
+	}
+
+	statement, err := s.statementRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Status != domain.StatementApproved {
+		return nil, ErrStatementNotApproved
+	}
+
+	if statement.Total() <= 0 {
+		return nil, ErrNothingToPay
+	}
+
+	account, err := s.statementRepo.FindBankAccount(ctx, statement.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := s.providerRepo.FindByID(ctx, statement.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := cnab.Payment{
+		Reference: fmt.Sprintf("EXTRATO-%d", statement.ID),
+		Payee: cnab.Account{
+			Name:        account.HolderName,
+			Document:    account.Document,
+			BankCode:    account.BankCode,
+			Agency:      account.Agency,
+			AgencyDigit: account.AgencyDigit,
+			Number:      account.Account,
+			NumberDigit: account.AccountDigit,
+			Address:     provider.Address,
+			City:        provider.City,
+			Zipcode:     provider.Zipcode,
+			State:       provider.State,
+		},
+		Amount: statement.Total().Cents(),
+		Date:   now,
+	}
+
+	// O número do extrato identifica a remessa (NSA)
+	var buf bytes.Buffer
+	if err := cnab.Write(&buf, s.payer, []cnab.Payment{payment}, now, statement.ID); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *statementService) FindBankAccount(ctx context.Context, providerID int) (*domain.ProviderBankAccount, error) {
+	if _, err := s.providerRepo.FindByID(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindBankAccount(ctx, providerID)
+}
+
+func (s *statementService) SaveBankAccount(ctx context.Context, account *domain.ProviderBankAccount) (*domain.ProviderBankAccount, error) {
+	// Validar se provider existe
+	if _, err := s.providerRepo.FindByID(ctx, account.ProviderID); err != nil {
+		return nil, err
+	}
+
+	if err := s.statementRepo.SaveBankAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.FindBankAccount(ctx, account.ProviderID)
+}
+
+// ensureCostsUnlocked impede alterar custos de um ticket que já entrou em extrato de pagamento ou em fatura
+func ensureCostsUnlocked(ctx context.Context, ticketRepo repository.TicketRepository, ticketID int) error {
+	locked, err := ticketRepo.CostsLocked(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrTicketCostsLocked
+	}
+	return nil
+}
+
+func statementHasTicket(statement *domain.ProviderStatement, ticketID int) bool {
+	for _, ticket := range statement.Tickets {
+		if ticket.TicketID == ticketID {
+			return true
+		}
+	}
+	return false
+}
+
+func statementStatusLabel(status string) string {
+	switch status {
+	case domain.StatementDraft:
+		return "Rascunho"
+	case domain.StatementApproved:
+		return "Aprovado"
+	case domain.StatementPaid:
+		return "Pago"
+	default:
+		return status
+	}
+}
+
+// formatMoney formata o valor em reais (ex.: R$ 1.234,56)
+func formatMoney(value domain.Money) string {
+	sign := ""
+	cents := value.Cents()
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	integer := fmt.Sprint(cents / 100)
+
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, strings.Join(groups, "."), cents%100)
+}
+
+func truncate(text string, size int) string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return text
+	}
+	return string(runes[:size-3]) + "..."
+}
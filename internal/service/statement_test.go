@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/cnab"
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeStatementRepo struct {
+	repository.StatementRepository
+	statement  domain.ProviderStatement
+	deductions []domain.StatementLine
+}
+
+func (f *fakeStatementRepo) FindByID(ctx context.Context, id int) (*domain.ProviderStatement, error) {
+	if id != f.statement.ID {
+		return nil, repository.ErrStatementNotFound
+	}
+	statement := f.statement
+	return &statement, nil
+}
+
+func (f *fakeStatementRepo) AddDeduction(ctx context.Context, line *domain.StatementLine) (int, error) {
+	f.deductions = append(f.deductions, *line)
+	return len(f.deductions), nil
+}
+
+func TestStatementCreateRejectsInvertedPeriod(t *testing.T) {
+	svc := &statementService{statementRepo: &fakeStatementRepo{}}
+
+	_, err := svc.Create(context.Background(), &domain.ProviderStatement{
+		ProviderID:  3,
+		PeriodStart: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, ErrInvalidStatementPeriod) {
+		t.Fatalf("got %v, want ErrInvalidStatementPeriod", err)
+	}
+}
+
+func TestStatementDeductionMustCiteStatementTicket(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		ticketID *int
+		want     error
+	}{
+		{"without ticket", nil, nil},
+		{"statement ticket", intPtr(10), nil},
+		{"other ticket", intPtr(11), ErrDeductionTicket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statementRepo := &fakeStatementRepo{statement: domain.ProviderStatement{
+				ID: 1, Status: domain.StatementDraft, Tickets: []domain.StatementTicket{{StatementID: 1, TicketID: 10}},
+			}}
+			svc := &statementService{statementRepo: statementRepo}
+
+			_, err := svc.AddDeduction(context.Background(), 1, &dto.StatementDeductionRequest{
+				TicketID: tt.ticketID, Description: "Peça extraviada", Amount: domain.NewMoney(80),
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if (tt.want == nil) != (len(statementRepo.deductions) == 1) {
+				t.Fatalf("got %d deductions recorded", len(statementRepo.deductions))
+			}
+		})
+	}
+}
+
+func TestStatementPaymentFileRequiresApprovedTotal(t *testing.T) {
+	payer := cnab.Account{Name: "Empresa", BankCode: "341"}
+	lines := func(amounts ...float64) []domain.StatementLine {
+		kinds := []string{domain.StatementLineLabor, domain.StatementLineDeduction}
+		var result []domain.StatementLine
+		for i, amount := range amounts {
+			result = append(result, domain.StatementLine{Kind: kinds[i], Amount: domain.NewMoney(amount)})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		payer  cnab.Account
+		status string
+		lines  []domain.StatementLine
+		want   error
+	}{
+		{"payer not configured", cnab.Account{}, domain.StatementApproved, lines(300), ErrPayerNotConfigured},
+		{"draft", payer, domain.StatementDraft, lines(300), ErrStatementNotApproved},
+		{"paid", payer, domain.StatementPaid, lines(300), ErrStatementNotApproved},
+		// Descontos que cobrem os créditos não geram pagamento
+		{"deductions cover the total", payer, domain.StatementApproved, lines(300, 300), ErrNothingToPay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &statementService{
+				statementRepo: &fakeStatementRepo{statement: domain.ProviderStatement{ID: 1, ProviderID: 3, Status: tt.status, Lines: tt.lines}},
+				payer:         tt.payer,
+			}
+
+			if _, err := svc.PaymentFile(context.Background(), 1); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
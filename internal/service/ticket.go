@@ -15,7 +15,7 @@ type TicketService interface {
 	Create(ctx context.Context, req *dto.TicketRequest) (*dto.TicketResponse, error)
 	List(ctx context.Context, limit, offset int) ([]dto.TicketResponse, int, error)
 	FindByID(ctx context.Context, id int) (*dto.TicketResponse, error)
-	Update(ctx context.Context, id int, req *dto.UpdateTicketRequest) (*dto.TicketResponse, error)
+	Update(ctx context.Context, id int, version int, req *dto.UpdateTicketRequest) (*dto.TicketResponse, error)
 	Delete(ctx context.Context, id int) error
 	GetTicketNumber(ctx context.Context) (int, error)
 	AddProvider(ctx context.Context, ticketID int, req *dto.AddProviderRequest) error
@@ -40,6 +40,14 @@ type ticketService struct {
 	problemRepo    repository.ProblemRepository
 	solutionRepo   repository.SolutionRepository
 	distanceService DistanceService
+	checkinRepo     repository.CheckinRepository
+	visitService    VisitService
+	inventoryService InventoryService
+	assetRepo       repository.AssetRepository
+	warrantyService WarrantyService
+	contractService ContractService
+	quoteRepo       repository.QuoteRepository
+	txManager       repository.TxManager
 }
 
 func NewTicketService(
@@ -49,6 +57,14 @@ func NewTicketService(
 	problemRepo repository.ProblemRepository,
 	solutionRepo repository.SolutionRepository,
 	distanceService DistanceService,
+	checkinRepo repository.CheckinRepository,
+	visitService VisitService,
+	inventoryService InventoryService,
+	assetRepo repository.AssetRepository,
+	warrantyService WarrantyService,
+	contractService ContractService,
+	quoteRepo repository.QuoteRepository,
+	txManager repository.TxManager,
 ) TicketService {
 	return &ticketService{
 		ticketRepo:     ticketRepo,
@@ -57,6 +73,14 @@ func NewTicketService(
 		problemRepo:    problemRepo,
 		solutionRepo:   solutionRepo,
 		distanceService: distanceService,
+		checkinRepo:     checkinRepo,
+		visitService:    visitService,
+		inventoryService: inventoryService,
+		assetRepo:       assetRepo,
+		warrantyService: warrantyService,
+		contractService: contractService,
+		quoteRepo:       quoteRepo,
+		txManager:       txManager,
 	}
 }
 
@@ -67,6 +91,11 @@ func (s *ticketService) Create(ctx context.Context, req *dto.TicketRequest) (*dt
 		return nil, fmt.Errorf("branch not found: %w", err)
 	}
 
+	// Validar equipamento (se informado)
+	if err := s.validateAsset(ctx, req.AssetID, req.BranchID); err != nil {
+		return nil, err
+	}
+
 	// Parsear data de abertura
 	openDate, err := time.Parse(time.RFC3339, req.OpenDate)
 	if err != nil {
@@ -82,6 +111,7 @@ func (s *ticketService) Create(ctx context.Context, req *dto.TicketRequest) (*dt
 		OpenDate:    openDate,
 		BranchID:    req.BranchID,
 		ProviderID:  nil, // Será associado posteriormente
+		AssetID:     req.AssetID,
 	}
 
 	// Criar ticket no repositório
@@ -136,7 +166,15 @@ func (s *ticketService) List(ctx context.Context, limit, offset int) ([]dto.Tick
 			distanceValue = &distance.Distance
 		}
 
-		responses = append(responses, *dto.ToTicketResponseWithBranchProviderDistanceAndCosts(&ticket, branch, provider, distanceValue, costs))
+		response := dto.ToTicketResponseWithBranchProviderDistanceAndCosts(&ticket, branch, provider, distanceValue, costs)
+		if err := s.applyVisits(ctx, response); err != nil {
+			return nil, 0, err
+		}
+		if err := s.applyCheckins(ctx, response); err != nil {
+			return nil, 0, err
+		}
+
+		responses = append(responses, *response)
 	}
 
 	return responses, total, nil
@@ -176,22 +214,105 @@ func (s *ticketService) FindByID(ctx context.Context, id int) (*dto.TicketRespon
 		distanceValue = &distance.Distance
 	}
 
-	return dto.ToTicketResponseWithBranchProviderDistanceAndCosts(ticket, branch, provider, distanceValue, costs), nil
+	response := dto.ToTicketResponseWithBranchProviderDistanceAndCosts(ticket, branch, provider, distanceValue, costs)
+	if err := s.applyVisits(ctx, response); err != nil {
+		return nil, err
+	}
+	if err := s.applyCheckins(ctx, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
 }
 
-func (s *ticketService) Update(ctx context.Context, id int, req *dto.UpdateTicketRequest) (*dto.TicketResponse, error) {
+// applyVisits preenche as visitas do ticket e agrega deslocamento e distância ao total
+func (s *ticketService) applyVisits(ctx context.Context, response *dto.TicketResponse) error {
+	visits, err := s.visitService.ListByTicket(ctx, response.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket visits: %w", err)
+	}
+	if len(visits) == 0 {
+		return nil
+	}
+
+	response.Visits = dto.ToVisitResponseList(visits)
+
+	var distance float64
+	var hasDistance bool
+	for _, visit := range visits {
+		response.TravelCost += visit.TravelCost
+		if visit.DistanceKm != nil {
+			distance += *visit.DistanceKm
+			hasDistance = true
+		}
+	}
+	response.TotalCost += response.TravelCost
+
+	// Com distâncias por visita, a distância do ticket é a soma das visitas
+	if hasDistance {
+		response.Distance = &distance
+	}
+
+	return nil
+}
+
+// applyCheckins preenche o tempo em campo e as anomalias de check-in/check-out do ticket
+func (s *ticketService) applyCheckins(ctx context.Context, response *dto.TicketResponse) error {
+	checkins, err := s.checkinRepo.ListByTicket(ctx, response.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket checkins: %w", err)
+	}
+
+	for _, checkin := range checkins {
+		response.OnSiteMinutes += checkin.OnSiteMinutes()
+		response.CheckinAnomalies = append(response.CheckinAnomalies, checkin.Anomalies...)
+	}
+
+	return nil
+}
+
+// validateAsset garante que o equipamento informado existe e está instalado na agência do ticket
+func (s *ticketService) validateAsset(ctx context.Context, assetID *int, branchID int) error {
+	if assetID == nil {
+		return nil
+	}
+
+	asset, err := s.assetRepo.FindByID(ctx, *assetID)
+	if err != nil {
+		return fmt.Errorf("asset not found: %w", err)
+	}
+
+	if asset.BranchID != branchID {
+		return ErrAssetBranchMismatch
+	}
+
+	return nil
+}
+
+// Update substitui os dados do ticket. Com version > 0 (If-Match), a alteração só é aplicada
+// se o ticket ainda estiver nessa versão.
+func (s *ticketService) Update(ctx context.Context, id int, version int, req *dto.UpdateTicketRequest) (*dto.TicketResponse, error) {
 	// Verificar se ticket existe
 	existingTicket, err := s.ticketRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("ticket not found: %w", err)
 	}
 
+	if version > 0 && existingTicket.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+
 	// Validar se branch existe
 	_, err = s.branchRepo.FindByID(ctx, req.BranchID)
 	if err != nil {
 		return nil, fmt.Errorf("branch not found: %w", err)
 	}
 
+	// Validar equipamento (se informado)
+	if err := s.validateAsset(ctx, req.AssetID, req.BranchID); err != nil {
+		return nil, err
+	}
+
 	// Validar se provider existe (se fornecido)
 	if req.ProviderID != 0 {
 		_, err := s.providerRepo.FindByID(ctx, req.ProviderID)
@@ -216,6 +337,30 @@ func (s *ticketService) Update(ctx context.Context, id int, req *dto.UpdateTicke
 		closeDate = &parsed
 	}
 
+	// Ticket em extrato de pagamento não tem os custos alterados
+	locked, err := s.ticketRepo.CostsLocked(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if locked && len(req.SolutionItems) > 0 {
+		return nil, ErrTicketCostsLocked
+	}
+
+	// provider_id 0 remove o técnico do ticket
+	var providerID *int
+	if req.ProviderID != 0 {
+		providerID = &req.ProviderID
+	}
+
+	previousStatus := existingTicket.Status
+
+	// Com orçamento exigido pelo contrato, o atendimento só começa após a aprovação do cliente
+	if int(req.Status) == domain.TicketStatusEmAtendimento && previousStatus != domain.TicketStatusEmAtendimento {
+		if err := ensureQuoteApproved(ctx, s.contractService, s.quoteRepo, id); err != nil {
+			return nil, err
+		}
+	}
+
 	// Atualizar campos do ticket
 	existingTicket.Number = req.Number
 	existingTicket.Status = int(req.Status)
@@ -224,53 +369,90 @@ func (s *ticketService) Update(ctx context.Context, id int, req *dto.UpdateTicke
 	existingTicket.OpenDate = openDate
 	existingTicket.CloseDate = closeDate
 	existingTicket.BranchID = req.BranchID
-	existingTicket.ProviderID = &req.ProviderID
+	existingTicket.ProviderID = providerID
+	existingTicket.AssetID = req.AssetID
+
+	// Ticket, baixa de estoque e custos são gravados juntos: uma falha no meio desfaz tudo
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ticketRepo.Update(ctx, existingTicket); err != nil {
+			return fmt.Errorf("failed to update ticket: %w", err)
+		}
+
+		// Ao concluir o ticket, as peças reservadas são baixadas do estoque
+		if existingTicket.Status == domain.TicketStatusConcluido && previousStatus != domain.TicketStatusConcluido {
+			if err := s.inventoryService.ConsumeTicket(ctx, id); err != nil {
+				return fmt.Errorf("failed to consume stock: %w", err)
+			}
+		}
+
+		// Custos só são substituídos quando solution_items é enviado; ticket em extrato de
+		// pagamento mantém os custos como estão
+		if !locked && req.SolutionItems != nil {
+			return s.replaceCosts(ctx, id, req.SolutionItems)
+		}
 
-	// Atualizar no repositório
-	err = s.ticketRepo.Update(ctx, existingTicket)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update ticket: %w", err)
+		return nil, err
 	}
 
+	// Buscar ticket atualizado
+	updatedTicket, err := s.ticketRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve updated ticket: %w", err)
+	}
+
+	// Buscar custos do ticket atualizado
+	costs, err := s.ticketRepo.GetTicketCosts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticket costs: %w", err)
+	}
+
+	return dto.ToTicketResponseWithCosts(updatedTicket, costs), nil
+}
+
+// replaceCosts substitui os custos do ticket pelos itens informados (sem itens, remove todos)
+func (s *ticketService) replaceCosts(ctx context.Context, id int, items []dto.SolutionItemRequest) error {
 	// Processar solution_items e atualizar custos do ticket
-	if len(req.SolutionItems) > 0 {
+	if len(items) > 0 {
 		var costs []domain.TicketCost
-		for _, item := range req.SolutionItems {
+		for _, item := range items {
+			if item.VisitID != nil {
+				if _, err := s.visitService.FindByID(ctx, id, *item.VisitID); err != nil {
+					return fmt.Errorf("visit not found: %w", err)
+				}
+			}
+
 			cost := domain.TicketCost{
 				TicketID:   id,
+				VisitID:    item.VisitID,
 				SolutionID: nil, // NULL - item customizado, não do catálogo de soluções
 				Quantity:   item.Quantity,
 				UnitPrice:  item.UnitPrice,
-				Subtotal:   float64(item.Quantity) * item.UnitPrice,
+				Subtotal:   item.UnitPrice.Mul(item.Quantity),
 			}
 			costs = append(costs, cost)
 		}
 
-		err = s.ticketRepo.UpdateTicketCosts(ctx, id, costs)
+		err := s.ticketRepo.UpdateTicketCosts(ctx, id, costs)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update ticket costs: %w", err)
+			return fmt.Errorf("failed to update ticket costs: %w", err)
 		}
 	} else {
 		// Se não há solution_items, remove todos os custos existentes
-		err = s.ticketRepo.DeleteTicketCosts(ctx, id)
+		err := s.ticketRepo.DeleteTicketCosts(ctx, id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete ticket costs: %w", err)
+			return fmt.Errorf("failed to delete ticket costs: %w", err)
 		}
 	}
 
-	// Buscar ticket atualizado
-	updatedTicket, err := s.ticketRepo.FindByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve updated ticket: %w", err)
+	// As solutions do catálogo saíram dos custos; reservas pendentes voltam ao estoque
+	if err := s.inventoryService.ReleaseTicket(ctx, id); err != nil {
+		return fmt.Errorf("failed to release stock: %w", err)
 	}
 
-	// Buscar custos do ticket atualizado
-	costs, err := s.ticketRepo.GetTicketCosts(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ticket costs: %w", err)
-	}
-
-	return dto.ToTicketResponseWithCosts(updatedTicket, costs), nil
+	return nil
 }
 
 func (s *ticketService) Delete(ctx context.Context, id int) error {
@@ -280,18 +462,34 @@ func (s *ticketService) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("ticket not found: %w", err)
 	}
 
-	// Remover custos do ticket antes de deletar o ticket
-	err = s.ticketRepo.DeleteTicketCosts(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete ticket costs: %w", err)
+	// Ticket em extrato de pagamento não pode ser removido
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, id); err != nil {
+		return err
 	}
 
-	err = s.ticketRepo.Delete(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete ticket: %w", err)
-	}
+	// Reservas, custos e o ticket saem juntos: uma falha no meio desfaz tudo
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Devolver reservas de estoque do ticket
+		if err := s.inventoryService.ReleaseTicket(ctx, id); err != nil {
+			return fmt.Errorf("failed to release stock: %w", err)
+		}
 
-	return nil
+		// Remover custos do ticket antes de deletar o ticket
+		if err := s.ticketRepo.DeleteTicketCosts(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete ticket costs: %w", err)
+		}
+
+		// Remover visitas: sem isso continuam na agenda do técnico e bloqueando horários
+		if err := s.visitService.DeleteByTicket(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete ticket visits: %w", err)
+		}
+
+		if err := s.ticketRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete ticket: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (s *ticketService) GetTicketNumber(ctx context.Context) (int, error) {
@@ -302,22 +500,16 @@ func (s *ticketService) GetTicketNumber(ctx context.Context) (int, error) {
 	return number, nil
 }
 
+// AddProvider agenda uma visita do provider no ticket
 func (s *ticketService) AddProvider(ctx context.Context, ticketID int, req *dto.AddProviderRequest) error {
-	// Verificar se ticket existe
-	_, err := s.ticketRepo.FindByID(ctx, ticketID)
-	if err != nil {
-		return fmt.Errorf("ticket not found: %w", err)
-	}
-
-	// Verificar se provider existe
-	_, err = s.providerRepo.FindByID(ctx, req.ProviderID)
+	_, err := s.visitService.Schedule(ctx, ticketID, &dto.VisitRequest{
+		ProviderID: req.ProviderID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Notes:      req.Notes,
+	})
 	if err != nil {
-		return fmt.Errorf("provider not found: %w", err)
-	}
-
-	err = s.ticketRepo.AddProvider(ctx, ticketID, req.ProviderID)
-	if err != nil {
-		return fmt.Errorf("failed to add provider to ticket: %w", err)
+		return fmt.Errorf("failed to schedule visit: %w", err)
 	}
 
 	return nil
@@ -325,17 +517,25 @@ func (s *ticketService) AddProvider(ctx context.Context, ticketID int, req *dto.
 
 func (s *ticketService) RemoveProvider(ctx context.Context, ticketID int) error {
 	// Verificar se ticket existe
-	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
 	if err != nil {
 		return fmt.Errorf("ticket not found: %w", err)
 	}
 
-	err = s.ticketRepo.RemoveProvider(ctx, ticketID)
-	if err != nil {
-		return fmt.Errorf("failed to remove provider from ticket: %w", err)
-	}
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ticketRepo.RemoveProvider(ctx, ticketID); err != nil {
+			return fmt.Errorf("failed to remove provider from ticket: %w", err)
+		}
 
-	return nil
+		// Liberar a agenda do técnico removido
+		if ticket.ProviderID != nil {
+			if err := s.visitService.CancelOpenByProvider(ctx, ticketID, *ticket.ProviderID); err != nil {
+				return fmt.Errorf("failed to cancel open visits: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
 func (s *ticketService) GetProviderOnTicket(ctx context.Context, ticketID int) (*dto.ProviderSummaryResponse, error) {
@@ -364,7 +564,7 @@ func (s *ticketService) GetProviderOnTicket(ctx context.Context, ticketID int) (
 // AddProblemToTicket associa um problema a um ticket
 func (s *ticketService) AddProblemToTicket(ctx context.Context, ticketID int, req *dto.TicketProblemRequest) error {
 	// Verificar se ticket existe
-	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
 	if err != nil {
 		return fmt.Errorf("ticket not found: %w", err)
 	}
@@ -375,8 +575,13 @@ func (s *ticketService) AddProblemToTicket(ctx context.Context, ticketID int, re
 		return fmt.Errorf("problem not found: %w", err)
 	}
 
+	// Validar equipamento (se informado)
+	if err := s.validateAsset(ctx, req.AssetID, ticket.BranchID); err != nil {
+		return err
+	}
+
 	// Associar problema ao ticket
-	err = s.ticketRepo.AddProblemToTicket(ctx, ticketID, req.ProblemID)
+	err = s.ticketRepo.AddProblemToTicket(ctx, ticketID, req.ProblemID, req.AssetID)
 	if err != nil {
 		return fmt.Errorf("failed to add problem to ticket: %w", err)
 	}
@@ -409,6 +614,7 @@ func (s *ticketService) GetTicketProblems(ctx context.Context, ticketID int) ([]
 				ID:        tp.ID,
 				TicketID:  tp.TicketID,
 				ProblemID: tp.ProblemID,
+				AssetID:   tp.AssetID,
 				CreatedAt: tp.CreatedAt,
 			})
 			continue
@@ -418,6 +624,7 @@ func (s *ticketService) GetTicketProblems(ctx context.Context, ticketID int) ([]
 			ID:        tp.ID,
 			TicketID:  tp.TicketID,
 			ProblemID: tp.ProblemID,
+			AssetID:   tp.AssetID,
 			Problem: &dto.ProblemResponse{
 				ID:          problem.ID,
 				Name:        problem.Name,
@@ -4,30 +4,72 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/dto"
 )
 
 // AddSolutionToTicket associa uma solution do catálogo a um ticket
 func (s *ticketService) AddSolutionToTicket(ctx context.Context, ticketID int, req *dto.TicketSolutionRequest) error {
 	// Verificar se ticket existe
-	_, err := s.ticketRepo.FindByID(ctx, ticketID)
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
 	if err != nil {
 		return fmt.Errorf("ticket not found: %w", err)
 	}
 
+	// Custos de ticket em extrato de pagamento estão travados
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticketID); err != nil {
+		return err
+	}
+
 	// Verificar se solution existe
-	_, err = s.solutionRepo.FindByID(ctx, req.SolutionID)
+	solution, err := s.solutionRepo.FindByID(ctx, req.SolutionID)
 	if err != nil {
 		return fmt.Errorf("solution not found: %w", err)
 	}
 
-	// Associar solution ao ticket
-	err = s.ticketRepo.AddSolutionToTicket(ctx, ticketID, req.SolutionID, req.Quantity)
+	// Verificar se a visita pertence ao ticket (se informada)
+	if req.VisitID != nil {
+		if _, err := s.visitService.FindByID(ctx, ticketID, *req.VisitID); err != nil {
+			return fmt.Errorf("visit not found: %w", err)
+		}
+	}
+
+	// Retrabalho dentro da garantia de um ticket anterior não é cobrado
+	warrantyTicketID, err := s.warrantyService.Detect(ctx, ticket, solution)
 	if err != nil {
-		return fmt.Errorf("failed to add solution to ticket: %w", err)
+		return fmt.Errorf("failed to check warranty: %w", err)
 	}
 
-	return nil
+	// Preço pelo contrato do cliente vigente na abertura do ticket
+	unitPrice, err := s.contractService.SolutionPrice(ctx, ticketID, solution)
+	if err != nil {
+		return fmt.Errorf("failed to resolve solution price: %w", err)
+	}
+
+	// Reserva das peças e associação na mesma transação: se a associação falhar, as reservas
+	// são desfeitas junto
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Reservar peças do BOM da solution
+		if _, err := s.inventoryService.ReserveForSolution(ctx, ticket, req.SolutionID, req.Quantity); err != nil {
+			return fmt.Errorf("failed to reserve stock: %w", err)
+		}
+
+		// Associar solution ao ticket
+		err := s.ticketRepo.AddSolutionToTicket(ctx, &domain.TicketCost{
+			TicketID:         ticketID,
+			VisitID:          req.VisitID,
+			SolutionID:       &req.SolutionID,
+			Quantity:         req.Quantity,
+			UnitPrice:        unitPrice,
+			Warranty:         warrantyTicketID != nil,
+			WarrantyTicketID: warrantyTicketID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add solution to ticket: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // GetTicketSolutions retorna todas as solutions associadas a um ticket
@@ -57,13 +99,16 @@ func (s *ticketService) GetTicketSolutions(ctx context.Context, ticketID int) ([
 		if err != nil {
 			// Se não encontrar a solution, continua sem os detalhes
 			responses = append(responses, dto.TicketSolutionResponse{
-				ID:         cost.ID,
-				TicketID:   cost.TicketID,
-				SolutionID: *cost.SolutionID,
-				Quantity:   cost.Quantity,
-				UnitPrice:  cost.UnitPrice,
-				Subtotal:   cost.Subtotal,
-				CreatedAt:  cost.CreatedAt,
+				ID:               cost.ID,
+				TicketID:         cost.TicketID,
+				VisitID:          cost.VisitID,
+				SolutionID:       *cost.SolutionID,
+				Quantity:         cost.Quantity,
+				UnitPrice:        cost.UnitPrice,
+				Subtotal:         cost.Subtotal,
+				Warranty:         cost.Warranty,
+				WarrantyTicketID: cost.WarrantyTicketID,
+				CreatedAt:        cost.CreatedAt,
 			})
 			continue
 		}
@@ -71,17 +116,21 @@ func (s *ticketService) GetTicketSolutions(ctx context.Context, ticketID int) ([
 		responses = append(responses, dto.TicketSolutionResponse{
 			ID:         cost.ID,
 			TicketID:   cost.TicketID,
+			VisitID:    cost.VisitID,
 			SolutionID: *cost.SolutionID,
 			Solution: &dto.SolutionResponse{
-				ID:          solution.ID,
-				Name:        solution.Name,
-				Description: solution.Description,
-				ProblemID:   solution.ProblemID,
+				ID:           solution.ID,
+				Name:         solution.Name,
+				Description:  solution.Description,
+				ProblemID:    solution.ProblemID,
+				WarrantyDays: solution.WarrantyDays,
 			},
-			Quantity:  cost.Quantity,
-			UnitPrice: cost.UnitPrice,
-			Subtotal:  cost.Subtotal,
-			CreatedAt: cost.CreatedAt,
+			Quantity:         cost.Quantity,
+			UnitPrice:        cost.UnitPrice,
+			Subtotal:         cost.Subtotal,
+			Warranty:         cost.Warranty,
+			WarrantyTicketID: cost.WarrantyTicketID,
+			CreatedAt:        cost.CreatedAt,
 		})
 	}
 
@@ -96,11 +145,22 @@ func (s *ticketService) RemoveSolutionFromTicket(ctx context.Context, ticketID i
 		return fmt.Errorf("ticket not found: %w", err)
 	}
 
-	// Remover associação
-	err = s.ticketRepo.RemoveSolutionFromTicket(ctx, ticketID, solutionID)
-	if err != nil {
-		return fmt.Errorf("failed to remove solution from ticket: %w", err)
+	// Custos de ticket em extrato de pagamento estão travados
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticketID); err != nil {
+		return err
 	}
 
-	return nil
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Remover associação
+		if err := s.ticketRepo.RemoveSolutionFromTicket(ctx, ticketID, solutionID); err != nil {
+			return fmt.Errorf("failed to remove solution from ticket: %w", err)
+		}
+
+		// Devolver as peças reservadas para a solution
+		if err := s.inventoryService.ReleaseForSolution(ctx, ticketID, solutionID); err != nil {
+			return fmt.Errorf("failed to release stock: %w", err)
+		}
+
+		return nil
+	})
 }
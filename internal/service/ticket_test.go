@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+// TestTicketUpdateStaleVersion confere que o If-Match desatualizado é recusado antes de qualquer
+// alteração: os demais repositórios ficam nil e seriam chamados só depois da conferência.
+func TestTicketUpdateStaleVersion(t *testing.T) {
+	svc := &ticketService{ticketRepo: &fakeTicketRepo{ticket: domain.Ticket{ID: 1, Version: 3}}}
+	req := &dto.UpdateTicketRequest{Number: "T-1", BranchID: 1, SolutionItems: []dto.SolutionItemRequest{}}
+
+	for _, version := range []int{1, 2, 4} {
+		if _, err := svc.Update(context.Background(), 1, version, req); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Fatalf("version %d: got %v, want %v", version, err, repository.ErrVersionConflict)
+		}
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/ericolvr/maintenance-v2/internal/domain"
 	"github.com/ericolvr/maintenance-v2/internal/dto"
 	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
 )
 
+var ErrUserClientRequired = errors.New("client users require client_id")
+
 type UserService interface {
 	Create(ctx context.Context, user *domain.User) (int, error)
 	List(ctx context.Context) ([]domain.User, error)
@@ -23,15 +26,25 @@ type UserService interface {
 }
 
 type userService struct {
-	repo      repository.UserRepository
-	jwtSecret []byte
+	repo         repository.UserRepository
+	providerRepo repository.ProviderRepository
+	clientRepo   repository.ClientRepository
+	jwtSecret    []byte
 }
 
-func NewUserService(repo repository.UserRepository, jwtSecret []byte) UserService {
-	return &userService{repo: repo, jwtSecret: jwtSecret}
+func NewUserService(repo repository.UserRepository, providerRepo repository.ProviderRepository, clientRepo repository.ClientRepository, jwtSecret []byte) UserService {
+	return &userService{repo: repo, providerRepo: providerRepo, clientRepo: clientRepo, jwtSecret: jwtSecret}
 }
 
 func (s *userService) Create(ctx context.Context, user *domain.User) (int, error) {
+	if err := s.validateProvider(ctx, user); err != nil {
+		return 0, err
+	}
+
+	if err := s.validateClient(ctx, user); err != nil {
+		return 0, err
+	}
+
 	hashedPassword, err := domain.HashPassword(user.Password)
 	if err != nil {
 		return 0, fmt.Errorf("erro ao gerar hash da senha: %w", err)
@@ -59,6 +72,14 @@ func (s *userService) FindByMobile(ctx context.Context, mobile string) ([]domain
 }
 
 func (s *userService) Update(ctx context.Context, user *domain.User) error {
+	if err := s.validateProvider(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.validateClient(ctx, user); err != nil {
+		return err
+	}
+
 	if user.Password != "" {
 		hashedPassword, err := domain.HashPassword(user.Password)
 		if err != nil {
@@ -75,7 +96,8 @@ func (s *userService) Delete(ctx context.Context, id int) error {
 }
 
 func (s *userService) Authenticate(ctx context.Context, mobile, password string) (*dto.AuthResponse, error) {
-	users, err := s.repo.FindByMobile(ctx, mobile)
+	// O login ainda não tem escopo: o usuário é buscado entre todos os clientes
+	users, err := s.repo.FindByMobile(tenant.System(ctx), mobile)
 	if err != nil {
 		fmt.Printf("Erro ao buscar usuário: %v\n", err)
 		return nil, errors.New("invalid mobile or password")
@@ -100,11 +122,17 @@ func (s *userService) Authenticate(ctx context.Context, mobile, password string)
 		return nil, errors.New("invalid mobile or password")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	claims := jwt.MapClaims{
+		"id":     user.ID,
 		"name":   user.Name,
 		"mobile": user.Mobile,
 		"role":   user.Role,
-	})
+	}
+	// Usuários de cliente levam o tenant no token; o middleware Tenant restringe os dados a ele
+	if user.ClientID != nil {
+		claims["client_id"] = *user.ClientID
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(s.jwtSecret)
 	if err != nil {
@@ -112,8 +140,39 @@ func (s *userService) Authenticate(ctx context.Context, mobile, password string)
 	}
 
 	return &dto.AuthResponse{
-		Name:  user.Name,
-		Token: tokenString,
-		Role:  user.Role,
+		Name:     user.Name,
+		Token:    tokenString,
+		Role:     user.Role,
+		ClientID: user.ClientID,
 	}, nil
 }
+
+// validateProvider garante que o prestador vinculado ao usuário existe
+func (s *userService) validateProvider(ctx context.Context, user *domain.User) error {
+	if user.ProviderID == nil {
+		return nil
+	}
+
+	if _, err := s.providerRepo.FindByID(ctx, *user.ProviderID); err != nil {
+		return fmt.Errorf("provider not found: %w", err)
+	}
+
+	return nil
+}
+
+// validateClient garante que o usuário de cliente está vinculado a um cliente existente
+func (s *userService) validateClient(ctx context.Context, user *domain.User) error {
+	if user.Role == domain.RoleCliente && user.ClientID == nil {
+		return ErrUserClientRequired
+	}
+
+	if user.ClientID == nil {
+		return nil
+	}
+
+	if _, err := s.clientRepo.FindByID(ctx, *user.ClientID); err != nil {
+		return fmt.Errorf("client not found: %w", err)
+	}
+
+	return nil
+}
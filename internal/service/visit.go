@@ -0,0 +1,419 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/dto"
+	"github.com/ericolvr/maintenance-v2/internal/ical"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+var (
+	ErrInvalidVisitDate    = errors.New("invalid visit date, use RFC3339")
+	ErrInvalidVisitPeriod  = errors.New("visit must have both starts_at and ends_at, ending after it starts")
+	ErrOutsideOpeningHours = errors.New("visit is outside branch opening hours")
+	ErrScheduleConflict    = errors.New("provider already has a visit in this period")
+	ErrVisitHasSolutions   = errors.New("visit has solutions applied and cannot be cancelled")
+	ErrInvalidFeedToken    = errors.New("invalid calendar feed token")
+)
+
+// ScheduleConflictError carrega as visitas que conflitam com o pedido
+type ScheduleConflictError struct {
+	Conflicts []domain.Visit
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return ErrScheduleConflict.Error()
+}
+
+func (e *ScheduleConflictError) Is(target error) bool {
+	return target == ErrScheduleConflict
+}
+
+// Janela publicada no feed .ics do técnico
+const (
+	feedPastWindow   = 30 * 24 * time.Hour
+	feedFutureWindow = 180 * 24 * time.Hour
+)
+
+type VisitService interface {
+	Schedule(ctx context.Context, ticketID int, req *dto.VisitRequest) (*domain.Visit, error)
+	Update(ctx context.Context, ticketID, visitID int, req *dto.VisitRequest) (*domain.Visit, error)
+	FindByID(ctx context.Context, ticketID, visitID int) (*domain.Visit, error)
+	ListByTicket(ctx context.Context, ticketID int) ([]domain.Visit, error)
+	Cancel(ctx context.Context, ticketID, visitID int) error
+	CancelOpenByProvider(ctx context.Context, ticketID, providerID int) error
+	DeleteByTicket(ctx context.Context, ticketID int) error
+	ProviderCalendar(ctx context.Context, providerID int, from, to time.Time) ([]domain.Visit, error)
+	ProviderFeed(ctx context.Context, providerID int, token string) ([]byte, error)
+	FeedToken(providerID int) string
+}
+
+type visitService struct {
+	visitRepo       repository.VisitRepository
+	ticketRepo      repository.TicketRepository
+	branchRepo      repository.BranchRepository
+	providerRepo    repository.ProviderRepository
+	contractService ContractService // Tabela de deslocamento com os valores do contrato do cliente
+	location        *time.Location  // Fuso da empresa, usado no horário de funcionamento das agências
+	feedKey         []byte          // Assina o token da URL do feed .ics
+	txManager       repository.TxManager
+}
+
+func NewVisitService(
+	visitRepo repository.VisitRepository,
+	ticketRepo repository.TicketRepository,
+	branchRepo repository.BranchRepository,
+	providerRepo repository.ProviderRepository,
+	contractService ContractService,
+	location *time.Location,
+	jwtSecret []byte,
+	txManager repository.TxManager,
+) VisitService {
+	return &visitService{
+		visitRepo:       visitRepo,
+		ticketRepo:      ticketRepo,
+		branchRepo:      branchRepo,
+		providerRepo:    providerRepo,
+		contractService: contractService,
+		location:        location,
+		feedKey:         calendarFeedKey(jwtSecret),
+		txManager:       txManager,
+	}
+}
+
+// Schedule cria uma visita no ticket. Sem datas, a visita fica pendente de agendamento.
+func (s *visitService) Schedule(ctx context.Context, ticketID int, req *dto.VisitRequest) (*domain.Visit, error) {
+	visit, err := toVisit(ticketID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar se ticket existe
+	ticket, err := s.ticketRepo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	// O deslocamento de ticket em extrato de pagamento está travado
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticketID); err != nil {
+		return nil, err
+	}
+
+	// Sem técnico informado, a visita é do técnico atual do ticket
+	if visit.ProviderID == 0 {
+		if ticket.ProviderID == nil {
+			return nil, ErrTicketWithoutProvider
+		}
+		visit.ProviderID = *ticket.ProviderID
+	}
+
+	if _, err := s.providerRepo.FindByID(ctx, visit.ProviderID); err != nil {
+		return nil, fmt.Errorf("provider not found: %w", err)
+	}
+
+	if err := s.validatePeriod(ctx, ticket, visit); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyTravelCost(ctx, visit); err != nil {
+		return nil, err
+	}
+
+	// A visita, o técnico e o status do ticket são gravados na mesma transação
+	var id int
+	var createErr error
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// A constraint do banco cobre visitas concorrentes criadas entre a verificação e o insert
+		id, createErr = s.visitRepo.Create(ctx, visit)
+		if createErr != nil {
+			return createErr
+		}
+
+		// O técnico da visita passa a ser o técnico atual do ticket
+		if ticket.ProviderID == nil || *ticket.ProviderID != visit.ProviderID {
+			if err := s.ticketRepo.AddProvider(ctx, ticket.ID, visit.ProviderID); err != nil {
+				return fmt.Errorf("failed to add provider to ticket: %w", err)
+			}
+		}
+
+		// Ticket novo com visita marcada passa a Agendado
+		if ticket.Status == domain.TicketStatusNovo && visit.Scheduled() {
+			if err := s.ticketRepo.UpdateStatus(ctx, ticket.ID, domain.TicketStatusAgendado); err != nil {
+				return fmt.Errorf("failed to update ticket status: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if createErr != nil {
+		// Fora da transação, que o erro do insert já abortou
+		return nil, s.conflictError(ctx, visit, createErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.visitRepo.FindByID(ctx, id)
+}
+
+// Update reagenda a visita e registra distância/observações
+func (s *visitService) Update(ctx context.Context, ticketID, visitID int, req *dto.VisitRequest) (*domain.Visit, error) {
+	existing, err := s.FindByID(ctx, ticketID, visitID)
+	if err != nil {
+		return nil, err
+	}
+
+	visit, err := toVisit(ticketID, req)
+	if err != nil {
+		return nil, err
+	}
+	visit.ID = visitID
+
+	// O técnico de uma visita não muda; para trocar, cancelar e agendar outra
+	visit.ProviderID = existing.ProviderID
+
+	ticket, err := s.ticketRepo.FindByID(ctx, visit.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticket.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.validatePeriod(ctx, ticket, visit); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyTravelCost(ctx, visit); err != nil {
+		return nil, err
+	}
+
+	if err := s.visitRepo.Update(ctx, visit); err != nil {
+		return nil, s.conflictError(ctx, visit, err)
+	}
+
+	return s.visitRepo.FindByID(ctx, visit.ID)
+}
+
+func (s *visitService) FindByID(ctx context.Context, ticketID, visitID int) (*domain.Visit, error) {
+	visit, err := s.visitRepo.FindByID(ctx, visitID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Garantir que a visita pertence ao ticket informado
+	if visit.TicketID != ticketID {
+		return nil, repository.ErrVisitNotFound
+	}
+
+	return visit, nil
+}
+
+func (s *visitService) ListByTicket(ctx context.Context, ticketID int) ([]domain.Visit, error) {
+	if _, err := s.ticketRepo.FindByID(ctx, ticketID); err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+
+	return s.visitRepo.ListByTicket(ctx, ticketID)
+}
+
+func (s *visitService) Cancel(ctx context.Context, ticketID, visitID int) error {
+	visit, err := s.FindByID(ctx, ticketID, visitID)
+	if err != nil {
+		return err
+	}
+
+	if visit.SolutionsCount > 0 {
+		return ErrVisitHasSolutions
+	}
+
+	if err := ensureCostsUnlocked(ctx, s.ticketRepo, ticketID); err != nil {
+		return err
+	}
+
+	return s.visitRepo.Delete(ctx, visitID)
+}
+
+// CancelOpenByProvider remove as visitas futuras do técnico quando ele sai do ticket
+func (s *visitService) CancelOpenByProvider(ctx context.Context, ticketID, providerID int) error {
+	return s.visitRepo.DeleteOpenByProvider(ctx, ticketID, providerID)
+}
+
+// DeleteByTicket remove as visitas de um ticket excluído, que deixam de aparecer na
+// agenda e no feed e de bloquear novos agendamentos do técnico
+func (s *visitService) DeleteByTicket(ctx context.Context, ticketID int) error {
+	return s.visitRepo.DeleteByTicket(ctx, ticketID)
+}
+
+func (s *visitService) ProviderCalendar(ctx context.Context, providerID int, from, to time.Time) ([]domain.Visit, error) {
+	if _, err := s.providerRepo.FindByID(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	return s.visitRepo.ListByProvider(ctx, providerID, from, to)
+}
+
+// ProviderFeed gera o feed iCalendar do técnico para assinatura no celular
+func (s *visitService) ProviderFeed(ctx context.Context, providerID int, token string) ([]byte, error) {
+	// Apps de agenda não enviam o header Authorization: a URL carrega o token do técnico
+	if !hmac.Equal([]byte(token), []byte(s.FeedToken(providerID))) {
+		return nil, ErrInvalidFeedToken
+	}
+
+	// O token dá acesso só à agenda deste técnico, em todos os clientes
+	ctx = tenant.System(ctx)
+	provider, err := s.providerRepo.FindByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	visits, err := s.visitRepo.ListByProvider(ctx, providerID, now.Add(-feedPastWindow), now.Add(feedFutureWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ical.Event, len(visits))
+	for i, visit := range visits {
+		events[i] = ical.Event{
+			UID:         fmt.Sprintf("visit-%d@maintenance-v2", visit.ID),
+			Start:       *visit.StartsAt,
+			End:         *visit.EndsAt,
+			Summary:     fmt.Sprintf("Chamado %s - %s", visit.TicketNumber, visit.BranchName),
+			Description: visit.Notes,
+			Location:    joinNonEmpty(visit.BranchAddress, visit.BranchCity),
+			Updated:     visit.CreatedAt,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, "Agenda - "+provider.Name, events); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FeedToken retorna o token da URL do feed .ics do técnico. Não expira, para que a
+// assinatura no celular continue funcionando; trocar o JWT_SECRET invalida todos
+func (s *visitService) FeedToken(providerID int) string {
+	mac := hmac.New(sha256.New, s.feedKey)
+	mac.Write([]byte(strconv.Itoa(providerID)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// calendarFeedKey deriva a chave do feed a partir do segredo do JWT, para que o token
+// do feed não sirva como token de login nem como link de orçamento
+func calendarFeedKey(jwtSecret []byte) []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("calendar-feed"))
+	return mac.Sum(nil)
+}
+
+// validatePeriod valida datas, horário da agência e conflitos de agenda do técnico
+func (s *visitService) validatePeriod(ctx context.Context, ticket *domain.Ticket, visit *domain.Visit) error {
+	if visit.StartsAt == nil && visit.EndsAt == nil {
+		return nil
+	}
+	if !visit.Scheduled() || !visit.EndsAt.After(*visit.StartsAt) {
+		return ErrInvalidVisitPeriod
+	}
+
+	branch, err := s.branchRepo.FindByID(ctx, ticket.BranchID)
+	if err != nil {
+		return fmt.Errorf("branch not found: %w", err)
+	}
+	if !branch.WithinOpeningHours(*visit.StartsAt, *visit.EndsAt, s.location) {
+		return ErrOutsideOpeningHours
+	}
+
+	conflicts, err := s.visitRepo.FindConflicts(ctx, visit.ProviderID, *visit.StartsAt, *visit.EndsAt, visit.ID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &ScheduleConflictError{Conflicts: conflicts}
+	}
+
+	return nil
+}
+
+// applyTravelCost calcula o deslocamento da visita pela tabela de custos vigente,
+// com os valores do contrato do cliente em vigor na abertura do ticket
+func (s *visitService) applyTravelCost(ctx context.Context, visit *domain.Visit) error {
+	visit.TravelCost = 0
+	if visit.DistanceKm == nil {
+		return nil
+	}
+
+	cost, err := s.contractService.TravelCostTable(ctx, visit.TicketID)
+	if err != nil {
+		return err
+	}
+
+	visit.TravelCost = cost.TravelCost(*visit.DistanceKm)
+	return nil
+}
+
+func (s *visitService) conflictError(ctx context.Context, visit *domain.Visit, err error) error {
+	if !errors.Is(err, repository.ErrVisitOverlap) {
+		return err
+	}
+
+	conflicts, _ := s.visitRepo.FindConflicts(ctx, visit.ProviderID, *visit.StartsAt, *visit.EndsAt, visit.ID)
+	return &ScheduleConflictError{Conflicts: conflicts}
+}
+
+// toVisit converte a requisição em domínio, validando o formato das datas
+func toVisit(ticketID int, req *dto.VisitRequest) (*domain.Visit, error) {
+	visit := &domain.Visit{
+		TicketID:   ticketID,
+		ProviderID: req.ProviderID,
+		DistanceKm: req.DistanceKm,
+		Notes:      req.Notes,
+	}
+
+	if req.StartsAt != nil {
+		startsAt, err := time.Parse(time.RFC3339, *req.StartsAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: starts_at", ErrInvalidVisitDate)
+		}
+		visit.StartsAt = &startsAt
+	}
+
+	if req.EndsAt != nil {
+		endsAt, err := time.Parse(time.RFC3339, *req.EndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ends_at", ErrInvalidVisitDate)
+		}
+		visit.EndsAt = &endsAt
+	}
+
+	return visit, nil
+}
+
+func joinNonEmpty(parts ...string) string {
+	var result string
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		if result != "" {
+			result += ", "
+		}
+		result += part
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+	"github.com/ericolvr/maintenance-v2/internal/tenant"
+)
+
+type fakeFeedProviderRepo struct {
+	repository.ProviderRepository
+}
+
+func (f *fakeFeedProviderRepo) FindByID(ctx context.Context, id int) (*domain.Provider, error) {
+	// Como o repositório real: a rota pública só lê o técnico se o serviço liberar o acesso
+	if !tenant.IsSystem(ctx) {
+		return nil, repository.ErrNoTenant
+	}
+	return &domain.Provider{ID: id, Name: "Técnico"}, nil
+}
+
+type fakeFeedVisitRepo struct {
+	repository.VisitRepository
+}
+
+func (f *fakeFeedVisitRepo) ListByProvider(ctx context.Context, providerID int, from, to time.Time) ([]domain.Visit, error) {
+	return nil, nil
+}
+
+func TestProviderFeedRequiresProviderToken(t *testing.T) {
+	service := NewVisitService(&fakeFeedVisitRepo{}, nil, nil, &fakeFeedProviderRepo{}, nil, time.UTC, []byte("secret"), fakeTxManager{})
+	other := NewVisitService(&fakeFeedVisitRepo{}, nil, nil, &fakeFeedProviderRepo{}, nil, time.UTC, []byte("other-secret"), fakeTxManager{})
+	ctx := context.Background()
+
+	token := service.FeedToken(3)
+	if token != service.FeedToken(3) {
+		t.Fatalf("feed token must be stable for calendar subscriptions")
+	}
+
+	tests := []struct {
+		name       string
+		providerID int
+		token      string
+		wantErr    error
+	}{
+		{"own token", 3, token, nil},
+		{"missing token", 3, "", ErrInvalidFeedToken},
+		{"token of another provider", 4, token, ErrInvalidFeedToken},
+		{"token signed with another secret", 3, other.FeedToken(3), ErrInvalidFeedToken},
+		{"tampered token", 3, strings.ToUpper(token), ErrInvalidFeedToken},
+	}
+
+	for _, tt := range tests {
+		feed, err := service.ProviderFeed(ctx, tt.providerID, tt.token)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && !strings.Contains(string(feed), "BEGIN:VCALENDAR") {
+			t.Errorf("%s: got %q, want an iCalendar feed", tt.name, feed)
+		}
+	}
+}
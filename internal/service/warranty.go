@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type WarrantyService interface {
+	Detect(ctx context.Context, ticket *domain.Ticket, solution *domain.Solution) (*int, error)
+	ReturnsByProvider(ctx context.Context, from, to time.Time) ([]domain.WarrantyReturn, error)
+}
+
+type warrantyService struct {
+	warrantyRepo repository.WarrantyRepository
+	ticketRepo   repository.TicketRepository
+}
+
+func NewWarrantyService(warrantyRepo repository.WarrantyRepository, ticketRepo repository.TicketRepository) WarrantyService {
+	return &warrantyService{
+		warrantyRepo: warrantyRepo,
+		ticketRepo:   ticketRepo,
+	}
+}
+
+// Detect retorna o ticket original quando a solution aplicada é retrabalho dentro da garantia
+func (s *warrantyService) Detect(ctx context.Context, ticket *domain.Ticket, solution *domain.Solution) (*int, error) {
+	// Equipamento atendido: o do problema no ticket ou, sem ele, o do próprio ticket
+	assetID := ticket.AssetID
+	problems, err := s.ticketRepo.GetTicketProblems(ctx, ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticket problems: %w", err)
+	}
+	for _, problem := range problems {
+		if problem.ProblemID == solution.ProblemID && problem.AssetID != nil {
+			assetID = problem.AssetID
+			break
+		}
+	}
+
+	return s.warrantyRepo.FindActiveWarranty(ctx, ticket, solution.ProblemID, assetID)
+}
+
+func (s *warrantyService) ReturnsByProvider(ctx context.Context, from, to time.Time) ([]domain.WarrantyReturn, error) {
+	return s.warrantyRepo.ReturnsByProvider(ctx, from, to)
+}
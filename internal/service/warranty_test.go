@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ericolvr/maintenance-v2/internal/domain"
+	"github.com/ericolvr/maintenance-v2/internal/repository"
+)
+
+type fakeWarrantyRepo struct {
+	repository.WarrantyRepository
+	original *int
+	assetID  *int
+}
+
+func (f *fakeWarrantyRepo) FindActiveWarranty(ctx context.Context, ticket *domain.Ticket, problemID int, assetID *int) (*int, error) {
+	f.assetID = assetID
+	return f.original, nil
+}
+
+type fakeWarrantyTicketRepo struct {
+	repository.TicketRepository
+	problems []domain.TicketProblem
+}
+
+func (f *fakeWarrantyTicketRepo) GetTicketProblems(ctx context.Context, ticketID int) ([]domain.TicketProblem, error) {
+	return f.problems, nil
+}
+
+func TestWarrantyDetectResolvesAsset(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name        string
+		ticketAsset *int
+		problems    []domain.TicketProblem
+		want        *int
+	}{
+		{"ticket asset", intPtr(7), nil, intPtr(7)},
+		{"no asset", nil, []domain.TicketProblem{{ProblemID: 1}}, nil},
+		// O equipamento do problema da solution prevalece sobre o do ticket
+		{"problem asset", intPtr(7), []domain.TicketProblem{{ProblemID: 1, AssetID: intPtr(8)}}, intPtr(8)},
+		{"asset of another problem", intPtr(7), []domain.TicketProblem{{ProblemID: 2, AssetID: intPtr(8)}}, intPtr(7)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warrantyRepo := &fakeWarrantyRepo{original: intPtr(40)}
+			svc := NewWarrantyService(warrantyRepo, &fakeWarrantyTicketRepo{problems: tt.problems})
+
+			original, err := svc.Detect(context.Background(), &domain.Ticket{ID: 41, AssetID: tt.ticketAsset}, &domain.Solution{ProblemID: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if original == nil || *original != 40 {
+				t.Fatalf("got original ticket %v, want 40", original)
+			}
+			switch {
+			case tt.want == nil && warrantyRepo.assetID != nil:
+				t.Fatalf("got asset %d, want none", *warrantyRepo.assetID)
+			case tt.want != nil && (warrantyRepo.assetID == nil || *warrantyRepo.assetID != *tt.want):
+				t.Fatalf("got asset %v, want %d", warrantyRepo.assetID, *tt.want)
+			}
+		})
+	}
+}
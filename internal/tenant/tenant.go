@@ -0,0 +1,32 @@
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+type systemKey struct{}
+
+// WithClient marca o context com o cliente (tenant) do usuário autenticado.
+// Os repositórios restringem a esse cliente todas as consultas a dados de clientes.
+func WithClient(ctx context.Context, clientID int) context.Context {
+	return context.WithValue(ctx, contextKey{}, clientID)
+}
+
+// ClientID retorna o cliente do context
+func ClientID(ctx context.Context) (int, bool) {
+	clientID, ok := ctx.Value(contextKey{}).(int)
+	return clientID, ok
+}
+
+// System marca o context como acesso interno, sem restrição de cliente: usuários internos
+// autenticados, workers e rotas públicas depois de validar o próprio token. Sem cliente e
+// sem essa marca os repositórios negam o acesso a dados de clientes.
+func System(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// IsSystem indica se o context foi marcado com System
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every executa fn imediatamente e depois a cada intervalo, até o contexto ser cancelado.
+// Erros são apenas registrados em log para que a próxima execução aconteça normalmente.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Worker %s started (interval %s)", name, interval)
+
+	for {
+		if err := fn(ctx); err != nil {
+			log.Printf("Worker %s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Worker %s stopped", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
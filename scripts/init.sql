@@ -1,10 +1,27 @@
 -- Database: maintenance_v2
 -- Complete schema with all entities
 
+-- Clients table
+CREATE TABLE clients (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    document VARCHAR(18) NOT NULL DEFAULT '',  -- CNPJ (tomador da NFS-e)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Insert Clients
+INSERT INTO clients (name) VALUES
+('Basa'),
+('Banco do Nordeste'),
+('Correios'),
+('Santander');
+
+
 -- Branchs table  
 CREATE TABLE branchs (
     id SERIAL PRIMARY KEY,
-    client VARCHAR(255),
+    client_id INTEGER NOT NULL REFERENCES clients(id),
     name VARCHAR(255) NOT NULL,
     uniorg VARCHAR(50),
     zipcode VARCHAR(10),
@@ -13,31 +30,21 @@ CREATE TABLE branchs (
     neighborhood VARCHAR(100),
     address VARCHAR(255),
     complement VARCHAR(255),
+    email_domain VARCHAR(255) NOT NULL DEFAULT '',
+    latitude DECIMAL(9,6) NULL,
+    longitude DECIMAL(9,6) NULL,
+    opens_at VARCHAR(5) NOT NULL DEFAULT '',
+    closes_at VARCHAR(5) NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,   -- Controle de concorrência (ETag/If-Match)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Insert Branchs
-INSERT INTO branchs (client, name, uniorg, zipcode, state, city, neighborhood, address, complement) VALUES 
-('Basa', 'Icoaraci', '001-0001', '68810-100', 'PA', 'Belém', 'Centro', 'Rua Manoel Barata, 660', ''),
-('Basa', 'Imperatriz', '002-0002', '65900-120', 'MA', 'Imperatriz', 'Beira io', 'Av. getúlio Vargas, 404', ''),
-('Correios', 'Vanderlei', '003-0009', '05011-001', 'SP', 'São Paulo', 'Pompéia', 'Rua Vanderlei, 832', '');
-
-
--- Clients table
-CREATE TABLE clients (
-    id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Insert Clients
-INSERT INTO clients (name) VALUES
-('Basa'),
-('Banco do Nordeste'),
-('Correios'),
-('Santander');
+INSERT INTO branchs (client_id, name, uniorg, zipcode, state, city, neighborhood, address, complement, email_domain, latitude, longitude, opens_at, closes_at) VALUES 
+(1, 'Icoaraci', '001-0001', '68810-100', 'PA', 'Belém', 'Centro', 'Rua Manoel Barata, 660', '', '', -1.298600, -48.479400, '10:00', '16:00'),
+(1, 'Imperatriz', '002-0002', '65900-120', 'MA', 'Imperatriz', 'Beira io', 'Av. getúlio Vargas, 404', '', '', -5.526400, -47.491900, '10:00', '16:00'),
+(3, 'Vanderlei', '003-0009', '05011-001', 'SP', 'São Paulo', 'Pompéia', 'Rua Vanderlei, 832', '', '', -23.527500, -46.680300, '09:00', '17:00');
 
 
 -- Costs table  
@@ -76,6 +83,7 @@ CREATE TABLE providers (
     neighborhood VARCHAR(100),
     address TEXT,
     complement VARCHAR(255),
+    version INTEGER NOT NULL DEFAULT 1,   -- Controle de concorrência (ETag/If-Match)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -95,15 +103,18 @@ CREATE TABLE IF NOT EXISTS users (
     password VARCHAR(255) NOT NULL,
     role INTEGER NOT NULL DEFAULT 0,
     status BOOLEAN NOT NULL DEFAULT true,
+    provider_id INTEGER NULL,
+    client_id INTEGER NULL REFERENCES clients(id),  -- Usuário de um cliente (tenant); NULL para usuários internos
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Insert Users
-INSERT INTO users (name, mobile, password, role, status) VALUES
-('Administrador', '11999000001', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 1, true),
-('João Silva', '11999000002', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 3, true),
-('Maria Santos', '11999000003', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 5, true);
+INSERT INTO users (name, mobile, password, role, status, provider_id, client_id) VALUES
+('Administrador', '11999000001', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 1, true, NULL, NULL),
+('João Silva', '11999000002', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 3, true, NULL, NULL),
+('Maria Santos', '11999000003', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 5, true, 1, NULL),
+('Gerência Basa', '11999000004', '$2a$10$N9qo8uLOickgx2ZMRZoMye', 7, true, NULL, 1);
 
 
 -- Ticket table
@@ -117,6 +128,10 @@ CREATE TABLE IF NOT EXISTS tickets (
     close_date TIMESTAMP NULL,
     branch_id INTEGER NOT NULL,
     provider_id INTEGER NULL,
+    asset_id INTEGER NULL,
+    assignment_status VARCHAR(20) NOT NULL DEFAULT '',
+    assigned_at TIMESTAMP NULL,           -- Última atribuição a um técnico
+    version INTEGER NOT NULL DEFAULT 1,   -- Controle de concorrência (ETag/If-Match)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -143,6 +158,7 @@ CREATE TABLE IF NOT EXISTS solutions (
     description TEXT,
     unit_price DECIMAL(10,2) NOT NULL,
     problem_id INTEGER,
+    warranty_days INTEGER NOT NULL DEFAULT 0,  -- Garantia do serviço em dias
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -161,6 +177,7 @@ CREATE TABLE IF NOT EXISTS ticket_problems (
     id SERIAL PRIMARY KEY,
     ticket_id INTEGER NOT NULL,
     problem_id INTEGER NOT NULL,
+    asset_id INTEGER NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(ticket_id, problem_id)
 );
@@ -169,13 +186,479 @@ CREATE TABLE IF NOT EXISTS ticket_problems (
 CREATE TABLE IF NOT EXISTS ticket_costs (
     id SERIAL PRIMARY KEY,
     ticket_id INTEGER NOT NULL,
+    visit_id INTEGER NULL,               -- Visita em que a solução foi aplicada
     problem_id INTEGER NOT NULL,
     problem_name VARCHAR(255) NOT NULL DEFAULT '',
     solution_id INTEGER NOT NULL,
     solution_name VARCHAR(255) NOT NULL DEFAULT '',
     quantity INTEGER NOT NULL DEFAULT 1,
     unit_price DECIMAL(10,2) NOT NULL,  -- Preço no momento da aplicação
-    subtotal DECIMAL(10,2) NOT NULL,    -- quantity * unit_price
+    subtotal DECIMAL(10,2) NOT NULL,    -- quantity * unit_price (zero em garantia)
+    warranty BOOLEAN NOT NULL DEFAULT FALSE,
+    warranty_ticket_id INTEGER NULL,    -- Ticket original cuja garantia cobre o retrabalho
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketAttachment table
+CREATE TABLE IF NOT EXISTS ticket_attachments (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT 'application/octet-stream',
+    size BIGINT NOT NULL DEFAULT 0,
+    path VARCHAR(500) NOT NULL,
+    public BOOLEAN NOT NULL DEFAULT FALSE,  -- Visível ao cliente no portal
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketComment table
+CREATE TABLE IF NOT EXISTS ticket_comments (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'api',
+    public BOOLEAN NOT NULL DEFAULT FALSE,  -- Visível ao cliente no portal
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketTimeEntry table (tempo em campo registrado pelo técnico)
+CREATE TABLE IF NOT EXISTS ticket_time_entries (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NOT NULL,
+    minutes INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketCheckin table (check-in/check-out do técnico na agência)
+CREATE TABLE IF NOT EXISTS ticket_checkins (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    checkin_at TIMESTAMP NOT NULL,
+    checkin_latitude DECIMAL(9,6) NOT NULL,
+    checkin_longitude DECIMAL(9,6) NOT NULL,
+    checkin_distance_meters DECIMAL(12,2) NULL,
+    checkout_at TIMESTAMP NULL,
+    checkout_latitude DECIMAL(9,6) NULL,
+    checkout_longitude DECIMAL(9,6) NULL,
+    checkout_distance_meters DECIMAL(12,2) NULL,
+    reported_km DECIMAL(10,2) NULL,
+    anomalies TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketVisit table (visitas do técnico: diagnóstico, retorno com peças, etc.)
+-- A exclusion constraint impede que o mesmo técnico tenha duas visitas sobrepostas
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS ticket_visits (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    provider_id INTEGER NOT NULL,
+    starts_at TIMESTAMPTZ NULL,
+    ends_at TIMESTAMPTZ NULL,
+    distance_km DECIMAL(10,2) NULL,
+    travel_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    EXCLUDE USING gist (provider_id WITH =, tstzrange(starts_at, ends_at) WITH &&) WHERE (starts_at IS NOT NULL)
+);
+
+-- InventoryItem table (peças do estoque)
+CREATE TABLE IF NOT EXISTS inventory_items (
+    id SERIAL PRIMARY KEY,
+    sku VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    unit VARCHAR(20) NOT NULL DEFAULT 'un',
+    min_quantity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- StockLocation table (almoxarifado central ou carro do técnico)
+CREATE TABLE IF NOT EXISTS stock_locations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'warehouse',  -- warehouse | van
+    provider_id INTEGER NULL,                       -- Técnico dono do carro (kind = van)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- StockLevel table (saldo por item e local)
+CREATE TABLE IF NOT EXISTS stock_levels (
+    item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    reserved INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, location_id),
+    CHECK (quantity >= 0),
+    CHECK (reserved >= 0 AND reserved <= quantity)
+);
+
+-- StockMovement table (histórico de movimentações)
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    quantity INTEGER NOT NULL,
+    ticket_id INTEGER NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SolutionPart table (lista de materiais por solution)
+CREATE TABLE IF NOT EXISTS solution_parts (
+    solution_id INTEGER NOT NULL,
+    item_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    PRIMARY KEY (solution_id, item_id),
+    CHECK (quantity > 0)
+);
+
+-- StockReservation table (peças reservadas para tickets)
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    solution_id INTEGER NOT NULL,
+    item_id INTEGER NOT NULL,
+    location_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved',  -- reserved | consumed | released
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Insert Stock Locations
+INSERT INTO stock_locations (name, kind, provider_id) VALUES
+('Almoxarifado Central', 'warehouse', NULL),
+('Carro - Julio Mesquita', 'van', 1);
+
+-- Supplier table (fornecedores de peças)
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    document VARCHAR(20) NOT NULL DEFAULT '',  -- CNPJ
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- PurchaseRequest table (compras de peças para tickets)
+CREATE TABLE IF NOT EXISTS purchase_requests (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    supplier_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- pending | approved | rejected | received
+    notes TEXT NOT NULL DEFAULT '',
+    approved_by INTEGER NULL,                       -- Usuário do Financeiro que aprovou/rejeitou
+    decided_at TIMESTAMP NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    location_id INTEGER NULL,                       -- Local que recebeu as peças
+    received_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- PurchaseRequestItem table (itens cotados)
+CREATE TABLE IF NOT EXISTS purchase_request_items (
+    id SERIAL PRIMARY KEY,
+    purchase_request_id INTEGER NOT NULL REFERENCES purchase_requests(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    CHECK (quantity > 0)
+);
+
+-- Shipment table (envio e logística reversa de equipamentos)
+CREATE TABLE IF NOT EXISTS shipments (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    direction VARCHAR(20) NOT NULL,                -- outbound | return
+    carrier VARCHAR(50) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'created',  -- created | posted | in_transit | out_for_delivery | delivered | failed
+    notes TEXT NOT NULL DEFAULT '',
+    shipped_at TIMESTAMPTZ NULL,
+    delivered_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ShipmentItem table (equipamentos e números de série do envio)
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    item_id INTEGER NULL,
+    description VARCHAR(255) NOT NULL,
+    serial_number VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 1,
+    CHECK (quantity > 0)
+);
+
+-- ShipmentEvent table (eventos de rastreio)
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (shipment_id, occurred_at, status)
+);
+
+-- Asset table (equipamentos instalados nas agências)
+CREATE TABLE IF NOT EXISTS assets (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    model VARCHAR(150) NOT NULL DEFAULT '',
+    serial_number VARCHAR(100) NOT NULL DEFAULT '',
+    installed_at DATE NULL,
+    warranty_ends_at DATE NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Contract table (contratos de clientes com preços negociados)
+CREATE TABLE IF NOT EXISTS contracts (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NULL,                    -- NULL = sem data de término
+    value_per_km DECIMAL(10,2) NULL,      -- NULL = tabela de custos padrão
+    initial_value DECIMAL(10,2) NULL,     -- NULL = tabela de custos padrão
+    show_costs BOOLEAN NOT NULL DEFAULT FALSE, -- Exibe os custos dos tickets no portal do cliente
+    require_quote BOOLEAN NOT NULL DEFAULT FALSE, -- Exige orçamento aprovado antes de Em Atendimento
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_on IS NULL OR ends_on >= starts_on)
+);
+
+-- ContractPrice table (preço negociado por solution)
+CREATE TABLE IF NOT EXISTS contract_prices (
+    id SERIAL PRIMARY KEY,
+    contract_id INTEGER NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    solution_id INTEGER NOT NULL,
+    unit_price DECIMAL(10,2) NOT NULL,
+    UNIQUE (contract_id, solution_id)
+);
+
+-- ProviderBankAccount table (conta bancária do técnico para pagamento)
+CREATE TABLE IF NOT EXISTS provider_bank_accounts (
+    provider_id INTEGER PRIMARY KEY,
+    holder_name VARCHAR(255) NOT NULL,
+    document VARCHAR(20) NOT NULL,        -- CPF ou CNPJ do titular
+    bank_code VARCHAR(3) NOT NULL,
+    agency VARCHAR(5) NOT NULL,
+    agency_digit VARCHAR(1) NOT NULL DEFAULT '',
+    account VARCHAR(12) NOT NULL,
+    account_digit VARCHAR(1) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ProviderStatement table (extrato de pagamento do técnico por período)
+CREATE TABLE IF NOT EXISTS provider_statements (
+    id SERIAL PRIMARY KEY,
+    provider_id INTEGER NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft', -- draft, approved, paid
+    notes TEXT NOT NULL DEFAULT '',
+    approved_by INTEGER NULL,
+    approved_at TIMESTAMP NULL,
+    paid_at TIMESTAMP NULL,
+    payment_reference VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period_end >= period_start)
+);
+
+-- ProviderStatementTicket table (tickets incluídos no extrato; custos ficam travados)
+CREATE TABLE IF NOT EXISTS provider_statement_tickets (
+    statement_id INTEGER NOT NULL REFERENCES provider_statements(id) ON DELETE CASCADE,
+    ticket_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    PRIMARY KEY (ticket_id, provider_id)  -- Um ticket entra uma vez no extrato de cada técnico
+);
+
+-- ProviderStatementLine table (deslocamento, mão de obra e descontos do extrato)
+CREATE TABLE IF NOT EXISTS provider_statement_lines (
+    id SERIAL PRIMARY KEY,
+    statement_id INTEGER NOT NULL REFERENCES provider_statements(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,            -- travel, labor, expense, deduction
+    ticket_id INTEGER NULL,
+    visit_id INTEGER NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketExpense table (prestação de contas do técnico no ticket)
+CREATE TABLE IF NOT EXISTS ticket_expenses (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected
+    notes TEXT NOT NULL DEFAULT '',
+    decided_by INTEGER NULL,
+    decided_at TIMESTAMP NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketExpenseItem table (despesa apresentada, com comprovante e decisão por item)
+CREATE TABLE IF NOT EXISTS ticket_expense_items (
+    id SERIAL PRIMARY KEY,
+    expense_id INTEGER NOT NULL REFERENCES ticket_expenses(id) ON DELETE CASCADE,
+    description VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10,2) NOT NULL,
+    attachment_id INTEGER NULL REFERENCES ticket_attachments(id) ON DELETE SET NULL, -- Comprovante
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected
+    rejection_reason TEXT NOT NULL DEFAULT ''
+);
+
+-- Invoice table (fatura do cliente por ciclo de faturamento, emitida como NFS-e)
+CREATE TABLE IF NOT EXISTS invoices (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',   -- draft, issuing, issued, cancelled
+    notes TEXT NOT NULL DEFAULT '',
+    issuer VARCHAR(50) NOT NULL DEFAULT '',       -- emissor da NFS-e
+    number VARCHAR(50) NOT NULL DEFAULT '',       -- número da NFS-e
+    verification_code VARCHAR(50) NOT NULL DEFAULT '',
+    xml TEXT NOT NULL DEFAULT '',                 -- XML devolvido pela prefeitura
+    issued_at TIMESTAMP NULL,
+    cancelled_at TIMESTAMP NULL,
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period_end >= period_start)
+);
+
+-- InvoiceTicket table (tickets faturados)
+CREATE TABLE IF NOT EXISTS invoice_tickets (
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    ticket_id INTEGER NOT NULL,
+    PRIMARY KEY (invoice_id, ticket_id)
+);
+
+-- InvoiceLine table (itens da fatura)
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,            -- service, travel
+    ticket_id INTEGER NOT NULL,
+    visit_id INTEGER NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ReportDefinition table (relatórios salvos, gerados e enviados por agendamento)
+CREATE TABLE IF NOT EXISTS report_definitions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(30) NOT NULL,                -- costs, client_tickets
+    client_id INTEGER NULL REFERENCES clients(id),
+    format VARCHAR(10) NOT NULL,              -- csv, xlsx, pdf
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    frequency VARCHAR(10) NOT NULL,           -- daily, weekly, monthly
+    weekday INTEGER NOT NULL DEFAULT 0,       -- 0 = domingo (weekly)
+    day_of_month INTEGER NOT NULL DEFAULT 1,  -- 1 a 28 (monthly)
+    hour INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP NOT NULL,
+    last_run_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ReportFile table (arquivo dos relatórios gerados)
+CREATE TABLE IF NOT EXISTS report_files (
+    id SERIAL PRIMARY KEY,
+    definition_id INTEGER NOT NULL REFERENCES report_definitions(id) ON DELETE CASCADE,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    path VARCHAR(500) NOT NULL,
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL,              -- sent, generated, failed
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketRating table (avaliação do atendimento pelo cliente, uma por ticket)
+CREATE TABLE IF NOT EXISTS ticket_ratings (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL UNIQUE REFERENCES tickets(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TicketQuote table (orçamentos do ticket, versionados e aprovados pelo cliente)
+CREATE TABLE IF NOT EXISTS ticket_quotes (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- pending, approved, rejected, superseded
+    notes TEXT NOT NULL DEFAULT '',
+    services_total DECIMAL(10,2) NOT NULL DEFAULT 0,
+    travel_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    total DECIMAL(10,2) NOT NULL DEFAULT 0,
+    sent_to TEXT[] NOT NULL DEFAULT '{}',           -- destinatários do link de aprovação
+    sent_at TIMESTAMP NULL,
+    decided_at TIMESTAMP NULL,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',    -- nome de quem aprovou ou rejeitou
+    decided_by_user_id INTEGER NULL REFERENCES users(id), -- NULL quando decidido pelo link
+    decision_channel VARCHAR(20) NOT NULL DEFAULT '',     -- portal, link
+    decision_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ticket_id, version)
+);
+
+-- TicketQuoteItem table (soluções planejadas no orçamento, copiadas dos custos do ticket)
+CREATE TABLE IF NOT EXISTS ticket_quote_items (
+    id SERIAL PRIMARY KEY,
+    quote_id INTEGER NOT NULL REFERENCES ticket_quotes(id) ON DELETE CASCADE,
+    solution_id INTEGER NULL,
+    problem_name VARCHAR(255) NOT NULL DEFAULT '',
+    solution_name VARCHAR(255) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    subtotal DECIMAL(10,2) NOT NULL DEFAULT 0,
+    warranty BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- EmailMessage table (emails já ingeridos, pelo Message-ID, para não duplicar tickets)
+CREATE TABLE IF NOT EXISTS email_messages (
+    message_id VARCHAR(998) PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    attachments_saved INTEGER NOT NULL DEFAULT 0, -- anexos já gravados, na ordem da mensagem
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -193,3 +676,40 @@ CREATE INDEX IF NOT EXISTS idx_ticket_costs_problem_id ON ticket_costs(problem_i
 CREATE INDEX IF NOT EXISTS idx_ticket_costs_solution_id ON ticket_costs(solution_id);
 CREATE INDEX IF NOT EXISTS idx_distances_ticket_number ON distances(ticket_number);
 CREATE INDEX IF NOT EXISTS idx_distances_provider_id ON distances(provider_id);
+CREATE INDEX IF NOT EXISTS idx_branchs_email_domain ON branchs(email_domain);
+CREATE INDEX IF NOT EXISTS idx_ticket_attachments_ticket_id ON ticket_attachments(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_comments_ticket_id ON ticket_comments(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_users_provider_id ON users(provider_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_time_entries_ticket_id ON ticket_time_entries(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_checkins_ticket_id ON ticket_checkins(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_visits_ticket_id ON ticket_visits(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_visits_provider_period ON ticket_visits(provider_id, starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_ticket_costs_visit_id ON ticket_costs(visit_id);
+CREATE INDEX IF NOT EXISTS idx_stock_locations_provider_id ON stock_locations(provider_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_item_id ON stock_movements(item_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_ticket_id ON stock_reservations(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_requests_ticket_id ON purchase_requests(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_requests_status ON purchase_requests(status);
+CREATE INDEX IF NOT EXISTS idx_purchase_request_items_request_id ON purchase_request_items(purchase_request_id);
+CREATE INDEX IF NOT EXISTS idx_shipments_ticket_id ON shipments(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_shipments_tracking_number ON shipments(tracking_number);
+CREATE INDEX IF NOT EXISTS idx_shipment_items_serial_number ON shipment_items(serial_number);
+CREATE INDEX IF NOT EXISTS idx_assets_branch_id ON assets(branch_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_asset_id ON tickets(asset_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_problems_asset_id ON ticket_problems(asset_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_costs_warranty_ticket_id ON ticket_costs(warranty_ticket_id);
+CREATE INDEX IF NOT EXISTS idx_contracts_client_id ON contracts(client_id, starts_on);
+CREATE INDEX IF NOT EXISTS idx_provider_statements_provider_id ON provider_statements(provider_id, period_start);
+CREATE INDEX IF NOT EXISTS idx_provider_statement_tickets_statement_id ON provider_statement_tickets(statement_id);
+CREATE INDEX IF NOT EXISTS idx_provider_statement_lines_statement_id ON provider_statement_lines(statement_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_expenses_ticket_id ON ticket_expenses(ticket_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_expenses_pending ON ticket_expenses(ticket_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_ticket_expense_items_expense_id ON ticket_expense_items(expense_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_client_id ON invoices(client_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_tickets_ticket_id ON invoice_tickets(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_lines_invoice_id ON invoice_lines(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_report_definitions_next_run_at ON report_definitions(next_run_at) WHERE active = TRUE;
+CREATE INDEX IF NOT EXISTS idx_report_files_definition_id ON report_files(definition_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_branchs_client_id ON branchs(client_id);
+CREATE INDEX IF NOT EXISTS idx_users_client_id ON users(client_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_quote_items_quote_id ON ticket_quote_items(quote_id);